@@ -15,9 +15,9 @@ import (
 func newMysqlConnection() *prom.SqlConnect {
 	// read from config or use default value
 	driver := "mysql"
-	timeoutMs := goapi.AppConfig.GetInt32("dao$boname__lower$.mysql.timeout", 10000)
-	timezone := goapi.AppConfig.GetString("dao$boname__lower$.mysql.timezone", "Asia/Ho_Chi_Minh")
-	dsn := goapi.AppConfig.GetString("dao$boname__lower$.mysql.dsn",
+	timeoutMs := goapi.AppConfig().GetInt32("dao$boname__lower$.mysql.timeout", 10000)
+	timezone := goapi.AppConfig().GetString("dao$boname__lower$.mysql.timezone", "Asia/Ho_Chi_Minh")
+	dsn := goapi.AppConfig().GetString("dao$boname__lower$.mysql.dsn",
 		"test:test@tcp(localhost:3306)/test?charset=utf8mb4,utf8&parseTime=true&loc="+strings.Replace(timezone, "/", "%2f", -1))
 	if sqlConnect, err := prom.NewSqlConnectWithFlavor(driver, dsn, int(timeoutMs), nil, prom.FlavorMySql); err != nil {
 		panic(err)
@@ -7,7 +7,9 @@ Application Server bootstrapper.
 package main
 
 import (
+	"flag"
 	"math/rand"
+	"os"
 	"time"
 
 	"main/src/goapi"
@@ -20,11 +22,20 @@ func main() {
 
 	// start Echo server with custom bootstrappers
 	// bootstrapper routine is passed the echo.Echo instance as argument, and also has access to
-	// - Application configurations via global variable goapi.AppConfig
+	// - Application configurations via function goapi.AppConfig()
 	// - itineris.ApiRouter instance via global variable goapi.ApiRouter
 	var bootstrappers = []goapi.IBootstrapper{
 		gvabe.Bootstrapper,
 		// samples_api_filters.Bootstrapper,
 	}
-	goapi.Start(bootstrappers...)
+
+	// --check-config: validate configurations and exit, without starting the application
+	checkConfig := flag.Bool("check-config", false, "validate configurations (see env APP_CONFIG) and exit")
+	flag.Parse()
+	if *checkConfig {
+		os.Exit(goapi.CheckConfig(bootstrappers...))
+	}
+
+	// no command or "serve": start the application; other commands (e.g. "user create", "db init") run without starting API gateways
+	os.Exit(goapi.RunCommand(flag.Args(), bootstrappers...))
 }
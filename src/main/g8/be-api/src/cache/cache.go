@@ -0,0 +1,126 @@
+/*
+Package cache provides caches for read-through DAO decorators.
+
+  - Cache is the interface a cache must implement; values are opaque byte slices so that a shared cache (e.g. one backed
+    by an external cache server) can be plugged in.
+  - LruCache is an in-process implementation with a maximum number of entries and per-entry TTL.
+  - MeteredCache wraps any Cache and counts hits, misses, writes and invalidations.
+
+@author Thanh Nguyen <btnguyen2k@gmail.com>
+@since template-v0.5.0
+*/
+package cache
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Cache stores values keyed by string.
+//
+// Implementations must be safe for concurrent use. Errors are reported to callers but read-through DAOs treat them
+// as cache misses, so a cache outage degrades performance rather than availability.
+type Cache interface {
+	// Get returns the value stored under the specified key; nil is returned if not found or if the entry has expired.
+	Get(key string) ([]byte, error)
+
+	// Set stores the value under the specified key, replacing the existing one (if any). The entry expires after ttl
+	// (0 or negative value means the cache's default).
+	Set(key string, value []byte, ttl time.Duration) error
+
+	// Delete removes the entry stored under the specified key (if any).
+	Delete(key string) error
+}
+
+// Stats is a snapshot of a cache's metrics.
+type Stats struct {
+	Hits          int64 `json:"hits"`
+	Misses        int64 `json:"misses"`
+	Sets          int64 `json:"sets"`
+	Invalidations int64 `json:"invalidations"`
+	Errors        int64 `json:"errors"`
+	Evictions     int64 `json:"evictions"` // entries removed to make room for new ones (in-process caches only)
+	Entries       int   `json:"entries"`   // number of entries currently held (in-process caches only)
+}
+
+// HitRatio returns hits / (hits + misses), or 0 if the cache has not been read.
+func (s Stats) HitRatio() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+// StatsReporter is implemented by caches that report their own metrics (e.g. LruCache reports evictions and number of entries).
+type StatsReporter interface {
+	Stats() Stats
+}
+
+/*----------------------------------------------------------------------*/
+
+// MeteredCache is a Cache that counts hits, misses, writes, invalidations and errors of the wrapped cache.
+type MeteredCache struct {
+	// counters are accessed atomically, keep them first for 64-bit alignment on 32-bit platforms
+	hits          int64
+	misses        int64
+	sets          int64
+	invalidations int64
+	errors        int64
+	cache         Cache
+}
+
+// NewMeteredCache creates a new MeteredCache that wraps the specified cache.
+func NewMeteredCache(cache Cache) *MeteredCache {
+	return &MeteredCache{cache: cache}
+}
+
+// Get implements Cache.Get
+func (c *MeteredCache) Get(key string) ([]byte, error) {
+	value, err := c.cache.Get(key)
+	switch {
+	case err != nil:
+		atomic.AddInt64(&c.errors, 1)
+		atomic.AddInt64(&c.misses, 1)
+	case value == nil:
+		atomic.AddInt64(&c.misses, 1)
+	default:
+		atomic.AddInt64(&c.hits, 1)
+	}
+	return value, err
+}
+
+// Set implements Cache.Set
+func (c *MeteredCache) Set(key string, value []byte, ttl time.Duration) error {
+	err := c.cache.Set(key, value, ttl)
+	if err != nil {
+		atomic.AddInt64(&c.errors, 1)
+	} else {
+		atomic.AddInt64(&c.sets, 1)
+	}
+	return err
+}
+
+// Delete implements Cache.Delete
+func (c *MeteredCache) Delete(key string) error {
+	err := c.cache.Delete(key)
+	if err != nil {
+		atomic.AddInt64(&c.errors, 1)
+	} else {
+		atomic.AddInt64(&c.invalidations, 1)
+	}
+	return err
+}
+
+// Stats implements StatsReporter.Stats, merging the counters with metrics reported by the wrapped cache (if any).
+func (c *MeteredCache) Stats() Stats {
+	var stats Stats
+	if reporter, ok := c.cache.(StatsReporter); ok {
+		stats = reporter.Stats()
+	}
+	stats.Hits = atomic.LoadInt64(&c.hits)
+	stats.Misses = atomic.LoadInt64(&c.misses)
+	stats.Sets = atomic.LoadInt64(&c.sets)
+	stats.Invalidations = atomic.LoadInt64(&c.invalidations)
+	stats.Errors = atomic.LoadInt64(&c.errors)
+	return stats
+}
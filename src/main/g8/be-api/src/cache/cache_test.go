@@ -0,0 +1,158 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLruCache_GetSetDelete(t *testing.T) {
+	name := "TestLruCache_GetSetDelete"
+	c := NewLruCache(10, 0)
+	if v, err := c.Get("key"); err != nil || v != nil {
+		t.Fatalf("%s failed: expected nil but received %#v (error %s)", name, v, err)
+	}
+	value := []byte("value")
+	if err := c.Set("key", value, 0); err != nil {
+		t.Fatalf("%s failed: error [%s]", name, err)
+	}
+	value[0] = 'X' // caller's slice must not be shared with the cache
+	v, err := c.Get("key")
+	if err != nil || string(v) != "value" {
+		t.Fatalf("%s failed: expected %#v but received %#v (error %s)", name, "value", string(v), err)
+	}
+	v[0] = 'X'
+	if v, _ := c.Get("key"); string(v) != "value" {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, "value", string(v))
+	}
+	if err := c.Set("key", []byte("another"), 0); err != nil {
+		t.Fatalf("%s failed: error [%s]", name, err)
+	}
+	if v, _ := c.Get("key"); string(v) != "another" || c.Size() != 1 {
+		t.Fatalf("%s failed: expected %#v but received %#v (size %d)", name, "another", string(v), c.Size())
+	}
+	if err := c.Delete("key"); err != nil {
+		t.Fatalf("%s failed: error [%s]", name, err)
+	}
+	if v, _ := c.Get("key"); v != nil || c.Size() != 0 {
+		t.Fatalf("%s failed: expected nil but received %#v (size %d)", name, v, c.Size())
+	}
+	if err := c.Delete("not-exist"); err != nil {
+		t.Fatalf("%s failed: error [%s]", name, err)
+	}
+}
+
+func TestLruCache_Ttl(t *testing.T) {
+	name := "TestLruCache_Ttl"
+	c := NewLruCache(0, 50*time.Millisecond)
+	c.Set("default", []byte("1"), 0)
+	c.Set("short", []byte("2"), 10*time.Millisecond)
+	c.Set("long", []byte("3"), time.Hour)
+	time.Sleep(20 * time.Millisecond)
+	if v, _ := c.Get("short"); v != nil {
+		t.Fatalf("%s failed: entry [short] should have expired", name)
+	}
+	if v, _ := c.Get("default"); string(v) != "1" {
+		t.Fatalf("%s failed: entry [default] should not have expired", name)
+	}
+	time.Sleep(40 * time.Millisecond)
+	if v, _ := c.Get("default"); v != nil {
+		t.Fatalf("%s failed: entry [default] should have expired", name)
+	}
+	if v, _ := c.Get("long"); string(v) != "3" {
+		t.Fatalf("%s failed: entry [long] should not have expired", name)
+	}
+	if c.Size() != 1 {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, 1, c.Size())
+	}
+
+	c = NewLruCache(0, 0)
+	c.Set("forever", []byte("1"), 0)
+	time.Sleep(10 * time.Millisecond)
+	if v, _ := c.Get("forever"); string(v) != "1" {
+		t.Fatalf("%s failed: entry without TTL should never expire", name)
+	}
+}
+
+func TestLruCache_Eviction(t *testing.T) {
+	name := "TestLruCache_Eviction"
+	c := NewLruCache(3, 0)
+	for i := 0; i < 3; i++ {
+		c.Set(fmt.Sprintf("%d", i), []byte{byte(i)}, 0)
+	}
+	c.Get("0") // "1" is now the least recently used entry
+	c.Set("3", []byte{3}, 0)
+	if v, _ := c.Get("1"); v != nil {
+		t.Fatalf("%s failed: entry [1] should have been evicted", name)
+	}
+	for _, key := range []string{"0", "2", "3"} {
+		if v, _ := c.Get(key); v == nil {
+			t.Fatalf("%s failed: entry [%s] should not have been evicted", name, key)
+		}
+	}
+	c.Set("3", []byte{33}, 0) // replacing an entry does not evict
+	if stats := c.Stats(); stats.Evictions != 1 || stats.Entries != 3 {
+		t.Fatalf("%s failed: unexpected stats %#v", name, stats)
+	}
+}
+
+func TestLruCache_Concurrency(t *testing.T) {
+	name := "TestLruCache_Concurrency"
+	c := NewLruCache(100, time.Minute)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				key := fmt.Sprintf("%d", (i*j)%150)
+				c.Set(key, []byte(key), 0)
+				if v, _ := c.Get(key); v != nil && string(v) != key {
+					t.Errorf("%s failed: expected %#v but received %#v", name, key, string(v))
+				}
+				if j%10 == 0 {
+					c.Delete(key)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	if c.Size() > 100 {
+		t.Fatalf("%s failed: cache holds %d entries, more than its capacity", name, c.Size())
+	}
+}
+
+type failingCache struct{}
+
+func (failingCache) Get(string) ([]byte, error)              { return nil, errors.New("get failed") }
+func (failingCache) Set(string, []byte, time.Duration) error { return errors.New("set failed") }
+func (failingCache) Delete(string) error                     { return errors.New("delete failed") }
+
+func TestMeteredCache(t *testing.T) {
+	name := "TestMeteredCache"
+	c := NewMeteredCache(NewLruCache(1, 0))
+	c.Get("a")
+	c.Set("a", []byte("a"), 0)
+	c.Get("a")
+	c.Get("a")
+	c.Set("b", []byte("b"), 0)
+	c.Delete("a")
+	expected := Stats{Hits: 2, Misses: 1, Sets: 2, Invalidations: 1, Evictions: 1, Entries: 1}
+	if stats := c.Stats(); stats != expected {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, expected, stats)
+	}
+	if ratio := c.Stats().HitRatio(); ratio < 0.66 || ratio > 0.67 {
+		t.Fatalf("%s failed: expected hit ratio 2/3 but received %#v", name, ratio)
+	}
+
+	c = NewMeteredCache(failingCache{})
+	c.Get("a")
+	c.Set("a", []byte("a"), 0)
+	c.Delete("a")
+	expected = Stats{Misses: 1, Errors: 3}
+	if stats := c.Stats(); stats != expected {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, expected, stats)
+	}
+}
@@ -0,0 +1,115 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+type lruEntry struct {
+	key    string
+	value  []byte
+	expiry time.Time
+}
+
+// LruCache is an in-process implementation of Cache.
+//
+//   - Entries are kept in the local process, hence are not shared between application instances.
+//   - When the cache is full, the least recently used entry is evicted; expired entries are removed when they are read.
+//   - Values are copied in and out, so callers never share byte slices with the cache.
+type LruCache struct {
+	lock       sync.Mutex
+	entries    map[string]*list.Element
+	order      *list.List // most recently used first
+	maxEntries int
+	defaultTtl time.Duration
+	evictions  int64
+}
+
+// NewLruCache creates a new LruCache instance that holds at most maxEntries entries (0 or negative value means
+// unlimited). Entries stored with ttl <= 0 expire after defaultTtl (0 or negative value means they never expire).
+func NewLruCache(maxEntries int, defaultTtl time.Duration) *LruCache {
+	return &LruCache{
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+		maxEntries: maxEntries,
+		defaultTtl: defaultTtl,
+	}
+}
+
+func _copyBytes(src []byte) []byte {
+	dst := make([]byte, len(src))
+	copy(dst, src)
+	return dst
+}
+
+// Get implements Cache.Get
+func (c *LruCache) Get(key string) ([]byte, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, nil
+	}
+	entry := el.Value.(*lruEntry)
+	if !entry.expiry.IsZero() && !entry.expiry.After(time.Now()) {
+		c._remove(el)
+		return nil, nil
+	}
+	c.order.MoveToFront(el)
+	return _copyBytes(entry.value), nil
+}
+
+// Set implements Cache.Set
+func (c *LruCache) Set(key string, value []byte, ttl time.Duration) error {
+	if ttl <= 0 {
+		ttl = c.defaultTtl
+	}
+	entry := &lruEntry{key: key, value: _copyBytes(value)}
+	if ttl > 0 {
+		entry.expiry = time.Now().Add(ttl)
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if el, ok := c.entries[key]; ok {
+		el.Value = entry
+		c.order.MoveToFront(el)
+		return nil
+	}
+	for c.maxEntries > 0 && c.order.Len() >= c.maxEntries {
+		c._remove(c.order.Back())
+		c.evictions++
+	}
+	c.entries[key] = c.order.PushFront(entry)
+	return nil
+}
+
+// Delete implements Cache.Delete
+func (c *LruCache) Delete(key string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if el, ok := c.entries[key]; ok {
+		c._remove(el)
+	}
+	return nil
+}
+
+// Size returns number of entries currently held by the cache (including expired ones that have not been removed).
+func (c *LruCache) Size() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.order.Len()
+}
+
+// Stats implements StatsReporter.Stats, only Evictions and Entries are populated.
+func (c *LruCache) Stats() Stats {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return Stats{Evictions: c.evictions, Entries: c.order.Len()}
+}
+
+// _remove removes an entry, caller must hold the lock.
+func (c *LruCache) _remove(el *list.Element) {
+	c.order.Remove(el)
+	delete(c.entries, el.Value.(*lruEntry).key)
+}
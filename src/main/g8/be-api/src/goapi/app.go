@@ -1,22 +1,27 @@
 package goapi
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
-	"log"
 	"net"
 	"net/http"
 	"os"
 	"regexp"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	hocon "github.com/go-akka/configuration"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	pb "main/grpc"
 	"main/src/itineris"
+	"main/src/logging"
 	"main/src/utils"
 )
 
@@ -27,94 +32,289 @@ const (
 var (
 	AppVersion       string
 	AppVersionNumber uint64
-	AppConfig        *hocon.Config
 	ApiRouter        *itineris.ApiRouter
+
+	// appConfig holds the *hocon.Config currently in effect, replaced as a whole when configurations are reloaded
+	appConfig atomic.Value
 )
 
+// AppConfig returns the application configurations currently in effect.
+//
+// Configurations may be replaced at any time by ReloadAppConfig, hence callers should not keep the returned instance
+// around; a caller that reads several related settings should call AppConfig once and read them from the same instance.
+//
+// @since template-v0.5.0
+func AppConfig() *hocon.Config {
+	conf, _ := appConfig.Load().(*hocon.Config)
+	return conf
+}
+
+// setAppConfig replaces the application configurations in effect.
+func setAppConfig(conf *hocon.Config) {
+	appConfig.Store(conf)
+}
+
 // Start bootstraps the application.
 func Start(bootstrappers ...IBootstrapper) {
-	var err error
+	initApp(bootstrappers)
+	bootstrapApp(bootstrappers)
 
-	// load application configurations
-	AppConfig = initAppConfig()
-	httpHeaderAppId = AppConfig.GetString("api.http.header_app_id")
-	httpHeaderAccessToken = AppConfig.GetString("api.http.header_access_token")
-	AppVersion = AppConfig.GetString("app.version")
-	AppVersionNumber = utils.VersionToNumber(AppVersion)
+	// initialize and start gRPC server
+	initGrpcServer()
 
-	// setup api-router
-	ApiRouter = itineris.NewApiRouter()
+	// initialize and start echo server
+	initEchoServer()
 
-	// initialize "Location"
-	utils.Location, err = time.LoadLocation(AppConfig.GetString("timezone"))
-	if err != nil {
-		panic(err)
+	// watch for configuration changes
+	SubscribeConfigChanges("logging", []string{"logging"}, reloadLogging)
+	initConfigReload()
+
+	// serve until SIGINT/SIGTERM is received, then shut down gracefully
+	setReady(true)
+	if err := waitForShutdown(bootstrappers); err != nil {
+		os.Exit(1)
 	}
+}
+
+// bootstrapApp sets up the api-router and API gateway features, then runs bootstrappers.
+// It is shared by Start and StartEmbedded.
+//
+// @since template-v0.5.0
+func bootstrapApp(bootstrappers []IBootstrapper) {
+	// setup api-router
+	ApiRouter = itineris.NewApiRouter()
+	initApiBatch()
+	initHttpResponse()
+	initEvents()
+	initWebSocket()
+	initOpenApi()
+	initHealth()
 
 	// bootstrapping
 	if bootstrappers != nil {
 		for _, b := range bootstrappers {
-			log.Println("Bootstrapping", b)
+			logging.Infof("Bootstrapping %v", b)
 			err := b.Bootstrap()
 			if err != nil {
-				log.Println(err)
+				logging.Errorf("Bootstrapping %v: %s", b, err)
 			}
 		}
 	}
+}
 
-	// initialize and start gRPC server
-	initGrpcServer()
+// initApp loads and validates application configurations, sets up the application logger and "Location".
+// It is shared by Start and commands (see RunCommand), which need configurations but not the API gateways.
+//
+// @since template-v0.5.0
+func initApp(bootstrappers []IBootstrapper) {
+	// load application configurations
+	setAppConfig(initAppConfig())
+	conf := AppConfig()
+	httpHeaderAppId = conf.GetString("api.http.header_app_id")
+	httpHeaderAccessToken = conf.GetString("api.http.header_access_token")
+	AppVersion = conf.GetString("app.version")
+	AppVersionNumber = utils.VersionToNumber(AppVersion)
 
-	// initialize and start echo server
-	initEchoServer()
+	// setup application logger
+	initLogging()
+
+	// validate configurations before any bootstrapping
+	initConfigSchema(bootstrappers)
+
+	// initialize "Location"
+	var err error
+	utils.Location, err = time.LoadLocation(AppConfig().GetString("timezone"))
+	if err != nil {
+		panic(err)
+	}
 }
 
 func initAppConfig() *hocon.Config {
 	configFile := os.Getenv("APP_CONFIG")
 	if configFile == "" {
-		log.Printf("No environment APP_CONFIG found, fallback to [%s]", defaultConfigFile)
+		logging.Warnf("No environment APP_CONFIG found, fallback to [%s]", defaultConfigFile)
 		configFile = defaultConfigFile
 	}
+	appConfigFile = configFile
 	return loadAppConfig(configFile)
 }
 
+// initLogging configures the application-wide logger from config keys "logging.*".
+//
+// The default logger is re-configured in place so that loggers derived from it earlier pick up the new settings.
+//
+// @since template-v0.5.0
+func initLogging() {
+	logger := logging.Default()
+	if level, err := logging.ParseLevel(AppConfig().GetString("logging.level", "info")); err != nil {
+		logging.Warnf("Invalid [logging.level]: %s", err)
+	} else {
+		logger.SetLevel(level)
+	}
+	if formatter, err := logging.NewFormatter(AppConfig().GetString("logging.format", "console")); err != nil {
+		logging.Warnf("Invalid [logging.format]: %s", err)
+	} else {
+		logger.SetFormatter(formatter)
+	}
+	switch strings.ToLower(AppConfig().GetString("logging.output", "stderr")) {
+	case "stdout":
+		logger.SetWriter(os.Stdout)
+	case "stderr", "":
+		logger.SetWriter(os.Stderr)
+	default:
+		logging.Warnf("Invalid [logging.output]: %s, fallback to stderr", AppConfig().GetString("logging.output"))
+		logger.SetWriter(os.Stderr)
+	}
+}
+
+// reloadLogging validates and applies the reloaded "logging.*" settings.
+//
+// @since template-v0.5.0
+func reloadLogging(conf *hocon.Config) error {
+	if _, err := logging.ParseLevel(conf.GetString("logging.level", "info")); err != nil {
+		return fmt.Errorf("invalid [logging.level]: %s", err)
+	}
+	if _, err := logging.NewFormatter(conf.GetString("logging.format", "console")); err != nil {
+		return fmt.Errorf("invalid [logging.format]: %s", err)
+	}
+	switch strings.ToLower(conf.GetString("logging.output", "stderr")) {
+	case "stdout", "stderr", "":
+	default:
+		return fmt.Errorf("invalid [logging.output]: %s", conf.GetString("logging.output"))
+	}
+	initLogging()
+	return nil
+}
+
 // @since template-v0.4.r2
 func initGrpcServer() {
-	listenPort := AppConfig.GetInt32("api.grpc.listen_port", 0)
+	listenPort := AppConfig().GetInt32("api.grpc.listen_port", 0)
 	if listenPort <= 0 {
-		log.Println("No valid [api.grpc.listen_port] configured, gRPC API gateway is disabled.")
+		logging.Warnf("No valid [api.grpc.listen_port] configured, gRPC API gateway is disabled.")
 		return
 	}
-	listenAddr := AppConfig.GetString("api.grpc.listen_addr", "127.0.0.1")
+	listenAddr := AppConfig().GetString("api.grpc.listen_addr", "127.0.0.1")
 	lis, err := net.Listen("tcp", fmt.Sprintf("%s:%d", listenAddr, listenPort))
 	if err != nil {
-		log.Printf("Failed to listen gRPC: %v", err)
+		logging.Errorf("Failed to listen gRPC: %s", err)
 		return
 	}
+	server, err := newGrpcServer()
+	if err != nil {
+		logging.Errorf("Failed to setup TLS for gRPC: %s", err)
+		return
+	}
+	logging.Infof("Starting [%s] gRPC server on [%s:%d]...", AppConfig().GetString("app.name")+" v"+AppConfig().GetString("app.version"), listenAddr, listenPort)
+	startGrpcServer(server, lis)
+}
+
+// newGrpcServer creates the gRPC server with the API gateway and health services registered.
+//
+// @since template-v0.5.0
+func newGrpcServer() (*grpc.Server, error) {
 	var opts []grpc.ServerOption
-	grpcServer := grpc.NewServer(opts...)
-	pb.RegisterPApiServiceServer(grpcServer, newGrpcGateway())
-	log.Printf("Starting [%s] gRPC server on [%s:%d]...\n", AppConfig.GetString("app.name")+" v"+AppConfig.GetString("app.version"), listenAddr, listenPort)
-	go grpcServer.Serve(lis)
+	if AppConfig().GetBoolean("api.grpc.tls.enabled", false) {
+		tlsConfig, err := buildGrpcTlsConfig()
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	server := grpc.NewServer(opts...)
+	pb.RegisterPApiServiceServer(server, newGrpcGateway())
+	registerGrpcHealthServer(server)
+	return server, nil
+}
+
+// startGrpcServer serves gRPC requests on lis in background; failing to serve causes the application to shut down.
+func startGrpcServer(server *grpc.Server, lis net.Listener) {
+	grpcServer = server
+	go func() {
+		if err := server.Serve(lis); err != nil && err != grpc.ErrServerStopped {
+			RequestShutdown(fmt.Errorf("gRPC server error: %s", err))
+		}
+	}()
+}
+
+// buildGrpcTlsConfig builds TLS config for gRPC server from config keys "api.grpc.tls.*".
+//
+// @since template-v0.5.0
+func buildGrpcTlsConfig() (*tls.Config, error) {
+	certFile := AppConfig().GetString("api.grpc.tls.cert_file", "")
+	keyFile := AppConfig().GetString("api.grpc.tls.key_file", "")
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load key pair [%s]/[%s]: %s", certFile, keyFile, err)
+	}
+	tlsConfig := &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+	clientAuth := strings.ToLower(AppConfig().GetString("api.grpc.tls.client_auth", "none"))
+	switch clientAuth {
+	case "none", "":
+		tlsConfig.ClientAuth = tls.NoClientCert
+	case "request":
+		tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	case "require":
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	default:
+		return nil, fmt.Errorf("invalid [api.grpc.tls.client_auth]: %s", clientAuth)
+	}
+	if tlsConfig.ClientAuth != tls.NoClientCert {
+		caFile := AppConfig().GetString("api.grpc.tls.client_ca_file", "")
+		caPem, err := ioutil.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read client CA file [%s]: %s", caFile, err)
+		}
+		tlsConfig.ClientCAs = x509.NewCertPool()
+		if !tlsConfig.ClientCAs.AppendCertsFromPEM(caPem) {
+			return nil, fmt.Errorf("no valid certificate found in client CA file [%s]", caFile)
+		}
+	}
+	return tlsConfig, nil
 }
 
 func initEchoServer() {
-	listenPort := AppConfig.GetInt32("api.http.listen_port", 0)
+	listenPort := AppConfig().GetInt32("api.http.listen_port", 0)
 	if listenPort <= 0 {
-		log.Println("No valid [api.http.listen_port] configured, REST API gateway is disabled.")
+		logging.Warnf("No valid [api.http.listen_port] configured, REST API gateway is disabled.")
 		return
 	}
-	listenAddr := AppConfig.GetString("api.http.listen_addr", "127.0.0.1")
-	e := echo.New()
-	requestTimeout := AppConfig.GetTimeDuration("api.request_timeout", time.Duration(0))
+	listenAddr := AppConfig().GetString("api.http.listen_addr", "127.0.0.1")
+	server := &http.Server{
+		Addr:    fmt.Sprintf("%s:%d", listenAddr, listenPort),
+		Handler: initHttpHandler(),
+	}
+	requestTimeout := AppConfig().GetTimeDuration("api.request_timeout", time.Duration(0))
 	if requestTimeout > 0 {
-		e.Server.ReadTimeout = requestTimeout
+		server.ReadTimeout = requestTimeout
 	}
-	bodyLimit := AppConfig.GetByteSize("api.max_request_size")
+	logging.Infof("Starting [%s] RESTful server on [%s:%d]...", AppConfig().GetString("app.name")+" v"+AppConfig().GetString("app.version"), listenAddr, listenPort)
+	startHttpServer(server)
+}
+
+// initHttpHandler builds the echo instance from the current configurations and returns the handler that serves HTTP requests.
+//
+// @since template-v0.5.0
+func initHttpHandler() http.Handler {
+	e, routingMap := buildEchoHandler()
+	setEchoHandler(e, routingMap)
+	SubscribeConfigChanges("http", []string{"api.http.allow_origins", "api.http.endpoints", "api.max_request_size"}, reloadEchoHandler)
+	// requests are dispatched to the current echo instance, which is replaced when configurations are reloaded
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		currentEchoHandler().ServeHTTP(w, r)
+	})
+}
+
+// buildEchoHandler creates the echo instance that serves HTTP requests (middlewares, frontend files and API endpoints) from the current
+// configurations, together with the mapping of API endpoints.
+//
+// @since template-v0.5.0
+func buildEchoHandler() (*echo.Echo, map[string]map[string]string) {
+	e := echo.New()
+	bodyLimit := AppConfig().GetByteSize("api.max_request_size")
 	if bodyLimit != nil && bodyLimit.Int64() > 0 {
 		e.Use(middleware.BodyLimit(bodyLimit.String()))
 	}
-	allowOgirinsStr := AppConfig.GetString("api.http.allow_origins", "*")
+	allowOgirinsStr := AppConfig().GetString("api.http.allow_origins", "*")
 	if allowOgirins := regexp.MustCompile("[,;\\s]+").Split(allowOgirinsStr, -1); len(allowOgirins) > 0 {
 		e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
 			AllowOrigins: allowOgirins,
@@ -151,24 +351,57 @@ func initEchoServer() {
 	})
 
 	// register API http endpoints
+	routingMap := map[string]map[string]string{}
 	hasEndpoints := false
-	confV := AppConfig.GetValue("api.http.endpoints")
+	confV := AppConfig().GetValue("api.http.endpoints")
 	if confV != nil && confV.IsObject() {
 		for uri, uriO := range confV.GetObject().Items() {
 			if uriO.IsObject() && !uriO.IsEmpty() {
 				hasEndpoints = true
 				e.Any(uri, apiHttpHandler)
 				for httpMethod, apiName := range uriO.GetObject().Items() {
-					registerHttpHandler(uri, httpMethod, apiName.GetString())
+					addHttpRoute(routingMap, uri, httpMethod, apiName.GetString())
 				}
 			}
 		}
 	}
-	js, _ := json.Marshal(httpRoutingMap)
-	log.Println("API http endpoints: " + string(js))
+	if batchUri := AppConfig().GetString("api.batch.uri", "/api/batch"); batchEnabled && batchUri != "" {
+		hasEndpoints = true
+		e.POST(batchUri, apiBatchHttpHandler)
+		logging.Infof("API batch endpoint: %s", batchUri)
+	}
+	if sseUri := AppConfig().GetString("api.events.sse_uri", "/api/events"); eventsEnabled && sseUri != "" {
+		e.GET(sseUri, apiEventsSseHandler)
+		logging.Infof("API events (SSE) endpoint: %s", sseUri)
+	}
+	if wsUri := AppConfig().GetString("api.websocket.uri", "/api/ws"); wsEnabled && wsUri != "" {
+		hasEndpoints = true
+		e.GET(wsUri, apiWebSocketHandler)
+		logging.Infof("API WebSocket endpoint: %s", wsUri)
+	}
+	if healthEnabled {
+		if healthLivenessUri != "" {
+			e.GET(healthLivenessUri, apiLivenessHandler)
+			logging.Infof("Liveness endpoint: %s", healthLivenessUri)
+		}
+		if healthReadinessUri != "" {
+			e.GET(healthReadinessUri, apiReadinessHandler)
+			e.GET(strings.TrimSuffix(healthReadinessUri, "/")+"/:check", apiReadinessHandler)
+			logging.Infof("Readiness endpoint: %s", healthReadinessUri)
+		}
+	}
+	if openApiEnabled && openApiUri != "" {
+		e.GET(openApiUri, apiOpenApiSpecHandler)
+		logging.Infof("OpenAPI spec endpoint: %s", openApiUri)
+		if openApiDocsUri != "" {
+			e.GET(openApiDocsUri, apiOpenApiDocsHandler)
+			logging.Infof("API docs endpoint: %s", openApiDocsUri)
+		}
+	}
+	js, _ := json.Marshal(routingMap)
+	logging.Infof("API http endpoints: %s", js)
 	if !hasEndpoints {
-		log.Println("No valid HTTP endpoints defined at key [api.http.endpoints].")
+		logging.Warnf("No valid HTTP endpoints defined at key [api.http.endpoints].")
 	}
-	log.Printf("Starting [%s] RESTful server on [%s:%d]...\n", AppConfig.GetString("app.name")+" v"+AppConfig.GetString("app.version"), listenAddr, listenPort)
-	go e.Logger.Fatal(e.Start(fmt.Sprintf("%s:%d", listenAddr, listenPort)))
+	return e, routingMap
 }
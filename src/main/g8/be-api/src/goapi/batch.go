@@ -0,0 +1,114 @@
+package goapi
+
+import (
+	"fmt"
+
+	"github.com/btnguyen2k/consu/reddo"
+	"github.com/labstack/echo/v4"
+	"main/src/itineris"
+)
+
+const (
+	// batchApiName is name of the API context of a batch call, each call in the batch has its own context.
+	batchApiName = "_batch"
+)
+
+var (
+	batchEnabled        = true
+	batchMaxItems       = 20
+	batchMaxConcurrency = 4
+)
+
+// initApiBatch loads batch settings from config keys "api.batch.*".
+//
+// @since template-v0.5.0
+func initApiBatch() {
+	batchEnabled = AppConfig().GetBoolean("api.batch.enabled", true)
+	batchMaxItems = int(AppConfig().GetInt32("api.batch.max_items", 20))
+	batchMaxConcurrency = int(AppConfig().GetInt32("api.batch.max_concurrency", 4))
+}
+
+// buildBatchOptions builds batch options requested by client, capped by server settings.
+func buildBatchOptions(parallel bool, maxConcurrency int) itineris.BatchOptions {
+	if maxConcurrency <= 0 || (batchMaxConcurrency > 0 && maxConcurrency > batchMaxConcurrency) {
+		maxConcurrency = batchMaxConcurrency
+	}
+	return itineris.BatchOptions{Parallel: parallel, MaxConcurrency: maxConcurrency}
+}
+
+// checkBatchRequest verifies if a batch request of numItems items can be served, nil is returned if ok.
+func checkBatchRequest(numItems int) *itineris.ApiResult {
+	if !batchEnabled {
+		return itineris.NewApiResult(itineris.StatusNotImplemented).SetMessage("Batch API call is disabled.")
+	}
+	if numItems == 0 {
+		return itineris.NewApiResult(itineris.StatusErrorClient).SetMessage("Batch is empty.")
+	}
+	if batchMaxItems > 0 && numItems > batchMaxItems {
+		return itineris.NewApiResult(itineris.StatusErrorClient).SetMessage(fmt.Sprintf("Batch has %d items, exceeding the limit of %d.", numItems, batchMaxItems))
+	}
+	return nil
+}
+
+// parseBatchItems parses the "items" param of a batch request sent via HTTP gateway.
+//
+// Each item is a map {"id": optional id, "apiName": name of the API to call, "params": optional map of API params}.
+func parseBatchItems(items interface{}) ([]*itineris.BatchCall, error) {
+	list, ok := items.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("parameter [items] must be a list")
+	}
+	calls := make([]*itineris.BatchCall, len(list))
+	for i, item := range list {
+		itemMap, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("item #%d must be a map", i)
+		}
+		apiName, _ := reddo.ToString(itemMap["apiName"])
+		if apiName == "" {
+			return nil, fmt.Errorf("item #%d: missing [apiName]", i)
+		}
+		params := itineris.NewApiParams()
+		if itemMap["params"] != nil {
+			paramsMap, ok := itemMap["params"].(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("item #%d: [params] must be a map", i)
+			}
+			for k, v := range paramsMap {
+				params.SetParam(k, v)
+			}
+		}
+		id := ""
+		if itemMap["id"] != nil {
+			id = fmt.Sprintf("%v", itemMap["id"])
+		}
+		calls[i] = &itineris.BatchCall{Id: id, ApiName: apiName, Params: params}
+	}
+	return calls, nil
+}
+
+// apiBatchHttpHandler handles batch API calls via HTTP.
+//
+// Request body: {"items": [{"id": ..., "apiName": ..., "params": {...}}, ...], "parallel": false, "maxConcurrency": 0}
+//
+// Result's data is the list of results in the same order as request's items, each one has fields "id", "apiName", "status", "message", "data", etc.
+//
+// @since template-v0.5.0
+func apiBatchHttpHandler(c echo.Context) error {
+	ctx, auth, params := _parseRequest(batchApiName, c)
+	calls, err := parseBatchItems(params.GetParam("items"))
+	if err != nil {
+		return writeApiResult(c, ctx, itineris.NewApiResult(itineris.StatusErrorClient).SetMessage(err.Error()))
+	}
+	if result := checkBatchRequest(len(calls)); result != nil {
+		return writeApiResult(c, ctx, result)
+	}
+	parallel, _ := reddo.ToBool(params.GetParam("parallel"))
+	maxConcurrency, _ := reddo.ToInt(params.GetParam("maxConcurrency"))
+	results := ApiRouter.CallBatch(ctx, auth, calls, buildBatchOptions(parallel, int(maxConcurrency)))
+	data := make([]map[string]interface{}, len(results))
+	for i, r := range results {
+		data[i] = r.ToMap()
+	}
+	return writeApiResult(c, ctx, itineris.NewApiResult(itineris.StatusOk).SetData(data))
+}
@@ -0,0 +1,134 @@
+package goapi
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Command is an administrative task run from the command line (e.g. "user create"), instead of starting the application.
+//
+// Commands have access to application configurations via goapi.AppConfig(), but neither bootstrappers are run nor
+// HTTP/gRPC gateways are started.
+//
+// @since template-v0.5.0
+type Command struct {
+	Name        string                    // name of the command, e.g. "user"
+	Usage       string                    // one line per sub-command, e.g. "user create <username> [--admin]"
+	Description string                    // short description of the command
+	Run         func(args []string) error // args are the command line arguments following the command's name
+}
+
+// ICommandProvider is implemented by bootstrappers that provide commands (see RunCommand).
+//
+// @since template-v0.5.0
+type ICommandProvider interface {
+	Commands() []*Command
+}
+
+// ErrCommandUsage is returned by Command.Run when arguments are invalid; the command's usage is printed.
+//
+// @since template-v0.5.0
+var ErrCommandUsage = errors.New("invalid arguments")
+
+// builtinCommands returns the commands provided by goapi.
+func builtinCommands(bootstrappers []IBootstrapper) []*Command {
+	return []*Command{
+		{
+			Name:        "serve",
+			Usage:       "serve",
+			Description: "start the application (default when no command is given)",
+		},
+		{
+			Name:        "config",
+			Usage:       "config check",
+			Description: "validate configurations (see env APP_CONFIG) and exit",
+			Run: func(args []string) error {
+				if len(args) != 1 || args[0] != "check" {
+					return ErrCommandUsage
+				}
+				if CheckConfig(bootstrappers...) != 0 {
+					return errors.New("configurations are invalid")
+				}
+				return nil
+			},
+		},
+	}
+}
+
+// collectCommands returns built-in commands plus commands of bootstrappers that implement ICommandProvider, mapped by name.
+func collectCommands(bootstrappers []IBootstrapper) map[string]*Command {
+	commands := make(map[string]*Command)
+	for _, cmd := range builtinCommands(bootstrappers) {
+		commands[cmd.Name] = cmd
+	}
+	for _, b := range bootstrappers {
+		if provider, ok := b.(ICommandProvider); ok {
+			for _, cmd := range provider.Commands() {
+				commands[cmd.Name] = cmd
+			}
+		}
+	}
+	return commands
+}
+
+func printCommandsUsage(w io.Writer, commands map[string]*Command) {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Fprintf(w, "Usage: %s [command] [arguments]\n\nCommands:\n", os.Args[0])
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-10s %s\n", name, commands[name].Description)
+		for _, usage := range strings.Split(commands[name].Usage, "\n") {
+			fmt.Fprintf(w, "      %s\n", usage)
+		}
+	}
+}
+
+// RunCommand runs the command specified by args (the command line arguments, without the program's name):
+//   - no argument or "serve": starts the application (see Start)
+//   - "help": prints usage of available commands
+//   - other: loads and validates configurations, then runs the command (see ICommandProvider)
+//
+// It returns the process's exit code: 0 on success, 1 if the command failed, 2 if the command or its arguments are invalid.
+//
+// @since template-v0.5.0
+func RunCommand(args []string, bootstrappers ...IBootstrapper) int {
+	commands := collectCommands(bootstrappers)
+	if len(args) == 0 || args[0] == "serve" {
+		Start(bootstrappers...)
+		return 0
+	}
+	switch args[0] {
+	case "help", "-h", "-help", "--help":
+		printCommandsUsage(os.Stdout, commands)
+		return 0
+	}
+	cmd := commands[args[0]]
+	if cmd == nil {
+		fmt.Fprintf(os.Stderr, "Unknown command [%s]\n\n", args[0])
+		printCommandsUsage(os.Stderr, commands)
+		return 2
+	}
+	if cmd.Name != "config" {
+		// "config check" loads configurations itself, so that problems are reported instead of causing exit
+		initApp(bootstrappers)
+	}
+	if err := cmd.Run(args[1:]); err != nil {
+		if err == ErrCommandUsage {
+			fmt.Fprintf(os.Stderr, "Usage:\n")
+			for _, usage := range strings.Split(cmd.Usage, "\n") {
+				fmt.Fprintf(os.Stderr, "  %s %s\n", os.Args[0], usage)
+			}
+			return 2
+		}
+		fmt.Fprintf(os.Stderr, "Command [%s] failed: %s\n", cmd.Name, err)
+		return 1
+	}
+	return 0
+}
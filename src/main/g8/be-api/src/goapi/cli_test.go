@@ -0,0 +1,47 @@
+package goapi
+
+import (
+	"testing"
+)
+
+type _cmdBootstrapper struct {
+	calls *[]string
+}
+
+func (b *_cmdBootstrapper) Bootstrap() error {
+	return nil
+}
+
+func (b *_cmdBootstrapper) Commands() []*Command {
+	return []*Command{{Name: "greet", Usage: "greet <name>", Run: func(args []string) error {
+		*b.calls = append(*b.calls, args...)
+		return nil
+	}}}
+}
+
+func TestCollectCommands(t *testing.T) {
+	name := "TestCollectCommands"
+	calls := make([]string, 0)
+	commands := collectCommands([]IBootstrapper{&_cmdBootstrapper{calls: &calls}})
+	for _, cmd := range []string{"serve", "config", "greet"} {
+		if commands[cmd] == nil {
+			t.Fatalf("%s failed: command [%s] not found", name, cmd)
+		}
+	}
+	if err := commands["greet"].Run([]string{"world"}); err != nil || len(calls) != 1 || calls[0] != "world" {
+		t.Fatalf("%s failed: expected %#v but received %#v / %s", name, []string{"world"}, calls, err)
+	}
+}
+
+func TestRunCommand_Invalid(t *testing.T) {
+	name := "TestRunCommand_Invalid"
+	if code := RunCommand([]string{"no-such-command"}); code != 2 {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, 2, code)
+	}
+	if code := RunCommand([]string{"config", "no-such-subcommand"}); code != 2 {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, 2, code)
+	}
+	if code := RunCommand([]string{"help"}); code != 0 {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, 0, code)
+	}
+}
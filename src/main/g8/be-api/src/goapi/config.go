@@ -2,13 +2,14 @@ package goapi
 
 import (
 	"io/ioutil"
-	"log"
 	"os"
 	"path"
 	"path/filepath"
 
 	hoconf "github.com/go-akka/configuration"
 	"github.com/go-akka/configuration/hocon"
+
+	"main/src/logging"
 )
 
 func loadAppConfig(file string) *hoconf.Config {
@@ -19,7 +20,7 @@ func loadAppConfig(file string) *hoconf.Config {
 		defer os.Chdir(curDir)
 	}
 
-	log.Printf("Loading configurations from file [%s]", file)
+	logging.Infof("Loading configurations from file [%s]", file)
 	confDir, confFile := path.Split(file)
 	os.Chdir(confDir)
 
@@ -34,12 +35,12 @@ func myIncludeCallback(filename string) *hocon.HoconRoot {
 	if files, err := filepath.Glob(filename); err != nil {
 		panic(err)
 	} else if len(files) == 0 {
-		log.Printf("[WARN] [%s] does not match any file", filename)
+		logging.Warnf("[%s] does not match any file", filename)
 		return hocon.Parse("", nil)
 	} else {
 		var root = hocon.Parse("", nil)
 		for _, f := range files {
-			log.Printf("Loading configurations from file [%s]", f)
+			logging.Infof("Loading configurations from file [%s]", f)
 			if data, err := ioutil.ReadFile(f); err != nil {
 				panic(err)
 			} else {
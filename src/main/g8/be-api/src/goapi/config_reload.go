@@ -0,0 +1,246 @@
+package goapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	hocon "github.com/go-akka/configuration"
+	"main/src/logging"
+)
+
+// ConfigChangeHandler applies reloaded configurations to a subsystem (e.g. rebuilds i18n bundles, re-configures the logger).
+//
+// Returning an error aborts the reload: AppConfig is restored and subsystems that have already applied the new configurations
+// are called again with the previous ones.
+//
+// @since template-v0.5.0
+type ConfigChangeHandler func(conf *hocon.Config) error
+
+// ConfigValidator validates reloaded configurations before they are applied. Returning an error rejects the reload.
+//
+// @since template-v0.5.0
+type ConfigValidator func(conf *hocon.Config) error
+
+type configSubscriber struct {
+	name    string
+	paths   []string
+	handler ConfigChangeHandler
+}
+
+type namedConfigValidator struct {
+	name      string
+	validator ConfigValidator
+}
+
+var (
+	appConfigFile     string
+	configReloadLock  sync.Mutex
+	configSubscribers []configSubscriber
+	configValidators  []namedConfigValidator
+)
+
+// SubscribeConfigChanges registers a handler to be called when configurations are reloaded and the value at any of the
+// specified paths (e.g. "api.http.allow_origins") has changed. If paths is empty, the handler is called on every reload
+// (e.g. for subsystems whose settings live in files not tracked by AppConfig).
+//
+// Handlers are called in order of subscription.
+//
+// @since template-v0.5.0
+func SubscribeConfigChanges(name string, paths []string, handler ConfigChangeHandler) {
+	configReloadLock.Lock()
+	defer configReloadLock.Unlock()
+	configSubscribers = append(configSubscribers, configSubscriber{name: name, paths: paths, handler: handler})
+}
+
+// RegisterConfigValidator registers a function to validate configurations before they are applied.
+//
+// @since template-v0.5.0
+func RegisterConfigValidator(name string, validator ConfigValidator) {
+	configReloadLock.Lock()
+	defer configReloadLock.Unlock()
+	configValidators = append(configValidators, namedConfigValidator{name: name, validator: validator})
+}
+
+// parseAppConfig loads configurations from file, returning parse errors instead of panicking.
+//
+// The hocon parser is lenient, some malformed input is only detected when the parsed tree is rendered, hence the whole tree is rendered once here.
+func parseAppConfig(file string) (conf *hocon.Config, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			conf, err = nil, fmt.Errorf("cannot parse configurations from [%s]: %v", file, r)
+		}
+	}()
+	conf = loadAppConfig(file)
+	if conf == nil || conf.IsEmpty() {
+		return nil, fmt.Errorf("no configurations loaded from [%s]", file)
+	}
+	_ = conf.Root().String()
+	return conf, nil
+}
+
+func configValueString(conf *hocon.Config, path string) string {
+	if conf == nil {
+		return ""
+	}
+	v := conf.GetValue(path)
+	if v == nil {
+		return ""
+	}
+	return v.String()
+}
+
+// configChanged checks if the value at any of the paths differs between old and new configurations.
+func configChanged(oldConf, newConf *hocon.Config, paths []string) bool {
+	if len(paths) == 0 {
+		return true
+	}
+	for _, p := range paths {
+		if configValueString(oldConf, p) != configValueString(newConf, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func callConfigChangeHandler(sub configSubscriber, conf *hocon.Config) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return sub.handler(conf)
+}
+
+func validateAppConfig(conf *hocon.Config, validators []namedConfigValidator) error {
+	messages := make([]string, 0)
+	for _, v := range validators {
+		if err := v.validator(conf); err != nil {
+			messages = append(messages, fmt.Sprintf("[%s] %s", v.name, err))
+		}
+	}
+	if len(messages) > 0 {
+		return errors.New(strings.Join(messages, "; "))
+	}
+	return nil
+}
+
+// ReloadAppConfig re-reads configurations from the application's config file and applies them to subscribed subsystems
+// (see SubscribeConfigChanges). trigger describes what initiated the reload (e.g. "file", "sighup", "api") and is logged.
+//
+// Configurations that fail to parse or validate are rejected and the current ones remain in effect.
+// If a subsystem fails to apply the new configurations, the reload is rolled back.
+//
+// @since template-v0.5.0
+func ReloadAppConfig(trigger string) error {
+	configReloadLock.Lock()
+	defer configReloadLock.Unlock()
+
+	logging.Infof("Reloading configurations (trigger: %s)...", trigger)
+	newConf, err := parseAppConfig(appConfigFile)
+	if err != nil {
+		logging.Errorf("Configurations reload rejected: %s", err)
+		return err
+	}
+	if err := validateAppConfig(newConf, configValidators); err != nil {
+		logging.Errorf("Configurations reload rejected: %s", err)
+		return fmt.Errorf("invalid configurations: %s", err)
+	}
+
+	oldConf := AppConfig()
+	setAppConfig(newConf)
+	applied := make([]configSubscriber, 0, len(configSubscribers))
+	for _, sub := range configSubscribers {
+		if !configChanged(oldConf, newConf, sub.paths) {
+			continue
+		}
+		applied = append(applied, sub)
+		if err := callConfigChangeHandler(sub, newConf); err != nil {
+			logging.Errorf("Configurations reload failed at [%s]: %s, rolling back...", sub.name, err)
+			setAppConfig(oldConf)
+			for i := len(applied) - 1; i >= 0; i-- {
+				if e := callConfigChangeHandler(applied[i], oldConf); e != nil {
+					logging.Errorf("Rolling back configurations of [%s]: %s", applied[i].name, e)
+				}
+			}
+			return fmt.Errorf("cannot apply configurations to [%s]: %s", sub.name, err)
+		}
+	}
+	names := make([]string, len(applied))
+	for i, sub := range applied {
+		names[i] = sub.name
+	}
+	logging.Infof("Configurations reloaded, applied to %v", names)
+	return nil
+}
+
+/*----------------------------------------------------------------------*/
+
+// configFingerprint summarizes modification time and size of config files under dir, so that changes can be detected by polling.
+func configFingerprint(dir string) string {
+	entries := make([]string, 0)
+	filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			entries = append(entries, fmt.Sprintf("%s|%d|%d", p, info.ModTime().UnixNano(), info.Size()))
+		}
+		return nil
+	})
+	sort.Strings(entries)
+	return strings.Join(entries, "\n")
+}
+
+// initConfigReload starts the triggers of configuration reload, settings are loaded from config keys "api.config_reload.*":
+//   - polling the config directory (including i18n bundles) for changes every "watch_interval" (0 disables watching)
+//   - SIGHUP, if "sighup" is true
+//
+// @since template-v0.5.0
+func initConfigReload() {
+	if appConfigFile == "" {
+		return
+	}
+	if interval := AppConfig().GetTimeDuration("api.config_reload.watch_interval", time.Duration(0)); interval > 0 {
+		confDir, _ := path.Split(appConfigFile)
+		if confDir == "" {
+			confDir = "."
+		}
+		logging.Infof("Watching [%s] for configuration changes every %s", confDir, interval)
+		GoBackground("config-watcher", func(ctx context.Context) {
+			last := configFingerprint(confDir)
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(interval):
+				}
+				if current := configFingerprint(confDir); current != last {
+					last = current
+					ReloadAppConfig("file")
+				}
+			}
+		})
+	}
+	if AppConfig().GetBoolean("api.config_reload.sighup", true) {
+		GoBackground("config-sighup", func(ctx context.Context) {
+			signals := make(chan os.Signal, 1)
+			signal.Notify(signals, syscall.SIGHUP)
+			defer signal.Stop(signals)
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-signals:
+					ReloadAppConfig("sighup")
+				}
+			}
+		})
+	}
+}
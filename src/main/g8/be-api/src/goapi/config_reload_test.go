@@ -0,0 +1,143 @@
+package goapi
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	hocon "github.com/go-akka/configuration"
+)
+
+func _setupConfigReload(t *testing.T, name, content string) func() {
+	dir, err := ioutil.TempDir("", "goapi")
+	if err != nil {
+		t.Fatalf("%s failed: %s", name, err)
+	}
+	oldConfig, oldFile, oldSubscribers, oldValidators := AppConfig(), appConfigFile, configSubscribers, configValidators
+	appConfigFile = filepath.Join(dir, "application.conf")
+	_writeConfigFile(t, name, content)
+	setAppConfig(hocon.ParseString(content))
+	configSubscribers, configValidators = nil, nil
+	return func() {
+		appConfigFile, configSubscribers, configValidators = oldFile, oldSubscribers, oldValidators
+		setAppConfig(oldConfig)
+		os.RemoveAll(dir)
+	}
+}
+
+func _writeConfigFile(t *testing.T, name, content string) {
+	if err := ioutil.WriteFile(appConfigFile, []byte(content), 0644); err != nil {
+		t.Fatalf("%s failed: %s", name, err)
+	}
+}
+
+func TestReloadAppConfig(t *testing.T) {
+	name := "TestReloadAppConfig"
+	defer _setupConfigReload(t, name, `a { b = 1 }
+c = "x"`)()
+
+	calls := make([]string, 0)
+	SubscribeConfigChanges("a", []string{"a"}, func(conf *hocon.Config) error {
+		calls = append(calls, "a="+conf.GetString("a.b"))
+		return nil
+	})
+	SubscribeConfigChanges("c", []string{"c"}, func(conf *hocon.Config) error {
+		calls = append(calls, "c="+conf.GetString("c"))
+		return nil
+	})
+	SubscribeConfigChanges("all", nil, func(conf *hocon.Config) error {
+		calls = append(calls, "all")
+		return nil
+	})
+
+	_writeConfigFile(t, name, `a { b = 2 }
+c = "x"`)
+	if err := ReloadAppConfig("test"); err != nil {
+		t.Fatalf("%s failed: %s", name, err)
+	}
+	if v := AppConfig().GetInt32("a.b"); v != 2 {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, 2, v)
+	}
+	expected := []string{"a=2", "all"}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, expected, calls)
+	}
+}
+
+func TestReloadAppConfig_Rollback(t *testing.T) {
+	name := "TestReloadAppConfig_Rollback"
+	defer _setupConfigReload(t, name, `a = 1
+b = 1`)()
+
+	calls := make([]string, 0)
+	SubscribeConfigChanges("a", []string{"a"}, func(conf *hocon.Config) error {
+		calls = append(calls, "a="+conf.GetString("a"))
+		return nil
+	})
+	SubscribeConfigChanges("b", []string{"b"}, func(conf *hocon.Config) error {
+		calls = append(calls, "b="+conf.GetString("b"))
+		if conf.GetInt32("b") > 1 {
+			return errors.New("b must not exceed 1")
+		}
+		return nil
+	})
+
+	_writeConfigFile(t, name, `a = 2
+b = 2`)
+	if err := ReloadAppConfig("test"); err == nil {
+		t.Fatalf("%s failed: expected error", name)
+	}
+	if v := AppConfig().GetInt32("a"); v != 1 {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, 1, v)
+	}
+	expected := []string{"a=2", "b=2", "b=1", "a=1"}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, expected, calls)
+	}
+}
+
+func TestReloadAppConfig_Rejected(t *testing.T) {
+	name := "TestReloadAppConfig_Rejected"
+	defer _setupConfigReload(t, name, `a = 1`)()
+
+	called := false
+	SubscribeConfigChanges("a", []string{"a"}, func(conf *hocon.Config) error {
+		called = true
+		return nil
+	})
+	RegisterConfigValidator("a", func(conf *hocon.Config) error {
+		if conf.GetInt32("a") > 2 {
+			return errors.New("a must not exceed 2")
+		}
+		return nil
+	})
+
+	// invalid configurations
+	_writeConfigFile(t, name, `a = 3`)
+	if err := ReloadAppConfig("test"); err == nil {
+		t.Fatalf("%s failed: expected error", name)
+	}
+
+	// config file cannot be parsed
+	_writeConfigFile(t, name, `a = ${undefined}`)
+	if err := ReloadAppConfig("test"); err == nil {
+		t.Fatalf("%s failed: expected error", name)
+	}
+	_writeConfigFile(t, name, `a b c }`)
+	if err := ReloadAppConfig("test"); err == nil {
+		t.Fatalf("%s failed: expected error", name)
+	}
+
+	// config file does not exist
+	os.Remove(appConfigFile)
+	if err := ReloadAppConfig("test"); err == nil {
+		t.Fatalf("%s failed: expected error", name)
+	}
+
+	if v := AppConfig().GetInt32("a"); v != 1 || called {
+		t.Fatalf("%s failed: expected %#v but received %#v / %#v", name, 1, v, called)
+	}
+}
@@ -0,0 +1,414 @@
+package goapi
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	hocon "github.com/go-akka/configuration"
+	hoconval "github.com/go-akka/configuration/hocon"
+	"main/src/logging"
+)
+
+// Types of configuration values, see ConfigRule.
+//
+// @since template-v0.5.0
+const (
+	ConfigTypeString   = "string"
+	ConfigTypeInt      = "int"
+	ConfigTypeNumber   = "number"
+	ConfigTypeBool     = "bool"
+	ConfigTypeDuration = "duration"  // e.g. 10s, 1h, 90d
+	ConfigTypeByteSize = "byte_size" // e.g. 64kB, 1MiB
+	ConfigTypeList     = "list"
+	ConfigTypeObject   = "object"
+)
+
+// ConfigRule describes the expected value of a configuration key.
+//
+// @since template-v0.5.0
+type ConfigRule struct {
+	Path       string                        // path of the configuration key, e.g. "api.http.listen_port"
+	Type       string                        // one of ConfigType* constants
+	Required   bool                          // if true, the key must exist (and must not be a blank string)
+	RequiredIf func(conf *hocon.Config) bool // if not nil, the key is required when the function returns true
+	Enum       []string                      // allowed values (case-insensitive)
+	Min, Max   string                        // bounds (inclusive) of the value, in the same format as the value, e.g. "1", "1s", "1kB"
+	Check      func(value string) error      // additional check of the (non-empty) value, e.g. the file must exist
+}
+
+// ConfigProblem describes a configuration key whose value does not satisfy its ConfigRule.
+//
+// @since template-v0.5.0
+type ConfigProblem struct {
+	Path    string
+	Message string
+}
+
+// String implements fmt.Stringer
+func (p *ConfigProblem) String() string {
+	if p.Path == "" {
+		return p.Message
+	}
+	return "[" + p.Path + "] " + p.Message
+}
+
+// IConfigSchemaProvider is implemented by bootstrappers that want their configurations to be validated
+// before any bootstrapping takes place (and before reloaded configurations are applied).
+//
+// @since template-v0.5.0
+type IConfigSchemaProvider interface {
+	ConfigRules() []*ConfigRule
+}
+
+// ConfigSchema is a set of ConfigRules.
+//
+// @since template-v0.5.0
+type ConfigSchema struct {
+	rules []*ConfigRule
+}
+
+// NewConfigSchema creates a new ConfigSchema instance.
+func NewConfigSchema(rules ...*ConfigRule) *ConfigSchema {
+	return &ConfigSchema{rules: append([]*ConfigRule{}, rules...)}
+}
+
+// Add appends rules to the schema.
+func (s *ConfigSchema) Add(rules ...*ConfigRule) *ConfigSchema {
+	s.rules = append(s.rules, rules...)
+	return s
+}
+
+// Validate checks configurations against all rules of the schema, returns all problems found (empty if configurations are valid).
+func (s *ConfigSchema) Validate(conf *hocon.Config) []*ConfigProblem {
+	problems := make([]*ConfigProblem, 0)
+	for _, rule := range s.rules {
+		if msg := rule.validate(conf); msg != "" {
+			problems = append(problems, &ConfigProblem{Path: rule.Path, Message: msg})
+		}
+	}
+	return problems
+}
+
+// parseConfigValue parses a configuration value of the specified type into a value that can be compared: int64, float64, bool,
+// time.Duration or *big.Int; strings are returned as-is.
+func parseConfigValue(typ string, value *hoconval.HoconValue) (result interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			result, err = nil, fmt.Errorf("%v", r)
+		}
+	}()
+	switch typ {
+	case ConfigTypeList:
+		if !value.IsArray() {
+			return nil, errors.New("not a list")
+		}
+		return value, nil
+	case ConfigTypeObject:
+		if !value.IsObject() {
+			return nil, errors.New("not an object")
+		}
+		return value, nil
+	}
+	if value.IsObject() || value.IsArray() {
+		return nil, errors.New("not a scalar value")
+	}
+	str := strings.TrimSpace(value.GetString())
+	switch typ {
+	case ConfigTypeInt:
+		return strconv.ParseInt(str, 10, 64)
+	case ConfigTypeNumber:
+		return strconv.ParseFloat(str, 64)
+	case ConfigTypeBool:
+		switch strings.ToLower(str) {
+		case "true", "yes", "on":
+			return true, nil
+		case "false", "no", "off":
+			return false, nil
+		}
+		return nil, errors.New("not a boolean")
+	case ConfigTypeDuration:
+		return value.GetTimeDuration(false), nil
+	case ConfigTypeByteSize:
+		return value.GetByteSize(), nil
+	}
+	return str, nil
+}
+
+// compareConfigValues compares two values returned by parseConfigValue, returns -1, 0 or 1.
+func compareConfigValues(a, b interface{}) int {
+	switch va := a.(type) {
+	case int64:
+		vb := b.(int64)
+		if va < vb {
+			return -1
+		} else if va > vb {
+			return 1
+		}
+	case float64:
+		vb := b.(float64)
+		if va < vb {
+			return -1
+		} else if va > vb {
+			return 1
+		}
+	case time.Duration:
+		vb := b.(time.Duration)
+		if va < vb {
+			return -1
+		} else if va > vb {
+			return 1
+		}
+	case *big.Int:
+		return va.Cmp(b.(*big.Int))
+	}
+	return 0
+}
+
+// validate checks configurations against the rule, returns the problem's message or empty string if the value is valid.
+func (r *ConfigRule) validate(conf *hocon.Config) string {
+	value := conf.GetValue(r.Path)
+	missing := value == nil || (!value.IsObject() && !value.IsArray() && strings.TrimSpace(value.GetString()) == "")
+	if missing {
+		if r.Required || (r.RequiredIf != nil && r.RequiredIf(conf)) {
+			return "is required"
+		}
+		return ""
+	}
+
+	parsed, err := parseConfigValue(r.Type, value)
+	if err != nil {
+		return fmt.Sprintf("must be of type %s, got %q", r.Type, value.String())
+	}
+	str := strings.TrimSpace(value.GetString())
+	if len(r.Enum) > 0 {
+		found := false
+		for _, v := range r.Enum {
+			if strings.EqualFold(v, str) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Sprintf("must be one of %v, got %q", r.Enum, str)
+		}
+	}
+	for _, bound := range []struct {
+		value, op string
+		sign      int
+	}{{r.Min, "at least", -1}, {r.Max, "at most", 1}} {
+		if bound.value == "" {
+			continue
+		}
+		boundValue, err := parseConfigValue(r.Type, hocon.ParseString("v = "+strconv.Quote(bound.value)).GetValue("v"))
+		if err != nil {
+			return fmt.Sprintf("invalid bound %q in config rule: %s", bound.value, err)
+		}
+		if compareConfigValues(parsed, boundValue) == bound.sign {
+			return fmt.Sprintf("must be %s %s, got %q", bound.op, bound.value, str)
+		}
+	}
+	if r.Check != nil && !value.IsObject() && !value.IsArray() {
+		if err := r.Check(str); err != nil {
+			return err.Error()
+		}
+	}
+	return ""
+}
+
+/*----------------------------------------------------------------------*/
+
+// ConfigCheckFileExists is a ConfigRule.Check that requires the value to be the path of an existing file or directory.
+//
+// @since template-v0.5.0
+func ConfigCheckFileExists(value string) error {
+	if _, err := os.Stat(value); err != nil {
+		return fmt.Errorf("file [%s] cannot be accessed: %s", value, err)
+	}
+	return nil
+}
+
+// ConfigCheckUrl is a ConfigRule.Check that requires the value to be an absolute http(s) URL.
+//
+// @since template-v0.5.0
+func ConfigCheckUrl(value string) error {
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("must be an absolute http(s) URL, got %q", value)
+	}
+	return nil
+}
+
+// ConfigCheckTimezone is a ConfigRule.Check that requires the value to be a valid IANA timezone, e.g. "UTC" or "Asia/Ho_Chi_Minh".
+//
+// @since template-v0.5.0
+func ConfigCheckTimezone(value string) error {
+	if _, err := time.LoadLocation(value); err != nil {
+		return fmt.Errorf("unknown timezone %q", value)
+	}
+	return nil
+}
+
+// configBoolIs returns a ConfigRule.RequiredIf function that checks if the boolean at path equals expected.
+func configBoolIs(path string, expected bool) func(conf *hocon.Config) bool {
+	return func(conf *hocon.Config) (result bool) {
+		defer func() {
+			if r := recover(); r != nil {
+				result = false
+			}
+		}()
+		return conf.GetBoolean(path, !expected) == expected
+	}
+}
+
+// coreConfigRules returns the rules of configurations used by goapi ("app.*", "timezone", "logging.*" and "api.*").
+//
+// @since template-v0.5.0
+func coreConfigRules() []*ConfigRule {
+	grpcTlsEnabled := func(conf *hocon.Config) bool {
+		return conf.GetInt32("api.grpc.listen_port", 0) > 0 && configBoolIs("api.grpc.tls.enabled", true)(conf)
+	}
+	return []*ConfigRule{
+		{Path: "app.name", Type: ConfigTypeString, Required: true},
+		{Path: "app.shortname", Type: ConfigTypeString, Required: true},
+		{Path: "app.version", Type: ConfigTypeString, Required: true},
+		{Path: "app.desc", Type: ConfigTypeString},
+		{Path: "timezone", Type: ConfigTypeString, Required: true, Check: ConfigCheckTimezone},
+
+		{Path: "logging.level", Type: ConfigTypeString, Check: func(value string) error {
+			_, err := logging.ParseLevel(value)
+			return err
+		}},
+		{Path: "logging.format", Type: ConfigTypeString, Check: func(value string) error {
+			_, err := logging.NewFormatter(value)
+			return err
+		}},
+		{Path: "logging.output", Type: ConfigTypeString, Enum: []string{"stderr", "stdout"}},
+		{Path: "logging.request_log", Type: ConfigTypeBool},
+
+		{Path: "api.http.listen_addr", Type: ConfigTypeString},
+		{Path: "api.http.listen_port", Type: ConfigTypeInt, Min: "0", Max: "65535"},
+		{Path: "api.http.header_app_id", Type: ConfigTypeString, Required: true},
+		{Path: "api.http.header_access_token", Type: ConfigTypeString, Required: true},
+		{Path: "api.http.allow_origins", Type: ConfigTypeString},
+		{Path: "api.http.endpoints", Type: ConfigTypeObject},
+		{Path: "api.http.response.mode", Type: ConfigTypeString, Enum: []string{"legacy", "status", "problem"}},
+		{Path: "api.http.response.allow_client_choice", Type: ConfigTypeBool},
+		{Path: "api.http.response.status_map", Type: ConfigTypeObject},
+
+		{Path: "api.grpc.listen_addr", Type: ConfigTypeString},
+		{Path: "api.grpc.listen_port", Type: ConfigTypeInt, Min: "0", Max: "65535"},
+		{Path: "api.grpc.tls.enabled", Type: ConfigTypeBool},
+		{Path: "api.grpc.tls.cert_file", Type: ConfigTypeString, RequiredIf: grpcTlsEnabled, Check: ConfigCheckFileExists},
+		{Path: "api.grpc.tls.key_file", Type: ConfigTypeString, RequiredIf: grpcTlsEnabled, Check: ConfigCheckFileExists},
+		{Path: "api.grpc.tls.client_auth", Type: ConfigTypeString, Enum: []string{"none", "request", "require"}},
+		{Path: "api.grpc.tls.client_ca_file", Type: ConfigTypeString, Check: ConfigCheckFileExists, RequiredIf: func(conf *hocon.Config) bool {
+			clientAuth := strings.ToLower(conf.GetString("api.grpc.tls.client_auth", "none"))
+			return grpcTlsEnabled(conf) && clientAuth != "none" && clientAuth != ""
+		}},
+
+		{Path: "api.batch.enabled", Type: ConfigTypeBool},
+		{Path: "api.batch.uri", Type: ConfigTypeString},
+		{Path: "api.batch.max_items", Type: ConfigTypeInt, Min: "1"},
+		{Path: "api.batch.max_concurrency", Type: ConfigTypeInt, Min: "1"},
+
+		{Path: "api.events.enabled", Type: ConfigTypeBool},
+		{Path: "api.events.sse_uri", Type: ConfigTypeString},
+		{Path: "api.events.buffer_size", Type: ConfigTypeInt, Min: "1"},
+		{Path: "api.events.heartbeat", Type: ConfigTypeDuration, Min: "1s"},
+
+		{Path: "api.websocket.enabled", Type: ConfigTypeBool},
+		{Path: "api.websocket.uri", Type: ConfigTypeString},
+		{Path: "api.websocket.max_message_size", Type: ConfigTypeByteSize},
+		{Path: "api.websocket.max_inflight", Type: ConfigTypeInt, Min: "1"},
+
+		{Path: "api.openapi.enabled", Type: ConfigTypeBool},
+		{Path: "api.openapi.uri", Type: ConfigTypeString},
+		{Path: "api.openapi.docs_uri", Type: ConfigTypeString},
+
+		{Path: "api.max_request_size", Type: ConfigTypeByteSize},
+		{Path: "api.request_timeout", Type: ConfigTypeDuration},
+
+		{Path: "api.health.enabled", Type: ConfigTypeBool},
+		{Path: "api.health.liveness_uri", Type: ConfigTypeString},
+		{Path: "api.health.readiness_uri", Type: ConfigTypeString},
+		{Path: "api.health.timeout", Type: ConfigTypeDuration, Min: "1ms"},
+		{Path: "api.health.watch_interval", Type: ConfigTypeDuration},
+
+		{Path: "api.shutdown.delay", Type: ConfigTypeDuration},
+		{Path: "api.shutdown.timeout", Type: ConfigTypeDuration, Min: "1s"},
+
+		{Path: "api.config_reload.watch_interval", Type: ConfigTypeDuration},
+		{Path: "api.config_reload.sighup", Type: ConfigTypeBool},
+	}
+}
+
+// buildConfigSchema builds the schema of the application's configurations: core rules plus rules of bootstrappers that implement IConfigSchemaProvider.
+func buildConfigSchema(bootstrappers []IBootstrapper) *ConfigSchema {
+	schema := NewConfigSchema(coreConfigRules()...)
+	for _, b := range bootstrappers {
+		if provider, ok := b.(IConfigSchemaProvider); ok {
+			schema.Add(provider.ConfigRules()...)
+		}
+	}
+	return schema
+}
+
+// formatConfigProblems renders problems as a human-readable report.
+func formatConfigProblems(file string, problems []*ConfigProblem) string {
+	lines := []string{fmt.Sprintf("Found %d problem(s) in configurations loaded from [%s]:", len(problems), file)}
+	for _, p := range problems {
+		lines = append(lines, "  - "+p.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// initConfigSchema validates the application's configurations before bootstrapping: all problems are logged at once and
+// the application exits. The schema is also registered as a validator of reloaded configurations (see ReloadAppConfig).
+//
+// @since template-v0.5.0
+func initConfigSchema(bootstrappers []IBootstrapper) {
+	schema := buildConfigSchema(bootstrappers)
+	if problems := schema.Validate(AppConfig()); len(problems) > 0 {
+		logging.Errorf("%s", formatConfigProblems(appConfigFile, problems))
+		os.Exit(1)
+	}
+	RegisterConfigValidator("schema", func(conf *hocon.Config) error {
+		problems := schema.Validate(conf)
+		if len(problems) == 0 {
+			return nil
+		}
+		messages := make([]string, len(problems))
+		for i, p := range problems {
+			messages[i] = p.String()
+		}
+		return errors.New(strings.Join(messages, "; "))
+	})
+}
+
+// CheckConfig loads configurations (see env APP_CONFIG) and validates them against the schema, without bootstrapping the application.
+// All problems found are printed to stdout. It returns the process's exit code: 0 if configurations are valid, 1 otherwise.
+//
+// @since template-v0.5.0
+func CheckConfig(bootstrappers ...IBootstrapper) int {
+	configFile := os.Getenv("APP_CONFIG")
+	if configFile == "" {
+		configFile = defaultConfigFile
+	}
+	conf, err := parseAppConfig(configFile)
+	if err != nil {
+		fmt.Println(formatConfigProblems(configFile, []*ConfigProblem{{Message: err.Error()}}))
+		return 1
+	}
+	if problems := buildConfigSchema(bootstrappers).Validate(conf); len(problems) > 0 {
+		fmt.Println(formatConfigProblems(configFile, problems))
+		return 1
+	}
+	fmt.Printf("Configurations loaded from [%s] are valid.\n", configFile)
+	return 0
+}
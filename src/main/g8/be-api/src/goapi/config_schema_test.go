@@ -0,0 +1,80 @@
+package goapi
+
+import (
+	"reflect"
+	"testing"
+
+	hocon "github.com/go-akka/configuration"
+)
+
+func TestConfigSchema_Validate(t *testing.T) {
+	name := "TestConfigSchema_Validate"
+	schema := NewConfigSchema(
+		&ConfigRule{Path: "a.name", Type: ConfigTypeString, Required: true},
+		&ConfigRule{Path: "a.port", Type: ConfigTypeInt, Min: "1", Max: "65535"},
+		&ConfigRule{Path: "a.enabled", Type: ConfigTypeBool},
+		&ConfigRule{Path: "a.timeout", Type: ConfigTypeDuration, Min: "1s"},
+		&ConfigRule{Path: "a.size", Type: ConfigTypeByteSize, Max: "1MB"},
+		&ConfigRule{Path: "a.mode", Type: ConfigTypeString, Enum: []string{"fast", "slow"}},
+		&ConfigRule{Path: "a.list", Type: ConfigTypeList},
+		&ConfigRule{Path: "a.url", Type: ConfigTypeString, Check: ConfigCheckUrl},
+		&ConfigRule{Path: "a.key", Type: ConfigTypeString, RequiredIf: func(conf *hocon.Config) bool {
+			return conf.GetString("a.mode") == "slow"
+		}},
+	)
+
+	conf := hocon.ParseString(`a {
+  name = "app"
+  port = 8000
+  enabled = yes
+  timeout = 10s
+  size = 64kB
+  mode = FAST
+  list = [1, 2]
+  url = "https://localhost"
+}`)
+	if problems := schema.Validate(conf); len(problems) != 0 {
+		t.Fatalf("%s failed: expected no problem but received %v", name, problems)
+	}
+
+	conf = hocon.ParseString(`a {
+  name = " "
+  port = 70000
+  enabled = maybe
+  timeout = 500ms
+  size = 2MB
+  mode = slow
+  list = "1, 2"
+  url = "localhost"
+}`)
+	problems := schema.Validate(conf)
+	paths := make([]string, len(problems))
+	for i, p := range problems {
+		paths[i] = p.Path
+	}
+	expected := []string{"a.name", "a.port", "a.enabled", "a.timeout", "a.size", "a.list", "a.url", "a.key"}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Fatalf("%s failed: expected %#v but received %v", name, expected, problems)
+	}
+}
+
+func TestCoreConfigRules(t *testing.T) {
+	name := "TestCoreConfigRules"
+	conf := hocon.ParseString(`app { name = "app", shortname = "app", version = "1.0" }
+timezone = "UTC"
+logging.level = "info"
+api {
+  http { listen_port = 8000, header_app_id = "X-App-Id", header_access_token = "X-Access-Token", response.mode = "legacy" }
+  grpc { listen_port = 8090, tls { enabled = true, client_auth = "require" } }
+  shutdown.timeout = 30s
+}`)
+	problems := buildConfigSchema(nil).Validate(conf)
+	paths := make([]string, len(problems))
+	for i, p := range problems {
+		paths[i] = p.Path
+	}
+	expected := []string{"api.grpc.tls.cert_file", "api.grpc.tls.key_file", "api.grpc.tls.client_ca_file"}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Fatalf("%s failed: expected %#v but received %v", name, expected, problems)
+	}
+}
@@ -0,0 +1,62 @@
+package goapi
+
+import (
+	"net"
+	"net/http"
+	"sync"
+
+	"main/src/logging"
+)
+
+var (
+	embeddedLock          sync.Mutex
+	embeddedBootstrappers []IBootstrapper
+	embeddedStarted       bool
+)
+
+// StartEmbedded bootstraps the application inside the current process, e.g. for end-to-end tests. Unlike Start, it
+// neither listens on the configured ports nor blocks waiting for signals:
+//   - HTTP requests are served by the returned http.Handler (e.g. wrap it with httptest.NewServer).
+//   - The gRPC gateway is served on grpcListener (e.g. a bufconn listener); nil disables the gRPC gateway.
+//   - Configuration reload (file watch, SIGHUP) is not started; ReloadAppConfig can still be called explicitly.
+//
+// Configurations are loaded the same way as Start (see env APP_CONFIG). The application can be started only once per
+// process; call StopEmbedded to shut it down.
+//
+// @since template-v0.5.0
+func StartEmbedded(grpcListener net.Listener, bootstrappers ...IBootstrapper) http.Handler {
+	embeddedLock.Lock()
+	defer embeddedLock.Unlock()
+	if embeddedStarted {
+		panic("application has already been started")
+	}
+	embeddedStarted = true
+	embeddedBootstrappers = bootstrappers
+
+	initApp(bootstrappers)
+	bootstrapApp(bootstrappers)
+	if grpcListener != nil {
+		server, err := newGrpcServer()
+		if err != nil {
+			panic(err)
+		}
+		logging.Infof("Starting [%s] gRPC server on [%s]...", AppConfig().GetString("app.name")+" v"+AppConfig().GetString("app.version"), grpcListener.Addr())
+		startGrpcServer(server, grpcListener)
+	}
+	handler := initHttpHandler()
+	SubscribeConfigChanges("logging", []string{"logging"}, reloadLogging)
+	setReady(true)
+	return handler
+}
+
+// StopEmbedded shuts down the application started by StartEmbedded, following the same sequence as a normal shutdown.
+//
+// @since template-v0.5.0
+func StopEmbedded() {
+	embeddedLock.Lock()
+	defer embeddedLock.Unlock()
+	if embeddedStarted {
+		shutdown(embeddedBootstrappers)
+		embeddedBootstrappers = nil
+	}
+}
@@ -0,0 +1,173 @@
+package goapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/btnguyen2k/consu/reddo"
+	"github.com/labstack/echo/v4"
+	"main/src/itineris"
+)
+
+const (
+	// eventsApiName is name of the API context of an event subscription, which is routed through the api-filter chain for authentication.
+	eventsApiName = "_subscribeEvents"
+
+	// MimeEventStream is the content type of Server-Sent Events.
+	MimeEventStream = "text/event-stream"
+)
+
+var (
+	// EventBus is the application-wide event bus, application publishes its domain events here.
+	//
+	// @since template-v0.5.0
+	EventBus *itineris.EventBus
+
+	// EventFilterBuilder is set by application to build the filter that decides which events a subscriber can see.
+	// It is called after the subscription request passed the api-filter chain, so ctx carries the authenticated session.
+	// If not set, event subscription is not supported.
+	//
+	// @since template-v0.5.0
+	EventFilterBuilder func(ctx *itineris.ApiContext) itineris.EventFilter
+
+	eventsEnabled    = true
+	eventsBufferSize = 64
+	eventsHeartbeat  = 30 * time.Second
+)
+
+// initEvents creates the event bus and loads settings from config keys "api.events.*".
+//
+// @since template-v0.5.0
+func initEvents() {
+	EventBus = itineris.NewEventBus()
+	eventsEnabled = AppConfig().GetBoolean("api.events.enabled", true)
+	eventsBufferSize = int(AppConfig().GetInt32("api.events.buffer_size", 64))
+	if eventsHeartbeat = AppConfig().GetTimeDuration("api.events.heartbeat", 30*time.Second); eventsHeartbeat <= 0 {
+		eventsHeartbeat = 30 * time.Second
+	}
+}
+
+var reEventTypesSeparator = regexp.MustCompile(`[,;\s]+`)
+
+// parseEventTypes parses list of event types, either a list or a comma-separated string.
+func parseEventTypes(input interface{}) []string {
+	result := make([]string, 0)
+	switch v := input.(type) {
+	case nil:
+	case []string:
+		result = append(result, v...)
+	case []interface{}:
+		for _, t := range v {
+			s, _ := reddo.ToString(t)
+			result = append(result, s)
+		}
+	default:
+		s, _ := reddo.ToString(v)
+		result = reEventTypesSeparator.Split(s, -1)
+	}
+	types := make([]string, 0, len(result))
+	for _, t := range result {
+		if t != "" {
+			types = append(types, t)
+		}
+	}
+	return types
+}
+
+// subscribeEvents routes the subscription request through the api-filter chain and, if allowed, subscribes to the event bus.
+//
+// Subscriber receives events of the specified types (empty means all types) that pass the application's filter.
+// If the subscription is not allowed, nil is returned together with the API result explaining why.
+//
+// @since template-v0.5.0
+func subscribeEvents(ctx *itineris.ApiContext, auth *itineris.ApiAuth, types []string) (*itineris.EventSubscription, *itineris.ApiResult) {
+	if !eventsEnabled || EventBus == nil || EventFilterBuilder == nil {
+		return nil, itineris.NewApiResult(itineris.StatusNotImplemented).SetMessage("Event subscription is not supported.")
+	}
+	var sub *itineris.EventSubscription
+	result := ApiRouter.CallHandler(ctx, auth, itineris.NewApiParams().SetParam("types", types), func(ctx *itineris.ApiContext, _ *itineris.ApiAuth, _ *itineris.ApiParams) *itineris.ApiResult {
+		appFilter := EventFilterBuilder(ctx)
+		typeMap := make(map[string]bool)
+		for _, t := range types {
+			typeMap[t] = true
+		}
+		sub = EventBus.Subscribe(func(event *itineris.Event) bool {
+			if len(typeMap) > 0 && !typeMap[event.Type] {
+				return false
+			}
+			return appFilter == nil || appFilter(event)
+		}, eventsBufferSize)
+		return itineris.NewApiResult(itineris.StatusOk)
+	})
+	if result.Status != itineris.StatusOk {
+		if sub != nil {
+			sub.Close()
+		}
+		return nil, result
+	}
+	return sub, result
+}
+
+// apiEventsSseHandler streams events to client via Server-Sent Events.
+//
+// Since browsers' EventSource does not support custom headers, app-id and access-token can also be passed via
+// query parameters "appId" and "accessToken". Query parameter "types" is the comma-separated list of event types to receive.
+//
+// @since template-v0.5.0
+func apiEventsSseHandler(c echo.Context) error {
+	ctx, auth, params := _parseRequest(eventsApiName, c)
+	if auth.GetAppId() == "" && auth.GetAccessToken() == "" {
+		auth = itineris.NewApiAuth(c.QueryParam("appId"), c.QueryParam("accessToken"))
+	}
+	if c.QueryParam("accessToken") != "" {
+		// do not leak access token to logs
+		u := *c.Request().URL
+		q := u.Query()
+		q.Del("accessToken")
+		u.RawQuery = q.Encode()
+		ctx.SetContextValue(itineris.CtxHttpRequestUrl, u.String())
+	}
+	sub, result := subscribeEvents(ctx, auth, parseEventTypes(params.GetParam("types")))
+	if sub == nil {
+		return writeApiResult(c, ctx, result)
+	}
+	defer sub.Close()
+
+	resp := c.Response()
+	resp.Header().Set(echo.HeaderContentType, MimeEventStream)
+	resp.Header().Set(echo.HeaderCacheControl, "no-cache")
+	resp.Header().Set(echo.HeaderConnection, "keep-alive")
+	resp.Header().Set("X-Accel-Buffering", "no")
+	resp.WriteHeader(http.StatusOK)
+	fmt.Fprintf(resp, ": subscribed %s\n\n", ctx.GetId())
+	resp.Flush()
+
+	logger := itineris.ContextLogger(nil, ctx)
+	logger.Debugf("SSE subscriber connected")
+	heartbeat := time.NewTicker(eventsHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-c.Request().Context().Done():
+			logger.Debugf("SSE subscriber disconnected, dropped events: %d", sub.Dropped())
+			return nil
+		case <-heartbeat.C:
+			fmt.Fprint(resp, ": ping\n\n")
+			resp.Flush()
+		case event, ok := <-sub.Events():
+			if !ok {
+				return nil
+			}
+			js, err := json.Marshal(event.ToMap())
+			if err != nil {
+				logger.Warnf("Cannot serialize event [%s/%s]: %s", event.Type, event.Id, err)
+				continue
+			}
+			fmt.Fprintf(resp, "id: %s\nevent: %s\ndata: %s\n\n", event.Id, event.Type, js)
+			resp.Flush()
+		}
+	}
+}
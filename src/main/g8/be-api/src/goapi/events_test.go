@@ -0,0 +1,76 @@
+package goapi
+
+import (
+	"reflect"
+	"testing"
+
+	"main/src/itineris"
+)
+
+func TestParseEventTypes(t *testing.T) {
+	name := "TestParseEventTypes"
+	testCases := []struct {
+		input    interface{}
+		expected []string
+	}{
+		{nil, []string{}},
+		{"", []string{}},
+		{"post.created, vote.changed;;comment.added", []string{"post.created", "vote.changed", "comment.added"}},
+		{[]string{"post.created", ""}, []string{"post.created"}},
+		{[]interface{}{"post.created", "vote.changed"}, []string{"post.created", "vote.changed"}},
+	}
+	for _, tc := range testCases {
+		if v := parseEventTypes(tc.input); !reflect.DeepEqual(v, tc.expected) {
+			t.Fatalf("%s failed: expected %#v but received %#v", name, tc.expected, v)
+		}
+	}
+}
+
+type _denyFilter struct {
+	*itineris.BaseApiFilter
+}
+
+func (f *_denyFilter) Call(handler itineris.IApiHandler, ctx *itineris.ApiContext, auth *itineris.ApiAuth, params *itineris.ApiParams) *itineris.ApiResult {
+	if auth.GetAccessToken() != "valid" {
+		return itineris.NewApiResult(itineris.StatusNoPermission)
+	}
+	ctx.SetContextValue("user", auth.GetAccessToken())
+	return handler(ctx, auth, params)
+}
+
+func TestSubscribeEvents(t *testing.T) {
+	name := "TestSubscribeEvents"
+	oldRouter, oldBus, oldBuilder := ApiRouter, EventBus, EventFilterBuilder
+	defer func() { ApiRouter, EventBus, EventFilterBuilder = oldRouter, oldBus, oldBuilder }()
+	ApiRouter = itineris.NewApiRouter()
+	ApiRouter.SetApiFilter(&_denyFilter{BaseApiFilter: &itineris.BaseApiFilter{ApiRouter: ApiRouter}})
+	EventBus = itineris.NewEventBus()
+
+	EventFilterBuilder = nil
+	if sub, result := subscribeEvents(itineris.NewApiContext(), itineris.NewApiAuth("", "valid"), nil); sub != nil || result.Status != itineris.StatusNotImplemented {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, itineris.StatusNotImplemented, result.Status)
+	}
+
+	EventFilterBuilder = func(ctx *itineris.ApiContext) itineris.EventFilter {
+		user := ctx.GetContextValue("user")
+		return func(event *itineris.Event) bool { return event.Data["owner"] == user }
+	}
+	if sub, result := subscribeEvents(itineris.NewApiContext(), itineris.NewApiAuth("", "invalid"), nil); sub != nil || result.Status != itineris.StatusNoPermission {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, itineris.StatusNoPermission, result.Status)
+	}
+	if EventBus.NumSubscribers() != 0 {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, 0, EventBus.NumSubscribers())
+	}
+
+	sub, result := subscribeEvents(itineris.NewApiContext(), itineris.NewApiAuth("", "valid"), []string{"post.created"})
+	if sub == nil || result.Status != itineris.StatusOk {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, itineris.StatusOk, result.Status)
+	}
+	defer sub.Close()
+	EventBus.Publish(itineris.NewEvent("post.created", map[string]interface{}{"owner": "valid"}))
+	EventBus.Publish(itineris.NewEvent("post.created", map[string]interface{}{"owner": "other"}))
+	EventBus.Publish(itineris.NewEvent("post.deleted", map[string]interface{}{"owner": "valid"}))
+	if len(sub.Events()) != 1 {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, 1, len(sub.Events()))
+	}
+}
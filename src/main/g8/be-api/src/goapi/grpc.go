@@ -7,12 +7,25 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net"
+	"strings"
+	"time"
 
 	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
 	"main/grpc"
 	"main/src/itineris"
 )
 
+const (
+	// grpcCheckApiName is name of the API context of rpc "check".
+	grpcCheckApiName = "_check"
+)
+
 /*
 PApiServiceServer is gRPC gateway server
 
@@ -26,39 +39,175 @@ func (s *PApiServiceServer) Ping(context.Context, *empty.Empty) (*empty.Empty, e
 	return &empty.Empty{}, nil
 }
 
-func (s *PApiServiceServer) Check(context.Context, *grpc.PApiAuth) (*grpc.PApiResult, error) {
-	return &grpc.PApiResult{
-		Status:     itineris.StatusOk,
-		Message:    "Ok",
-		Encoding:   grpc.PDataEncoding_JSON_STRING,
-		ResultData: nil,
-		DebugData:  nil,
-	}, nil
+// Check verifies the app id and access token by routing a no-op API call through the api-filter chain.
+//
+// Since template-v0.5.0, the app id and access token are validated (previously this function always returned OK).
+func (s *PApiServiceServer) Check(gctx context.Context, gauth *grpc.PApiAuth) (*grpc.PApiResult, error) {
+	ctx := _parseGrpcContext(gctx, grpcCheckApiName)
+	auth := itineris.NewApiAuth(gauth.GetAppId(), gauth.GetAccessToken())
+	result := ApiRouter.CallHandler(ctx, auth, itineris.NewApiParams(), func(_ *itineris.ApiContext, _ *itineris.ApiAuth, _ *itineris.ApiParams) *itineris.ApiResult {
+		return itineris.NewApiResult(itineris.StatusOk).SetMessage("Ok")
+	})
+	return toPApiResult(grpc.PDataEncoding_JSON_STRING, result), nil
 }
 
-func (s *PApiServiceServer) Call(_ context.Context, gctx *grpc.PApiContext) (*grpc.PApiResult, error) {
-	ctx := itineris.NewApiContext().SetApiName(gctx.ApiName).SetGateway("GRPC")
-	auth := itineris.NewApiAuth(gctx.ApiAuth.AppId, gctx.ApiAuth.AccessToken)
-	params := parseParams(gctx.ApiParams)
+func (s *PApiServiceServer) Call(gctx context.Context, pctx *grpc.PApiContext) (*grpc.PApiResult, error) {
+	ctx := _parseGrpcContext(gctx, pctx.GetApiName())
+	auth := itineris.NewApiAuth(pctx.GetApiAuth().GetAppId(), pctx.GetApiAuth().GetAccessToken())
+	params := itineris.NewApiParams()
+	if pctx.ApiParams != nil {
+		params = parseParams(pctx.ApiParams)
+	}
 	if params == nil {
 		result := itineris.NewApiResult(itineris.StatusErrorClient).SetMessage("Cannot parse request parameters.")
 		return toPApiResult(grpc.PDataEncoding_JSON_STRING, result), nil
 	}
 	result := ApiRouter.CallApi(ctx, auth, params)
-	resultEncoding := gctx.ApiParams.ExpectedReturnEncoding
-	if resultEncoding == grpc.PDataEncoding_JSON_DEFAULT {
-		resultEncoding = gctx.ApiParams.Encoding
-		if resultEncoding == grpc.PDataEncoding_JSON_DEFAULT {
-			resultEncoding = grpc.PDataEncoding_JSON_STRING
+	return toPApiResult(expectedResultEncoding(pctx.ApiParams), result), nil
+}
+
+// CallBatch invokes a batch of API calls.
+//
+// @since template-v0.5.0
+func (s *PApiServiceServer) CallBatch(gctx context.Context, pctx *grpc.PApiBatchContext) (*grpc.PApiBatchResult, error) {
+	if result := checkBatchRequest(len(pctx.Items)); result != nil {
+		return &grpc.PApiBatchResult{Status: int32(result.Status), Message: result.Message}, nil
+	}
+	ctx := _parseGrpcContext(gctx, batchApiName)
+	auth := itineris.NewApiAuth(pctx.GetApiAuth().GetAppId(), pctx.GetApiAuth().GetAccessToken())
+	calls := make([]*itineris.BatchCall, len(pctx.Items))
+	for i, item := range pctx.Items {
+		params := itineris.NewApiParams()
+		if item.ApiParams != nil {
+			if params = parseParams(item.ApiParams); params == nil {
+				return &grpc.PApiBatchResult{
+					Status:  itineris.StatusErrorClient,
+					Message: fmt.Sprintf("Cannot parse request parameters of item #%d.", i),
+				}, nil
+			}
 		}
+		calls[i] = &itineris.BatchCall{Id: item.Id, ApiName: item.ApiName, Params: params}
+	}
+	results := ApiRouter.CallBatch(ctx, auth, calls, buildBatchOptions(pctx.Parallel, int(pctx.MaxConcurrency)))
+	batchResult := &grpc.PApiBatchResult{Status: itineris.StatusOk, Items: make([]*grpc.PApiBatchResultItem, len(results))}
+	for i, r := range results {
+		resultEncoding := expectedResultEncoding(pctx.Items[i].ApiParams)
+		batchResult.Items[i] = &grpc.PApiBatchResultItem{Id: r.Id, ApiName: r.ApiName, Result: toPApiResult(resultEncoding, r.Result)}
 	}
-	return toPApiResult(resultEncoding, result), nil
+	return batchResult, nil
 }
 
 func newGrpcGateway() *PApiServiceServer {
 	return &PApiServiceServer{}
 }
 
+// SubscribeEvents streams events published to the application's event bus until client cancels the stream.
+//
+// @since template-v0.5.0
+func (s *PApiServiceServer) SubscribeEvents(psub *grpc.PApiEventSubscription, stream grpc.PApiService_SubscribeEventsServer) error {
+	ctx := _parseGrpcContext(stream.Context(), eventsApiName)
+	auth := itineris.NewApiAuth(psub.GetApiAuth().GetAppId(), psub.GetApiAuth().GetAccessToken())
+	sub, result := subscribeEvents(ctx, auth, psub.GetTypes())
+	if sub == nil {
+		return status.Error(toGrpcCode(result.Status), result.Message)
+	}
+	defer sub.Close()
+	encoding := psub.GetExpectedEncoding()
+	if encoding == grpc.PDataEncoding_JSON_DEFAULT {
+		encoding = grpc.PDataEncoding_JSON_STRING
+	}
+	for {
+		select {
+		case <-stream.Context().Done():
+			itineris.ContextLogger(nil, ctx).Debugf("gRPC event subscriber disconnected, dropped events: %d", sub.Dropped())
+			return nil
+		case event, ok := <-sub.Events():
+			if !ok {
+				return nil
+			}
+			js, _ := json.Marshal(event.Data)
+			if encoding == grpc.PDataEncoding_JSON_GZIP {
+				js, _ = gzipEncode(js)
+			}
+			pevent := &grpc.PApiEvent{Id: event.Id, Type: event.Type, Timestamp: event.Timestamp.UnixNano() / int64(time.Millisecond), Encoding: encoding, Data: js}
+			if err := stream.Send(pevent); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// toGrpcCode maps API result's status to gRPC status code.
+func toGrpcCode(apiStatus int) codes.Code {
+	switch apiStatus {
+	case itineris.StatusOk:
+		return codes.OK
+	case itineris.StatusErrorClient:
+		return codes.InvalidArgument
+	case itineris.StatusNoPermission:
+		return codes.PermissionDenied
+	case itineris.StatusNotFound:
+		return codes.NotFound
+	case itineris.StatusConflict:
+		return codes.Aborted
+	case itineris.StatusNotImplemented:
+		return codes.Unimplemented
+	}
+	return codes.Internal
+}
+
+// expectedResultEncoding determines encoding of API result: the requested one, or same as params' encoding, or JSON string.
+func expectedResultEncoding(params *grpc.PApiParams) grpc.PDataEncoding {
+	if encoding := params.GetExpectedReturnEncoding(); encoding != grpc.PDataEncoding_JSON_DEFAULT {
+		return encoding
+	}
+	if encoding := params.GetEncoding(); encoding != grpc.PDataEncoding_JSON_DEFAULT {
+		return encoding
+	}
+	return grpc.PDataEncoding_JSON_STRING
+}
+
+// _parseGrpcContext builds the ApiContext of a gRPC call:
+//   - peer address is captured as CtxClientAddr; CtxClientRealAddr is taken from metadata "x-real-ip"/"x-forwarded-for" if present, otherwise the peer's host.
+//   - metadata keys starting with "x-" are mapped to context values (prefix stripped), just like HTTP "x-" headers
+//     (app-id and access-token keys are excluded).
+//   - (mTLS) common name of the verified client certificate is captured as CtxClientCertCN.
+//
+// @since template-v0.5.0
+func _parseGrpcContext(gctx context.Context, apiName string) *itineris.ApiContext {
+	ctx := itineris.NewApiContext().SetApiName(apiName).SetGateway("GRPC")
+	if p, ok := peer.FromContext(gctx); ok && p.Addr != nil {
+		addr := p.Addr.String()
+		ctx.SetContextValue(itineris.CtxClientAddr, addr)
+		if host, _, err := net.SplitHostPort(addr); err == nil {
+			ctx.SetContextValue(itineris.CtxClientRealAddr, host)
+		} else {
+			ctx.SetContextValue(itineris.CtxClientRealAddr, addr)
+		}
+		if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok && len(tlsInfo.State.VerifiedChains) > 0 && len(tlsInfo.State.VerifiedChains[0]) > 0 {
+			ctx.SetContextValue(itineris.CtxClientCertCN, tlsInfo.State.VerifiedChains[0][0].Subject.CommonName)
+		}
+	}
+	if md, ok := metadata.FromIncomingContext(gctx); ok {
+		for k, v := range md {
+			k = strings.TrimSpace(strings.ToLower(k))
+			if len(v) == 0 || !strings.HasPrefix(k, "x-") || k == strings.ToLower(httpHeaderAppId) || k == strings.ToLower(httpHeaderAccessToken) {
+				continue
+			}
+			ctx.SetContextValue(k[2:], v[0])
+		}
+		if v := md.Get("idempotency-key"); len(v) > 0 && v[0] != "" {
+			ctx.SetContextValue(itineris.CtxIdempotencyKey, v[0])
+		}
+		if v := md.Get("x-real-ip"); len(v) > 0 && v[0] != "" {
+			ctx.SetContextValue(itineris.CtxClientRealAddr, v[0])
+		} else if v := md.Get("x-forwarded-for"); len(v) > 0 && v[0] != "" {
+			ctx.SetContextValue(itineris.CtxClientRealAddr, strings.TrimSpace(strings.Split(v[0], ",")[0]))
+		}
+	}
+	return ctx
+}
+
 func toPApiResult(encoding grpc.PDataEncoding, apiResult *itineris.ApiResult) *grpc.PApiResult {
 	result := &grpc.PApiResult{
 		Status:   int32(apiResult.Status),
@@ -79,6 +228,13 @@ func toPApiResult(encoding grpc.PDataEncoding, apiResult *itineris.ApiResult) *g
 		}
 		result.DebugData = js
 	}
+	if len(apiResult.Extras) > 0 {
+		js, _ := json.Marshal(apiResult.Extras)
+		if encoding == grpc.PDataEncoding_JSON_GZIP {
+			js, _ = gzipEncode(js)
+		}
+		result.ExtrasData = js
+	}
 
 	return result
 }
@@ -93,8 +249,6 @@ func parseParams(gparams *grpc.PApiParams) *itineris.ApiParams {
 		}
 	case grpc.PDataEncoding_JSON_GZIP:
 		buf, err := gzipDecode(gparams.ParamsData)
-		fmt.Println(len(gparams.ParamsData), len(buf))
-		fmt.Println("Encoding Gzip", err)
 		if err != nil {
 			return nil
 		}
@@ -1,29 +1,29 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.1
+// 	protoc        (unknown)
 // source: api_service.proto
 
 package grpc
 
 import (
 	context "context"
-	fmt "fmt"
-	proto "github.com/golang/protobuf/proto"
-	empty "github.com/golang/protobuf/ptypes/empty"
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
 	status "google.golang.org/grpc/status"
-	math "math"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	reflect "reflect"
+	sync "sync"
 )
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ = proto.Marshal
-var _ = fmt.Errorf
-var _ = math.Inf
-
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the proto package it is being compiled against.
-// A compilation error at this line likely means your copy of the
-// proto package needs to be updated.
-const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
 
 type PDataEncoding int32
 
@@ -33,329 +33,1066 @@ const (
 	PDataEncoding_JSON_GZIP    PDataEncoding = 2
 )
 
-var PDataEncoding_name = map[int32]string{
-	0: "JSON_DEFAULT",
-	1: "JSON_STRING",
-	2: "JSON_GZIP",
-}
+// Enum value maps for PDataEncoding.
+var (
+	PDataEncoding_name = map[int32]string{
+		0: "JSON_DEFAULT",
+		1: "JSON_STRING",
+		2: "JSON_GZIP",
+	}
+	PDataEncoding_value = map[string]int32{
+		"JSON_DEFAULT": 0,
+		"JSON_STRING":  1,
+		"JSON_GZIP":    2,
+	}
+)
 
-var PDataEncoding_value = map[string]int32{
-	"JSON_DEFAULT": 0,
-	"JSON_STRING":  1,
-	"JSON_GZIP":    2,
+func (x PDataEncoding) Enum() *PDataEncoding {
+	p := new(PDataEncoding)
+	*p = x
+	return p
 }
 
 func (x PDataEncoding) String() string {
-	return proto.EnumName(PDataEncoding_name, int32(x))
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
 }
 
-func (PDataEncoding) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_dac1f622be3e5824, []int{0}
+func (PDataEncoding) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_service_proto_enumTypes[0].Descriptor()
 }
 
-type PApiAuth struct {
-	AppId                string   `protobuf:"bytes,1,opt,name=appId,proto3" json:"appId,omitempty"`
-	AccessToken          string   `protobuf:"bytes,2,opt,name=accessToken,proto3" json:"accessToken,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (PDataEncoding) Type() protoreflect.EnumType {
+	return &file_api_service_proto_enumTypes[0]
 }
 
-func (m *PApiAuth) Reset()         { *m = PApiAuth{} }
-func (m *PApiAuth) String() string { return proto.CompactTextString(m) }
-func (*PApiAuth) ProtoMessage()    {}
-func (*PApiAuth) Descriptor() ([]byte, []int) {
-	return fileDescriptor_dac1f622be3e5824, []int{0}
+func (x PDataEncoding) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
 }
 
-func (m *PApiAuth) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_PApiAuth.Unmarshal(m, b)
+// Deprecated: Use PDataEncoding.Descriptor instead.
+func (PDataEncoding) EnumDescriptor() ([]byte, []int) {
+	return file_api_service_proto_rawDescGZIP(), []int{0}
 }
-func (m *PApiAuth) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_PApiAuth.Marshal(b, m, deterministic)
+
+type PApiAuth struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	AppId       string `protobuf:"bytes,1,opt,name=appId,proto3" json:"appId,omitempty"`
+	AccessToken string `protobuf:"bytes,2,opt,name=accessToken,proto3" json:"accessToken,omitempty"`
 }
-func (m *PApiAuth) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_PApiAuth.Merge(m, src)
+
+func (x *PApiAuth) Reset() {
+	*x = PApiAuth{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_service_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
-func (m *PApiAuth) XXX_Size() int {
-	return xxx_messageInfo_PApiAuth.Size(m)
+
+func (x *PApiAuth) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
-func (m *PApiAuth) XXX_DiscardUnknown() {
-	xxx_messageInfo_PApiAuth.DiscardUnknown(m)
+
+func (*PApiAuth) ProtoMessage() {}
+
+func (x *PApiAuth) ProtoReflect() protoreflect.Message {
+	mi := &file_api_service_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-var xxx_messageInfo_PApiAuth proto.InternalMessageInfo
+// Deprecated: Use PApiAuth.ProtoReflect.Descriptor instead.
+func (*PApiAuth) Descriptor() ([]byte, []int) {
+	return file_api_service_proto_rawDescGZIP(), []int{0}
+}
 
-func (m *PApiAuth) GetAppId() string {
-	if m != nil {
-		return m.AppId
+func (x *PApiAuth) GetAppId() string {
+	if x != nil {
+		return x.AppId
 	}
 	return ""
 }
 
-func (m *PApiAuth) GetAccessToken() string {
-	if m != nil {
-		return m.AccessToken
+func (x *PApiAuth) GetAccessToken() string {
+	if x != nil {
+		return x.AccessToken
 	}
 	return ""
 }
 
 type PApiParams struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
 	Encoding               PDataEncoding `protobuf:"varint,1,opt,name=encoding,proto3,enum=PDataEncoding" json:"encoding,omitempty"`
 	ParamsData             []byte        `protobuf:"bytes,2,opt,name=paramsData,proto3" json:"paramsData,omitempty"`
 	ExpectedReturnEncoding PDataEncoding `protobuf:"varint,3,opt,name=expectedReturnEncoding,proto3,enum=PDataEncoding" json:"expectedReturnEncoding,omitempty"`
-	XXX_NoUnkeyedLiteral   struct{}      `json:"-"`
-	XXX_unrecognized       []byte        `json:"-"`
-	XXX_sizecache          int32         `json:"-"`
 }
 
-func (m *PApiParams) Reset()         { *m = PApiParams{} }
-func (m *PApiParams) String() string { return proto.CompactTextString(m) }
-func (*PApiParams) ProtoMessage()    {}
-func (*PApiParams) Descriptor() ([]byte, []int) {
-	return fileDescriptor_dac1f622be3e5824, []int{1}
+func (x *PApiParams) Reset() {
+	*x = PApiParams{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_service_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
 
-func (m *PApiParams) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_PApiParams.Unmarshal(m, b)
+func (x *PApiParams) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
-func (m *PApiParams) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_PApiParams.Marshal(b, m, deterministic)
-}
-func (m *PApiParams) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_PApiParams.Merge(m, src)
-}
-func (m *PApiParams) XXX_Size() int {
-	return xxx_messageInfo_PApiParams.Size(m)
-}
-func (m *PApiParams) XXX_DiscardUnknown() {
-	xxx_messageInfo_PApiParams.DiscardUnknown(m)
+
+func (*PApiParams) ProtoMessage() {}
+
+func (x *PApiParams) ProtoReflect() protoreflect.Message {
+	mi := &file_api_service_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-var xxx_messageInfo_PApiParams proto.InternalMessageInfo
+// Deprecated: Use PApiParams.ProtoReflect.Descriptor instead.
+func (*PApiParams) Descriptor() ([]byte, []int) {
+	return file_api_service_proto_rawDescGZIP(), []int{1}
+}
 
-func (m *PApiParams) GetEncoding() PDataEncoding {
-	if m != nil {
-		return m.Encoding
+func (x *PApiParams) GetEncoding() PDataEncoding {
+	if x != nil {
+		return x.Encoding
 	}
 	return PDataEncoding_JSON_DEFAULT
 }
 
-func (m *PApiParams) GetParamsData() []byte {
-	if m != nil {
-		return m.ParamsData
+func (x *PApiParams) GetParamsData() []byte {
+	if x != nil {
+		return x.ParamsData
 	}
 	return nil
 }
 
-func (m *PApiParams) GetExpectedReturnEncoding() PDataEncoding {
-	if m != nil {
-		return m.ExpectedReturnEncoding
+func (x *PApiParams) GetExpectedReturnEncoding() PDataEncoding {
+	if x != nil {
+		return x.ExpectedReturnEncoding
 	}
 	return PDataEncoding_JSON_DEFAULT
 }
 
 type PApiResult struct {
-	Status               int32         `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
-	Message              string        `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-	Encoding             PDataEncoding `protobuf:"varint,3,opt,name=encoding,proto3,enum=PDataEncoding" json:"encoding,omitempty"`
-	ResultData           []byte        `protobuf:"bytes,4,opt,name=resultData,proto3" json:"resultData,omitempty"`
-	DebugData            []byte        `protobuf:"bytes,5,opt,name=debugData,proto3" json:"debugData,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
-	XXX_unrecognized     []byte        `json:"-"`
-	XXX_sizecache        int32         `json:"-"`
-}
-
-func (m *PApiResult) Reset()         { *m = PApiResult{} }
-func (m *PApiResult) String() string { return proto.CompactTextString(m) }
-func (*PApiResult) ProtoMessage()    {}
-func (*PApiResult) Descriptor() ([]byte, []int) {
-	return fileDescriptor_dac1f622be3e5824, []int{2}
-}
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-func (m *PApiResult) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_PApiResult.Unmarshal(m, b)
-}
-func (m *PApiResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_PApiResult.Marshal(b, m, deterministic)
+	Status     int32         `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	Message    string        `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Encoding   PDataEncoding `protobuf:"varint,3,opt,name=encoding,proto3,enum=PDataEncoding" json:"encoding,omitempty"`
+	ResultData []byte        `protobuf:"bytes,4,opt,name=resultData,proto3" json:"resultData,omitempty"`
+	DebugData  []byte        `protobuf:"bytes,5,opt,name=debugData,proto3" json:"debugData,omitempty"`
+	ExtrasData []byte        `protobuf:"bytes,6,opt,name=extrasData,proto3" json:"extrasData,omitempty"`
 }
-func (m *PApiResult) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_PApiResult.Merge(m, src)
+
+func (x *PApiResult) Reset() {
+	*x = PApiResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_service_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
-func (m *PApiResult) XXX_Size() int {
-	return xxx_messageInfo_PApiResult.Size(m)
+
+func (x *PApiResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
-func (m *PApiResult) XXX_DiscardUnknown() {
-	xxx_messageInfo_PApiResult.DiscardUnknown(m)
+
+func (*PApiResult) ProtoMessage() {}
+
+func (x *PApiResult) ProtoReflect() protoreflect.Message {
+	mi := &file_api_service_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-var xxx_messageInfo_PApiResult proto.InternalMessageInfo
+// Deprecated: Use PApiResult.ProtoReflect.Descriptor instead.
+func (*PApiResult) Descriptor() ([]byte, []int) {
+	return file_api_service_proto_rawDescGZIP(), []int{2}
+}
 
-func (m *PApiResult) GetStatus() int32 {
-	if m != nil {
-		return m.Status
+func (x *PApiResult) GetStatus() int32 {
+	if x != nil {
+		return x.Status
 	}
 	return 0
 }
 
-func (m *PApiResult) GetMessage() string {
-	if m != nil {
-		return m.Message
+func (x *PApiResult) GetMessage() string {
+	if x != nil {
+		return x.Message
 	}
 	return ""
 }
 
-func (m *PApiResult) GetEncoding() PDataEncoding {
-	if m != nil {
-		return m.Encoding
+func (x *PApiResult) GetEncoding() PDataEncoding {
+	if x != nil {
+		return x.Encoding
 	}
 	return PDataEncoding_JSON_DEFAULT
 }
 
-func (m *PApiResult) GetResultData() []byte {
-	if m != nil {
-		return m.ResultData
+func (x *PApiResult) GetResultData() []byte {
+	if x != nil {
+		return x.ResultData
+	}
+	return nil
+}
+
+func (x *PApiResult) GetDebugData() []byte {
+	if x != nil {
+		return x.DebugData
 	}
 	return nil
 }
 
-func (m *PApiResult) GetDebugData() []byte {
-	if m != nil {
-		return m.DebugData
+func (x *PApiResult) GetExtrasData() []byte {
+	if x != nil {
+		return x.ExtrasData
 	}
 	return nil
 }
 
 type PApiContext struct {
-	ApiName              string      `protobuf:"bytes,1,opt,name=apiName,proto3" json:"apiName,omitempty"`
-	ApiAuth              *PApiAuth   `protobuf:"bytes,2,opt,name=apiAuth,proto3" json:"apiAuth,omitempty"`
-	ApiParams            *PApiParams `protobuf:"bytes,3,opt,name=apiParams,proto3" json:"apiParams,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
-	XXX_unrecognized     []byte      `json:"-"`
-	XXX_sizecache        int32       `json:"-"`
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ApiName   string      `protobuf:"bytes,1,opt,name=apiName,proto3" json:"apiName,omitempty"`
+	ApiAuth   *PApiAuth   `protobuf:"bytes,2,opt,name=apiAuth,proto3" json:"apiAuth,omitempty"`
+	ApiParams *PApiParams `protobuf:"bytes,3,opt,name=apiParams,proto3" json:"apiParams,omitempty"`
+}
+
+func (x *PApiContext) Reset() {
+	*x = PApiContext{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_service_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
 
-func (m *PApiContext) Reset()         { *m = PApiContext{} }
-func (m *PApiContext) String() string { return proto.CompactTextString(m) }
-func (*PApiContext) ProtoMessage()    {}
+func (x *PApiContext) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PApiContext) ProtoMessage() {}
+
+func (x *PApiContext) ProtoReflect() protoreflect.Message {
+	mi := &file_api_service_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PApiContext.ProtoReflect.Descriptor instead.
 func (*PApiContext) Descriptor() ([]byte, []int) {
-	return fileDescriptor_dac1f622be3e5824, []int{3}
+	return file_api_service_proto_rawDescGZIP(), []int{3}
 }
 
-func (m *PApiContext) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_PApiContext.Unmarshal(m, b)
+func (x *PApiContext) GetApiName() string {
+	if x != nil {
+		return x.ApiName
+	}
+	return ""
 }
-func (m *PApiContext) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_PApiContext.Marshal(b, m, deterministic)
+
+func (x *PApiContext) GetApiAuth() *PApiAuth {
+	if x != nil {
+		return x.ApiAuth
+	}
+	return nil
 }
-func (m *PApiContext) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_PApiContext.Merge(m, src)
+
+func (x *PApiContext) GetApiParams() *PApiParams {
+	if x != nil {
+		return x.ApiParams
+	}
+	return nil
 }
-func (m *PApiContext) XXX_Size() int {
-	return xxx_messageInfo_PApiContext.Size(m)
+
+type PApiBatchItem struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id        string      `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ApiName   string      `protobuf:"bytes,2,opt,name=apiName,proto3" json:"apiName,omitempty"`
+	ApiParams *PApiParams `protobuf:"bytes,3,opt,name=apiParams,proto3" json:"apiParams,omitempty"`
 }
-func (m *PApiContext) XXX_DiscardUnknown() {
-	xxx_messageInfo_PApiContext.DiscardUnknown(m)
+
+func (x *PApiBatchItem) Reset() {
+	*x = PApiBatchItem{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_service_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
 
-var xxx_messageInfo_PApiContext proto.InternalMessageInfo
+func (x *PApiBatchItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PApiBatchItem) ProtoMessage() {}
+
+func (x *PApiBatchItem) ProtoReflect() protoreflect.Message {
+	mi := &file_api_service_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PApiBatchItem.ProtoReflect.Descriptor instead.
+func (*PApiBatchItem) Descriptor() ([]byte, []int) {
+	return file_api_service_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *PApiBatchItem) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
 
-func (m *PApiContext) GetApiName() string {
-	if m != nil {
-		return m.ApiName
+func (x *PApiBatchItem) GetApiName() string {
+	if x != nil {
+		return x.ApiName
 	}
 	return ""
 }
 
-func (m *PApiContext) GetApiAuth() *PApiAuth {
-	if m != nil {
-		return m.ApiAuth
+func (x *PApiBatchItem) GetApiParams() *PApiParams {
+	if x != nil {
+		return x.ApiParams
+	}
+	return nil
+}
+
+type PApiBatchContext struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ApiAuth        *PApiAuth        `protobuf:"bytes,1,opt,name=apiAuth,proto3" json:"apiAuth,omitempty"`
+	Items          []*PApiBatchItem `protobuf:"bytes,2,rep,name=items,proto3" json:"items,omitempty"`
+	Parallel       bool             `protobuf:"varint,3,opt,name=parallel,proto3" json:"parallel,omitempty"`
+	MaxConcurrency int32            `protobuf:"varint,4,opt,name=maxConcurrency,proto3" json:"maxConcurrency,omitempty"`
+}
+
+func (x *PApiBatchContext) Reset() {
+	*x = PApiBatchContext{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_service_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PApiBatchContext) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PApiBatchContext) ProtoMessage() {}
+
+func (x *PApiBatchContext) ProtoReflect() protoreflect.Message {
+	mi := &file_api_service_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PApiBatchContext.ProtoReflect.Descriptor instead.
+func (*PApiBatchContext) Descriptor() ([]byte, []int) {
+	return file_api_service_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *PApiBatchContext) GetApiAuth() *PApiAuth {
+	if x != nil {
+		return x.ApiAuth
 	}
 	return nil
 }
 
-func (m *PApiContext) GetApiParams() *PApiParams {
-	if m != nil {
-		return m.ApiParams
+func (x *PApiBatchContext) GetItems() []*PApiBatchItem {
+	if x != nil {
+		return x.Items
 	}
 	return nil
 }
 
-func init() {
-	proto.RegisterEnum("PDataEncoding", PDataEncoding_name, PDataEncoding_value)
-	proto.RegisterType((*PApiAuth)(nil), "PApiAuth")
-	proto.RegisterType((*PApiParams)(nil), "PApiParams")
-	proto.RegisterType((*PApiResult)(nil), "PApiResult")
-	proto.RegisterType((*PApiContext)(nil), "PApiContext")
-}
-
-func init() { proto.RegisterFile("api_service.proto", fileDescriptor_dac1f622be3e5824) }
-
-var fileDescriptor_dac1f622be3e5824 = []byte{
-	// 435 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x52, 0xc1, 0x6e, 0xd3, 0x40,
-	0x14, 0xc4, 0x6d, 0x52, 0xe2, 0xe7, 0xb4, 0x84, 0x27, 0x14, 0x45, 0x01, 0x41, 0x09, 0x17, 0xe8,
-	0x61, 0x2b, 0x05, 0x89, 0x7b, 0x4a, 0xd3, 0x12, 0x84, 0x42, 0xb4, 0x09, 0x97, 0x5e, 0xaa, 0xcd,
-	0xe6, 0xe1, 0x5a, 0x4d, 0xec, 0x95, 0x77, 0x0d, 0xe5, 0x13, 0xf8, 0x0b, 0x8e, 0x7c, 0x26, 0xf2,
-	0xb3, 0x8d, 0x13, 0x89, 0x8a, 0xe3, 0xcc, 0x6c, 0x26, 0x33, 0x9e, 0x07, 0x8f, 0x95, 0x89, 0xae,
-	0x2d, 0xa5, 0xdf, 0x22, 0x4d, 0xc2, 0xa4, 0x89, 0x4b, 0xfa, 0x4f, 0xc3, 0x24, 0x09, 0xd7, 0x74,
-	0xca, 0x68, 0x99, 0x7d, 0x3d, 0xa5, 0x8d, 0x71, 0x3f, 0x0a, 0x71, 0x70, 0x06, 0xad, 0xd9, 0xc8,
-	0x44, 0xa3, 0xcc, 0xdd, 0xe0, 0x13, 0x68, 0x2a, 0x63, 0x26, 0xab, 0x9e, 0x77, 0xec, 0xbd, 0xf6,
-	0x65, 0x01, 0xf0, 0x18, 0x02, 0xa5, 0x35, 0x59, 0xbb, 0x48, 0x6e, 0x29, 0xee, 0xed, 0xb1, 0xb6,
-	0x4d, 0x0d, 0x7e, 0x79, 0x00, 0xb9, 0xc9, 0x4c, 0xa5, 0x6a, 0x63, 0xf1, 0x04, 0x5a, 0x14, 0xeb,
-	0x64, 0x15, 0xc5, 0x21, 0x3b, 0x1d, 0x0d, 0x8f, 0xc4, 0xec, 0x5c, 0x39, 0x35, 0x2e, 0x59, 0xf9,
-	0x57, 0xc7, 0xe7, 0x00, 0x86, 0x7f, 0x95, 0xeb, 0xec, 0xdd, 0x96, 0x5b, 0x0c, 0x5e, 0x40, 0x97,
-	0xee, 0x0c, 0x69, 0x47, 0x2b, 0x49, 0x2e, 0x4b, 0xe3, 0xca, 0xa3, 0xb7, 0xff, 0x4f, 0xe7, 0x7b,
-	0x5e, 0x0f, 0x7e, 0x97, 0x11, 0x25, 0xd9, 0x6c, 0xed, 0xb0, 0x0b, 0x07, 0xd6, 0x29, 0x97, 0x59,
-	0x0e, 0xd8, 0x94, 0x25, 0xc2, 0x1e, 0x3c, 0xdc, 0x90, 0xb5, 0x2a, 0xa4, 0xb2, 0x67, 0x05, 0x77,
-	0x4a, 0xed, 0xff, 0xbf, 0x54, 0xca, 0xff, 0xc3, 0xa5, 0x1a, 0x45, 0xa9, 0x9a, 0xc1, 0x67, 0xe0,
-	0xaf, 0x68, 0x99, 0x85, 0x2c, 0x37, 0x59, 0xae, 0x89, 0xc1, 0x77, 0x08, 0xf2, 0xa4, 0xef, 0x93,
-	0xd8, 0xd1, 0x9d, 0xcb, 0x23, 0x29, 0x13, 0x4d, 0xd5, 0x86, 0xca, 0x59, 0x2a, 0x88, 0xaf, 0x58,
-	0xc9, 0x97, 0xe3, 0xb0, 0xc1, 0xd0, 0x17, 0xd5, 0x94, 0xb2, 0x52, 0xf0, 0x0d, 0xf8, 0xaa, 0x5a,
-	0x86, 0x83, 0x07, 0xc3, 0x40, 0xd4, 0x63, 0xc9, 0x5a, 0x3d, 0x19, 0xc1, 0xe1, 0x4e, 0x23, 0xec,
-	0x40, 0xfb, 0xe3, 0xfc, 0xf3, 0xf4, 0xfa, 0x7c, 0x7c, 0x31, 0xfa, 0xf2, 0x69, 0xd1, 0x79, 0x80,
-	0x8f, 0x20, 0x60, 0x66, 0xbe, 0x90, 0x93, 0xe9, 0x65, 0xc7, 0xc3, 0x43, 0xf0, 0x99, 0xb8, 0xbc,
-	0x9a, 0xcc, 0x3a, 0x7b, 0xc3, 0x9f, 0x5e, 0x11, 0x7e, 0x5e, 0x1c, 0x20, 0xbe, 0x83, 0x86, 0xc9,
-	0x9d, 0xba, 0xa2, 0xb8, 0x41, 0x51, 0xdd, 0xa0, 0x18, 0xe7, 0x37, 0xd8, 0xbf, 0x87, 0xc7, 0x17,
-	0xd0, 0xd4, 0x37, 0xa4, 0x6f, 0xb1, 0xae, 0xd4, 0x2f, 0x62, 0x97, 0x03, 0xbe, 0x84, 0x86, 0x56,
-	0xeb, 0x35, 0xb6, 0xc5, 0xd6, 0xb7, 0xda, 0x79, 0x72, 0xd6, 0xfa, 0xe0, 0x5d, 0x35, 0xc2, 0xd4,
-	0xe8, 0xe5, 0x01, 0xbb, 0xbf, 0xfd, 0x13, 0x00, 0x00, 0xff, 0xff, 0xc7, 0x5f, 0x1a, 0x3f, 0x1c,
-	0x03, 0x00, 0x00,
+func (x *PApiBatchContext) GetParallel() bool {
+	if x != nil {
+		return x.Parallel
+	}
+	return false
+}
+
+func (x *PApiBatchContext) GetMaxConcurrency() int32 {
+	if x != nil {
+		return x.MaxConcurrency
+	}
+	return 0
+}
+
+type PApiBatchResultItem struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id      string      `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ApiName string      `protobuf:"bytes,2,opt,name=apiName,proto3" json:"apiName,omitempty"`
+	Result  *PApiResult `protobuf:"bytes,3,opt,name=result,proto3" json:"result,omitempty"`
+}
+
+func (x *PApiBatchResultItem) Reset() {
+	*x = PApiBatchResultItem{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_service_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PApiBatchResultItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PApiBatchResultItem) ProtoMessage() {}
+
+func (x *PApiBatchResultItem) ProtoReflect() protoreflect.Message {
+	mi := &file_api_service_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PApiBatchResultItem.ProtoReflect.Descriptor instead.
+func (*PApiBatchResultItem) Descriptor() ([]byte, []int) {
+	return file_api_service_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *PApiBatchResultItem) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *PApiBatchResultItem) GetApiName() string {
+	if x != nil {
+		return x.ApiName
+	}
+	return ""
+}
+
+func (x *PApiBatchResultItem) GetResult() *PApiResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+type PApiBatchResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Status  int32                  `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	Message string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Items   []*PApiBatchResultItem `protobuf:"bytes,3,rep,name=items,proto3" json:"items,omitempty"`
+}
+
+func (x *PApiBatchResult) Reset() {
+	*x = PApiBatchResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_service_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PApiBatchResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PApiBatchResult) ProtoMessage() {}
+
+func (x *PApiBatchResult) ProtoReflect() protoreflect.Message {
+	mi := &file_api_service_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PApiBatchResult.ProtoReflect.Descriptor instead.
+func (*PApiBatchResult) Descriptor() ([]byte, []int) {
+	return file_api_service_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *PApiBatchResult) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+func (x *PApiBatchResult) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *PApiBatchResult) GetItems() []*PApiBatchResultItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type PApiEventSubscription struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ApiAuth          *PApiAuth     `protobuf:"bytes,1,opt,name=apiAuth,proto3" json:"apiAuth,omitempty"`
+	Types            []string      `protobuf:"bytes,2,rep,name=types,proto3" json:"types,omitempty"`
+	ExpectedEncoding PDataEncoding `protobuf:"varint,3,opt,name=expectedEncoding,proto3,enum=PDataEncoding" json:"expectedEncoding,omitempty"`
+}
+
+func (x *PApiEventSubscription) Reset() {
+	*x = PApiEventSubscription{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_service_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PApiEventSubscription) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PApiEventSubscription) ProtoMessage() {}
+
+func (x *PApiEventSubscription) ProtoReflect() protoreflect.Message {
+	mi := &file_api_service_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PApiEventSubscription.ProtoReflect.Descriptor instead.
+func (*PApiEventSubscription) Descriptor() ([]byte, []int) {
+	return file_api_service_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *PApiEventSubscription) GetApiAuth() *PApiAuth {
+	if x != nil {
+		return x.ApiAuth
+	}
+	return nil
+}
+
+func (x *PApiEventSubscription) GetTypes() []string {
+	if x != nil {
+		return x.Types
+	}
+	return nil
+}
+
+func (x *PApiEventSubscription) GetExpectedEncoding() PDataEncoding {
+	if x != nil {
+		return x.ExpectedEncoding
+	}
+	return PDataEncoding_JSON_DEFAULT
+}
+
+type PApiEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id        string        `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type      string        `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Timestamp int64         `protobuf:"varint,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Encoding  PDataEncoding `protobuf:"varint,4,opt,name=encoding,proto3,enum=PDataEncoding" json:"encoding,omitempty"`
+	Data      []byte        `protobuf:"bytes,5,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (x *PApiEvent) Reset() {
+	*x = PApiEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_service_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PApiEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PApiEvent) ProtoMessage() {}
+
+func (x *PApiEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_api_service_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PApiEvent.ProtoReflect.Descriptor instead.
+func (*PApiEvent) Descriptor() ([]byte, []int) {
+	return file_api_service_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *PApiEvent) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *PApiEvent) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *PApiEvent) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *PApiEvent) GetEncoding() PDataEncoding {
+	if x != nil {
+		return x.Encoding
+	}
+	return PDataEncoding_JSON_DEFAULT
+}
+
+func (x *PApiEvent) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+var File_api_service_proto protoreflect.FileDescriptor
+
+var file_api_service_proto_rawDesc = []byte{
+	0x0a, 0x11, 0x61, 0x70, 0x69, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x1a, 0x1b, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2f, 0x65, 0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x22, 0x42, 0x0a, 0x08, 0x50, 0x41, 0x70, 0x69, 0x41, 0x75, 0x74, 0x68, 0x12, 0x14, 0x0a, 0x05,
+	0x61, 0x70, 0x70, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x61, 0x70, 0x70,
+	0x49, 0x64, 0x12, 0x20, 0x0a, 0x0b, 0x61, 0x63, 0x63, 0x65, 0x73, 0x73, 0x54, 0x6f, 0x6b, 0x65,
+	0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x61, 0x63, 0x63, 0x65, 0x73, 0x73, 0x54,
+	0x6f, 0x6b, 0x65, 0x6e, 0x22, 0xa0, 0x01, 0x0a, 0x0a, 0x50, 0x41, 0x70, 0x69, 0x50, 0x61, 0x72,
+	0x61, 0x6d, 0x73, 0x12, 0x2a, 0x0a, 0x08, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x69, 0x6e, 0x67, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0e, 0x2e, 0x50, 0x44, 0x61, 0x74, 0x61, 0x45, 0x6e, 0x63,
+	0x6f, 0x64, 0x69, 0x6e, 0x67, 0x52, 0x08, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x69, 0x6e, 0x67, 0x12,
+	0x1e, 0x0a, 0x0a, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x44, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x0a, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x44, 0x61, 0x74, 0x61, 0x12,
+	0x46, 0x0a, 0x16, 0x65, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x52, 0x65, 0x74, 0x75, 0x72,
+	0x6e, 0x45, 0x6e, 0x63, 0x6f, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x0e, 0x2e, 0x50, 0x44, 0x61, 0x74, 0x61, 0x45, 0x6e, 0x63, 0x6f, 0x64, 0x69, 0x6e, 0x67, 0x52,
+	0x16, 0x65, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x52, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x45,
+	0x6e, 0x63, 0x6f, 0x64, 0x69, 0x6e, 0x67, 0x22, 0xc8, 0x01, 0x0a, 0x0a, 0x50, 0x41, 0x70, 0x69,
+	0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x18,
+	0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x2a, 0x0a, 0x08, 0x65, 0x6e, 0x63, 0x6f,
+	0x64, 0x69, 0x6e, 0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0e, 0x2e, 0x50, 0x44, 0x61,
+	0x74, 0x61, 0x45, 0x6e, 0x63, 0x6f, 0x64, 0x69, 0x6e, 0x67, 0x52, 0x08, 0x65, 0x6e, 0x63, 0x6f,
+	0x64, 0x69, 0x6e, 0x67, 0x12, 0x1e, 0x0a, 0x0a, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x44, 0x61,
+	0x74, 0x61, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x44, 0x61, 0x74, 0x61, 0x12, 0x1c, 0x0a, 0x09, 0x64, 0x65, 0x62, 0x75, 0x67, 0x44, 0x61, 0x74,
+	0x61, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x64, 0x65, 0x62, 0x75, 0x67, 0x44, 0x61,
+	0x74, 0x61, 0x12, 0x1e, 0x0a, 0x0a, 0x65, 0x78, 0x74, 0x72, 0x61, 0x73, 0x44, 0x61, 0x74, 0x61,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x65, 0x78, 0x74, 0x72, 0x61, 0x73, 0x44, 0x61,
+	0x74, 0x61, 0x22, 0x77, 0x0a, 0x0b, 0x50, 0x41, 0x70, 0x69, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x78,
+	0x74, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x70, 0x69, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x61, 0x70, 0x69, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x23, 0x0a, 0x07, 0x61,
+	0x70, 0x69, 0x41, 0x75, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x09, 0x2e, 0x50,
+	0x41, 0x70, 0x69, 0x41, 0x75, 0x74, 0x68, 0x52, 0x07, 0x61, 0x70, 0x69, 0x41, 0x75, 0x74, 0x68,
+	0x12, 0x29, 0x0a, 0x09, 0x61, 0x70, 0x69, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x50, 0x41, 0x70, 0x69, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73,
+	0x52, 0x09, 0x61, 0x70, 0x69, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x22, 0x64, 0x0a, 0x0d, 0x50,
+	0x41, 0x70, 0x69, 0x42, 0x61, 0x74, 0x63, 0x68, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x0e, 0x0a, 0x02,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x18, 0x0a, 0x07,
+	0x61, 0x70, 0x69, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x61,
+	0x70, 0x69, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x29, 0x0a, 0x09, 0x61, 0x70, 0x69, 0x50, 0x61, 0x72,
+	0x61, 0x6d, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x50, 0x41, 0x70, 0x69,
+	0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x52, 0x09, 0x61, 0x70, 0x69, 0x50, 0x61, 0x72, 0x61, 0x6d,
+	0x73, 0x22, 0xa1, 0x01, 0x0a, 0x10, 0x50, 0x41, 0x70, 0x69, 0x42, 0x61, 0x74, 0x63, 0x68, 0x43,
+	0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x12, 0x23, 0x0a, 0x07, 0x61, 0x70, 0x69, 0x41, 0x75, 0x74,
+	0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x09, 0x2e, 0x50, 0x41, 0x70, 0x69, 0x41, 0x75,
+	0x74, 0x68, 0x52, 0x07, 0x61, 0x70, 0x69, 0x41, 0x75, 0x74, 0x68, 0x12, 0x24, 0x0a, 0x05, 0x69,
+	0x74, 0x65, 0x6d, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x50, 0x41, 0x70,
+	0x69, 0x42, 0x61, 0x74, 0x63, 0x68, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x05, 0x69, 0x74, 0x65, 0x6d,
+	0x73, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x61, 0x72, 0x61, 0x6c, 0x6c, 0x65, 0x6c, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x08, 0x70, 0x61, 0x72, 0x61, 0x6c, 0x6c, 0x65, 0x6c, 0x12, 0x26, 0x0a,
+	0x0e, 0x6d, 0x61, 0x78, 0x43, 0x6f, 0x6e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0e, 0x6d, 0x61, 0x78, 0x43, 0x6f, 0x6e, 0x63, 0x75, 0x72,
+	0x72, 0x65, 0x6e, 0x63, 0x79, 0x22, 0x64, 0x0a, 0x13, 0x50, 0x41, 0x70, 0x69, 0x42, 0x61, 0x74,
+	0x63, 0x68, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x0e, 0x0a, 0x02,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x18, 0x0a, 0x07,
+	0x61, 0x70, 0x69, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x61,
+	0x70, 0x69, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x23, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x50, 0x41, 0x70, 0x69, 0x52, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x6f, 0x0a, 0x0f, 0x50,
+	0x41, 0x70, 0x69, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x16,
+	0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06,
+	0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x12, 0x2a, 0x0a, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x14, 0x2e, 0x50, 0x41, 0x70, 0x69, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x75, 0x6c,
+	0x74, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x22, 0x8e, 0x01, 0x0a,
+	0x15, 0x50, 0x41, 0x70, 0x69, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72,
+	0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x23, 0x0a, 0x07, 0x61, 0x70, 0x69, 0x41, 0x75, 0x74,
+	0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x09, 0x2e, 0x50, 0x41, 0x70, 0x69, 0x41, 0x75,
+	0x74, 0x68, 0x52, 0x07, 0x61, 0x70, 0x69, 0x41, 0x75, 0x74, 0x68, 0x12, 0x14, 0x0a, 0x05, 0x74,
+	0x79, 0x70, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x74, 0x79, 0x70, 0x65,
+	0x73, 0x12, 0x3a, 0x0a, 0x10, 0x65, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x45, 0x6e, 0x63,
+	0x6f, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0e, 0x2e, 0x50, 0x44,
+	0x61, 0x74, 0x61, 0x45, 0x6e, 0x63, 0x6f, 0x64, 0x69, 0x6e, 0x67, 0x52, 0x10, 0x65, 0x78, 0x70,
+	0x65, 0x63, 0x74, 0x65, 0x64, 0x45, 0x6e, 0x63, 0x6f, 0x64, 0x69, 0x6e, 0x67, 0x22, 0x8d, 0x01,
+	0x0a, 0x09, 0x50, 0x41, 0x70, 0x69, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x74,
+	0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12,
+	0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x2a, 0x0a,
+	0x08, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x0e, 0x2e, 0x50, 0x44, 0x61, 0x74, 0x61, 0x45, 0x6e, 0x63, 0x6f, 0x64, 0x69, 0x6e, 0x67, 0x52,
+	0x08, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x69, 0x6e, 0x67, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74,
+	0x61, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x2a, 0x41, 0x0a,
+	0x0d, 0x50, 0x44, 0x61, 0x74, 0x61, 0x45, 0x6e, 0x63, 0x6f, 0x64, 0x69, 0x6e, 0x67, 0x12, 0x10,
+	0x0a, 0x0c, 0x4a, 0x53, 0x4f, 0x4e, 0x5f, 0x44, 0x45, 0x46, 0x41, 0x55, 0x4c, 0x54, 0x10, 0x00,
+	0x12, 0x0f, 0x0a, 0x0b, 0x4a, 0x53, 0x4f, 0x4e, 0x5f, 0x53, 0x54, 0x52, 0x49, 0x4e, 0x47, 0x10,
+	0x01, 0x12, 0x0d, 0x0a, 0x09, 0x4a, 0x53, 0x4f, 0x4e, 0x5f, 0x47, 0x5a, 0x49, 0x50, 0x10, 0x02,
+	0x32, 0xf4, 0x01, 0x0a, 0x0b, 0x50, 0x41, 0x70, 0x69, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x12, 0x36, 0x0a, 0x04, 0x70, 0x69, 0x6e, 0x67, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79,
+	0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x1f, 0x0a, 0x05, 0x63, 0x68, 0x65, 0x63,
+	0x6b, 0x12, 0x09, 0x2e, 0x50, 0x41, 0x70, 0x69, 0x41, 0x75, 0x74, 0x68, 0x1a, 0x0b, 0x2e, 0x50,
+	0x41, 0x70, 0x69, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x21, 0x0a, 0x04, 0x63, 0x61, 0x6c,
+	0x6c, 0x12, 0x0c, 0x2e, 0x50, 0x41, 0x70, 0x69, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x1a,
+	0x0b, 0x2e, 0x50, 0x41, 0x70, 0x69, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x30, 0x0a, 0x09,
+	0x63, 0x61, 0x6c, 0x6c, 0x42, 0x61, 0x74, 0x63, 0x68, 0x12, 0x11, 0x2e, 0x50, 0x41, 0x70, 0x69,
+	0x42, 0x61, 0x74, 0x63, 0x68, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x1a, 0x10, 0x2e, 0x50,
+	0x41, 0x70, 0x69, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x37,
+	0x0a, 0x0f, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x73, 0x12, 0x16, 0x2e, 0x50, 0x41, 0x70, 0x69, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x53, 0x75, 0x62,
+	0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x1a, 0x0a, 0x2e, 0x50, 0x41, 0x70, 0x69,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x30, 0x01, 0x42, 0x12, 0x48, 0x01, 0x5a, 0x0e, 0x6d, 0x61, 0x69,
+	0x6e, 0x2f, 0x67, 0x72, 0x70, 0x63, 0x3b, 0x67, 0x72, 0x70, 0x63, 0x62, 0x06, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x33,
+}
+
+var (
+	file_api_service_proto_rawDescOnce sync.Once
+	file_api_service_proto_rawDescData = file_api_service_proto_rawDesc
+)
+
+func file_api_service_proto_rawDescGZIP() []byte {
+	file_api_service_proto_rawDescOnce.Do(func() {
+		file_api_service_proto_rawDescData = protoimpl.X.CompressGZIP(file_api_service_proto_rawDescData)
+	})
+	return file_api_service_proto_rawDescData
+}
+
+var file_api_service_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_api_service_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_api_service_proto_goTypes = []interface{}{
+	(PDataEncoding)(0),            // 0: PDataEncoding
+	(*PApiAuth)(nil),              // 1: PApiAuth
+	(*PApiParams)(nil),            // 2: PApiParams
+	(*PApiResult)(nil),            // 3: PApiResult
+	(*PApiContext)(nil),           // 4: PApiContext
+	(*PApiBatchItem)(nil),         // 5: PApiBatchItem
+	(*PApiBatchContext)(nil),      // 6: PApiBatchContext
+	(*PApiBatchResultItem)(nil),   // 7: PApiBatchResultItem
+	(*PApiBatchResult)(nil),       // 8: PApiBatchResult
+	(*PApiEventSubscription)(nil), // 9: PApiEventSubscription
+	(*PApiEvent)(nil),             // 10: PApiEvent
+	(*emptypb.Empty)(nil),         // 11: google.protobuf.Empty
+}
+var file_api_service_proto_depIdxs = []int32{
+	0,  // 0: PApiParams.encoding:type_name -> PDataEncoding
+	0,  // 1: PApiParams.expectedReturnEncoding:type_name -> PDataEncoding
+	0,  // 2: PApiResult.encoding:type_name -> PDataEncoding
+	1,  // 3: PApiContext.apiAuth:type_name -> PApiAuth
+	2,  // 4: PApiContext.apiParams:type_name -> PApiParams
+	2,  // 5: PApiBatchItem.apiParams:type_name -> PApiParams
+	1,  // 6: PApiBatchContext.apiAuth:type_name -> PApiAuth
+	5,  // 7: PApiBatchContext.items:type_name -> PApiBatchItem
+	3,  // 8: PApiBatchResultItem.result:type_name -> PApiResult
+	7,  // 9: PApiBatchResult.items:type_name -> PApiBatchResultItem
+	1,  // 10: PApiEventSubscription.apiAuth:type_name -> PApiAuth
+	0,  // 11: PApiEventSubscription.expectedEncoding:type_name -> PDataEncoding
+	0,  // 12: PApiEvent.encoding:type_name -> PDataEncoding
+	11, // 13: PApiService.ping:input_type -> google.protobuf.Empty
+	1,  // 14: PApiService.check:input_type -> PApiAuth
+	4,  // 15: PApiService.call:input_type -> PApiContext
+	6,  // 16: PApiService.callBatch:input_type -> PApiBatchContext
+	9,  // 17: PApiService.subscribeEvents:input_type -> PApiEventSubscription
+	11, // 18: PApiService.ping:output_type -> google.protobuf.Empty
+	3,  // 19: PApiService.check:output_type -> PApiResult
+	3,  // 20: PApiService.call:output_type -> PApiResult
+	8,  // 21: PApiService.callBatch:output_type -> PApiBatchResult
+	10, // 22: PApiService.subscribeEvents:output_type -> PApiEvent
+	18, // [18:23] is the sub-list for method output_type
+	13, // [13:18] is the sub-list for method input_type
+	13, // [13:13] is the sub-list for extension type_name
+	13, // [13:13] is the sub-list for extension extendee
+	0,  // [0:13] is the sub-list for field type_name
+}
+
+func init() { file_api_service_proto_init() }
+func file_api_service_proto_init() {
+	if File_api_service_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_api_service_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PApiAuth); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_service_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PApiParams); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_service_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PApiResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_service_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PApiContext); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_service_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PApiBatchItem); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_service_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PApiBatchContext); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_service_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PApiBatchResultItem); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_service_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PApiBatchResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_service_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PApiEventSubscription); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_service_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PApiEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_api_service_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_api_service_proto_goTypes,
+		DependencyIndexes: file_api_service_proto_depIdxs,
+		EnumInfos:         file_api_service_proto_enumTypes,
+		MessageInfos:      file_api_service_proto_msgTypes,
+	}.Build()
+	File_api_service_proto = out.File
+	file_api_service_proto_rawDesc = nil
+	file_api_service_proto_goTypes = nil
+	file_api_service_proto_depIdxs = nil
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
 var _ context.Context
-var _ grpc.ClientConn
+var _ grpc.ClientConnInterface
 
 // This is a compile-time assertion to ensure that this generated file
 // is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+const _ = grpc.SupportPackageIsVersion6
 
 // PApiServiceClient is the client API for PApiService service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
 type PApiServiceClient interface {
-	//*
-	// This method is to test if server is online.
-	Ping(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*empty.Empty, error)
-	//*
-	// This method is to test if server is online.
+	Ping(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	Check(ctx context.Context, in *PApiAuth, opts ...grpc.CallOption) (*PApiResult, error)
-	//*
-	// Invoke API call.
 	Call(ctx context.Context, in *PApiContext, opts ...grpc.CallOption) (*PApiResult, error)
+	CallBatch(ctx context.Context, in *PApiBatchContext, opts ...grpc.CallOption) (*PApiBatchResult, error)
+	SubscribeEvents(ctx context.Context, in *PApiEventSubscription, opts ...grpc.CallOption) (PApiService_SubscribeEventsClient, error)
 }
 
 type pApiServiceClient struct {
-	cc *grpc.ClientConn
+	cc grpc.ClientConnInterface
 }
 
-func NewPApiServiceClient(cc *grpc.ClientConn) PApiServiceClient {
+func NewPApiServiceClient(cc grpc.ClientConnInterface) PApiServiceClient {
 	return &pApiServiceClient{cc}
 }
 
-func (c *pApiServiceClient) Ping(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*empty.Empty, error) {
-	out := new(empty.Empty)
+func (c *pApiServiceClient) Ping(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
 	err := c.cc.Invoke(ctx, "/PApiService/ping", in, out, opts...)
 	if err != nil {
 		return nil, err
@@ -381,39 +1118,82 @@ func (c *pApiServiceClient) Call(ctx context.Context, in *PApiContext, opts ...g
 	return out, nil
 }
 
+func (c *pApiServiceClient) CallBatch(ctx context.Context, in *PApiBatchContext, opts ...grpc.CallOption) (*PApiBatchResult, error) {
+	out := new(PApiBatchResult)
+	err := c.cc.Invoke(ctx, "/PApiService/callBatch", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pApiServiceClient) SubscribeEvents(ctx context.Context, in *PApiEventSubscription, opts ...grpc.CallOption) (PApiService_SubscribeEventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_PApiService_serviceDesc.Streams[0], "/PApiService/subscribeEvents", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &pApiServiceSubscribeEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type PApiService_SubscribeEventsClient interface {
+	Recv() (*PApiEvent, error)
+	grpc.ClientStream
+}
+
+type pApiServiceSubscribeEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *pApiServiceSubscribeEventsClient) Recv() (*PApiEvent, error) {
+	m := new(PApiEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // PApiServiceServer is the server API for PApiService service.
 type PApiServiceServer interface {
-	//*
-	// This method is to test if server is online.
-	Ping(context.Context, *empty.Empty) (*empty.Empty, error)
-	//*
-	// This method is to test if server is online.
+	Ping(context.Context, *emptypb.Empty) (*emptypb.Empty, error)
 	Check(context.Context, *PApiAuth) (*PApiResult, error)
-	//*
-	// Invoke API call.
 	Call(context.Context, *PApiContext) (*PApiResult, error)
+	CallBatch(context.Context, *PApiBatchContext) (*PApiBatchResult, error)
+	SubscribeEvents(*PApiEventSubscription, PApiService_SubscribeEventsServer) error
 }
 
 // UnimplementedPApiServiceServer can be embedded to have forward compatible implementations.
 type UnimplementedPApiServiceServer struct {
 }
 
-func (*UnimplementedPApiServiceServer) Ping(ctx context.Context, req *empty.Empty) (*empty.Empty, error) {
+func (*UnimplementedPApiServiceServer) Ping(context.Context, *emptypb.Empty) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Ping not implemented")
 }
-func (*UnimplementedPApiServiceServer) Check(ctx context.Context, req *PApiAuth) (*PApiResult, error) {
+func (*UnimplementedPApiServiceServer) Check(context.Context, *PApiAuth) (*PApiResult, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Check not implemented")
 }
-func (*UnimplementedPApiServiceServer) Call(ctx context.Context, req *PApiContext) (*PApiResult, error) {
+func (*UnimplementedPApiServiceServer) Call(context.Context, *PApiContext) (*PApiResult, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Call not implemented")
 }
+func (*UnimplementedPApiServiceServer) CallBatch(context.Context, *PApiBatchContext) (*PApiBatchResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CallBatch not implemented")
+}
+func (*UnimplementedPApiServiceServer) SubscribeEvents(*PApiEventSubscription, PApiService_SubscribeEventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeEvents not implemented")
+}
 
 func RegisterPApiServiceServer(s *grpc.Server, srv PApiServiceServer) {
 	s.RegisterService(&_PApiService_serviceDesc, srv)
 }
 
 func _PApiService_Ping_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(empty.Empty)
+	in := new(emptypb.Empty)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -425,7 +1205,7 @@ func _PApiService_Ping_Handler(srv interface{}, ctx context.Context, dec func(in
 		FullMethod: "/PApiService/Ping",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(PApiServiceServer).Ping(ctx, req.(*empty.Empty))
+		return srv.(PApiServiceServer).Ping(ctx, req.(*emptypb.Empty))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -466,6 +1246,45 @@ func _PApiService_Call_Handler(srv interface{}, ctx context.Context, dec func(in
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PApiService_CallBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PApiBatchContext)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PApiServiceServer).CallBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/PApiService/CallBatch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PApiServiceServer).CallBatch(ctx, req.(*PApiBatchContext))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PApiService_SubscribeEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(PApiEventSubscription)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PApiServiceServer).SubscribeEvents(m, &pApiServiceSubscribeEventsServer{stream})
+}
+
+type PApiService_SubscribeEventsServer interface {
+	Send(*PApiEvent) error
+	grpc.ServerStream
+}
+
+type pApiServiceSubscribeEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *pApiServiceSubscribeEventsServer) Send(m *PApiEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _PApiService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "PApiService",
 	HandlerType: (*PApiServiceServer)(nil),
@@ -482,7 +1301,17 @@ var _PApiService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "call",
 			Handler:    _PApiService_Call_Handler,
 		},
+		{
+			MethodName: "callBatch",
+			Handler:    _PApiService_CallBatch_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "subscribeEvents",
+			Handler:       _PApiService_SubscribeEvents_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "api_service.proto",
 }
@@ -0,0 +1,65 @@
+package goapi
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	pb "main/grpc"
+	"main/src/itineris"
+)
+
+func TestParseGrpcContext(t *testing.T) {
+	name := "TestParseGrpcContext"
+	oldAppId, oldAccessToken := httpHeaderAppId, httpHeaderAccessToken
+	defer func() { httpHeaderAppId, httpHeaderAccessToken = oldAppId, oldAccessToken }()
+	httpHeaderAppId, httpHeaderAccessToken = "X-App-Id", "X-Access-Token"
+	gctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 1234}})
+	gctx = metadata.NewIncomingContext(gctx, metadata.Pairs(
+		"x-locale", "vi", "x-app-id", "app", "x-access-token", "token",
+		"idempotency-key", "key", "x-forwarded-for", "1.2.3.4, 10.0.0.1", "other", "ignored"))
+	ctx := _parseGrpcContext(gctx, "myApi")
+	if ctx.GetApiName() != "myApi" || ctx.GetGateway() != "GRPC" {
+		t.Fatalf("%s failed: unexpected context %#v", name, ctx.GetAllContextValues())
+	}
+	expected := map[string]interface{}{
+		itineris.CtxClientAddr:     "10.0.0.1:1234",
+		itineris.CtxClientRealAddr: "1.2.3.4",
+		itineris.CtxLocale:         "vi",
+		itineris.CtxIdempotencyKey: "key",
+		"app-id":                   nil,
+		"access-token":             nil,
+		"other":                    nil,
+		itineris.CtxClientCertCN:   nil,
+	}
+	for k, v := range expected {
+		if ctx.GetContextValue(k) != v {
+			t.Fatalf("%s failed: expected %#v for key %#v but received %#v", name, v, k, ctx.GetContextValue(k))
+		}
+	}
+
+	ctx = _parseGrpcContext(context.Background(), "myApi")
+	if ctx.GetContextValue(itineris.CtxClientAddr) != nil || ctx.GetGateway() != "GRPC" {
+		t.Fatalf("%s failed: unexpected context %#v", name, ctx.GetAllContextValues())
+	}
+}
+
+func TestExpectedResultEncoding(t *testing.T) {
+	name := "TestExpectedResultEncoding"
+	testCases := []struct {
+		params   *pb.PApiParams
+		expected pb.PDataEncoding
+	}{
+		{nil, pb.PDataEncoding_JSON_STRING},
+		{&pb.PApiParams{}, pb.PDataEncoding_JSON_STRING},
+		{&pb.PApiParams{Encoding: pb.PDataEncoding_JSON_GZIP}, pb.PDataEncoding_JSON_GZIP},
+		{&pb.PApiParams{Encoding: pb.PDataEncoding_JSON_GZIP, ExpectedReturnEncoding: pb.PDataEncoding_JSON_STRING}, pb.PDataEncoding_JSON_STRING},
+	}
+	for _, tc := range testCases {
+		if v := expectedResultEncoding(tc.params); v != tc.expected {
+			t.Fatalf("%s failed: expected %#v but received %#v", name, tc.expected, v)
+		}
+	}
+}
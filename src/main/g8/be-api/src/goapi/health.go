@@ -0,0 +1,324 @@
+package goapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// Health statuses reported by health checks.
+//
+// @since template-v0.5.0
+const (
+	HealthStatusUp   = "UP"
+	HealthStatusDown = "DOWN"
+)
+
+// HealthCheck probes a dependency of the application (e.g. database, remote service), returning nil if the dependency is healthy.
+//
+// ctx carries the deadline of the probe; checks that ignore ctx are abandoned (and reported as failed) when the deadline passes.
+//
+// @since template-v0.5.0
+type HealthCheck func(ctx context.Context) error
+
+type healthCheckEntry struct {
+	name     string
+	critical bool
+	check    HealthCheck
+}
+
+// HealthCheckResult is the outcome of a single health check.
+//
+// @since template-v0.5.0
+type HealthCheckResult struct {
+	Name     string
+	Status   string
+	Critical bool
+	Error    string
+	Duration time.Duration
+}
+
+// ToMap exports the result to a map.
+func (r *HealthCheckResult) ToMap() map[string]interface{} {
+	result := map[string]interface{}{"status": r.Status, "critical": r.Critical, "duration_ms": r.Duration.Milliseconds()}
+	if r.Error != "" {
+		result["error"] = r.Error
+	}
+	return result
+}
+
+// HealthReport aggregates results of health checks: status is HealthStatusUp only if all critical checks pass.
+//
+// @since template-v0.5.0
+type HealthReport struct {
+	Status string
+	Checks []*HealthCheckResult
+}
+
+// ToMap exports the report to a map.
+func (r *HealthReport) ToMap() map[string]interface{} {
+	checks := make(map[string]interface{}, len(r.Checks))
+	for _, c := range r.Checks {
+		checks[c.Name] = c.ToMap()
+	}
+	return map[string]interface{}{"status": r.Status, "checks": checks}
+}
+
+// HealthRegistry holds health checks registered by bootstrappers.
+//
+// @since template-v0.5.0
+type HealthRegistry struct {
+	lock    sync.RWMutex
+	checks  map[string]*healthCheckEntry
+	timeout time.Duration
+}
+
+// NewHealthRegistry creates a new HealthRegistry instance, each check is given at most timeout to complete.
+func NewHealthRegistry(timeout time.Duration) *HealthRegistry {
+	if timeout <= 0 {
+		timeout = 2 * time.Second
+	}
+	return &HealthRegistry{checks: make(map[string]*healthCheckEntry), timeout: timeout}
+}
+
+// Register adds (or replaces) a health check. Failure of a non-critical check is reported but does not make the application unhealthy.
+func (hr *HealthRegistry) Register(name string, critical bool, check HealthCheck) *HealthRegistry {
+	hr.lock.Lock()
+	defer hr.lock.Unlock()
+	hr.checks[name] = &healthCheckEntry{name: name, critical: critical, check: check}
+	return hr
+}
+
+// Unregister removes a health check.
+func (hr *HealthRegistry) Unregister(name string) *HealthRegistry {
+	hr.lock.Lock()
+	defer hr.lock.Unlock()
+	delete(hr.checks, name)
+	return hr
+}
+
+// Names returns the sorted names of registered health checks.
+func (hr *HealthRegistry) Names() []string {
+	hr.lock.RLock()
+	defer hr.lock.RUnlock()
+	names := make([]string, 0, len(hr.checks))
+	for name := range hr.checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Has checks if a health check has been registered.
+func (hr *HealthRegistry) Has(name string) bool {
+	hr.lock.RLock()
+	defer hr.lock.RUnlock()
+	return hr.checks[name] != nil
+}
+
+func (hr *HealthRegistry) runCheck(ctx context.Context, entry *healthCheckEntry) *HealthCheckResult {
+	ctx, cancel := context.WithTimeout(ctx, hr.timeout)
+	defer cancel()
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("panic: %v", r)
+			}
+		}()
+		done <- entry.check(ctx)
+	}()
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	result := &HealthCheckResult{Name: entry.name, Status: HealthStatusUp, Critical: entry.critical, Duration: time.Since(start)}
+	if err != nil {
+		result.Status = HealthStatusDown
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// Check runs the specified health checks (all registered checks if names is empty) concurrently and aggregates their results.
+// Unknown names are reported as failed critical checks.
+func (hr *HealthRegistry) Check(ctx context.Context, names ...string) *HealthReport {
+	if len(names) == 0 {
+		names = hr.Names()
+	}
+	hr.lock.RLock()
+	entries := make([]*healthCheckEntry, len(names))
+	for i, name := range names {
+		entries[i] = hr.checks[name]
+		if entries[i] == nil {
+			entries[i] = &healthCheckEntry{name: name, critical: true, check: func(_ context.Context) error {
+				return errors.New("health check not found")
+			}}
+		}
+	}
+	hr.lock.RUnlock()
+
+	report := &HealthReport{Status: HealthStatusUp, Checks: make([]*HealthCheckResult, len(entries))}
+	var wg sync.WaitGroup
+	for i, entry := range entries {
+		wg.Add(1)
+		go func(i int, entry *healthCheckEntry) {
+			defer wg.Done()
+			report.Checks[i] = hr.runCheck(ctx, entry)
+		}(i, entry)
+	}
+	wg.Wait()
+	for _, r := range report.Checks {
+		if r.Critical && r.Status != HealthStatusUp {
+			report.Status = HealthStatusDown
+		}
+	}
+	return report
+}
+
+/*----------------------------------------------------------------------*/
+
+var (
+	// Health is the application's health registry, bootstrappers register their health checks here.
+	//
+	// @since template-v0.5.0
+	Health = NewHealthRegistry(2 * time.Second)
+
+	healthEnabled       = true
+	healthLivenessUri   = "/healthz"
+	healthReadinessUri  = "/readyz"
+	healthWatchInterval = 5 * time.Second
+)
+
+// initHealth loads settings from config keys "api.health.*".
+//
+// @since template-v0.5.0
+func initHealth() {
+	healthEnabled = AppConfig().GetBoolean("api.health.enabled", true)
+	healthLivenessUri = AppConfig().GetString("api.health.liveness_uri", "/healthz")
+	healthReadinessUri = AppConfig().GetString("api.health.readiness_uri", "/readyz")
+	if interval := AppConfig().GetTimeDuration("api.health.watch_interval", 5*time.Second); interval > 0 {
+		healthWatchInterval = interval
+	}
+	Health.timeout = AppConfig().GetTimeDuration("api.health.timeout", 2*time.Second)
+	if Health.timeout <= 0 {
+		Health.timeout = 2 * time.Second
+	}
+}
+
+// checkReadiness reports the application as not ready while it is starting up or shutting down, without running health checks.
+// When a single check is requested, the report's status is the check's own status, regardless of whether the check is critical.
+func checkReadiness(ctx context.Context, names ...string) *HealthReport {
+	if !IsReady() {
+		return &HealthReport{Status: HealthStatusDown, Checks: []*HealthCheckResult{}}
+	}
+	report := Health.Check(ctx, names...)
+	if len(names) == 1 && len(report.Checks) == 1 {
+		report.Status = report.Checks[0].Status
+	}
+	return report
+}
+
+// apiLivenessHandler reports that the process is alive; it does not probe dependencies, so that a failing dependency does not cause restarts.
+//
+// @since template-v0.5.0
+func apiLivenessHandler(c echo.Context) error {
+	return c.JSON(http.StatusOK, map[string]interface{}{"status": HealthStatusUp, "ready": IsReady()})
+}
+
+// apiReadinessHandler runs all health checks (or the one specified by path param "check") and reports aggregated and per-check status.
+// Response status is 200 if the application is ready, 503 otherwise.
+//
+// @since template-v0.5.0
+func apiReadinessHandler(c echo.Context) error {
+	var names []string
+	if name := c.Param("check"); name != "" {
+		if !Health.Has(name) {
+			return c.JSON(http.StatusNotFound, map[string]interface{}{"status": HealthStatusDown, "error": "health check [" + name + "] not found"})
+		}
+		names = []string{name}
+	}
+	report := checkReadiness(c.Request().Context(), names...)
+	httpStatus := http.StatusOK
+	if report.Status != HealthStatusUp {
+		httpStatus = http.StatusServiceUnavailable
+	}
+	result := report.ToMap()
+	result["ready"] = IsReady()
+	return c.JSON(httpStatus, result)
+}
+
+// grpcHealthServer implements the standard gRPC health service (grpc.health.v1.Health) on top of the health registry:
+// service "" denotes the application's readiness, other service names denote individual health checks.
+//
+// @since template-v0.5.0
+type grpcHealthServer struct {
+	healthpb.UnimplementedHealthServer
+}
+
+func (s *grpcHealthServer) servingStatus(ctx context.Context, service string) (healthpb.HealthCheckResponse_ServingStatus, error) {
+	var names []string
+	if service != "" {
+		if !Health.Has(service) {
+			return healthpb.HealthCheckResponse_SERVICE_UNKNOWN, status.Error(codes.NotFound, "unknown service")
+		}
+		names = []string{service}
+	}
+	if checkReadiness(ctx, names...).Status == HealthStatusUp {
+		return healthpb.HealthCheckResponse_SERVING, nil
+	}
+	return healthpb.HealthCheckResponse_NOT_SERVING, nil
+}
+
+// Check implements healthpb.HealthServer.Check
+func (s *grpcHealthServer) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	servingStatus, err := s.servingStatus(ctx, req.GetService())
+	if err != nil {
+		return nil, err
+	}
+	return &healthpb.HealthCheckResponse{Status: servingStatus}, nil
+}
+
+// Watch implements healthpb.HealthServer.Watch: health checks are re-evaluated every "api.health.watch_interval" and client is notified when the status changes.
+func (s *grpcHealthServer) Watch(req *healthpb.HealthCheckRequest, stream healthpb.Health_WatchServer) error {
+	last := healthpb.HealthCheckResponse_ServingStatus(-1)
+	for {
+		servingStatus, _ := s.servingStatus(stream.Context(), req.GetService())
+		if servingStatus != last {
+			if err := stream.Send(&healthpb.HealthCheckResponse{Status: servingStatus}); err != nil {
+				return err
+			}
+			last = servingStatus
+		}
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-stoppingCh:
+			if last != healthpb.HealthCheckResponse_NOT_SERVING {
+				stream.Send(&healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_NOT_SERVING})
+			}
+			return nil
+		case <-time.After(healthWatchInterval):
+		}
+	}
+}
+
+// registerGrpcHealthServer registers the standard gRPC health service with the gRPC server.
+func registerGrpcHealthServer(server *grpc.Server) {
+	if healthEnabled {
+		healthpb.RegisterHealthServer(server, &grpcHealthServer{})
+	}
+}
@@ -0,0 +1,108 @@
+package goapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestHealthRegistry_Check(t *testing.T) {
+	name := "TestHealthRegistry_Check"
+	hr := NewHealthRegistry(100 * time.Millisecond)
+	hr.Register("ok", true, func(_ context.Context) error { return nil })
+	hr.Register("optional", false, func(_ context.Context) error { return errors.New("unreachable") })
+	report := hr.Check(context.Background())
+	if report.Status != HealthStatusUp || len(report.Checks) != 2 {
+		t.Fatalf("%s failed: unexpected report %#v", name, report)
+	}
+	if r := report.Checks[1]; r.Name != "optional" || r.Status != HealthStatusDown || r.Error != "unreachable" {
+		t.Fatalf("%s failed: unexpected result %#v", name, r)
+	}
+
+	hr.Register("slow", true, func(_ context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	})
+	hr.Register("panic", true, func(_ context.Context) error { panic("boom") })
+	for _, check := range []string{"slow", "panic", "notfound"} {
+		if report := hr.Check(context.Background(), check); report.Status != HealthStatusDown || report.Checks[0].Error == "" {
+			t.Fatalf("%s failed: check [%s] should fail, received %#v", name, check, report.Checks[0])
+		}
+	}
+	if report := hr.Check(context.Background(), "ok"); report.Status != HealthStatusUp || len(report.Checks) != 1 {
+		t.Fatalf("%s failed: unexpected report %#v", name, report)
+	}
+}
+
+func TestHealthEndpoints(t *testing.T) {
+	name := "TestHealthEndpoints"
+	oldHealth := Health
+	defer func() { Health = oldHealth; setReady(false) }()
+	Health = NewHealthRegistry(time.Second)
+	dbUp := true
+	Health.Register("optional", false, func(_ context.Context) error { return errors.New("unreachable") })
+	Health.Register("database", true, func(_ context.Context) error {
+		if !dbUp {
+			return errors.New("connection refused")
+		}
+		return nil
+	})
+
+	e := echo.New()
+	e.GET("/healthz", apiLivenessHandler)
+	e.GET("/readyz", apiReadinessHandler)
+	e.GET("/readyz/:check", apiReadinessHandler)
+	call := func(uri string) (int, map[string]interface{}) {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, uri, nil))
+		body := map[string]interface{}{}
+		json.Unmarshal(rec.Body.Bytes(), &body)
+		return rec.Code, body
+	}
+
+	setReady(false)
+	if code, _ := call("/healthz"); code != http.StatusOK {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, http.StatusOK, code)
+	}
+	if code, _ := call("/readyz"); code != http.StatusServiceUnavailable {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, http.StatusServiceUnavailable, code)
+	}
+
+	setReady(true)
+	code, body := call("/readyz")
+	checks, _ := body["checks"].(map[string]interface{})
+	if code != http.StatusOK || body["status"] != HealthStatusUp || checks["database"] == nil {
+		t.Fatalf("%s failed: unexpected response %d/%#v", name, code, body)
+	}
+	dbUp = false
+	code, body = call("/readyz/database")
+	checks, _ = body["checks"].(map[string]interface{})
+	if db, _ := checks["database"].(map[string]interface{}); code != http.StatusServiceUnavailable || db["error"] != "connection refused" {
+		t.Fatalf("%s failed: unexpected response %d/%#v", name, code, body)
+	}
+	if code, _ := call("/readyz/optional"); code != http.StatusServiceUnavailable {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, http.StatusServiceUnavailable, code)
+	}
+	if code, _ := call("/readyz/notfound"); code != http.StatusNotFound {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, http.StatusNotFound, code)
+	}
+
+	server := &grpcHealthServer{}
+	if resp, err := server.Check(context.Background(), &healthpb.HealthCheckRequest{}); err != nil || resp.Status != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Fatalf("%s failed: unexpected response %#v/%s", name, resp, err)
+	}
+	dbUp = true
+	if resp, err := server.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "database"}); err != nil || resp.Status != healthpb.HealthCheckResponse_SERVING {
+		t.Fatalf("%s failed: unexpected response %#v/%s", name, resp, err)
+	}
+	if _, err := server.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "notfound"}); err == nil {
+		t.Fatalf("%s failed: unknown service should fail", name)
+	}
+}
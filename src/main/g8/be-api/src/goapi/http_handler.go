@@ -1,10 +1,12 @@
 package goapi
 
 import (
-	"log"
-	"net/http"
+	"fmt"
 	"strings"
+	"sync"
+	"sync/atomic"
 
+	hocon "github.com/go-akka/configuration"
 	"github.com/labstack/echo/v4"
 	"main/src/itineris"
 )
@@ -12,16 +14,64 @@ import (
 var (
 	// mapping uri -> {http_method -> api_name}
 	httpRoutingMap        = map[string]map[string]string{}
+	httpRoutingLock       sync.RWMutex
 	httpHeaderAppId       string
 	httpHeaderAccessToken string
+
+	// the echo instance currently serving HTTP requests (see buildEchoHandler)
+	echoHandler atomic.Value
 )
 
-func registerHttpHandler(uri, httpMethod, apiName string) {
-	_, ok := httpRoutingMap[uri]
+const httpHeaderIdempotencyKey = "Idempotency-Key"
+
+func addHttpRoute(routingMap map[string]map[string]string, uri, httpMethod, apiName string) {
+	_, ok := routingMap[uri]
 	if !ok {
-		httpRoutingMap[uri] = map[string]string{}
+		routingMap[uri] = map[string]string{}
 	}
-	httpRoutingMap[uri][strings.ToUpper(httpMethod)] = apiName
+	routingMap[uri][strings.ToUpper(httpMethod)] = apiName
+}
+
+func registerHttpHandler(uri, httpMethod, apiName string) {
+	httpRoutingLock.Lock()
+	defer httpRoutingLock.Unlock()
+	addHttpRoute(httpRoutingMap, uri, httpMethod, apiName)
+}
+
+// lookupHttpRoute returns name of the API mapped to the uri pattern and http method, or empty string if there is no such mapping.
+func lookupHttpRoute(uriPattern, httpMethod string) string {
+	httpRoutingLock.RLock()
+	defer httpRoutingLock.RUnlock()
+	return httpRoutingMap[uriPattern][strings.ToUpper(httpMethod)]
+}
+
+// setEchoHandler replaces the echo instance serving HTTP requests and the mapping of API endpoints.
+//
+// @since template-v0.5.0
+func setEchoHandler(e *echo.Echo, routingMap map[string]map[string]string) {
+	httpRoutingLock.Lock()
+	defer httpRoutingLock.Unlock()
+	httpRoutingMap = routingMap
+	echoHandler.Store(e)
+}
+
+func currentEchoHandler() *echo.Echo {
+	return echoHandler.Load().(*echo.Echo)
+}
+
+// reloadEchoHandler rebuilds the echo instance (CORS, body limit, API endpoints) from the reloaded configurations.
+// In-flight requests are completed by the previous instance.
+//
+// @since template-v0.5.0
+func reloadEchoHandler(_ *hocon.Config) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("cannot build HTTP handler: %v", r)
+		}
+	}()
+	e, routingMap := buildEchoHandler()
+	setEchoHandler(e, routingMap)
+	return nil
 }
 
 func _parseRequest(apiName string, c echo.Context) (*itineris.ApiContext, *itineris.ApiAuth, *itineris.ApiParams) {
@@ -39,6 +89,10 @@ func _parseRequest(apiName string, c echo.Context) (*itineris.ApiContext, *itine
 			}
 		}
 	}
+	// standard "Idempotency-Key" header is surfaced the same way as "X-Idempotency-Key"
+	if v := c.Request().Header.Get(httpHeaderIdempotencyKey); v != "" {
+		ctx.SetContextValue(itineris.CtxIdempotencyKey, v)
+	}
 
 	auth := itineris.NewApiAuth(c.Request().Header.Get(httpHeaderAppId), c.Request().Header.Get(httpHeaderAccessToken))
 
@@ -47,8 +101,7 @@ func _parseRequest(apiName string, c echo.Context) (*itineris.ApiContext, *itine
 	if !strings.EqualFold("GET", httpMethod) && !strings.EqualFold("HEAD", httpMethod) {
 		requestBodyData := map[string]interface{}{}
 		if err := c.Bind(&requestBodyData); err != nil {
-			log.Printf("Error while parsing request body as Json: " + err.Error())
-			log.Printf("Request: " + ctx.ToJsonString())
+			itineris.ContextLogger(nil, ctx).WithField("request", ctx.ToJsonString()).Warnf("Error while parsing request body as Json: %s", err)
 		} else {
 			for k, v := range requestBodyData {
 				params.SetParam(k, v)
@@ -72,18 +125,12 @@ func _parseRequest(apiName string, c echo.Context) (*itineris.ApiContext, *itine
 
 // Handle API request via HTTP.
 func apiHttpHandler(c echo.Context) error {
-	uriPattern := c.Path()
-	if _, ok := httpRoutingMap[uriPattern]; !ok {
-		return c.JSON(http.StatusOK, itineris.ResultNotImplemented.ToMap())
+	apiName := lookupHttpRoute(c.Path(), c.Request().Method)
+	if apiName == "" {
+		return writeApiResult(c, nil, itineris.ResultNotImplemented)
 	}
-	httpMethod := strings.ToUpper(c.Request().Method)
-	if _, ok := httpRoutingMap[uriPattern][httpMethod]; !ok {
-		return c.JSON(http.StatusOK, itineris.ResultNotImplemented.ToMap())
-	}
-
-	apiName := httpRoutingMap[uriPattern][httpMethod]
 	ctx, auth, params := _parseRequest(apiName, c)
 
 	apiResult := ApiRouter.CallApi(ctx, auth, params)
-	return c.JSON(http.StatusOK, apiResult.ToMap())
+	return writeApiResult(c, ctx, apiResult)
 }
@@ -0,0 +1,156 @@
+package goapi
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"main/src/itineris"
+	"main/src/logging"
+)
+
+// httpResponseMode controls how API results are rendered by the HTTP gateway.
+//
+// @since template-v0.5.0
+type httpResponseMode int
+
+const (
+	// respModeLegacy: always responds HTTP 200, API result's status is in the response body (the legacy envelope).
+	respModeLegacy httpResponseMode = iota
+
+	// respModeStatus: HTTP status code is mapped from API result's status, response body is the legacy envelope.
+	respModeStatus
+
+	// respModeProblem: same as respModeStatus, but errors are rendered as RFC 7807 "application/problem+json".
+	respModeProblem
+)
+
+const (
+	// MimeProblemJson is the content type of RFC 7807 problem details.
+	MimeProblemJson = "application/problem+json"
+
+	// ctxResponseMode is the context attribute surfaced from header "X-Response-Mode", which lets client choose the response mode.
+	ctxResponseMode = "response-mode"
+)
+
+var (
+	httpRespMode              = respModeLegacy
+	httpRespAllowClientChoice = true
+
+	// mapping API result's status -> HTTP status code
+	httpStatusMap = map[int]int{
+		itineris.StatusOk:             http.StatusOK,
+		itineris.StatusErrorClient:    http.StatusBadRequest,
+		itineris.StatusNoPermission:   http.StatusForbidden,
+		itineris.StatusNotFound:       http.StatusNotFound,
+		itineris.StatusConflict:       http.StatusConflict,
+		itineris.StatusDeprecated:     http.StatusGone,
+		itineris.StatusErrorServer:    http.StatusInternalServerError,
+		itineris.StatusNotImplemented: http.StatusNotImplemented,
+	}
+)
+
+func parseHttpResponseMode(mode string) (httpResponseMode, bool) {
+	switch strings.ToLower(strings.TrimSpace(mode)) {
+	case "legacy", "":
+		return respModeLegacy, true
+	case "status":
+		return respModeStatus, true
+	case "problem":
+		return respModeProblem, true
+	}
+	return respModeLegacy, false
+}
+
+// initHttpResponse loads settings from config keys "api.http.response.*".
+//
+// @since template-v0.5.0
+func initHttpResponse() {
+	modeStr := AppConfig().GetString("api.http.response.mode", "legacy")
+	if mode, ok := parseHttpResponseMode(modeStr); ok {
+		httpRespMode = mode
+	} else {
+		logging.Warnf("Invalid [api.http.response.mode]: %s, fallback to legacy", modeStr)
+	}
+	httpRespAllowClientChoice = AppConfig().GetBoolean("api.http.response.allow_client_choice", true)
+	if confV := AppConfig().GetValue("api.http.response.status_map"); confV != nil && confV.IsObject() {
+		for k, v := range confV.GetObject().Items() {
+			apiStatus, err1 := strconv.Atoi(k)
+			httpStatus, err2 := strconv.Atoi(v.GetString())
+			if err1 != nil || err2 != nil || httpStatus < 100 || httpStatus > 599 {
+				logging.Warnf("Invalid mapping at [api.http.response.status_map]: %s -> %s", k, v.GetString())
+				continue
+			}
+			httpStatusMap[apiStatus] = httpStatus
+		}
+	}
+}
+
+// toHttpStatus maps API result's status to HTTP status code.
+//
+// Unmapped statuses that are valid HTTP status codes are used as-is; others are mapped by their class (2xx -> 200, 4xx -> 400, otherwise 500).
+func toHttpStatus(apiStatus int) int {
+	if httpStatus, ok := httpStatusMap[apiStatus]; ok {
+		return httpStatus
+	}
+	if http.StatusText(apiStatus) != "" {
+		return apiStatus
+	}
+	switch {
+	case apiStatus >= 200 && apiStatus < 300:
+		return http.StatusOK
+	case apiStatus >= 400 && apiStatus < 500:
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// toProblemDetails renders an API result as RFC 7807 problem details. API result's "data" and "extras" are kept as extension members.
+func toProblemDetails(ctx *itineris.ApiContext, httpStatus int, apiResult *itineris.ApiResult) map[string]interface{} {
+	problem := map[string]interface{}{
+		"type":   "about:blank",
+		"title":  http.StatusText(httpStatus),
+		"status": httpStatus,
+	}
+	if apiResult.Message != "" {
+		problem["detail"] = apiResult.Message
+	}
+	if ctx != nil {
+		problem["instance"] = ctx.GetContextValueAsString(itineris.CtxHttpRequestUrl)
+		problem["request_id"] = ctx.GetId()
+	}
+	if apiResult.Status != httpStatus {
+		problem["api_status"] = apiResult.Status
+	}
+	if apiResult.Data != nil {
+		problem["data"] = apiResult.Data
+	}
+	if len(apiResult.Extras) > 0 {
+		problem["extras"] = apiResult.Extras
+	}
+	return problem
+}
+
+// writeApiResult renders the API result to the HTTP response according to the configured (or client-chosen) response mode.
+//
+// @since template-v0.5.0
+func writeApiResult(c echo.Context, ctx *itineris.ApiContext, apiResult *itineris.ApiResult) error {
+	mode := httpRespMode
+	if httpRespAllowClientChoice && ctx != nil {
+		if v := ctx.GetContextValueAsString(ctxResponseMode); v != "" {
+			if m, ok := parseHttpResponseMode(v); ok {
+				mode = m
+			}
+		}
+	}
+	if mode == respModeLegacy {
+		return c.JSON(http.StatusOK, apiResult.ToMap())
+	}
+	httpStatus := toHttpStatus(apiResult.Status)
+	if mode == respModeProblem && httpStatus >= 400 {
+		c.Response().Header().Set(echo.HeaderContentType, MimeProblemJson)
+		return c.JSON(httpStatus, toProblemDetails(ctx, httpStatus, apiResult))
+	}
+	return c.JSON(httpStatus, apiResult.ToMap())
+}
@@ -0,0 +1,53 @@
+package goapi
+
+import (
+	"net/http"
+	"testing"
+
+	"main/src/itineris"
+)
+
+func TestToHttpStatus(t *testing.T) {
+	name := "TestToHttpStatus"
+	testCases := map[int]int{
+		itineris.StatusOk:             http.StatusOK,
+		itineris.StatusErrorClient:    http.StatusBadRequest,
+		itineris.StatusNoPermission:   http.StatusForbidden,
+		itineris.StatusNotFound:       http.StatusNotFound,
+		itineris.StatusConflict:       http.StatusConflict,
+		itineris.StatusDeprecated:     http.StatusGone,
+		itineris.StatusErrorServer:    http.StatusInternalServerError,
+		itineris.StatusNotImplemented: http.StatusNotImplemented,
+		http.StatusTooManyRequests:    http.StatusTooManyRequests,
+		299:                           http.StatusOK,
+		499:                           http.StatusBadRequest,
+		0:                             http.StatusInternalServerError,
+	}
+	for input, expected := range testCases {
+		if v := toHttpStatus(input); v != expected {
+			t.Fatalf("%s failed: expected %#v for %#v but received %#v", name, expected, input, v)
+		}
+	}
+}
+
+func TestToProblemDetails(t *testing.T) {
+	name := "TestToProblemDetails"
+	ctx := itineris.NewApiContext().SetContextValue(itineris.CtxHttpRequestUrl, "/api/post/1")
+	result := itineris.NewApiResult(itineris.StatusNotFound).SetMessage("not found").AddExtraInfo("key", "value")
+	problem := toProblemDetails(ctx, http.StatusNotFound, result)
+	expected := map[string]interface{}{
+		"type": "about:blank", "title": "Not Found", "status": http.StatusNotFound, "detail": "not found",
+		"instance": "/api/post/1", "request_id": ctx.GetId(),
+	}
+	for k, v := range expected {
+		if problem[k] != v {
+			t.Fatalf("%s failed: expected %#v for field %s but received %#v", name, v, k, problem[k])
+		}
+	}
+	if _, ok := problem["api_status"]; ok {
+		t.Fatalf("%s failed: field api_status should be omitted when it equals HTTP status", name)
+	}
+	if extras, ok := problem["extras"].(map[string]interface{}); !ok || extras["key"] != "value" {
+		t.Fatalf("%s failed: extras should be kept, received %#v", name, problem["extras"])
+	}
+}
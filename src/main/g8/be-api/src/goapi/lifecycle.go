@@ -0,0 +1,232 @@
+package goapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+	"main/src/logging"
+)
+
+// IShutdownHook is implemented by bootstrappers that need to release resources (e.g. close database connections) when the application shuts down.
+//
+// Shutdown hooks of bootstrappers are called in reverse order of bootstrapping, after HTTP/gRPC servers have been drained
+// and background workers have been stopped. ctx carries the deadline of the shutdown (see config key "api.shutdown.timeout").
+//
+// @since template-v0.5.0
+type IShutdownHook interface {
+	Shutdown(ctx context.Context) error
+}
+
+type namedShutdownHook struct {
+	name string
+	hook func(ctx context.Context) error
+}
+
+var (
+	appCtx, appCancel = context.WithCancel(context.Background())
+	backgroundWorkers sync.WaitGroup
+	ready             int32
+	shutdownHooks     []namedShutdownHook
+	shutdownHooksLock sync.Mutex
+	shutdownCh        = make(chan error, 1)
+	stoppingCh        = make(chan struct{}) // closed when the shutdown sequence starts
+	stoppingOnce      sync.Once
+
+	httpServer *http.Server
+	grpcServer *grpc.Server
+)
+
+// AppContext returns the application-wide context, which is cancelled when the application is shutting down.
+//
+// @since template-v0.5.0
+func AppContext() context.Context {
+	return appCtx
+}
+
+// IsReady returns true if the application is serving requests, false if it is starting up or shutting down.
+//
+// @since template-v0.5.0
+func IsReady() bool {
+	return atomic.LoadInt32(&ready) != 0
+}
+
+func setReady(value bool) {
+	if value {
+		atomic.StoreInt32(&ready, 1)
+	} else {
+		atomic.StoreInt32(&ready, 0)
+	}
+}
+
+// GoBackground runs a background worker in its own goroutine. The worker should return when ctx is done (see AppContext);
+// the application waits for running workers to finish before calling shutdown hooks.
+//
+// @since template-v0.5.0
+func GoBackground(name string, worker func(ctx context.Context)) {
+	backgroundWorkers.Add(1)
+	go func() {
+		defer backgroundWorkers.Done()
+		defer func() {
+			if r := recover(); r != nil {
+				logging.Errorf("Background worker [%s] panicked: %v", name, r)
+			}
+		}()
+		worker(appCtx)
+	}()
+}
+
+// RegisterShutdownHook registers a function to be called when the application shuts down.
+// Hooks are called in reverse order of registration, after shutdown hooks of bootstrappers.
+//
+// @since template-v0.5.0
+func RegisterShutdownHook(name string, hook func(ctx context.Context) error) {
+	shutdownHooksLock.Lock()
+	defer shutdownHooksLock.Unlock()
+	shutdownHooks = append(shutdownHooks, namedShutdownHook{name: name, hook: hook})
+}
+
+// RequestShutdown asks the application to shut down gracefully, as if it received SIGTERM.
+// A non-nil err denotes an abnormal shutdown, the process exits with non-zero code.
+//
+// @since template-v0.5.0
+func RequestShutdown(err error) {
+	select {
+	case shutdownCh <- err:
+	default:
+	}
+}
+
+// waitForShutdown blocks until the application receives SIGINT/SIGTERM or RequestShutdown is called, then shuts down gracefully.
+func waitForShutdown(bootstrappers []IBootstrapper) error {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(signals)
+	var cause error
+	select {
+	case sig := <-signals:
+		logging.Infof("Received signal [%s], shutting down...", sig)
+	case cause = <-shutdownCh:
+		if cause != nil {
+			logging.Errorf("Shutting down: %s", cause)
+		} else {
+			logging.Infof("Shutdown requested, shutting down...")
+		}
+	}
+	shutdown(bootstrappers)
+	return cause
+}
+
+// shutdown performs the shutdown sequence, settings are loaded from config keys "api.shutdown.*":
+//   - flip readiness so that load balancers stop routing new requests, then wait for "api.shutdown.delay"
+//   - end event streams (SSE, gRPC) and WebSocket connections after their in-flight API calls have completed
+//   - drain HTTP and gRPC servers
+//   - cancel AppContext and wait for background workers
+//   - call shutdown hooks of bootstrappers (reverse order), then hooks registered via RegisterShutdownHook (reverse order)
+//
+// The whole sequence is bounded by "api.shutdown.timeout", after which remaining connections are closed forcibly.
+func shutdown(bootstrappers []IBootstrapper) {
+	setReady(false)
+	stoppingOnce.Do(func() { close(stoppingCh) })
+	timeout := AppConfig().GetTimeDuration("api.shutdown.timeout", 30*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if delay := AppConfig().GetTimeDuration("api.shutdown.delay", time.Duration(0)); delay > 0 {
+		logging.Infof("Waiting %s before draining servers...", delay)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+		}
+	}
+
+	if EventBus != nil {
+		EventBus.Close()
+	}
+	closeWebSockets(ctx)
+
+	var wg sync.WaitGroup
+	if httpServer != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := httpServer.Shutdown(ctx); err != nil {
+				logging.Warnf("HTTP server did not shut down gracefully: %s", err)
+				httpServer.Close()
+			} else {
+				logging.Infof("HTTP server stopped.")
+			}
+		}()
+	}
+	if grpcServer != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			done := make(chan struct{})
+			go func() {
+				grpcServer.GracefulStop()
+				close(done)
+			}()
+			select {
+			case <-done:
+				logging.Infof("gRPC server stopped.")
+			case <-ctx.Done():
+				logging.Warnf("gRPC server did not shut down gracefully: %s", ctx.Err())
+				grpcServer.Stop()
+			}
+		}()
+	}
+	wg.Wait()
+
+	appCancel()
+	workersDone := make(chan struct{})
+	go func() {
+		backgroundWorkers.Wait()
+		close(workersDone)
+	}()
+	select {
+	case <-workersDone:
+	case <-ctx.Done():
+		logging.Warnf("Background workers did not stop in time: %s", ctx.Err())
+	}
+
+	for i := len(bootstrappers) - 1; i >= 0; i-- {
+		if hook, ok := bootstrappers[i].(IShutdownHook); ok {
+			runShutdownHook(ctx, fmt.Sprintf("%v", bootstrappers[i]), hook.Shutdown)
+		}
+	}
+	shutdownHooksLock.Lock()
+	hooks := append([]namedShutdownHook{}, shutdownHooks...)
+	shutdownHooksLock.Unlock()
+	for i := len(hooks) - 1; i >= 0; i-- {
+		runShutdownHook(ctx, hooks[i].name, hooks[i].hook)
+	}
+	logging.Infof("Application [%s] stopped.", AppConfig().GetString("app.name")+" v"+AppConfig().GetString("app.version"))
+}
+
+func runShutdownHook(ctx context.Context, name string, hook func(ctx context.Context) error) {
+	defer func() {
+		if r := recover(); r != nil {
+			logging.Errorf("Shutdown hook [%s] panicked: %v", name, r)
+		}
+	}()
+	if err := hook(ctx); err != nil {
+		logging.Errorf("Shutdown hook [%s]: %s", name, err)
+	}
+}
+
+// startHttpServer starts the HTTP server in background; failing to listen causes the application to shut down.
+func startHttpServer(server *http.Server) {
+	httpServer = server
+	go func() {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			RequestShutdown(fmt.Errorf("HTTP server error: %s", err))
+		}
+	}()
+}
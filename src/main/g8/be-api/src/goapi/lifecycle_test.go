@@ -0,0 +1,67 @@
+package goapi
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	hocon "github.com/go-akka/configuration"
+	"main/src/itineris"
+)
+
+type _hookBootstrapper struct {
+	name  string
+	calls *[]string
+}
+
+func (b *_hookBootstrapper) Bootstrap() error {
+	return nil
+}
+
+func (b *_hookBootstrapper) Shutdown(_ context.Context) error {
+	*b.calls = append(*b.calls, b.name)
+	return nil
+}
+
+func TestShutdown(t *testing.T) {
+	name := "TestShutdown"
+	oldConfig, oldBus, oldHooks := AppConfig(), EventBus, shutdownHooks
+	defer func() {
+		EventBus, shutdownHooks = oldBus, oldHooks
+		setAppConfig(oldConfig)
+	}()
+	setAppConfig(hocon.ParseString(`api.shutdown.timeout = 5s`))
+	EventBus = itineris.NewEventBus()
+	sub := EventBus.Subscribe(nil, 1)
+	shutdownHooks = nil
+
+	calls := make([]string, 0)
+	workerStopped := false
+	GoBackground("worker", func(ctx context.Context) {
+		<-ctx.Done()
+		workerStopped = true
+	})
+	RegisterShutdownHook("hook1", func(_ context.Context) error {
+		calls = append(calls, "hook1")
+		return nil
+	})
+	RegisterShutdownHook("hook2", func(_ context.Context) error {
+		calls = append(calls, "hook2")
+		panic("should be recovered")
+	})
+	setReady(true)
+	shutdown([]IBootstrapper{&_hookBootstrapper{name: "b1", calls: &calls}, &_hookBootstrapper{name: "b2", calls: &calls}})
+
+	if IsReady() {
+		t.Fatalf("%s failed: application should not be ready", name)
+	}
+	if _, ok := <-sub.Events(); ok {
+		t.Fatalf("%s failed: event subscription should be closed", name)
+	}
+	if !workerStopped || AppContext().Err() == nil {
+		t.Fatalf("%s failed: background worker should be stopped", name)
+	}
+	if expected := "b2,b1,hook2,hook1"; strings.Join(calls, ",") != expected {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, expected, strings.Join(calls, ","))
+	}
+}
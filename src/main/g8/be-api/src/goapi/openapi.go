@@ -0,0 +1,284 @@
+package goapi
+
+import (
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"main/src/itineris"
+)
+
+const (
+	openApiVersion = "3.0.3"
+
+	// names of security schemes in the OpenAPI document
+	openApiSecurityAppId       = "AppId"
+	openApiSecurityAccessToken = "AccessToken"
+)
+
+var (
+	openApiEnabled = true
+	openApiUri     = "/api/openapi.json"
+	openApiDocsUri = ""
+
+	// echo's path params (e.g. ":id") -> OpenAPI's path params (e.g. "{id}")
+	reEchoPathParam = regexp.MustCompile(`:([^/]+)`)
+)
+
+// initOpenApi loads settings from config keys "api.openapi.*".
+//
+// @since template-v0.5.0
+func initOpenApi() {
+	openApiEnabled = AppConfig().GetBoolean("api.openapi.enabled", true)
+	openApiUri = AppConfig().GetString("api.openapi.uri", "/api/openapi.json")
+	openApiDocsUri = AppConfig().GetString("api.openapi.docs_uri", "")
+}
+
+// toOpenApiPath converts echo's uri pattern to OpenAPI's path, e.g. "/api/post/:id" -> "/api/post/{id}".
+func toOpenApiPath(uri string) string {
+	return reEchoPathParam.ReplaceAllStringFunc(uri, func(param string) string {
+		return "{" + param[1:] + "}"
+	})
+}
+
+// toOpenApiSchema converts an itineris.Schema to an OpenAPI schema object.
+func toOpenApiSchema(schema *itineris.Schema) map[string]interface{} {
+	if schema == nil {
+		return map[string]interface{}{}
+	}
+	result := map[string]interface{}{}
+	if schema.Type != "" {
+		result["type"] = schema.Type
+	}
+	if schema.Description != "" {
+		result["description"] = schema.Description
+	}
+	if schema.Format != "" {
+		result["format"] = schema.Format
+	}
+	if len(schema.Enum) > 0 {
+		result["enum"] = schema.Enum
+	}
+	if schema.MinLength > 0 {
+		result["minLength"] = schema.MinLength
+	}
+	if schema.MaxLength > 0 {
+		result["maxLength"] = schema.MaxLength
+	}
+	if schema.Pattern != "" {
+		result["pattern"] = schema.Pattern
+	}
+	if schema.Example != nil {
+		result["example"] = schema.Example
+	}
+	if len(schema.Properties) > 0 {
+		result["properties"], result["required"] = toOpenApiProperties(schema.Properties)
+		if len(result["required"].([]string)) == 0 {
+			delete(result, "required")
+		}
+	}
+	if schema.Items != nil {
+		result["items"] = toOpenApiSchema(schema.Items)
+	}
+	return result
+}
+
+// toOpenApiProperties converts a map of fields to OpenAPI "properties", plus the sorted list of required fields.
+func toOpenApiProperties(fields map[string]*itineris.Schema) (map[string]interface{}, []string) {
+	properties := make(map[string]interface{}, len(fields))
+	required := make([]string, 0)
+	for name, field := range fields {
+		properties[name] = toOpenApiSchema(field)
+		if field != nil && field.Required {
+			required = append(required, name)
+		}
+	}
+	sort.Strings(required)
+	return properties, required
+}
+
+// toOpenApiResponses describes the API result's envelope, with "data" described by the API's response schema.
+func toOpenApiResponses(schema *itineris.ApiSchema) map[string]interface{} {
+	dataSchema := map[string]interface{}{}
+	if schema != nil && schema.Response != nil {
+		dataSchema = toOpenApiSchema(schema.Response)
+	}
+	return map[string]interface{}{
+		"200": map[string]interface{}{
+			"description": "API result, the actual status of the API call is in field \"status\" (see api.http.response.mode)",
+			"content": map[string]interface{}{
+				echo.MIMEApplicationJSON: map[string]interface{}{
+					"schema": map[string]interface{}{
+						"type": itineris.TypeObject,
+						"properties": map[string]interface{}{
+							"status":  map[string]interface{}{"type": itineris.TypeInteger},
+							"message": map[string]interface{}{"type": itineris.TypeString},
+							"data":    dataSchema,
+							"extras":  map[string]interface{}{"type": itineris.TypeObject},
+						},
+						"required": []string{"status"},
+					},
+				},
+			},
+		},
+	}
+}
+
+// toOpenApiSecurity builds the security requirement of an API.
+func toOpenApiSecurity(schema *itineris.ApiSchema) []map[string][]string {
+	security := []string{itineris.SecurityAppId, itineris.SecurityAccessToken}
+	if schema != nil && schema.Security != nil {
+		security = schema.Security
+	}
+	requirement := map[string][]string{}
+	for _, s := range security {
+		switch s {
+		case itineris.SecurityAppId:
+			requirement[openApiSecurityAppId] = []string{}
+		case itineris.SecurityAccessToken:
+			requirement[openApiSecurityAccessToken] = []string{}
+		}
+	}
+	if len(requirement) == 0 {
+		return []map[string][]string{}
+	}
+	return []map[string][]string{requirement}
+}
+
+// toOpenApiOperation builds the OpenAPI operation of an API exposed at a HTTP method of an uri.
+//
+// Path params are taken from the uri; other params are query params for GET/HEAD/DELETE requests, or JSON request body otherwise.
+func toOpenApiOperation(uri, httpMethod, apiName string, schema *itineris.ApiSchema) map[string]interface{} {
+	op := map[string]interface{}{
+		"operationId": apiName,
+		"responses":   toOpenApiResponses(schema),
+		"security":    toOpenApiSecurity(schema),
+	}
+	params := map[string]*itineris.Schema{}
+	if schema != nil {
+		if schema.Summary != "" {
+			op["summary"] = schema.Summary
+		}
+		if schema.Description != "" {
+			op["description"] = schema.Description
+		}
+		if len(schema.Tags) > 0 {
+			op["tags"] = schema.Tags
+		}
+		for k, v := range schema.Params {
+			params[k] = v
+		}
+	}
+
+	parameters := make([]map[string]interface{}, 0)
+	for _, m := range reEchoPathParam.FindAllStringSubmatch(uri, -1) {
+		name := m[1]
+		paramSchema := params[name]
+		if paramSchema == nil {
+			paramSchema = &itineris.Schema{Type: itineris.TypeString}
+		}
+		delete(params, name)
+		parameters = append(parameters, map[string]interface{}{
+			"name": name, "in": "path", "required": true, "schema": toOpenApiSchema(paramSchema),
+		})
+	}
+	switch httpMethod {
+	case http.MethodGet, http.MethodHead, http.MethodDelete:
+		names := make([]string, 0, len(params))
+		for name := range params {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			parameters = append(parameters, map[string]interface{}{
+				"name": name, "in": "query", "required": params[name] != nil && params[name].Required, "schema": toOpenApiSchema(params[name]),
+			})
+		}
+	default:
+		if len(params) > 0 {
+			bodySchema := toOpenApiSchema(&itineris.Schema{Type: itineris.TypeObject, Properties: params})
+			op["requestBody"] = map[string]interface{}{
+				"content": map[string]interface{}{echo.MIMEApplicationJSON: map[string]interface{}{"schema": bodySchema}},
+			}
+		}
+	}
+	if len(parameters) > 0 {
+		op["parameters"] = parameters
+	}
+	return op
+}
+
+// buildOpenApiSpec builds the OpenAPI 3 document from the HTTP endpoints (httpRoutingMap) and the schemas registered with ApiRouter.
+//
+// @since template-v0.5.0
+func buildOpenApiSpec() map[string]interface{} {
+	paths := map[string]interface{}{}
+	httpRoutingLock.RLock()
+	defer httpRoutingLock.RUnlock()
+	for uri, methods := range httpRoutingMap {
+		pathItem := map[string]interface{}{}
+		for httpMethod, apiName := range methods {
+			if httpMethod == "*" || httpMethod == "ANY" {
+				continue
+			}
+			pathItem[strings.ToLower(httpMethod)] = toOpenApiOperation(uri, httpMethod, apiName, ApiRouter.GetApiSchema(apiName))
+		}
+		if len(pathItem) > 0 {
+			paths[toOpenApiPath(uri)] = pathItem
+		}
+	}
+	info := map[string]interface{}{
+		"title":   AppConfig().GetString("app.name"),
+		"version": AppConfig().GetString("app.version"),
+	}
+	if desc := AppConfig().GetString("app.desc"); desc != "" {
+		info["description"] = desc
+	}
+	return map[string]interface{}{
+		"openapi": openApiVersion,
+		"info":    info,
+		"paths":   paths,
+		"components": map[string]interface{}{
+			"securitySchemes": map[string]interface{}{
+				openApiSecurityAppId:       map[string]interface{}{"type": "apiKey", "in": "header", "name": httpHeaderAppId},
+				openApiSecurityAccessToken: map[string]interface{}{"type": "apiKey", "in": "header", "name": httpHeaderAccessToken},
+			},
+		},
+	}
+}
+
+// apiOpenApiSpecHandler serves the OpenAPI 3 document.
+//
+// @since template-v0.5.0
+func apiOpenApiSpecHandler(c echo.Context) error {
+	return c.JSON(http.StatusOK, buildOpenApiSpec())
+}
+
+// openApiDocsHtml is the interactive docs page, which loads Swagger UI from CDN.
+const openApiDocsHtml = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8"/>
+  <title>API docs</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@4/swagger-ui.css"/>
+</head>
+<body>
+<div id="swagger-ui"></div>
+<script src="https://unpkg.com/swagger-ui-dist@4/swagger-ui-bundle.js" crossorigin></script>
+<script>
+  window.onload = function () {
+    window.ui = SwaggerUIBundle({url: "{{SPEC_URI}}", dom_id: "#swagger-ui"});
+  };
+</script>
+</body>
+</html>
+`
+
+// apiOpenApiDocsHandler serves the interactive docs UI.
+//
+// @since template-v0.5.0
+func apiOpenApiDocsHandler(c echo.Context) error {
+	return c.HTML(http.StatusOK, strings.ReplaceAll(openApiDocsHtml, "{{SPEC_URI}}", openApiUri))
+}
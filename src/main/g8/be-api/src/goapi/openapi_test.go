@@ -0,0 +1,88 @@
+package goapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	hocon "github.com/go-akka/configuration"
+	"main/src/itineris"
+)
+
+func TestToOpenApiPath(t *testing.T) {
+	name := "TestToOpenApiPath"
+	testCases := map[string]string{
+		"/api/info":                "/api/info",
+		"/api/post/:id":            "/api/post/{id}",
+		"/api/post/:id/vote/:vote": "/api/post/{id}/vote/{vote}",
+	}
+	for input, expected := range testCases {
+		if v := toOpenApiPath(input); v != expected {
+			t.Fatalf("%s failed: expected %#v but received %#v", name, expected, v)
+		}
+	}
+}
+
+func TestBuildOpenApiSpec(t *testing.T) {
+	name := "TestBuildOpenApiSpec"
+	oldRouter, oldConfig, oldRoutingMap := ApiRouter, AppConfig(), httpRoutingMap
+	defer func() {
+		ApiRouter, httpRoutingMap = oldRouter, oldRoutingMap
+		setAppConfig(oldConfig)
+	}()
+	setAppConfig(hocon.ParseString(`app { name = "test", version = "1.2.3" }`))
+	ApiRouter = itineris.NewApiRouter()
+	ApiRouter.SetApiSchema("getPost", &itineris.ApiSchema{
+		Summary:  "Get a post",
+		Params:   map[string]*itineris.Schema{"id": {Type: itineris.TypeString, Required: true}, "lang": {Type: itineris.TypeString}},
+		Response: &itineris.Schema{Type: itineris.TypeObject, Properties: map[string]*itineris.Schema{"title": {Type: itineris.TypeString}}},
+	})
+	ApiRouter.SetApiSchema("updatePost", &itineris.ApiSchema{
+		Params:   map[string]*itineris.Schema{"title": {Type: itineris.TypeString, Required: true, MaxLength: 10}},
+		Security: []string{itineris.SecurityAppId},
+	})
+	httpRoutingMap = map[string]map[string]string{}
+	registerHttpHandler("/api/post/:id", "get", "getPost")
+	registerHttpHandler("/api/post/:id", "put", "updatePost")
+	registerHttpHandler("/api/info", "get", "info")
+
+	js, _ := json.Marshal(buildOpenApiSpec())
+	var spec map[string]interface{}
+	json.Unmarshal(js, &spec)
+	if spec["openapi"] != openApiVersion || spec["info"].(map[string]interface{})["version"] != "1.2.3" {
+		t.Fatalf("%s failed: unexpected spec %s", name, js)
+	}
+	paths := spec["paths"].(map[string]interface{})
+	if len(paths) != 2 || paths["/api/post/{id}"] == nil || paths["/api/info"] == nil {
+		t.Fatalf("%s failed: unexpected paths %#v", name, paths)
+	}
+
+	getOp := paths["/api/post/{id}"].(map[string]interface{})["get"].(map[string]interface{})
+	if getOp["operationId"] != "getPost" || getOp["summary"] != "Get a post" {
+		t.Fatalf("%s failed: unexpected operation %#v", name, getOp)
+	}
+	expectedParams := []interface{}{
+		map[string]interface{}{"name": "id", "in": "path", "required": true, "schema": map[string]interface{}{"type": "string"}},
+		map[string]interface{}{"name": "lang", "in": "query", "required": false, "schema": map[string]interface{}{"type": "string"}},
+	}
+	if !reflect.DeepEqual(getOp["parameters"], expectedParams) {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, expectedParams, getOp["parameters"])
+	}
+	if security := getOp["security"].([]interface{}); len(security) != 1 || len(security[0].(map[string]interface{})) != 2 {
+		t.Fatalf("%s failed: unexpected security %#v", name, security)
+	}
+
+	putOp := paths["/api/post/{id}"].(map[string]interface{})["put"].(map[string]interface{})
+	body := putOp["requestBody"].(map[string]interface{})["content"].(map[string]interface{})["application/json"].(map[string]interface{})["schema"].(map[string]interface{})
+	expectedBody := map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{"title": map[string]interface{}{"type": "string", "maxLength": 10.0}},
+		"required":   []interface{}{"title"},
+	}
+	if !reflect.DeepEqual(body, expectedBody) {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, expectedBody, body)
+	}
+	if security := putOp["security"].([]interface{}); len(security) != 1 || security[0].(map[string]interface{})[openApiSecurityAppId] == nil {
+		t.Fatalf("%s failed: unexpected security %#v", name, security)
+	}
+}
@@ -0,0 +1,344 @@
+package goapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/net/websocket"
+	"main/src/itineris"
+	"main/src/utils"
+)
+
+// Types of WebSocket frames.
+//
+// @since template-v0.5.0
+const (
+	wsFrameAuth        = "auth"        // client -> server: authenticate the connection, response has the same type
+	wsFrameCall        = "call"        // client -> server: invoke an API, response has type "result"
+	wsFrameResult      = "result"      // server -> client: result of an API call
+	wsFrameSubscribe   = "subscribe"   // client -> server: subscribe to server-pushed events, response has the same type
+	wsFrameUnsubscribe = "unsubscribe" // client -> server: stop receiving server-pushed events, response has the same type
+	wsFrameEvent       = "event"       // server -> client: server-pushed event
+	wsFrameError       = "error"       // server -> client: the client's frame cannot be processed
+)
+
+const (
+	// wsAuthApiName is name of the API context of an "auth" frame, which is routed through the api-filter chain for authentication.
+	wsAuthApiName = "_wsAuth"
+
+	// CtxWsConnectionId is the context attribute that holds id of the WebSocket connection (available only for API calls via WebSocket gateway).
+	//
+	// @since template-v0.5.0
+	CtxWsConnectionId = "ws_conn_id"
+)
+
+var (
+	wsEnabled        = true
+	wsMaxMessageSize = 64 * 1024
+	wsMaxInflight    = 8
+
+	// active connections, closed when the application shuts down (see closeWebSockets)
+	wsConnections sync.Map
+)
+
+// initWebSocket loads WebSocket gateway settings from config keys "api.websocket.*".
+//
+// @since template-v0.5.0
+func initWebSocket() {
+	wsEnabled = AppConfig().GetBoolean("api.websocket.enabled", true)
+	if size := AppConfig().GetByteSize("api.websocket.max_message_size"); size != nil && size.Int64() > 0 {
+		wsMaxMessageSize = int(size.Int64())
+	}
+	wsMaxInflight = int(AppConfig().GetInt32("api.websocket.max_inflight", 8))
+	if wsMaxInflight < 1 {
+		wsMaxInflight = 1
+	}
+}
+
+// wsFrame is a JSON frame sent by client.
+type wsFrame struct {
+	Id             interface{}            `json:"id"`             // optional id supplied by client, echoed back in the corresponding response frame
+	Type           string                 `json:"type"`           // frame type, default is "call"
+	ApiName        string                 `json:"apiName"`        // ("call") name of the API to call
+	Params         map[string]interface{} `json:"params"`         // ("call") params of the API call
+	IdempotencyKey string                 `json:"idempotencyKey"` // ("call") optional idempotency key of the API call
+	AppId          string                 `json:"appId"`          // ("auth") app id; ("call") optional, overrides the connection's app id for this call only
+	AccessToken    string                 `json:"accessToken"`    // ("auth") access token; ("call") optional, overrides the connection's access token for this call only
+	Types          []string               `json:"types"`          // ("subscribe") types of events to receive, empty means all types
+}
+
+// wsConnection holds the state of a WebSocket connection.
+type wsConnection struct {
+	id        string
+	ws        *websocket.Conn
+	ctxValues map[string]interface{} // context values captured from the handshake request, copied to every API call
+	writeLock sync.Mutex
+	lock      sync.Mutex
+	auth      *itineris.ApiAuth
+	sub       *itineris.EventSubscription
+	inflight  chan struct{}
+	wg        sync.WaitGroup
+}
+
+func (conn *wsConnection) newApiContext(apiName string) *itineris.ApiContext {
+	ctx := itineris.NewApiContext()
+	for k, v := range conn.ctxValues {
+		ctx.SetContextValue(k, v)
+	}
+	return ctx.SetApiName(apiName).SetGateway("WS").SetContextValue(CtxWsConnectionId, conn.id)
+}
+
+func (conn *wsConnection) getAuth() *itineris.ApiAuth {
+	conn.lock.Lock()
+	defer conn.lock.Unlock()
+	if conn.auth == nil {
+		return itineris.NewApiAuth("", "")
+	}
+	return conn.auth
+}
+
+// send writes a frame to client; frames are written one at a time.
+func (conn *wsConnection) send(frame map[string]interface{}) error {
+	conn.writeLock.Lock()
+	defer conn.writeLock.Unlock()
+	return websocket.JSON.Send(conn.ws, frame)
+}
+
+func (conn *wsConnection) sendResult(id interface{}, frameType string, result *itineris.ApiResult) {
+	frame := result.ToMap()
+	frame["id"] = id
+	frame["type"] = frameType
+	if err := conn.send(frame); err != nil {
+		itineris.ContextLogger(nil, conn.newApiContext("")).Debugf("Cannot send frame [%s] to WebSocket client: %s", frameType, err)
+	}
+}
+
+// handleAuth validates app id and access token via the api-filter chain; if valid, they are used for subsequent API calls.
+func (conn *wsConnection) handleAuth(frame *wsFrame) {
+	auth := itineris.NewApiAuth(frame.AppId, frame.AccessToken)
+	result := ApiRouter.CallHandler(conn.newApiContext(wsAuthApiName), auth, itineris.NewApiParams(), func(_ *itineris.ApiContext, _ *itineris.ApiAuth, _ *itineris.ApiParams) *itineris.ApiResult {
+		return itineris.NewApiResult(itineris.StatusOk).SetMessage("Ok")
+	})
+	if result.Status == itineris.StatusOk {
+		conn.lock.Lock()
+		conn.auth = auth
+		// subscription was authorized with the previous credentials
+		if conn.sub != nil {
+			conn.sub.Close()
+			conn.sub = nil
+		}
+		conn.lock.Unlock()
+	}
+	conn.sendResult(frame.Id, wsFrameAuth, result)
+}
+
+// handleCall routes the API call via ApiRouter.CallApi and sends back the result, calls are handled concurrently.
+func (conn *wsConnection) handleCall(frame *wsFrame) {
+	if frame.ApiName == "" {
+		conn.sendResult(frame.Id, wsFrameError, itineris.NewApiResult(itineris.StatusErrorClient).SetMessage("Missing [apiName]."))
+		return
+	}
+	ctx := conn.newApiContext(frame.ApiName)
+	if frame.IdempotencyKey != "" {
+		ctx.SetContextValue(itineris.CtxIdempotencyKey, frame.IdempotencyKey)
+	}
+	params := itineris.NewApiParams()
+	for k, v := range frame.Params {
+		params.SetParam(k, v)
+	}
+	auth := conn.getAuth()
+	if frame.AppId != "" || frame.AccessToken != "" {
+		// e.g. calling "login" before the connection is authenticated
+		appId, accessToken := frame.AppId, frame.AccessToken
+		if appId == "" {
+			appId = auth.GetAppId()
+		}
+		if accessToken == "" {
+			accessToken = auth.GetAccessToken()
+		}
+		auth = itineris.NewApiAuth(appId, accessToken)
+	}
+	conn.inflight <- struct{}{}
+	conn.wg.Add(1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				itineris.ContextLogger(nil, ctx).Errorf("Panic while calling API [%s] via WebSocket: %v", frame.ApiName, r)
+				conn.sendResult(frame.Id, wsFrameResult, itineris.NewApiResult(itineris.StatusErrorServer).SetMessage("Error while calling API ["+frame.ApiName+"]."))
+			}
+			<-conn.inflight
+			conn.wg.Done()
+		}()
+		result := ApiRouter.CallApi(ctx, auth, params)
+		if result == nil {
+			result = itineris.NewApiResult(itineris.StatusErrorServer).SetMessage("API [" + frame.ApiName + "] returned no result.")
+		}
+		frameResult := result.ToMap()
+		frameResult["id"] = frame.Id
+		frameResult["type"] = wsFrameResult
+		frameResult["apiName"] = frame.ApiName
+		if err := conn.send(frameResult); err != nil {
+			itineris.ContextLogger(nil, ctx).Debugf("Cannot send result to WebSocket client: %s", err)
+		}
+	}()
+}
+
+// handleSubscribe subscribes the connection to server-pushed events, replacing the existing subscription if any.
+func (conn *wsConnection) handleSubscribe(frame *wsFrame) {
+	sub, result := subscribeEvents(conn.newApiContext(eventsApiName), conn.getAuth(), frame.Types)
+	if sub != nil {
+		conn.lock.Lock()
+		if conn.sub != nil {
+			conn.sub.Close()
+		}
+		conn.sub = sub
+		conn.lock.Unlock()
+		conn.wg.Add(1)
+		go conn.pushEvents(sub)
+	}
+	conn.sendResult(frame.Id, wsFrameSubscribe, result)
+}
+
+func (conn *wsConnection) handleUnsubscribe(frame *wsFrame) {
+	conn.lock.Lock()
+	if conn.sub != nil {
+		conn.sub.Close()
+		conn.sub = nil
+	}
+	conn.lock.Unlock()
+	conn.sendResult(frame.Id, wsFrameUnsubscribe, itineris.NewApiResult(itineris.StatusOk))
+}
+
+// pushEvents forwards events of the subscription to client until the subscription is closed.
+func (conn *wsConnection) pushEvents(sub *itineris.EventSubscription) {
+	defer conn.wg.Done()
+	for event := range sub.Events() {
+		if err := conn.send(map[string]interface{}{"type": wsFrameEvent, "event": event.ToMap()}); err != nil {
+			sub.Close()
+		}
+	}
+}
+
+func (conn *wsConnection) close() {
+	conn.lock.Lock()
+	if conn.sub != nil {
+		conn.sub.Close()
+		conn.sub = nil
+	}
+	conn.lock.Unlock()
+	conn.wg.Wait()
+	conn.ws.Close()
+}
+
+// serve reads frames from client until the connection is closed.
+func (conn *wsConnection) serve() {
+	defer conn.close()
+	logger := itineris.ContextLogger(nil, conn.newApiContext(""))
+	logger.Debugf("WebSocket client connected")
+	for {
+		frame := &wsFrame{}
+		if err := websocket.JSON.Receive(conn.ws, frame); err != nil {
+			switch err.(type) {
+			case *json.SyntaxError, *json.UnmarshalTypeError:
+				conn.sendResult(nil, wsFrameError, itineris.NewApiResult(itineris.StatusErrorClient).SetMessage(fmt.Sprintf("Cannot parse frame: %s", err)))
+				continue
+			}
+			if err == websocket.ErrFrameTooLarge {
+				conn.sendResult(nil, wsFrameError, itineris.NewApiResult(itineris.StatusErrorClient).SetMessage(fmt.Sprintf("Frame exceeds the limit of %d bytes.", wsMaxMessageSize)))
+				continue
+			}
+			logger.Debugf("WebSocket client disconnected: %s", err)
+			return
+		}
+		switch strings.ToLower(frame.Type) {
+		case wsFrameAuth:
+			conn.handleAuth(frame)
+		case wsFrameCall, "":
+			conn.handleCall(frame)
+		case wsFrameSubscribe:
+			conn.handleSubscribe(frame)
+		case wsFrameUnsubscribe:
+			conn.handleUnsubscribe(frame)
+		default:
+			conn.sendResult(frame.Id, wsFrameError, itineris.NewApiResult(itineris.StatusErrorClient).SetMessage("Unknown frame type ["+frame.Type+"]."))
+		}
+	}
+}
+
+// apiWebSocketHandler upgrades the HTTP request to a WebSocket connection and serves API calls over it.
+//
+// Client authenticates once with frame {"type": "auth", "appId": ..., "accessToken": ...}, then sends
+// frames {"id": ..., "apiName": ..., "params": {...}}; results come back as {"id": ..., "type": "result", "status": ..., "data": ...}.
+// After {"type": "subscribe", "types": [...]}, server-pushed events arrive on the same socket as {"type": "event", "event": {...}}.
+//
+// Headers of the handshake request are captured like HTTP gateway does (e.g. "X-" headers are mapped to context values).
+// Since authentication is done via access token, not cookies, the Origin of the handshake request is not verified.
+//
+// @since template-v0.5.0
+func apiWebSocketHandler(c echo.Context) error {
+	req := c.Request()
+	ctxValues := map[string]interface{}{
+		itineris.CtxClientRealAddr: c.RealIP(),
+		itineris.CtxClientAddr:     req.RemoteAddr,
+	}
+	for k := range req.Header {
+		if k != httpHeaderAppId && k != httpHeaderAccessToken {
+			lk := strings.TrimSpace(strings.ToLower(k))
+			if strings.HasPrefix(lk, "x-") {
+				ctxValues[lk[2:]] = req.Header.Get(k)
+			}
+		}
+	}
+	server := websocket.Server{
+		Handshake: func(_ *websocket.Config, _ *http.Request) error { return nil },
+		Handler: func(ws *websocket.Conn) {
+			ws.MaxPayloadBytes = wsMaxMessageSize
+			conn := &wsConnection{
+				id:        utils.UniqueId(),
+				ws:        ws,
+				ctxValues: ctxValues,
+				inflight:  make(chan struct{}, wsMaxInflight),
+			}
+			if appId, accessToken := req.Header.Get(httpHeaderAppId), req.Header.Get(httpHeaderAccessToken); appId != "" || accessToken != "" {
+				conn.auth = itineris.NewApiAuth(appId, accessToken)
+			}
+			wsConnections.Store(conn, true)
+			defer wsConnections.Delete(conn)
+			conn.serve()
+		},
+	}
+	server.ServeHTTP(c.Response(), req)
+	return nil
+}
+
+// closeWebSockets stops reading new frames from active WebSocket connections; each connection is closed after its in-flight API calls complete.
+// It returns when all connections are closed or ctx is done.
+//
+// @since template-v0.5.0
+func closeWebSockets(ctx context.Context) {
+	wsConnections.Range(func(key, _ interface{}) bool {
+		key.(*wsConnection).ws.SetReadDeadline(time.Now())
+		return true
+	})
+	for {
+		empty := true
+		wsConnections.Range(func(_, _ interface{}) bool {
+			empty = false
+			return false
+		})
+		if empty {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package goapi
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/net/websocket"
+	"main/src/itineris"
+)
+
+func _wsReceive(t *testing.T, name string, ws *websocket.Conn) map[string]interface{} {
+	ws.SetReadDeadline(time.Now().Add(5 * time.Second))
+	frame := map[string]interface{}{}
+	if err := websocket.JSON.Receive(ws, &frame); err != nil {
+		t.Fatalf("%s failed: %s", name, err)
+	}
+	return frame
+}
+
+func TestWebSocketGateway(t *testing.T) {
+	name := "TestWebSocketGateway"
+	oldRouter, oldBus, oldBuilder := ApiRouter, EventBus, EventFilterBuilder
+	defer func() { ApiRouter, EventBus, EventFilterBuilder = oldRouter, oldBus, oldBuilder }()
+	ApiRouter = itineris.NewApiRouter()
+	ApiRouter.SetApiFilter(&_denyFilter{BaseApiFilter: &itineris.BaseApiFilter{ApiRouter: ApiRouter}})
+	ApiRouter.SetHandler("echo", func(ctx *itineris.ApiContext, _ *itineris.ApiAuth, params *itineris.ApiParams) *itineris.ApiResult {
+		return itineris.NewApiResult(itineris.StatusOk).SetData(map[string]interface{}{
+			"value": params.GetParam("value"), "gateway": ctx.GetGateway(), "conn": ctx.GetContextValue(CtxWsConnectionId),
+		})
+	})
+	EventBus = itineris.NewEventBus()
+	EventFilterBuilder = func(_ *itineris.ApiContext) itineris.EventFilter { return nil }
+
+	e := echo.New()
+	e.GET("/ws", apiWebSocketHandler)
+	server := httptest.NewServer(e)
+	defer server.Close()
+	ws, err := websocket.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws", "", server.URL)
+	if err != nil {
+		t.Fatalf("%s failed: %s", name, err)
+	}
+	defer ws.Close()
+
+	// API call without authentication
+	websocket.JSON.Send(ws, map[string]interface{}{"id": 1, "apiName": "echo", "params": map[string]interface{}{"value": "v"}})
+	if frame := _wsReceive(t, name, ws); frame["id"] != 1.0 || frame["type"] != wsFrameResult || frame["status"] != 403.0 {
+		t.Fatalf("%s failed: unexpected frame %#v", name, frame)
+	}
+
+	websocket.JSON.Send(ws, map[string]interface{}{"id": "a1", "type": "auth", "accessToken": "invalid"})
+	if frame := _wsReceive(t, name, ws); frame["id"] != "a1" || frame["type"] != wsFrameAuth || frame["status"] != 403.0 {
+		t.Fatalf("%s failed: unexpected frame %#v", name, frame)
+	}
+	websocket.JSON.Send(ws, map[string]interface{}{"id": "a2", "type": "auth", "accessToken": "valid"})
+	if frame := _wsReceive(t, name, ws); frame["id"] != "a2" || frame["status"] != 200.0 {
+		t.Fatalf("%s failed: unexpected frame %#v", name, frame)
+	}
+
+	// API call after authentication
+	websocket.JSON.Send(ws, map[string]interface{}{"id": 2, "apiName": "echo", "params": map[string]interface{}{"value": "v"}})
+	frame := _wsReceive(t, name, ws)
+	data, _ := frame["data"].(map[string]interface{})
+	if frame["id"] != 2.0 || frame["apiName"] != "echo" || frame["status"] != 200.0 || data["value"] != "v" || data["gateway"] != "WS" || data["conn"] == nil {
+		t.Fatalf("%s failed: unexpected frame %#v", name, frame)
+	}
+
+	// server-pushed events
+	websocket.JSON.Send(ws, map[string]interface{}{"id": "s", "type": "subscribe", "types": []string{"post.created"}})
+	if frame := _wsReceive(t, name, ws); frame["id"] != "s" || frame["status"] != 200.0 {
+		t.Fatalf("%s failed: unexpected frame %#v", name, frame)
+	}
+	EventBus.Publish(itineris.NewEvent("post.deleted", nil))
+	EventBus.Publish(itineris.NewEvent("post.created", map[string]interface{}{"id": "p1"}))
+	frame = _wsReceive(t, name, ws)
+	event, _ := frame["event"].(map[string]interface{})
+	if frame["type"] != wsFrameEvent || event["type"] != "post.created" {
+		t.Fatalf("%s failed: unexpected frame %#v", name, frame)
+	}
+
+	// malformed frames
+	websocket.Message.Send(ws, "not json")
+	if frame := _wsReceive(t, name, ws); frame["type"] != wsFrameError || frame["status"] != 400.0 {
+		t.Fatalf("%s failed: unexpected frame %#v", name, frame)
+	}
+	websocket.JSON.Send(ws, map[string]interface{}{"id": 3, "type": "unknown"})
+	if frame := _wsReceive(t, name, ws); frame["id"] != 3.0 || frame["type"] != wsFrameError {
+		t.Fatalf("%s failed: unexpected frame %#v", name, frame)
+	}
+}
@@ -7,20 +7,23 @@ Package gvabe provides backend API for GoVueAdmin Frontend.
 package gvabe
 
 import (
+	"context"
 	"crypto/rsa"
 	"crypto/x509"
 	"encoding/pem"
 	"fmt"
 	"io/ioutil"
-	"log"
 	"os"
 	"strings"
 
 	"github.com/btnguyen2k/consu/reddo"
 	"github.com/btnguyen2k/goyai"
+	hocon "github.com/go-akka/configuration"
 	"main/src/goapi"
+	auditv2 "main/src/gvabe/bov2/audit"
 	blogv2 "main/src/gvabe/bov2/blog"
 	userv2 "main/src/gvabe/bov2/user"
+	"main/src/logging"
 )
 
 var (
@@ -28,6 +31,8 @@ var (
 	blogPostDaov2    blogv2.BlogPostDao
 	blogCommentDaov2 blogv2.BlogCommentDao
 	blogVoteDaov2    blogv2.BlogVoteDao
+	blogPostVoter    blogv2.BlogPostVoter
+	auditEventDaov2  auditv2.AuditEventDao
 )
 
 // MyBootstrapper implements goapi.IBootstrapper
@@ -45,83 +50,128 @@ var Bootstrapper = &MyBootstrapper{name: "gvabe"}
 func (b *MyBootstrapper) Bootstrap() error {
 	DEBUG_MODE,_ = reddo.ToBool(os.Getenv("DEBUG"))
 	DEMO_MODE,_ = reddo.ToBool(os.Getenv("DEMO"))
-	go routineUpdateSystemInfo()
+	if DEBUG_MODE {
+		logging.Default().SetLevel(logging.LevelDebug)
+	}
 
 	initRsaKeys()
 	initI18n()
 	initExter()
 	initDaos()
+	// started after initDaos: the job reports metrics of the DAO cache created there
+	goapi.GoBackground("updateSystemInfo", routineUpdateSystemInfo)
+	initAudit()
+	initVoteReconciliation()
+	initHealthChecks()
 	initApiHandlers(goapi.ApiRouter)
 	initApiFilters(goapi.ApiRouter)
+	goapi.EventFilterBuilder = buildEventFilter
 	return nil
 }
 
+// Shutdown implements goapi.IShutdownHook.Shutdown
+//
+// By the time Shutdown is called, in-flight API calls have completed and background workers have stopped, so it is safe to close database connections.
+//
+// available since template-v0.5.0
+func (b *MyBootstrapper) Shutdown(ctx context.Context) error {
+	logging.Infof("Closing database connections...")
+	return closeDaos(ctx)
+}
+
 // available since template-v0.2.0
 func initExter() {
-	if exterAppId = goapi.AppConfig.GetString("gvabe.exter.app_id"); exterAppId == "" {
-		log.Printf("[WARN] No Exter app-id configured at [gvabe.exter.app_id], Exter login is disabled.")
-	} else if exterBaseUrl = goapi.AppConfig.GetString("gvabe.exter.base_url"); exterBaseUrl == "" {
-		log.Printf("[WARN] No Exter base-url configured at [gvabe.exter.base_url], default value will be used.")
+	if exterAppId = goapi.AppConfig().GetString("gvabe.exter.app_id"); exterAppId == "" {
+		logging.Warnf("No Exter app-id configured at [gvabe.exter.app_id], Exter login is disabled.")
+	} else if exterBaseUrl = goapi.AppConfig().GetString("gvabe.exter.base_url"); exterBaseUrl == "" {
+		logging.Warnf("No Exter base-url configured at [gvabe.exter.base_url], default value will be used.")
 		exterBaseUrl = "https://exteross.gpvcloud.com"
 	}
 	exterBaseUrl = strings.TrimSuffix(exterBaseUrl, "/") // trim trailing slashes
 	if exterAppId != "" {
 		exterClient = NewExterClient(exterAppId, exterBaseUrl)
 	}
-	log.Printf("[INFO] Exter app-id: %s / Base Url: %s", exterAppId, exterBaseUrl)
+	logging.Infof("Exter app-id: %s / Base Url: %s", exterAppId, exterBaseUrl)
 
-	go goFetchExterInfo(60)
+	if exterClient != nil {
+		goapi.GoBackground("fetchExterInfo", func(ctx context.Context) { goFetchExterInfo(ctx, 60) })
+	}
 }
 
 // available since template-v0.4.0
 func initI18n() {
-	i18nConfigFileOrDir := goapi.AppConfig.GetString("gvabe.i18n.i18n_file_or_directory")
+	bundles, err := buildI18n(goapi.AppConfig())
+	if err != nil {
+		panic(err.Error())
+	}
+	setI18n(bundles)
+	goapi.SubscribeConfigChanges("i18n", nil, reloadI18n)
+}
+
+// buildI18n builds i18n bundles from config keys "gvabe.i18n.*".
+//
+// available since template-v0.5.0
+func buildI18n(conf *hocon.Config) (goyai.I18n, error) {
+	i18nConfigFileOrDir := conf.GetString("gvabe.i18n.i18n_file_or_directory")
 	if i18nConfigFileOrDir == "" {
-		log.Println("[INFO] No i18n config specified at [gvabe.i18n.i18n_file_or_directory].")
-		i18n = goyai.NullI18n()
-		return
+		logging.Infof("No i18n config specified at [gvabe.i18n.i18n_file_or_directory].")
+		return goyai.NullI18n(), nil
 	}
 	_, err := os.Stat(i18nConfigFileOrDir)
 	if err != nil {
-		panic(fmt.Sprintf("error loading i18n config [%s]: %e", i18nConfigFileOrDir, err))
+		return nil, fmt.Errorf("error loading i18n config [%s]: %e", i18nConfigFileOrDir, err)
 	}
 
-	defaultLocale := goapi.AppConfig.GetString("gvabe.i18n.default_locale")
-	i18n, err = goyai.BuildI18n(goyai.I18nOptions{
+	defaultLocale := conf.GetString("gvabe.i18n.default_locale")
+	bundles, err := goyai.BuildI18n(goyai.I18nOptions{
 		ConfigFileOrDir: i18nConfigFileOrDir,
 		DefaultLocale:   defaultLocale,
 		I18nFileFormat:  goyai.Auto,
 	})
 	if err != nil {
-		panic(fmt.Sprintf("error loading i18n config [%s]: %e", i18nConfigFileOrDir, err))
+		return nil, fmt.Errorf("error loading i18n config [%s]: %e", i18nConfigFileOrDir, err)
 	}
 
-	locales := i18n.AvailableLocales()
+	locales := bundles.AvailableLocales()
 	if len(locales) == 0 {
-		log.Printf("[WARN] i18n config loaded from file [%s] but no locale configuration found", i18nConfigFileOrDir)
-		return
+		logging.Warnf("i18n config loaded from file [%s] but no locale configuration found", i18nConfigFileOrDir)
+		return bundles, nil
 	}
 
 	if DEBUG_MODE {
 		if defaultLocale == "" {
 			defaultLocale = locales[0].Id
 		}
-		log.Printf("[DEBUG_MODE] i18n config loaded from [%s], available locales: %v / default: [%s]", i18nConfigFileOrDir, locales, defaultLocale)
+		logging.Debugf("i18n config loaded from [%s], available locales: %v / default: [%s]", i18nConfigFileOrDir, locales, defaultLocale)
 	}
+	return bundles, nil
+}
+
+// reloadI18n rebuilds i18n bundles when configurations are reloaded. Bundle files are not tracked by goapi.AppConfig(),
+// hence bundles are rebuilt on every reload; the current bundles remain in use if the new ones fail to load.
+//
+// available since template-v0.5.0
+func reloadI18n(conf *hocon.Config) error {
+	bundles, err := buildI18n(conf)
+	if err != nil {
+		return err
+	}
+	setI18n(bundles)
+	return nil
 }
 
 // available since template-v0.2.0
 func initRsaKeys() {
-	rsaPrivKeyFile := goapi.AppConfig.GetString("gvabe.keys.rsa_privkey_file")
+	rsaPrivKeyFile := goapi.AppConfig().GetString("gvabe.keys.rsa_privkey_file")
 	if rsaPrivKeyFile == "" {
-		log.Println("[WARN] No RSA private key file configured at [gvabe.keys.rsa_privkey_file], generating one...")
+		logging.Warnf("No RSA private key file configured at [gvabe.keys.rsa_privkey_file], generating one...")
 		privKey, err := genRsaKey(2048)
 		if err != nil {
 			panic(err)
 		}
 		rsaPrivKey = privKey
 	} else {
-		log.Println(fmt.Sprintf("[INFO] Loading RSA private key from [%s]...", rsaPrivKeyFile))
+		logging.Infof("Loading RSA private key from [%s]...", rsaPrivKeyFile)
 		content, err := ioutil.ReadFile(rsaPrivKeyFile)
 		if err != nil {
 			panic(err)
@@ -131,9 +181,9 @@ func initRsaKeys() {
 			panic(fmt.Sprintf("cannot decode PEM from file [%s]", rsaPrivKeyFile))
 		}
 		var der []byte
-		passphrase := goapi.AppConfig.GetString("gvabe.keys.rsa_privkey_passphrase")
+		passphrase := goapi.AppConfig().GetString("gvabe.keys.rsa_privkey_passphrase")
 		if passphrase != "" {
-			log.Println("[INFO] RSA private key is pass-phrase protected")
+			logging.Infof("RSA private key is pass-phrase protected")
 			if decrypted, err := x509.DecryptPEMBlock(block, []byte(passphrase)); err != nil {
 				panic(err)
 			} else {
@@ -160,7 +210,7 @@ func initRsaKeys() {
 	rsaPubKey = &rsaPrivKey.PublicKey
 
 	if DEBUG_MODE {
-		log.Printf("[DEBUG_MODE] Exter public key: {Size: %d / Exponent: %d / Modulus: %x}",
+		logging.Debugf("Exter public key: {Size: %d / Exponent: %d / Modulus: %x}",
 			rsaPubKey.Size()*8, rsaPubKey.E, rsaPubKey.N)
 
 		pubBlockPKCS1 := pem.Block{
@@ -169,7 +219,7 @@ func initRsaKeys() {
 			Bytes:   x509.MarshalPKCS1PublicKey(rsaPubKey),
 		}
 		rsaPubKeyPemPKCS1 := pem.EncodeToMemory(&pubBlockPKCS1)
-		log.Printf("[DEBUG_MODE] Exter public key (PKCS1): %s", string(rsaPubKeyPemPKCS1))
+		logging.Debugf("Exter public key (PKCS1): %s", string(rsaPubKeyPemPKCS1))
 
 		pubPKIX, _ := x509.MarshalPKIXPublicKey(rsaPubKey)
 		pubBlockPKIX := pem.Block{
@@ -178,6 +228,6 @@ func initRsaKeys() {
 			Bytes:   pubPKIX,
 		}
 		rsaPubKeyPemPKIX := pem.EncodeToMemory(&pubBlockPKIX)
-		log.Printf("[DEBUG_MODE] Exter public key (PKIX): %s", string(rsaPubKeyPemPKIX))
+		logging.Debugf("Exter public key (PKIX): %s", string(rsaPubKeyPemPKIX))
 	}
 }
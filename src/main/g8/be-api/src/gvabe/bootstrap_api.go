@@ -3,7 +3,6 @@ package gvabe
 import (
 	"crypto/x509"
 	"encoding/pem"
-	"log"
 	"reflect"
 	"regexp"
 	"runtime"
@@ -17,29 +16,36 @@ import (
 	"github.com/btnguyen2k/henge"
 
 	"main/src/goapi"
+	auditv2 "main/src/gvabe/bov2/audit"
 	"main/src/gvabe/bov2/blog"
 	"main/src/gvabe/bov2/user"
 	"main/src/itineris"
+	"main/src/logging"
+	"main/src/utils"
 )
 
 // Setup API handlers: application register its api-handlers by calling router.SetHandler(apiName, apiHandlerFunc)
+// (or router.SetHandlerWithSchema(apiName, apiHandlerFunc, apiSchema) to also describe the API's params and result, since template-v0.5.0)
 //   - api-handler function must have the following signature:
 //     func (itineris.ApiContext, itineris.ApiAuth, itineris.ApiParams) *itineris.ApiResult
 func initApiHandlers(router *itineris.ApiRouter) {
-	router.SetHandler("info", apiInfo)
-	router.SetHandler("login", apiLogin)
-	router.SetHandler("verifyLoginToken", apiVerifyLoginToken)
-	router.SetHandler("systemInfo", apiSystemInfo)
-
-	router.SetHandler("myFeed", apiMyFeed)
-	router.SetHandler("myBlog", apiMyBlog)
-	router.SetHandler("createBlogPost", apiCreateBlogPost)
-	router.SetHandler("getBlogPost", apiGetBlogPost)
-	router.SetHandler("updateBlogPost", apiUpdateBlogPost)
-	router.SetHandler("deleteBlogPost", apiDeleteBlogPost)
-
-	router.SetHandler("getUserVoteForPost", apiGetUserVoteForPost)
-	router.SetHandler("voteForPost", apiVoteForPost)
+	router.SetHandlerWithSchema("info", apiInfo, apiSchemaInfo)
+	router.SetHandlerWithSchema("login", apiLogin, apiSchemaLogin)
+	router.SetHandlerWithSchema("verifyLoginToken", apiVerifyLoginToken, apiSchemaVerifyLoginToken)
+	router.SetHandlerWithSchema("systemInfo", apiSystemInfo, apiSchemaSystemInfo)
+
+	router.SetHandlerWithSchema("myFeed", apiMyFeed, apiSchemaMyFeed)
+	router.SetHandlerWithSchema("myBlog", apiMyBlog, apiSchemaMyBlog)
+	router.SetHandlerWithSchema("createBlogPost", apiCreateBlogPost, apiSchemaCreateBlogPost)
+	router.SetHandlerWithSchema("getBlogPost", apiGetBlogPost, apiSchemaGetBlogPost)
+	router.SetHandlerWithSchema("updateBlogPost", apiUpdateBlogPost, apiSchemaUpdateBlogPost)
+	router.SetHandlerWithSchema("deleteBlogPost", apiDeleteBlogPost, apiSchemaDeleteBlogPost)
+
+	router.SetHandlerWithSchema("getUserVoteForPost", apiGetUserVoteForPost, apiSchemaGetUserVoteForPost)
+	router.SetHandlerWithSchema("voteForPost", apiVoteForPost, apiSchemaVoteForPost)
+
+	router.SetHandlerWithSchema("auditLog", apiAuditLog, apiSchemaAuditLog)
+	router.SetHandlerWithSchema("reloadConfig", apiReloadConfig, apiSchemaReloadConfig)
 }
 
 /*------------------------------ shared variables and functions ------------------------------*/
@@ -102,10 +108,10 @@ func apiInfo(_ *itineris.ApiContext, _ *itineris.ApiAuth, _ *itineris.ApiParams)
 
 	result := map[string]interface{}{
 		"app": map[string]interface{}{
-			"name":        goapi.AppConfig.GetString("app.name"),
-			"shortname":   goapi.AppConfig.GetString("app.shortname"),
-			"version":     goapi.AppConfig.GetString("app.version"),
-			"description": goapi.AppConfig.GetString("app.desc"),
+			"name":        goapi.AppConfig().GetString("app.name"),
+			"shortname":   goapi.AppConfig().GetString("app.shortname"),
+			"version":     goapi.AppConfig().GetString("app.version"),
+			"description": goapi.AppConfig().GetString("app.desc"),
 		},
 		"exter": map[string]interface{}{
 			"app_id":   exterAppId,
@@ -117,9 +123,9 @@ func apiInfo(_ *itineris.ApiContext, _ *itineris.ApiAuth, _ *itineris.ApiParams)
 	}
 	if DEMO_MODE {
 		result["demo"] = map[string]interface{}{
-			"user_id":   goapi.AppConfig.GetString("gvabe.init.admin_user_id"),
-			"user_pwd":  goapi.AppConfig.GetString("gvabe.init.admin_user_pwd"),
-			"user_name": goapi.AppConfig.GetString("gvabe.init.admin_user_name"),
+			"user_id":   goapi.AppConfig().GetString("gvabe.init.admin_user_id"),
+			"user_pwd":  goapi.AppConfig().GetString("gvabe.init.admin_user_pwd"),
+			"user_name": goapi.AppConfig().GetString("gvabe.init.admin_user_name"),
 		}
 	}
 	if DEBUG_MODE || DEMO_MODE {
@@ -150,37 +156,37 @@ func _doLoginExter(ctx *itineris.ApiContext, params *itineris.ApiParams) *itiner
 	if DEBUG_MODE && exterRsaPubKey != nil {
 		exterToken, err := parseExterJwt(token.(string))
 		if err != nil {
-			log.Printf("[DEBUG_MODE] Error parsing submitted JWT: %e", err)
+			itineris.ContextLogger(nil, ctx).Debugf("Error parsing submitted JWT: %s", err)
 		} else {
-			log.Printf("[DEBUG_MODE] Submitted JWT: {Id: %s / Type: %s / AppId: %s / UserId: %s / UserName: %s}",
+			itineris.ContextLogger(nil, ctx).Debugf("Submitted JWT: {Id: %s / Type: %s / AppId: %s / UserId: %s / UserName: %s}",
 				exterToken.Id, exterToken.Type, exterToken.AppId, exterToken.UserId, exterToken.UserName)
 		}
 	}
 
 	if exterClient == nil {
 		return itineris.NewApiResult(itineris.StatusErrorServer).SetMessage(
-			i18n.Localize(ctx.GetClientLocale(), "error_exter_not_enabled",
+			i18n().Localize(ctx.GetClientLocale(), "error_exter_not_enabled",
 				&goyai.LocalizeConfig{DefaultMessage: "Exter login is not enabled"}),
 		)
 	}
 	resp, err := exterClient.VerifyLoginToken(token.(string))
 	if err != nil {
 		return itineris.NewApiResult(itineris.StatusErrorServer).SetMessage(
-			i18n.Localize(ctx.GetClientLocale(), "error_exter_token_validation_failed",
+			i18n().Localize(ctx.GetClientLocale(), "error_exter_token_validation_failed",
 				&goyai.LocalizeConfig{DefaultMessage: err.Error(),
 					TemplateData: map[string]interface{}{"error": err.Error()}}),
 		)
 	}
 	if resp.Status != 200 {
 		return itineris.NewApiResult(itineris.StatusNoPermission).SetMessage(
-			i18n.Localize(ctx.GetClientLocale(), "error_exter_login_failed",
+			i18n().Localize(ctx.GetClientLocale(), "error_exter_login_failed",
 				&goyai.LocalizeConfig{DefaultMessage: err.Error(), PluralCount: -1,
 					TemplateData: map[string]interface{}{"code": resp.Status, "message": resp.Message}}),
 		)
 	}
 	if exterRsaPubKey == nil {
 		return itineris.NewApiResult(itineris.StatusErrorServer).SetMessage(
-			i18n.Localize(ctx.GetClientLocale(), "error_exter_login_failed",
+			i18n().Localize(ctx.GetClientLocale(), "error_exter_login_failed",
 				&goyai.LocalizeConfig{DefaultMessage: "Exter login failed, please retry", PluralCount: 0}),
 		)
 	}
@@ -188,36 +194,36 @@ func _doLoginExter(ctx *itineris.ApiContext, params *itineris.ApiParams) *itiner
 	exterToken, err := parseExterJwt(exterJwt)
 	if DEBUG_MODE {
 		if err != nil {
-			log.Printf("[DEBUG_MODE] Error parsing returned JWT: %e", err)
+			itineris.ContextLogger(nil, ctx).Debugf("Error parsing returned JWT: %s", err)
 		} else {
-			log.Printf("[DEBUG_MODE] Submitted JWT: {Id: %s / Type: %s / AppId: %s / UserId: %s / UserName: %s}",
+			itineris.ContextLogger(nil, ctx).Debugf("Returned JWT: {Id: %s / Type: %s / AppId: %s / UserId: %s / UserName: %s}",
 				exterToken.Id, exterToken.Type, exterToken.AppId, exterToken.UserId, exterToken.UserName)
 		}
 	}
 	if err != nil {
 		return itineris.NewApiResult(itineris.StatusErrorServer).SetMessage(
-			i18n.Localize(ctx.GetClientLocale(), "error_exter_token_validation_failed",
+			i18n().Localize(ctx.GetClientLocale(), "error_exter_token_validation_failed",
 				&goyai.LocalizeConfig{DefaultMessage: err.Error(),
 					TemplateData: map[string]interface{}{"error": err.Error()}}),
 		)
 	}
 	if exterToken.Type != "login" {
 		return itineris.NewApiResult(itineris.StatusNoPermission).SetMessage(
-			i18n.Localize(ctx.GetClientLocale(), "error_exter_login_failed",
+			i18n().Localize(ctx.GetClientLocale(), "error_exter_login_failed",
 				&goyai.LocalizeConfig{DefaultMessage: "Exter login failed, please retry", PluralCount: 0}),
 		)
 	}
 	user, err := createUserFromExterToken(exterToken)
 	if err != nil {
 		return itineris.NewApiResult(itineris.StatusErrorServer).SetMessage(
-			i18n.Localize(ctx.GetClientLocale(), "error_user_creation_failed",
+			i18n().Localize(ctx.GetClientLocale(), "error_user_creation_failed",
 				&goyai.LocalizeConfig{DefaultMessage: err.Error(), PluralCount: -1,
 					TemplateData: map[string]interface{}{"error": resp.Message}}),
 		)
 	}
 	if user == nil {
 		return itineris.NewApiResult(itineris.StatusErrorServer).SetMessage(
-			i18n.Localize(ctx.GetClientLocale(), "error_user_creation_failed",
+			i18n().Localize(ctx.GetClientLocale(), "error_user_creation_failed",
 				&goyai.LocalizeConfig{DefaultMessage: "User account initialization failed, please retry", PluralCount: 0}),
 		)
 	}
@@ -232,7 +238,7 @@ func _doLoginExter(ctx *itineris.ApiContext, params *itineris.ApiParams) *itiner
 	})
 	if err != nil {
 		return itineris.NewApiResult(itineris.StatusErrorServer).SetMessage(
-			i18n.Localize(ctx.GetClientLocale(), "error_jwt_generation_failed",
+			i18n().Localize(ctx.GetClientLocale(), "error_jwt_generation_failed",
 				&goyai.LocalizeConfig{DefaultMessage: err.Error(),
 					TemplateData: map[string]interface{}{"error": err.Error()}}),
 		)
@@ -240,18 +246,18 @@ func _doLoginExter(ctx *itineris.ApiContext, params *itineris.ApiParams) *itiner
 	jwt, err := genJws(claims)
 	if err != nil {
 		return itineris.NewApiResult(itineris.StatusErrorServer).SetMessage(
-			i18n.Localize(ctx.GetClientLocale(), "error_jwt_generation_failed",
+			i18n().Localize(ctx.GetClientLocale(), "error_jwt_generation_failed",
 				&goyai.LocalizeConfig{DefaultMessage: err.Error(),
 					TemplateData: map[string]interface{}{"error": err.Error()}}),
 		)
 	}
-	return itineris.NewApiResult(itineris.StatusOk).SetData(jwt)
+	return itineris.NewApiResult(itineris.StatusOk).SetData(jwt).SetDataSensitive(true)
 }
 
 func _doLoginForm(ctx *itineris.ApiContext, params *itineris.ApiParams) *itineris.ApiResult {
 	username := _extractParam(params, "username", reddo.TypeString, "", nil)
 	resultLoginFailed := itineris.NewApiResult(itineris.StatusNoPermission).SetMessage(
-		i18n.Localize(ctx.GetClientLocale(), "error_login_failed",
+		i18n().Localize(ctx.GetClientLocale(), "error_login_failed",
 			&goyai.LocalizeConfig{DefaultMessage: "Authentication failed", PluralCount: 0}),
 	)
 	if username == "" {
@@ -264,7 +270,7 @@ func _doLoginForm(ctx *itineris.ApiContext, params *itineris.ApiParams) *itineri
 	user, err := userDaov2.Get(username.(string))
 	if err != nil {
 		return itineris.NewApiResult(itineris.StatusErrorServer).SetMessage(
-			i18n.Localize(ctx.GetClientLocale(), "error_login_failed",
+			i18n().Localize(ctx.GetClientLocale(), "error_login_failed",
 				&goyai.LocalizeConfig{DefaultMessage: err.Error(), PluralCount: -1,
 					TemplateData: map[string]interface{}{"error": err.Error()}}),
 		)
@@ -287,7 +293,7 @@ func _doLoginForm(ctx *itineris.ApiContext, params *itineris.ApiParams) *itineri
 	})
 	if err != nil {
 		return itineris.NewApiResult(itineris.StatusErrorServer).SetMessage(
-			i18n.Localize(ctx.GetClientLocale(), "error_jwt_generation_failed",
+			i18n().Localize(ctx.GetClientLocale(), "error_jwt_generation_failed",
 				&goyai.LocalizeConfig{DefaultMessage: err.Error(),
 					TemplateData: map[string]interface{}{"error": err.Error()}}),
 		)
@@ -295,12 +301,12 @@ func _doLoginForm(ctx *itineris.ApiContext, params *itineris.ApiParams) *itineri
 	jwt, err := genJws(claims)
 	if err != nil {
 		return itineris.NewApiResult(itineris.StatusErrorServer).SetMessage(
-			i18n.Localize(ctx.GetClientLocale(), "error_jwt_generation_failed",
+			i18n().Localize(ctx.GetClientLocale(), "error_jwt_generation_failed",
 				&goyai.LocalizeConfig{DefaultMessage: err.Error(),
 					TemplateData: map[string]interface{}{"error": err.Error()}}),
 		)
 	}
-	return itineris.NewApiResult(itineris.StatusOk).SetData(jwt)
+	return itineris.NewApiResult(itineris.StatusOk).SetData(jwt).SetDataSensitive(true)
 }
 
 // apiLogin handles API call "login".
@@ -322,21 +328,21 @@ func apiVerifyLoginToken(ctx *itineris.ApiContext, _ *itineris.ApiAuth, params *
 	token := _extractParam(params, "token", reddo.TypeString, "", nil)
 	if token == "" {
 		return itineris.NewApiResult(itineris.StatusNoPermission).SetMessage(
-			i18n.Localize(ctx.GetClientLocale(), "error_login_failed",
+			i18n().Localize(ctx.GetClientLocale(), "error_login_failed",
 				&goyai.LocalizeConfig{DefaultMessage: "Authentication failed", PluralCount: 0}),
 		)
 	}
 	claims, err := parseLoginToken(token.(string))
 	if err != nil {
 		return itineris.NewApiResult(itineris.StatusNoPermission).SetMessage(
-			i18n.Localize(ctx.GetClientLocale(), "error_login_failed",
+			i18n().Localize(ctx.GetClientLocale(), "error_login_failed",
 				&goyai.LocalizeConfig{DefaultMessage: err.Error(), PluralCount: -1,
 					TemplateData: map[string]interface{}{"error": err.Error()}}),
 		)
 	}
 	if claims.isExpired() {
 		return itineris.NewApiResult(itineris.StatusNoPermission).SetMessage(
-			i18n.Localize(ctx.GetClientLocale(), "error_login_failed",
+			i18n().Localize(ctx.GetClientLocale(), "error_login_failed",
 				&goyai.LocalizeConfig{DefaultMessage: errorExpiredJwt.Error(), PluralCount: -1,
 					TemplateData: map[string]interface{}{"error": errorExpiredJwt.Error()}}),
 		)
@@ -346,16 +352,23 @@ func apiVerifyLoginToken(ctx *itineris.ApiContext, _ *itineris.ApiAuth, params *
 	jwt, err := genJws(claims)
 	if err != nil {
 		return itineris.NewApiResult(itineris.StatusErrorServer).SetMessage(
-			i18n.Localize(ctx.GetClientLocale(), "error_jwt_generation_failed",
+			i18n().Localize(ctx.GetClientLocale(), "error_jwt_generation_failed",
 				&goyai.LocalizeConfig{DefaultMessage: err.Error(),
 					TemplateData: map[string]interface{}{"error": err.Error()}}),
 		)
 	}
-	return itineris.NewApiResult(itineris.StatusOk).SetData(jwt)
+	return itineris.NewApiResult(itineris.StatusOk).SetData(jwt).SetDataSensitive(true)
 }
 
 var funcPostToMapTransform = func(m map[string]interface{}) map[string]interface{} {
 	u, _ := userDaov2.Get(m[blog.PostFieldOwnerId].(string))
+	return _transformPostMap(m, u)
+}
+
+// _transformPostMap transforms a blog post's map to API output, embedding the post's owner (if not nil).
+//
+// available since template-v0.5.0
+func _transformPostMap(m map[string]interface{}, u *user.User) map[string]interface{} {
 	// transform input map
 	result := map[string]interface{}{
 		"id":             m[henge.FieldId],
@@ -384,6 +397,33 @@ var funcPostToMapTransform = func(m map[string]interface{}) map[string]interface
 	return result
 }
 
+// postListToMaps transforms a list of blog posts to API output. Posts' owners are resolved in one batch instead of
+// one lookup per post; owners that can not be resolved are omitted, same as funcPostToMapTransform.
+//
+// available since template-v0.5.0
+func postListToMaps(blogPostList []*blog.BlogPost) []map[string]interface{} {
+	ownerIds := make([]string, 0, len(blogPostList))
+	for _, p := range blogPostList {
+		ownerIds = append(ownerIds, p.GetOwnerId())
+	}
+	owners := make(map[string]*user.User)
+	if userList, err := userDaov2.GetMany(ownerIds); err != nil {
+		logging.Warnf("error resolving owners of %d blog post(s): %s", len(blogPostList), err)
+	} else {
+		for _, u := range userList {
+			owners[u.GetId()] = u
+		}
+	}
+	data := make([]map[string]interface{}, 0, len(blogPostList))
+	for _, p := range blogPostList {
+		owner := owners[p.GetOwnerId()]
+		data = append(data, p.ToMap(func(m map[string]interface{}) map[string]interface{} {
+			return _transformPostMap(m, owner)
+		}))
+	}
+	return data
+}
+
 // apiMyFeed handles API call "myFeed"
 //
 // @available since template-v0.2.0
@@ -391,30 +431,26 @@ func apiMyFeed(ctx *itineris.ApiContext, _ *itineris.ApiAuth, _ *itineris.ApiPar
 	_, user, err := _currentUserFromContext(ctx)
 	if err != nil {
 		return itineris.NewApiResult(itineris.StatusErrorServer).SetMessage(
-			i18n.Localize(ctx.GetClientLocale(), "error_server",
+			i18n().Localize(ctx.GetClientLocale(), "error_server",
 				&goyai.LocalizeConfig{DefaultMessage: err.Error(), PluralCount: -1,
 					TemplateData: map[string]interface{}{"error": err.Error()}}),
 		)
 	}
 	if user == nil {
 		return itineris.NewApiResult(itineris.StatusNoPermission).SetMessage(
-			i18n.Localize(ctx.GetClientLocale(), "error_no_permission",
+			i18n().Localize(ctx.GetClientLocale(), "error_no_permission",
 				&goyai.LocalizeConfig{DefaultMessage: "Not authorized"}),
 		)
 	}
 	blogPostList, err := blogPostDaov2.GetUserFeedAll(user)
 	if err != nil {
 		return itineris.NewApiResult(itineris.StatusErrorServer).SetMessage(
-			i18n.Localize(ctx.GetClientLocale(), "error_server",
+			i18n().Localize(ctx.GetClientLocale(), "error_server",
 				&goyai.LocalizeConfig{DefaultMessage: err.Error(), PluralCount: -1,
 					TemplateData: map[string]interface{}{"error": err.Error()}}),
 		)
 	}
-	data := make([]map[string]interface{}, 0)
-	for _, p := range blogPostList {
-		data = append(data, p.ToMap(funcPostToMapTransform))
-	}
-	return itineris.NewApiResult(itineris.StatusOk).SetData(data)
+	return itineris.NewApiResult(itineris.StatusOk).SetData(postListToMaps(blogPostList))
 }
 
 // apiMyBlog handles API call "myBlog"
@@ -424,30 +460,26 @@ func apiMyBlog(ctx *itineris.ApiContext, _ *itineris.ApiAuth, _ *itineris.ApiPar
 	_, user, err := _currentUserFromContext(ctx)
 	if err != nil {
 		return itineris.NewApiResult(itineris.StatusErrorServer).SetMessage(
-			i18n.Localize(ctx.GetClientLocale(), "error_server",
+			i18n().Localize(ctx.GetClientLocale(), "error_server",
 				&goyai.LocalizeConfig{DefaultMessage: err.Error(), PluralCount: -1,
 					TemplateData: map[string]interface{}{"error": err.Error()}}),
 		)
 	}
 	if user == nil {
 		return itineris.NewApiResult(itineris.StatusNoPermission).SetMessage(
-			i18n.Localize(ctx.GetClientLocale(), "error_no_permission",
+			i18n().Localize(ctx.GetClientLocale(), "error_no_permission",
 				&goyai.LocalizeConfig{DefaultMessage: "Not authorized"}),
 		)
 	}
 	blogPostList, err := blogPostDaov2.GetUserPostsAll(user)
 	if err != nil {
 		return itineris.NewApiResult(itineris.StatusErrorServer).SetMessage(
-			i18n.Localize(ctx.GetClientLocale(), "error_server",
+			i18n().Localize(ctx.GetClientLocale(), "error_server",
 				&goyai.LocalizeConfig{DefaultMessage: err.Error(), PluralCount: -1,
 					TemplateData: map[string]interface{}{"error": err.Error()}}),
 		)
 	}
-	data := make([]map[string]interface{}, 0)
-	for _, p := range blogPostList {
-		data = append(data, p.ToMap(funcPostToMapTransform))
-	}
-	return itineris.NewApiResult(itineris.StatusOk).SetData(data)
+	return itineris.NewApiResult(itineris.StatusOk).SetData(postListToMaps(blogPostList))
 }
 
 // apiCreateBlogPost handles API call "createBlogPost"
@@ -457,14 +489,14 @@ func apiCreateBlogPost(ctx *itineris.ApiContext, _ *itineris.ApiAuth, params *it
 	_, user, err := _currentUserFromContext(ctx)
 	if err != nil {
 		return itineris.NewApiResult(itineris.StatusErrorServer).SetMessage(
-			i18n.Localize(ctx.GetClientLocale(), "error_server",
+			i18n().Localize(ctx.GetClientLocale(), "error_server",
 				&goyai.LocalizeConfig{DefaultMessage: err.Error(), PluralCount: -1,
 					TemplateData: map[string]interface{}{"error": err.Error()}}),
 		)
 	}
 	if user == nil {
 		return itineris.NewApiResult(itineris.StatusNoPermission).SetMessage(
-			i18n.Localize(ctx.GetClientLocale(), "error_no_permission",
+			i18n().Localize(ctx.GetClientLocale(), "error_no_permission",
 				&goyai.LocalizeConfig{DefaultMessage: "Not authorized"}),
 		)
 	}
@@ -472,14 +504,14 @@ func apiCreateBlogPost(ctx *itineris.ApiContext, _ *itineris.ApiAuth, params *it
 	title := _extractParam(params, "title", reddo.TypeString, "", nil)
 	if title == "" {
 		return itineris.NewApiResult(itineris.StatusErrorClient).SetMessage(
-			i18n.Localize(ctx.GetClientLocale(), "error_empty_blog_title",
+			i18n().Localize(ctx.GetClientLocale(), "error_empty_blog_title",
 				&goyai.LocalizeConfig{DefaultMessage: "Blog title is empty, please provide one"}),
 		)
 	}
 	content := _extractParam(params, "content", reddo.TypeString, "", nil)
 	if content == "" {
 		return itineris.NewApiResult(itineris.StatusErrorClient).SetMessage(
-			i18n.Localize(ctx.GetClientLocale(), "error_empty_blog_content",
+			i18n().Localize(ctx.GetClientLocale(), "error_empty_blog_content",
 				&goyai.LocalizeConfig{DefaultMessage: "Blog content is empty, please provide one"}),
 		)
 	}
@@ -487,17 +519,18 @@ func apiCreateBlogPost(ctx *itineris.ApiContext, _ *itineris.ApiAuth, params *it
 	ok, err := blogPostDaov2.Create(blogPost)
 	if err != nil {
 		return itineris.NewApiResult(itineris.StatusErrorServer).SetMessage(
-			i18n.Localize(ctx.GetClientLocale(), "error_server",
+			i18n().Localize(ctx.GetClientLocale(), "error_server",
 				&goyai.LocalizeConfig{DefaultMessage: err.Error(), PluralCount: -1,
 					TemplateData: map[string]interface{}{"error": err.Error()}}),
 		)
 	}
 	if !ok {
 		return itineris.NewApiResult(itineris.StatusErrorServer).SetMessage(
-			i18n.Localize(ctx.GetClientLocale(), "error_server",
+			i18n().Localize(ctx.GetClientLocale(), "error_server",
 				&goyai.LocalizeConfig{DefaultMessage: "General server error occurred", PluralCount: 0}),
 		)
 	}
+	publishPostEvent(EventPostCreated, blogPost)
 	return itineris.NewApiResult(itineris.StatusOk)
 }
 
@@ -508,13 +541,13 @@ func apiGetBlogPost(ctx *itineris.ApiContext, _ *itineris.ApiAuth, params *itine
 	_, user, err := _currentUserFromContext(ctx)
 	if err != nil {
 		return itineris.NewApiResult(itineris.StatusErrorServer).SetMessage(
-			i18n.Localize(ctx.GetClientLocale(), "error_server",
+			i18n().Localize(ctx.GetClientLocale(), "error_server",
 				&goyai.LocalizeConfig{DefaultMessage: err.Error(), PluralCount: -1,
 					TemplateData: map[string]interface{}{"error": err.Error()}}),
 		)
 	}
 	resultNoPermission := itineris.NewApiResult(itineris.StatusNoPermission).SetMessage(
-		i18n.Localize(ctx.GetClientLocale(), "error_no_permission",
+		i18n().Localize(ctx.GetClientLocale(), "error_no_permission",
 			&goyai.LocalizeConfig{DefaultMessage: "Not authorized"}),
 	)
 	if user == nil {
@@ -524,14 +557,14 @@ func apiGetBlogPost(ctx *itineris.ApiContext, _ *itineris.ApiAuth, params *itine
 	blogPost, err := blogPostDaov2.Get(id.(string))
 	if err != nil {
 		return itineris.NewApiResult(itineris.StatusErrorServer).SetMessage(
-			i18n.Localize(ctx.GetClientLocale(), "error_server",
+			i18n().Localize(ctx.GetClientLocale(), "error_server",
 				&goyai.LocalizeConfig{DefaultMessage: err.Error(), PluralCount: -1,
 					TemplateData: map[string]interface{}{"error": err.Error()}}),
 		)
 	}
 	if blogPost == nil {
 		return itineris.NewApiResult(itineris.StatusNotFound).SetMessage(
-			i18n.Localize(ctx.GetClientLocale(), "error_blog_not_exist",
+			i18n().Localize(ctx.GetClientLocale(), "error_blog_not_exist",
 				&goyai.LocalizeConfig{DefaultMessage: "Blog post not found",
 					TemplateData: map[string]interface{}{"id": id}}),
 		)
@@ -539,7 +572,74 @@ func apiGetBlogPost(ctx *itineris.ApiContext, _ *itineris.ApiAuth, params *itine
 	if !blogPost.IsPublic() && blogPost.GetOwnerId() != user.GetId() {
 		return resultNoPermission
 	}
-	return itineris.NewApiResult(itineris.StatusOk).SetData(blogPost.ToMap(funcPostToMapTransform))
+	return itineris.NewApiResult(itineris.StatusOk).SetData(_postToMap(blogPost))
+}
+
+// _postToMap transforms a blog post to API output, including the post's version to be sent back with
+// updateBlogPost/deleteBlogPost.
+//
+// available since template-v0.5.0
+func _postToMap(blogPost *blog.BlogPost) map[string]interface{} {
+	data := blogPost.ToMap(funcPostToMapTransform)
+	data["version"] = postVersion(blogPost)
+	return data
+}
+
+// postVersion returns the version token of a blog post, which changes whenever the post's title, content or visibility
+// is modified, but not when its vote and comment counters change (see blog.BlogPost.GetVersion).
+//
+// available since template-v0.5.0
+func postVersion(blogPost *blog.BlogPost) string {
+	return blogPost.GetVersion()
+}
+
+// _resultPostWriteError builds the result of updateBlogPost/deleteBlogPost when the blog post could not be written.
+// Too many concurrent writes (e.g. votes) is contention rather than a server fault: the result is the same as a version
+// conflict (status 409 with the post's current version), so that the client can retry.
+//
+// available since template-v0.5.0
+func _resultPostWriteError(ctx *itineris.ApiContext, id string, err error) *itineris.ApiResult {
+	if err == blog.ErrPostConflict {
+		result := _resultPostVersionConflict(ctx, id)
+		if result.GetStatus() == itineris.StatusConflict {
+			result.SetMessage(i18n().Localize(ctx.GetClientLocale(), "error_blog_write_conflict",
+				&goyai.LocalizeConfig{DefaultMessage: err.Error()}))
+		}
+		return result
+	}
+	return itineris.NewApiResult(itineris.StatusErrorServer).SetMessage(
+		i18n().Localize(ctx.GetClientLocale(), "error_server",
+			&goyai.LocalizeConfig{DefaultMessage: err.Error(), PluralCount: -1,
+				TemplateData: map[string]interface{}{"error": err.Error()}}),
+	)
+}
+
+// _resultPostVersionConflict builds the result of updateBlogPost/deleteBlogPost when the blog post has been modified
+// since the version sent by the client: status 409 with the post's current version, or 404 if the post has been
+// deleted in the meantime.
+//
+// available since template-v0.5.0
+func _resultPostVersionConflict(ctx *itineris.ApiContext, id string) *itineris.ApiResult {
+	blogPost, err := blogPostDaov2.Get(id)
+	if err != nil {
+		return itineris.NewApiResult(itineris.StatusErrorServer).SetMessage(
+			i18n().Localize(ctx.GetClientLocale(), "error_server",
+				&goyai.LocalizeConfig{DefaultMessage: err.Error(), PluralCount: -1,
+					TemplateData: map[string]interface{}{"error": err.Error()}}),
+		)
+	}
+	if blogPost == nil {
+		return itineris.NewApiResult(itineris.StatusNotFound).SetMessage(
+			i18n().Localize(ctx.GetClientLocale(), "error_blog_not_exist",
+				&goyai.LocalizeConfig{DefaultMessage: "Blog post not found",
+					TemplateData: map[string]interface{}{"id": id}}),
+		)
+	}
+	return itineris.NewApiResult(itineris.StatusConflict).SetMessage(
+		i18n().Localize(ctx.GetClientLocale(), "error_blog_version_conflict",
+			&goyai.LocalizeConfig{DefaultMessage: "Blog post has been modified in the meantime",
+				TemplateData: map[string]interface{}{"id": id}}),
+	).SetData(map[string]interface{}{"version": postVersion(blogPost), "post": _postToMap(blogPost)})
 }
 
 // apiUpdateBlogPost handles API call "updateBlogPost"
@@ -549,13 +649,13 @@ func apiUpdateBlogPost(ctx *itineris.ApiContext, _ *itineris.ApiAuth, params *it
 	_, user, err := _currentUserFromContext(ctx)
 	if err != nil {
 		return itineris.NewApiResult(itineris.StatusErrorServer).SetMessage(
-			i18n.Localize(ctx.GetClientLocale(), "error_server",
+			i18n().Localize(ctx.GetClientLocale(), "error_server",
 				&goyai.LocalizeConfig{DefaultMessage: err.Error(), PluralCount: -1,
 					TemplateData: map[string]interface{}{"error": err.Error()}}),
 		)
 	}
 	resultNoPermission := itineris.NewApiResult(itineris.StatusNoPermission).SetMessage(
-		i18n.Localize(ctx.GetClientLocale(), "error_no_permission",
+		i18n().Localize(ctx.GetClientLocale(), "error_no_permission",
 			&goyai.LocalizeConfig{DefaultMessage: "Not authorized"}),
 	)
 	if user == nil {
@@ -565,14 +665,14 @@ func apiUpdateBlogPost(ctx *itineris.ApiContext, _ *itineris.ApiAuth, params *it
 	blogPost, err := blogPostDaov2.Get(id.(string))
 	if err != nil {
 		return itineris.NewApiResult(itineris.StatusErrorServer).SetMessage(
-			i18n.Localize(ctx.GetClientLocale(), "error_server",
+			i18n().Localize(ctx.GetClientLocale(), "error_server",
 				&goyai.LocalizeConfig{DefaultMessage: err.Error(), PluralCount: -1,
 					TemplateData: map[string]interface{}{"error": err.Error()}}),
 		)
 	}
 	if blogPost == nil {
 		return itineris.NewApiResult(itineris.StatusNotFound).SetMessage(
-			i18n.Localize(ctx.GetClientLocale(), "error_blog_not_exist",
+			i18n().Localize(ctx.GetClientLocale(), "error_blog_not_exist",
 				&goyai.LocalizeConfig{DefaultMessage: "Blog post not found",
 					TemplateData: map[string]interface{}{"id": id}}),
 		)
@@ -580,37 +680,40 @@ func apiUpdateBlogPost(ctx *itineris.ApiContext, _ *itineris.ApiAuth, params *it
 	if blogPost.GetOwnerId() != user.GetId() {
 		return resultNoPermission
 	}
+	version := _extractParam(params, "version", reddo.TypeString, "", nil)
+	if version == "" {
+		return itineris.NewApiResult(itineris.StatusErrorClient).SetMessage(
+			i18n().Localize(ctx.GetClientLocale(), "error_field_required",
+				&goyai.LocalizeConfig{DefaultMessage: "Parameter version is required",
+					TemplateData: map[string]interface{}{"param": "version"}}),
+		)
+	}
 	isPublic := _extractParam(params, "is_public", reddo.TypeBool, false, nil)
 	title := _extractParam(params, "title", reddo.TypeString, "", nil)
 	if title == "" {
 		return itineris.NewApiResult(itineris.StatusErrorClient).SetMessage(
-			i18n.Localize(ctx.GetClientLocale(), "error_empty_blog_title",
+			i18n().Localize(ctx.GetClientLocale(), "error_empty_blog_title",
 				&goyai.LocalizeConfig{DefaultMessage: "Blog title is empty, please provide one"}),
 		)
 	}
 	content := _extractParam(params, "content", reddo.TypeString, "", nil)
 	if content == "" {
 		return itineris.NewApiResult(itineris.StatusErrorClient).SetMessage(
-			i18n.Localize(ctx.GetClientLocale(), "error_empty_blog_content",
+			i18n().Localize(ctx.GetClientLocale(), "error_empty_blog_content",
 				&goyai.LocalizeConfig{DefaultMessage: "Blog content is empty, please provide one"}),
 		)
 	}
 	blogPost.SetPublic(isPublic.(bool)).SetTitle(title.(string)).SetContent(content.(string))
-	ok, err := blogPostDaov2.Update(blogPost)
+	// the post is written only if its content has not been modified since the version the client edited
+	ok, err := blogPostDaov2.UpdateIfVersion(blogPost, version.(string))
 	if err != nil {
-		return itineris.NewApiResult(itineris.StatusErrorServer).SetMessage(
-			i18n.Localize(ctx.GetClientLocale(), "error_server",
-				&goyai.LocalizeConfig{DefaultMessage: err.Error(), PluralCount: -1,
-					TemplateData: map[string]interface{}{"error": err.Error()}}),
-		)
+		return _resultPostWriteError(ctx, id.(string), err)
 	}
 	if !ok {
-		return itineris.NewApiResult(itineris.StatusErrorServer).SetMessage(
-			i18n.Localize(ctx.GetClientLocale(), "error_server",
-				&goyai.LocalizeConfig{DefaultMessage: "General server error occurred", PluralCount: 0}),
-		)
+		return _resultPostVersionConflict(ctx, id.(string))
 	}
-	return itineris.NewApiResult(itineris.StatusOk)
+	publishPostEvent(EventPostUpdated, blogPost)
+	return itineris.NewApiResult(itineris.StatusOk).SetData(map[string]interface{}{"version": postVersion(blogPost)})
 }
 
 // apiDeleteBlogPost handles API call "deleteBlogPost"
@@ -620,13 +723,13 @@ func apiDeleteBlogPost(ctx *itineris.ApiContext, _ *itineris.ApiAuth, params *it
 	_, user, err := _currentUserFromContext(ctx)
 	if err != nil {
 		return itineris.NewApiResult(itineris.StatusErrorServer).SetMessage(
-			i18n.Localize(ctx.GetClientLocale(), "error_server",
+			i18n().Localize(ctx.GetClientLocale(), "error_server",
 				&goyai.LocalizeConfig{DefaultMessage: err.Error(), PluralCount: -1,
 					TemplateData: map[string]interface{}{"error": err.Error()}}),
 		)
 	}
 	resultNoPermission := itineris.NewApiResult(itineris.StatusNoPermission).SetMessage(
-		i18n.Localize(ctx.GetClientLocale(), "error_no_permission",
+		i18n().Localize(ctx.GetClientLocale(), "error_no_permission",
 			&goyai.LocalizeConfig{DefaultMessage: "Not authorized"}),
 	)
 	if user == nil {
@@ -636,14 +739,14 @@ func apiDeleteBlogPost(ctx *itineris.ApiContext, _ *itineris.ApiAuth, params *it
 	blogPost, err := blogPostDaov2.Get(id.(string))
 	if err != nil {
 		return itineris.NewApiResult(itineris.StatusErrorServer).SetMessage(
-			i18n.Localize(ctx.GetClientLocale(), "error_server",
+			i18n().Localize(ctx.GetClientLocale(), "error_server",
 				&goyai.LocalizeConfig{DefaultMessage: err.Error(), PluralCount: -1,
 					TemplateData: map[string]interface{}{"error": err.Error()}}),
 		)
 	}
 	if blogPost == nil {
 		return itineris.NewApiResult(itineris.StatusNotFound).SetMessage(
-			i18n.Localize(ctx.GetClientLocale(), "error_blog_not_exist",
+			i18n().Localize(ctx.GetClientLocale(), "error_blog_not_exist",
 				&goyai.LocalizeConfig{DefaultMessage: "Blog post not found",
 					TemplateData: map[string]interface{}{"id": id}}),
 		)
@@ -651,20 +754,23 @@ func apiDeleteBlogPost(ctx *itineris.ApiContext, _ *itineris.ApiAuth, params *it
 	if blogPost.GetOwnerId() != user.GetId() {
 		return resultNoPermission
 	}
-	ok, err := blogPostDaov2.Delete(blogPost)
-	if err != nil {
-		return itineris.NewApiResult(itineris.StatusErrorServer).SetMessage(
-			i18n.Localize(ctx.GetClientLocale(), "error_server",
-				&goyai.LocalizeConfig{DefaultMessage: err.Error(), PluralCount: -1,
-					TemplateData: map[string]interface{}{"error": err.Error()}}),
+	version := _extractParam(params, "version", reddo.TypeString, "", nil)
+	if version == "" {
+		return itineris.NewApiResult(itineris.StatusErrorClient).SetMessage(
+			i18n().Localize(ctx.GetClientLocale(), "error_field_required",
+				&goyai.LocalizeConfig{DefaultMessage: "Parameter version is required",
+					TemplateData: map[string]interface{}{"param": "version"}}),
 		)
 	}
+	// the post is deleted only if its content has not been modified since the version the client saw
+	ok, err := blogPostDaov2.DeleteIfVersion(blogPost, version.(string))
+	if err != nil {
+		return _resultPostWriteError(ctx, id.(string), err)
+	}
 	if !ok {
-		return itineris.NewApiResult(itineris.StatusErrorServer).SetMessage(
-			i18n.Localize(ctx.GetClientLocale(), "error_server",
-				&goyai.LocalizeConfig{DefaultMessage: "General server error occurred", PluralCount: 0}),
-		)
+		return _resultPostVersionConflict(ctx, id.(string))
 	}
+	publishPostEvent(EventPostDeleted, blogPost)
 	return itineris.NewApiResult(itineris.StatusOk)
 }
 
@@ -675,13 +781,13 @@ func apiGetUserVoteForPost(ctx *itineris.ApiContext, _ *itineris.ApiAuth, params
 	_, user, err := _currentUserFromContext(ctx)
 	if err != nil {
 		return itineris.NewApiResult(itineris.StatusErrorServer).SetMessage(
-			i18n.Localize(ctx.GetClientLocale(), "error_server",
+			i18n().Localize(ctx.GetClientLocale(), "error_server",
 				&goyai.LocalizeConfig{DefaultMessage: err.Error(), PluralCount: -1,
 					TemplateData: map[string]interface{}{"error": err.Error()}}),
 		)
 	}
 	resultNoPermission := itineris.NewApiResult(itineris.StatusNoPermission).SetMessage(
-		i18n.Localize(ctx.GetClientLocale(), "error_no_permission",
+		i18n().Localize(ctx.GetClientLocale(), "error_no_permission",
 			&goyai.LocalizeConfig{DefaultMessage: "Not authorized"}),
 	)
 	if user == nil {
@@ -691,7 +797,7 @@ func apiGetUserVoteForPost(ctx *itineris.ApiContext, _ *itineris.ApiAuth, params
 	vote, err := blogVoteDaov2.GetUserVoteForTarget(user, postId)
 	if err != nil {
 		return itineris.NewApiResult(itineris.StatusErrorServer).SetMessage(
-			i18n.Localize(ctx.GetClientLocale(), "error_server",
+			i18n().Localize(ctx.GetClientLocale(), "error_server",
 				&goyai.LocalizeConfig{DefaultMessage: err.Error(), PluralCount: -1,
 					TemplateData: map[string]interface{}{"error": err.Error()}}),
 		)
@@ -710,13 +816,13 @@ func apiVoteForPost(ctx *itineris.ApiContext, _ *itineris.ApiAuth, params *itine
 	_, user, err := _currentUserFromContext(ctx)
 	if err != nil {
 		return itineris.NewApiResult(itineris.StatusErrorServer).SetMessage(
-			i18n.Localize(ctx.GetClientLocale(), "error_server",
+			i18n().Localize(ctx.GetClientLocale(), "error_server",
 				&goyai.LocalizeConfig{DefaultMessage: err.Error(), PluralCount: -1,
 					TemplateData: map[string]interface{}{"error": err.Error()}}),
 		)
 	}
 	resultNoPermission := itineris.NewApiResult(itineris.StatusNoPermission).SetMessage(
-		i18n.Localize(ctx.GetClientLocale(), "error_no_permission",
+		i18n().Localize(ctx.GetClientLocale(), "error_no_permission",
 			&goyai.LocalizeConfig{DefaultMessage: "Not authorized"}),
 	)
 	if user == nil {
@@ -730,14 +836,14 @@ func apiVoteForPost(ctx *itineris.ApiContext, _ *itineris.ApiAuth, params *itine
 	blogPost, err := blogPostDaov2.Get(postId)
 	if err != nil {
 		return itineris.NewApiResult(itineris.StatusErrorServer).SetMessage(
-			i18n.Localize(ctx.GetClientLocale(), "error_server",
+			i18n().Localize(ctx.GetClientLocale(), "error_server",
 				&goyai.LocalizeConfig{DefaultMessage: err.Error(), PluralCount: -1,
 					TemplateData: map[string]interface{}{"error": err.Error()}}),
 		)
 	}
 	if blogPost == nil {
 		return itineris.NewApiResult(itineris.StatusNotFound).SetMessage(
-			i18n.Localize(ctx.GetClientLocale(), "error_blog_not_exist",
+			i18n().Localize(ctx.GetClientLocale(), "error_blog_not_exist",
 				&goyai.LocalizeConfig{DefaultMessage: "Blog post not found",
 					TemplateData: map[string]interface{}{"id": postId}}),
 		)
@@ -750,71 +856,151 @@ func apiVoteForPost(ctx *itineris.ApiContext, _ *itineris.ApiAuth, params *itine
 	} else if value < -1 {
 		value = -1
 	}
-	existingVote, err := blogVoteDaov2.GetUserVoteForTarget(user, postId)
+	// the vote and the post's counters are written atomically, see blog.BlogPostVoter
+	newVote, blogPost, err := blogPostVoter.Vote(blog.NewBlogVote(goapi.AppVersionNumber, user, blogPost.GetId(), int(value)))
+	evictCachedBlogPost(postId)
+	if err == blog.ErrVoteConflict {
+		// contention rather than a server fault: the client may vote again
+		return itineris.NewApiResult(itineris.StatusConflict).SetMessage(
+			i18n().Localize(ctx.GetClientLocale(), "error_vote_conflict",
+				&goyai.LocalizeConfig{DefaultMessage: err.Error()}),
+		)
+	}
 	if err != nil {
 		return itineris.NewApiResult(itineris.StatusErrorServer).SetMessage(
-			i18n.Localize(ctx.GetClientLocale(), "error_server",
+			i18n().Localize(ctx.GetClientLocale(), "error_server",
 				&goyai.LocalizeConfig{DefaultMessage: err.Error(), PluralCount: -1,
 					TemplateData: map[string]interface{}{"error": err.Error()}}),
 		)
 	}
-	log.Printf("Existing vote: %#v\n", existingVote)
-	newVote := blog.NewBlogVote(goapi.AppVersionNumber, user, blogPost.GetId(), int(value))
-	if existingVote == nil {
-		// new vote
-		if value > 0 {
-			blogPost.IncNumVotesUp(1)
-		} else {
-			blogPost.IncNumVotesDown(1)
+	if blogPost == nil {
+		// post was deleted in the meantime
+		return itineris.NewApiResult(itineris.StatusNotFound).SetMessage(
+			i18n().Localize(ctx.GetClientLocale(), "error_blog_not_exist",
+				&goyai.LocalizeConfig{DefaultMessage: "Blog post not found",
+					TemplateData: map[string]interface{}{"id": postId}}),
+		)
+	}
+	logger := itineris.ContextLogger(nil, ctx)
+	if logger.IsEnabled(logging.LevelDebug) {
+		logger.Debugf("New vote: %#v", newVote)
+	}
+	publishVoteChangedEvent(blogPost)
+	return itineris.NewApiResult(itineris.StatusOk).SetData(map[string]interface{}{
+		"vote": true, "value": newVote.GetValue(), "num_votes_up": blogPost.GetNumVotesUp(), "num_votes_down": blogPost.GetNumVotesDown(),
+	})
+}
+
+// _parseAuditTime parses a time param for audit-log query, accepted formats: RFC3339 or "yyyy-MM-dd".
+//
+// @available since template-v0.5.0
+func _parseAuditTime(params *itineris.ApiParams, paramName string) (time.Time, bool) {
+	v := _extractParam(params, paramName, reddo.TypeString, "", nil)
+	if v == nil || v.(string) == "" {
+		return time.Time{}, true
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, v.(string), utils.Location); err == nil {
+			return t, true
 		}
-		if _, err := blogVoteDaov2.Create(newVote); err != nil {
-			return itineris.NewApiResult(itineris.StatusErrorServer).SetMessage(
-				i18n.Localize(ctx.GetClientLocale(), "error_server",
-					&goyai.LocalizeConfig{DefaultMessage: err.Error(), PluralCount: -1,
-						TemplateData: map[string]interface{}{"error": err.Error()}}),
+	}
+	return time.Time{}, false
+}
+
+// apiAuditLog handles API call "auditLog"
+//   - only admin users are allowed to query audit log
+//   - query params: actor, api, target_id, status, from, to (RFC3339 or yyyy-MM-dd), offset, limit
+//
+// @available since template-v0.5.0
+func apiAuditLog(ctx *itineris.ApiContext, _ *itineris.ApiAuth, params *itineris.ApiParams) *itineris.ApiResult {
+	_, user, err := _currentUserFromContext(ctx)
+	if err != nil {
+		return itineris.NewApiResult(itineris.StatusErrorServer).SetMessage(
+			i18n().Localize(ctx.GetClientLocale(), "error_server",
+				&goyai.LocalizeConfig{DefaultMessage: err.Error(), PluralCount: -1,
+					TemplateData: map[string]interface{}{"error": err.Error()}}),
+		)
+	}
+	if user == nil || !user.IsAdmin() {
+		return itineris.NewApiResult(itineris.StatusNoPermission).SetMessage(
+			i18n().Localize(ctx.GetClientLocale(), "error_no_permission",
+				&goyai.LocalizeConfig{DefaultMessage: "Not authorized"}),
+		)
+	}
+	if auditEventDaov2 == nil {
+		return itineris.ResultNotImplemented
+	}
+	query := &auditv2.AuditQuery{
+		Actor:    _extractParam(params, "actor", reddo.TypeString, "", nil).(string),
+		Api:      _extractParam(params, "api", reddo.TypeString, "", nil).(string),
+		TargetId: _extractParam(params, "target_id", reddo.TypeString, "", nil).(string),
+	}
+	if v, err := params.GetParamAsType("status", reddo.TypeInt); err == nil && v != nil {
+		query.Status = int(v.(int64))
+	}
+	for _, p := range []string{"from", "to"} {
+		t, ok := _parseAuditTime(params, p)
+		if !ok {
+			return itineris.NewApiResult(itineris.StatusErrorClient).SetMessage(
+				i18n().Localize(ctx.GetClientLocale(), "error_invalid_param",
+					&goyai.LocalizeConfig{DefaultMessage: "Invalid value for parameter " + p,
+						TemplateData: map[string]interface{}{"param": p}}),
 			)
 		}
-	} else {
-		newVote.SetId(existingVote.GetId())
-		if existingVote.GetValue() == newVote.GetValue() {
-			// cancel existing vote
-			newVote.SetValue(0)
-			if value > 0 {
-				blogPost.IncNumVotesUp(-1)
-			} else {
-				blogPost.IncNumVotesDown(-1)
-			}
+		if p == "from" {
+			query.From = t
 		} else {
-			// chance existing vote
-			if value > 0 {
-				blogPost.IncNumVotesUp(1)
-				if existingVote.GetValue() != 0 {
-					blogPost.IncNumVotesDown(-1)
-				}
-			} else {
-				if existingVote.GetValue() != 0 {
-					blogPost.IncNumVotesUp(-1)
-				}
-				blogPost.IncNumVotesDown(1)
-			}
-		}
-		log.Printf("New vote: %#v\n", newVote)
-		if _, err := blogVoteDaov2.Update(newVote); err != nil {
-			return itineris.NewApiResult(itineris.StatusErrorServer).SetMessage(
-				i18n.Localize(ctx.GetClientLocale(), "error_server",
-					&goyai.LocalizeConfig{DefaultMessage: err.Error(), PluralCount: -1,
-						TemplateData: map[string]interface{}{"error": err.Error()}}),
-			)
+			query.To = t
 		}
 	}
-	if _, err := blogPostDaov2.Update(blogPost); err != nil {
+	offset := _extractParam(params, "offset", reddo.TypeInt, int64(0), nil).(int64)
+	limit := _extractParam(params, "limit", reddo.TypeInt, int64(100), nil).(int64)
+	if offset < 0 {
+		offset = 0
+	}
+	if limit <= 0 || limit > 1000 {
+		limit = 1000
+	}
+	eventList, err := auditEventDaov2.FindN(query, int(offset), int(limit))
+	if err != nil {
 		return itineris.NewApiResult(itineris.StatusErrorServer).SetMessage(
-			i18n.Localize(ctx.GetClientLocale(), "error_server",
+			i18n().Localize(ctx.GetClientLocale(), "error_server",
 				&goyai.LocalizeConfig{DefaultMessage: err.Error(), PluralCount: -1,
 					TemplateData: map[string]interface{}{"error": err.Error()}}),
 		)
 	}
-	return itineris.NewApiResult(itineris.StatusOk).SetData(map[string]interface{}{
-		"vote": true, "value": newVote.GetValue(), "num_votes_up": blogPost.GetNumVotesUp(), "num_votes_down": blogPost.GetNumVotesDown(),
-	})
+	data := make([]map[string]interface{}, 0)
+	for _, e := range eventList {
+		data = append(data, e.ToMap(nil))
+	}
+	return itineris.NewApiResult(itineris.StatusOk).SetData(data)
+}
+
+// apiReloadConfig handles API call "reloadConfig"
+//   - only admin users are allowed to reload configurations
+//
+// @available since template-v0.5.0
+func apiReloadConfig(ctx *itineris.ApiContext, _ *itineris.ApiAuth, _ *itineris.ApiParams) *itineris.ApiResult {
+	_, user, err := _currentUserFromContext(ctx)
+	if err != nil {
+		return itineris.NewApiResult(itineris.StatusErrorServer).SetMessage(
+			i18n().Localize(ctx.GetClientLocale(), "error_server",
+				&goyai.LocalizeConfig{DefaultMessage: err.Error(), PluralCount: -1,
+					TemplateData: map[string]interface{}{"error": err.Error()}}),
+		)
+	}
+	if user == nil || !user.IsAdmin() {
+		return itineris.NewApiResult(itineris.StatusNoPermission).SetMessage(
+			i18n().Localize(ctx.GetClientLocale(), "error_no_permission",
+				&goyai.LocalizeConfig{DefaultMessage: "Not authorized"}),
+		)
+	}
+	if err := goapi.ReloadAppConfig("api"); err != nil {
+		return itineris.NewApiResult(itineris.StatusErrorServer).SetMessage(
+			i18n().Localize(ctx.GetClientLocale(), "error_server",
+				&goyai.LocalizeConfig{DefaultMessage: err.Error(), PluralCount: -1,
+					TemplateData: map[string]interface{}{"error": err.Error()}}),
+		)
+	}
+	return itineris.NewApiResult(itineris.StatusOk)
 }
@@ -0,0 +1,176 @@
+package gvabe
+
+import (
+	"main/src/itineris"
+)
+
+// Schemas of API params and results, registered alongside api-handlers (see initApiHandlers) and published in the OpenAPI document.
+//
+// available since template-v0.5.0
+var (
+	schemaPostId = &itineris.Schema{Type: itineris.TypeString, Required: true, Description: "id of the blog post"}
+
+	schemaPostVersion = &itineris.Schema{Type: itineris.TypeString, Required: true,
+		Description: "version of the blog post as returned by getBlogPost, the call fails with status 409 if the post has been modified since"}
+
+	schemaUserOwner = &itineris.Schema{Type: itineris.TypeObject, Properties: map[string]*itineris.Schema{
+		"id":           {Type: itineris.TypeString},
+		"mid":          {Type: itineris.TypeString, Description: "masked id of the user"},
+		"is_admin":     {Type: itineris.TypeBoolean},
+		"display_name": {Type: itineris.TypeString},
+	}}
+
+	schemaBlogPost = &itineris.Schema{Type: itineris.TypeObject, Properties: map[string]*itineris.Schema{
+		"id":             {Type: itineris.TypeString},
+		"t_created":      {Type: itineris.TypeString, Format: "date-time"},
+		"is_public":      {Type: itineris.TypeBoolean},
+		"owner_id":       {Type: itineris.TypeString},
+		"owner":          schemaUserOwner,
+		"title":          {Type: itineris.TypeString},
+		"content":        {Type: itineris.TypeString},
+		"num_comments":   {Type: itineris.TypeInteger},
+		"num_votes_up":   {Type: itineris.TypeInteger},
+		"num_votes_down": {Type: itineris.TypeInteger},
+		"version":        {Type: itineris.TypeString, Description: "version of the blog post, to be sent back when updating/deleting the post"},
+	}}
+
+	schemaBlogPostParams = map[string]*itineris.Schema{
+		"is_public": {Type: itineris.TypeBoolean, Description: "is the blog post visible to other users?"},
+		"title":     {Type: itineris.TypeString, Required: true, MaxLength: 256, Description: "title of the blog post"},
+		"content":   {Type: itineris.TypeString, Required: true, Description: "content of the blog post (markdown)"},
+	}
+
+	apiSchemaInfo = &itineris.ApiSchema{
+		Summary:  "Application's information",
+		Tags:     []string{"system"},
+		Security: []string{},
+		Response: &itineris.Schema{Type: itineris.TypeObject, Properties: map[string]*itineris.Schema{
+			"app":            {Type: itineris.TypeObject},
+			"exter":          {Type: itineris.TypeObject},
+			"rsa_public_key": {Type: itineris.TypeString, Description: "RSA public key (PEM) to encrypt sensitive data sent to server"},
+			"debug_mode":     {Type: itineris.TypeBoolean},
+			"demo_mode":      {Type: itineris.TypeBoolean},
+		}},
+	}
+
+	apiSchemaLogin = &itineris.ApiSchema{
+		Summary:     "Log in",
+		Description: "Login with username/password (mode \"form\") or with an Exter token (mode \"exter\"). Result's data is the login token.",
+		Tags:        []string{"auth"},
+		Security:    []string{itineris.SecurityAppId},
+		Params: map[string]*itineris.Schema{
+			"mode":     {Type: itineris.TypeString, Enum: []interface{}{"form", "exter"}, Description: "login mode, default is \"form\""},
+			"username": {Type: itineris.TypeString, Description: "(mode form) username"},
+			"password": {Type: itineris.TypeString, Description: "(mode form) password"},
+			"token":    {Type: itineris.TypeString, Description: "(mode exter) Exter login token"},
+		},
+		Response: &itineris.Schema{Type: itineris.TypeString, Description: "login token (JWT)"},
+	}
+
+	apiSchemaVerifyLoginToken = &itineris.ApiSchema{
+		Summary:  "Verify a login token",
+		Tags:     []string{"auth"},
+		Security: []string{},
+		Params: map[string]*itineris.Schema{
+			"token": {Type: itineris.TypeString, Required: true, Description: "login token to verify"},
+		},
+		Response: &itineris.Schema{Type: itineris.TypeString, Description: "login token (JWT)"},
+	}
+
+	apiSchemaSystemInfo = &itineris.ApiSchema{
+		Summary:  "System's information (CPU, memory, etc)",
+		Tags:     []string{"system"},
+		Response: &itineris.Schema{Type: itineris.TypeObject},
+	}
+
+	apiSchemaMyFeed = &itineris.ApiSchema{
+		Summary:  "Blog posts visible to current user: public posts and user's own posts",
+		Tags:     []string{"blog"},
+		Response: &itineris.Schema{Type: itineris.TypeArray, Items: schemaBlogPost},
+	}
+
+	apiSchemaMyBlog = &itineris.ApiSchema{
+		Summary:  "Current user's blog posts",
+		Tags:     []string{"blog"},
+		Response: &itineris.Schema{Type: itineris.TypeArray, Items: schemaBlogPost},
+	}
+
+	apiSchemaCreateBlogPost = &itineris.ApiSchema{
+		Summary: "Create a new blog post",
+		Tags:    []string{"blog"},
+		Params:  schemaBlogPostParams,
+	}
+
+	apiSchemaGetBlogPost = &itineris.ApiSchema{
+		Summary:  "Get a blog post",
+		Tags:     []string{"blog"},
+		Params:   map[string]*itineris.Schema{"id": schemaPostId},
+		Response: schemaBlogPost,
+	}
+
+	apiSchemaUpdateBlogPost = &itineris.ApiSchema{
+		Summary: "Update a blog post",
+		Tags:    []string{"blog"},
+		Params: map[string]*itineris.Schema{
+			"id":        schemaPostId,
+			"is_public": schemaBlogPostParams["is_public"],
+			"title":     schemaBlogPostParams["title"],
+			"content":   schemaBlogPostParams["content"],
+			"version":   schemaPostVersion,
+		},
+		Response: &itineris.Schema{Type: itineris.TypeObject, Properties: map[string]*itineris.Schema{
+			"version": {Type: itineris.TypeString, Description: "new version of the blog post"},
+		}},
+	}
+
+	apiSchemaDeleteBlogPost = &itineris.ApiSchema{
+		Summary: "Delete a blog post",
+		Tags:    []string{"blog"},
+		Params:  map[string]*itineris.Schema{"id": schemaPostId, "version": schemaPostVersion},
+	}
+
+	apiSchemaGetUserVoteForPost = &itineris.ApiSchema{
+		Summary:  "Get current user's vote for a blog post",
+		Tags:     []string{"blog"},
+		Params:   map[string]*itineris.Schema{"postId": schemaPostId},
+		Response: &itineris.Schema{Type: itineris.TypeInteger, Enum: []interface{}{-1, 0, 1}},
+	}
+
+	apiSchemaVoteForPost = &itineris.ApiSchema{
+		Summary:     "Vote for a blog post",
+		Description: "Voting the same value again cancels the existing vote.",
+		Tags:        []string{"blog"},
+		Params: map[string]*itineris.Schema{
+			"postId": schemaPostId,
+			"vote":   {Type: itineris.TypeInteger, Description: "positive value: vote up, negative value: vote down"},
+		},
+		Response: &itineris.Schema{Type: itineris.TypeObject, Properties: map[string]*itineris.Schema{
+			"vote":           {Type: itineris.TypeBoolean},
+			"value":          {Type: itineris.TypeInteger},
+			"num_votes_up":   {Type: itineris.TypeInteger},
+			"num_votes_down": {Type: itineris.TypeInteger},
+		}},
+	}
+
+	apiSchemaAuditLog = &itineris.ApiSchema{
+		Summary: "Query the audit trail (admin only)",
+		Tags:    []string{"system"},
+		Params: map[string]*itineris.Schema{
+			"actor":     {Type: itineris.TypeString},
+			"api":       {Type: itineris.TypeString},
+			"target_id": {Type: itineris.TypeString},
+			"status":    {Type: itineris.TypeInteger},
+			"from":      {Type: itineris.TypeString, Description: "RFC3339 or yyyy-MM-dd"},
+			"to":        {Type: itineris.TypeString, Description: "RFC3339 or yyyy-MM-dd"},
+			"offset":    {Type: itineris.TypeInteger},
+			"limit":     {Type: itineris.TypeInteger},
+		},
+		Response: &itineris.Schema{Type: itineris.TypeArray, Items: &itineris.Schema{Type: itineris.TypeObject}},
+	}
+
+	apiSchemaReloadConfig = &itineris.ApiSchema{
+		Summary:     "Reload configurations and i18n bundles (admin only)",
+		Description: "Configurations that fail to parse or validate are rejected, and a reload that fails to apply is rolled back.",
+		Tags:        []string{"system"},
+	}
+)
@@ -1,12 +1,12 @@
 package gvabe
 
 import (
-	"log"
 	"os"
 	"time"
 
 	"main/src/goapi"
 	"main/src/itineris"
+	"main/src/logging"
 )
 
 /*
@@ -18,8 +18,8 @@ Setup API filters: application register its api-handlers by calling router.SetHa
 */
 func initApiFilters(apiRouter *itineris.ApiRouter) {
 	var apiFilter itineris.IApiFilter = nil
-	appName := goapi.AppConfig.GetString("app.name")
-	appVersion := goapi.AppConfig.GetString("app.version")
+	appName := goapi.AppConfig().GetString("app.name")
+	appVersion := goapi.AppConfig().GetString("app.version")
 
 	if DEBUG_MODE {
 		// apiFilter = itineris.NewAddPerfInfoFilter(goapi.ApiRouter, apiFilter)
@@ -29,17 +29,32 @@ func initApiFilters(apiRouter *itineris.ApiRouter) {
 			itineris.NewWriterPerfLogger(os.Stderr, appName, appVersion))
 	}
 
+	// Validation filter is placed after the authentication filter so that unauthenticated calls are rejected before their params are inspected
+	if validationFilter := newValidationFilter(apiRouter, apiFilter); validationFilter != nil {
+		apiFilter = validationFilter
+	}
+
+	// Idempotency filter is placed after the authentication filter so that idempotency keys are scoped per logged-in user
+	if idempotencyFilter := newIdempotencyFilter(apiRouter, apiFilter); idempotencyFilter != nil {
+		apiFilter = idempotencyFilter
+	}
+
 	apiFilter = (&GVAFEAuthenticationFilter{
 		BaseApiFilter: &itineris.BaseApiFilter{ApiRouter: apiRouter, NextFilter: apiFilter},
 	}).Init()
 
-	// if DEBUG_MODE {
-	// 	// Request logger should be the last one to capture full request/response
-	// 	apiFilter = itineris.NewLoggingFilter(
-	// 		goapi.ApiRouter,
-	// 		apiFilter,
-	// 		itineris.NewWriterRequestLogger(os.Stdout, appName, appVersion))
-	// }
+	// Audit filter wraps the authentication filter so that failed authentication attempts are also recorded
+	apiFilter = &AuditFilter{
+		BaseApiFilter: &itineris.BaseApiFilter{ApiRouter: apiRouter, NextFilter: apiFilter},
+	}
+
+	if goapi.AppConfig().GetBoolean("logging.request_log", false) {
+		// Request logger should be the last one to capture full request/response
+		requestLogger := itineris.NewStructuredRequestLogger(logging.WithField("component", "request"), appName, appVersion)
+		// renewed access tokens are returned to client via result's extras
+		requestLogger.SensitiveParams = append(requestLogger.SensitiveParams, apiResultExtraAccessToken)
+		apiFilter = itineris.NewLoggingFilter(goapi.ApiRouter, apiFilter, requestLogger)
+	}
 
 	apiRouter.SetApiFilter(apiFilter)
 }
@@ -57,7 +72,7 @@ type GVAFEAuthenticationFilter struct {
 
 // Init initializes the filter instance.
 func (f *GVAFEAuthenticationFilter) Init() *GVAFEAuthenticationFilter {
-	f.clientAppId = goapi.AppConfig.GetString("app.shortname") + "_fe"
+	f.clientAppId = goapi.AppConfig().GetString("app.shortname") + "_fe"
 	return f
 }
 
@@ -113,7 +128,7 @@ func (f *GVAFEAuthenticationFilter) authenticate(ctx *itineris.ApiContext, auth
 	}
 	sessionClaim, err := parseLoginToken(auth.GetAccessToken())
 	if err != nil {
-		log.Printf("Cannot decode JWT [API: %s / Error: %e", ctx.GetApiName(), err)
+		itineris.ContextLogger(nil, ctx).Warnf("Cannot decode JWT: %s", err)
 		return nil, errorInvalidJwt
 	}
 	if sessionClaim.isExpired() {
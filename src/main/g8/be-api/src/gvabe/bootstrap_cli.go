@@ -0,0 +1,431 @@
+package gvabe
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"main/src/goapi"
+	auditv2 "main/src/gvabe/bov2/audit"
+	blogv2 "main/src/gvabe/bov2/blog"
+	userv2 "main/src/gvabe/bov2/user"
+	"main/src/utils"
+)
+
+// Commands implements goapi.ICommandProvider.Commands
+//
+// available since template-v0.5.0
+func (b *MyBootstrapper) Commands() []*goapi.Command {
+	return []*goapi.Command{
+		{
+			Name: "user",
+			Usage: "user create <username> [--name <display-name>] [--password <password>] [--admin]\n" +
+				"user passwd <username> [--password <password>]\n" +
+				"user promote <username> [--revoke]\n" +
+				"user list [--admin]",
+			Description: "manage user accounts (password is read from stdin if not specified)",
+			Run:         cmdUser,
+		},
+		{
+			Name: "db",
+			Usage: "db init\n" +
+				"db migrate [--to <version>] [--dry-run]\n" +
+				"db status\n" +
+				"db check\n" +
+				"db export <directory> [--resume]\n" +
+				"db import <directory> [--resume] [--overwrite]\n" +
+				"db reconcile-votes",
+			Description: "migrate database schema and create initial data (admin user, intro blog post), show schema version, check database connectivity, export/import users and blog data (JSON Lines) to move them between databases, or recompute vote counters of blog posts from stored votes",
+			Run:         cmdDb,
+		},
+		{
+			Name: "keys",
+			Usage: "keys generate [--bits <n>] [--passphrase <passphrase>] [--out <file>]\n" +
+				"keys rotate [--bits <n>]",
+			Description: "generate a new RSA private key, or replace the one at [gvabe.keys.rsa_privkey_file] (previous key is backed up)",
+			Run:         cmdKeys,
+		},
+	}
+}
+
+// _parseCommandArgs parses flags that may appear before, between or after positional arguments, returns the positional arguments.
+func _parseCommandArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	fs.SetOutput(ioutil.Discard)
+	positional := make([]string, 0)
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, goapi.ErrCommandUsage
+		}
+		if fs.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+// _readSecret reads a line from stdin, prompt is printed to stderr.
+func _readSecret(prompt string) (string, error) {
+	fmt.Fprint(os.Stderr, prompt)
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("cannot read from stdin: %s", err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// _withDaos opens database connections and DAOs (see openDaos), runs f and closes the connections.
+func _withDaos(f func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	openDaos()
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		closeDaos(ctx)
+	}()
+	return f()
+}
+
+/*----------------------------------------------------------------------*/
+
+// cmdUser handles command "user"
+//
+// available since template-v0.5.0
+func cmdUser(args []string) error {
+	if len(args) == 0 {
+		return goapi.ErrCommandUsage
+	}
+	fs := flag.NewFlagSet("user "+args[0], flag.ContinueOnError)
+	displayName := fs.String("name", "", "display name")
+	password := fs.String("password", "", "password")
+	isAdmin := fs.Bool("admin", false, "admin account (create) / list admin accounts only (list)")
+	revoke := fs.Bool("revoke", false, "revoke admin permission")
+	positional, err := _parseCommandArgs(fs, args[1:])
+	if err != nil {
+		return err
+	}
+	if args[0] == "list" {
+		if len(positional) != 0 {
+			return goapi.ErrCommandUsage
+		}
+		return _withDaos(func() error { return cmdUserList(*isAdmin) })
+	}
+	if len(positional) != 1 || strings.TrimSpace(positional[0]) == "" {
+		return goapi.ErrCommandUsage
+	}
+	username := strings.TrimSpace(positional[0])
+	switch args[0] {
+	case "create", "passwd":
+		if *password == "" {
+			if *password, err = _readSecret("Password for [" + username + "]: "); err != nil {
+				return err
+			}
+		}
+		if *password == "" {
+			return errors.New("password must not be empty")
+		}
+		if args[0] == "create" {
+			return _withDaos(func() error { return cmdUserCreate(username, *displayName, *password, *isAdmin) })
+		}
+		return _withDaos(func() error {
+			return cmdUserUpdate(username, func(u *userv2.User) { u.SetPassword(encryptPassword(username, *password)) }, "password changed")
+		})
+	case "promote":
+		if *revoke {
+			return _withDaos(func() error {
+				return cmdUserUpdate(username, func(u *userv2.User) { u.SetAdmin(false) }, "admin permission revoked")
+			})
+		}
+		return _withDaos(func() error {
+			return cmdUserUpdate(username, func(u *userv2.User) { u.SetAdmin(true) }, "promoted to admin")
+		})
+	}
+	return goapi.ErrCommandUsage
+}
+
+func cmdUserCreate(username, displayName, password string, isAdmin bool) error {
+	if existing, err := userDaov2.Get(username); err != nil {
+		return err
+	} else if existing != nil {
+		return fmt.Errorf("user [%s] already exists", username)
+	}
+	if displayName == "" {
+		displayName = username
+	}
+	u := userv2.NewUser(goapi.AppVersionNumber, username, utils.UniqueId())
+	u.SetPassword(encryptPassword(username, password)).SetDisplayName(displayName).SetAdmin(isAdmin)
+	if ok, err := userDaov2.Create(u); err != nil {
+		return err
+	} else if !ok {
+		return fmt.Errorf("cannot create user [%s]", username)
+	}
+	fmt.Printf("User [%s] created (mid: %s, admin: %v)\n", username, u.GetMaskId(), isAdmin)
+	return nil
+}
+
+func cmdUserUpdate(username string, update func(u *userv2.User), msg string) error {
+	u, err := userDaov2.Get(username)
+	if err != nil {
+		return err
+	}
+	if u == nil {
+		return fmt.Errorf("user [%s] not found", username)
+	}
+	update(u)
+	if ok, err := userDaov2.Update(u); err != nil {
+		return err
+	} else if !ok {
+		return fmt.Errorf("cannot update user [%s]", username)
+	}
+	fmt.Printf("User [%s]: %s\n", username, msg)
+	return nil
+}
+
+func cmdUserList(adminOnly bool) error {
+	userList, err := userDaov2.GetAll(nil, nil)
+	if err != nil {
+		return err
+	}
+	sort.Slice(userList, func(i, j int) bool { return userList[i].GetId() < userList[j].GetId() })
+	fmt.Printf("%-32s %-28s %-6s %s\n", "USERNAME", "MID", "ADMIN", "DISPLAY NAME")
+	for _, u := range userList {
+		if !adminOnly || u.IsAdmin() {
+			fmt.Printf("%-32s %-28s %-6v %s\n", u.GetId(), u.GetMaskId(), u.IsAdmin(), u.GetDisplayName())
+		}
+	}
+	return nil
+}
+
+/*----------------------------------------------------------------------*/
+
+// cmdDb handles command "db"
+//
+// available since template-v0.5.0
+func cmdDb(args []string) error {
+	if len(args) == 0 {
+		return goapi.ErrCommandUsage
+	}
+	fs := flag.NewFlagSet("db "+args[0], flag.ContinueOnError)
+	toVersion := fs.Int("to", 0, "target schema version (default: latest)")
+	dryRun := fs.Bool("dry-run", false, "print migration steps without executing them")
+	resume := fs.Bool("resume", false, "continue a previous (interrupted) export/import")
+	overwrite := fs.Bool("overwrite", false, "replace existing records that have different content")
+	positional, err := _parseCommandArgs(fs, args[1:])
+	if err != nil {
+		return err
+	}
+	report := func(format string, a ...interface{}) { fmt.Printf(format+"\n", a...) }
+	switch args[0] {
+	case "export", "import":
+		if len(positional) != 1 || strings.TrimSpace(positional[0]) == "" {
+			return goapi.ErrCommandUsage
+		}
+		dir := strings.TrimSpace(positional[0])
+		if args[0] == "export" {
+			return _withDaos(func() error {
+				if err := exportData(dir, *resume, report); err != nil {
+					return err
+				}
+				fmt.Printf("Database [%s] exported to [%s]\n", goapi.AppConfig().GetString("gvabe.db.type"), dir)
+				return nil
+			})
+		}
+		return _withDaos(func() error {
+			if _, _, err := migrateDatabase(0, false, report); err != nil {
+				return err
+			}
+			if err := importData(dir, *resume, *overwrite, report); err != nil {
+				return err
+			}
+			fmt.Printf("Data imported from [%s] to database [%s]\n", dir, goapi.AppConfig().GetString("gvabe.db.type"))
+			return nil
+		})
+	}
+	if len(positional) != 0 {
+		return goapi.ErrCommandUsage
+	}
+	switch args[0] {
+	case "init":
+		return _withDaos(func() error {
+			if _, _, err := migrateDatabase(0, false, report); err != nil {
+				return err
+			}
+			_initUsers()
+			_initBlog()
+			fmt.Printf("Database [%s] initialized\n", goapi.AppConfig().GetString("gvabe.db.type"))
+			return nil
+		})
+	case "migrate":
+		if *toVersion < 0 {
+			return goapi.ErrCommandUsage
+		}
+		return _withDaos(func() error {
+			_, _, err := migrateDatabase(*toVersion, *dryRun, report)
+			return err
+		})
+	case "status":
+		return _withDaos(cmdDbStatus)
+	case "check":
+		return _withDaos(cmdDbCheck)
+	case "reconcile-votes":
+		return _withDaos(func() error {
+			numPosts, numFixed, err := reconcileVotes(context.Background(), report)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Vote counters of %d post(s) checked, %d post(s) corrected\n", numPosts, numFixed)
+			return nil
+		})
+	}
+	return goapi.ErrCommandUsage
+}
+
+// cmdDbStatus prints the current schema version, applied and pending migrations.
+func cmdDbStatus() error {
+	current, err := getSchemaVersion()
+	if err != nil {
+		return fmt.Errorf("cannot read schema version (run \"db migrate\" to create it): %s", err)
+	}
+	history, err := getSchemaHistory()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Database [%s] schema version: %d (latest: %d)\n", goapi.AppConfig().GetString("gvabe.db.type"), current, latestSchemaVersion())
+	for _, entry := range history {
+		fmt.Printf("  applied  %-4v %-40v at %v (app v%v)\n", entry["version"], entry["description"], entry["t_applied"], entry["app_version"])
+	}
+	for _, m := range pendingMigrations(current, latestSchemaVersion()) {
+		fmt.Printf("  pending  %-4d %s\n", m.Version, m.Description)
+	}
+	return nil
+}
+
+// cmdDbCheck pings the database then queries every table/collection, reporting all failures.
+func cmdDbCheck() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := pingDatabase(ctx); err != nil {
+		return fmt.Errorf("database [%s] is not reachable: %s", goapi.AppConfig().GetString("gvabe.db.type"), err)
+	}
+	fmt.Printf("Database [%s] is reachable\n", goapi.AppConfig().GetString("gvabe.db.type"))
+
+	const probeId = "-"
+	checks := []struct {
+		name  string
+		check func() error
+	}{
+		{userv2.TableUser, func() error { _, err := userDaov2.Get(probeId); return err }},
+		{blogv2.TableBlogPost, func() error { _, err := blogPostDaov2.Get(probeId); return err }},
+		{blogv2.TableBlogComment, func() error { _, err := blogCommentDaov2.Get(probeId); return err }},
+		{blogv2.TableBlogVote, func() error { _, err := blogVoteDaov2.Get(probeId); return err }},
+		{auditv2.TableAuditEvent, func() error { _, err := auditEventDaov2.Get(probeId); return err }},
+		{TableIdempotency, func() error { _, err := idempotencyDao.Get(probeId); return err }},
+	}
+	failed := 0
+	for _, c := range checks {
+		if err := c.check(); err != nil {
+			failed++
+			fmt.Printf("  %-20s FAILED: %s\n", c.name, err)
+		} else {
+			fmt.Printf("  %-20s OK\n", c.name)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d table(s)/collection(s) cannot be accessed, run \"db init\" to create them", failed)
+	}
+	if current, err := getSchemaVersion(); err != nil {
+		return fmt.Errorf("cannot read schema version: %s", err)
+	} else if latest := latestSchemaVersion(); current < latest {
+		return fmt.Errorf("schema version is %d but %d is required, run \"db migrate\" to upgrade", current, latest)
+	} else {
+		fmt.Printf("  %-20s version %d\n", TableSchemaVersion, current)
+	}
+	return nil
+}
+
+/*----------------------------------------------------------------------*/
+
+// cmdKeys handles command "keys"
+//
+// available since template-v0.5.0
+func cmdKeys(args []string) error {
+	if len(args) == 0 {
+		return goapi.ErrCommandUsage
+	}
+	fs := flag.NewFlagSet("keys "+args[0], flag.ContinueOnError)
+	bits := fs.Int("bits", 2048, "key size in bits")
+	passphrase := fs.String("passphrase", "", "passphrase to encrypt the private key")
+	out := fs.String("out", "", "output file (default: stdout)")
+	if positional, err := _parseCommandArgs(fs, args[1:]); err != nil || len(positional) != 0 {
+		return goapi.ErrCommandUsage
+	}
+	if *bits < 2048 {
+		return fmt.Errorf("key size must be at least 2048 bits")
+	}
+	switch args[0] {
+	case "generate":
+		pemData, err := _genRsaKeyPem(*bits, *passphrase)
+		if err != nil {
+			return err
+		}
+		if *out == "" {
+			_, err = os.Stdout.Write(pemData)
+			return err
+		}
+		if _, err := os.Stat(*out); err == nil {
+			return fmt.Errorf("file [%s] already exists", *out)
+		}
+		if err := ioutil.WriteFile(*out, pemData, 0600); err != nil {
+			return err
+		}
+		fmt.Printf("RSA private key (%d bits) written to [%s]\n", *bits, *out)
+		return nil
+	case "rotate":
+		return cmdKeysRotate(*bits)
+	}
+	return goapi.ErrCommandUsage
+}
+
+func _genRsaKeyPem(bits int, passphrase string) ([]byte, error) {
+	privKey, err := genRsaKey(bits)
+	if err != nil {
+		return nil, err
+	}
+	return encodeRsaPrivateKeyPem(privKey, passphrase)
+}
+
+// cmdKeysRotate replaces the key file at "gvabe.keys.rsa_privkey_file" with a new key encrypted with "gvabe.keys.rsa_privkey_passphrase".
+func cmdKeysRotate(bits int) error {
+	keyFile := goapi.AppConfig().GetString("gvabe.keys.rsa_privkey_file")
+	if keyFile == "" {
+		return errors.New("no RSA private key file configured at [gvabe.keys.rsa_privkey_file]")
+	}
+	pemData, err := _genRsaKeyPem(bits, goapi.AppConfig().GetString("gvabe.keys.rsa_privkey_passphrase"))
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(keyFile); err == nil {
+		backupFile := keyFile + "." + time.Now().Format("20060102150405") + ".bak"
+		if err := os.Rename(keyFile, backupFile); err != nil {
+			return err
+		}
+		fmt.Printf("Previous RSA private key backed up to [%s]\n", backupFile)
+	}
+	if err := ioutil.WriteFile(keyFile, pemData, 0600); err != nil {
+		return err
+	}
+	fmt.Printf("New RSA private key (%d bits) written to [%s]\n", bits, keyFile)
+	fmt.Println("Restart the application to use the new key; login tokens issued with the previous key will no longer be valid.")
+	return nil
+}
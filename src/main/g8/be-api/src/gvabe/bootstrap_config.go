@@ -0,0 +1,95 @@
+package gvabe
+
+import (
+	"strings"
+
+	hocon "github.com/go-akka/configuration"
+	"main/src/goapi"
+)
+
+// supported values of "gvabe.db.type", grouped by database
+var (
+	dbTypesSqlite   = []string{"sqlite", "sqlite3"}
+	dbTypesPgsql    = []string{"pg", "pgsql", "postgres", "postgresql"}
+	dbTypesMysql    = []string{"mysql"}
+	dbTypesCosmosdb = []string{"cosmos", "cosmosdb"}
+	dbTypesDynamodb = []string{"dynamo", "dynamodb", "awsdynamo", "awsdynamodb"}
+	dbTypesMongodb  = []string{"mongo", "mongodb"}
+	dbTypesMemory   = []string{"memory", "inmem"}
+)
+
+// dbTypeIn returns a goapi.ConfigRule.RequiredIf function that checks if "gvabe.db.type" is one of the specified types.
+func dbTypeIn(dbTypeGroups ...[]string) func(conf *hocon.Config) bool {
+	return func(conf *hocon.Config) bool {
+		dbtype := strings.ToLower(strings.TrimSpace(conf.GetString("gvabe.db.type")))
+		for _, group := range dbTypeGroups {
+			for _, t := range group {
+				if t == dbtype {
+					return true
+				}
+			}
+		}
+		return false
+	}
+}
+
+// ConfigRules implements goapi.IConfigSchemaProvider.ConfigRules
+//
+// available since template-v0.5.0
+func (b *MyBootstrapper) ConfigRules() []*goapi.ConfigRule {
+	dbTypes := make([]string, 0)
+	for _, group := range [][]string{dbTypesSqlite, dbTypesPgsql, dbTypesMysql, dbTypesCosmosdb, dbTypesDynamodb, dbTypesMongodb, dbTypesMemory} {
+		dbTypes = append(dbTypes, group...)
+	}
+	exterEnabled := func(conf *hocon.Config) bool {
+		return strings.TrimSpace(conf.GetString("gvabe.exter.app_id")) != ""
+	}
+	return []*goapi.ConfigRule{
+		{Path: "gvabe.init.admin_user_id", Type: goapi.ConfigTypeString, Required: true},
+		{Path: "gvabe.init.admin_user_pwd", Type: goapi.ConfigTypeString, Required: true},
+		{Path: "gvabe.init.admin_user_name", Type: goapi.ConfigTypeString},
+
+		{Path: "gvabe.db.type", Type: goapi.ConfigTypeString, Required: true, Enum: dbTypes},
+		{Path: "gvabe.db.auto_migrate", Type: goapi.ConfigTypeBool},
+		{Path: "gvabe.db.sqlite.directory", Type: goapi.ConfigTypeString, RequiredIf: dbTypeIn(dbTypesSqlite)},
+		{Path: "gvabe.db.sqlite.dbname", Type: goapi.ConfigTypeString, RequiredIf: dbTypeIn(dbTypesSqlite)},
+		{Path: "gvabe.db.pgsql.url", Type: goapi.ConfigTypeString, RequiredIf: dbTypeIn(dbTypesPgsql)},
+		{Path: "gvabe.db.mysql.url", Type: goapi.ConfigTypeString, RequiredIf: dbTypeIn(dbTypesMysql)},
+		{Path: "gvabe.db.cosmosdb.url", Type: goapi.ConfigTypeString, RequiredIf: dbTypeIn(dbTypesCosmosdb)},
+		{Path: "gvabe.db.dynamodb.region", Type: goapi.ConfigTypeString, RequiredIf: dbTypeIn(dbTypesDynamodb)},
+		{Path: "gvabe.db.dynamodb.endpoint", Type: goapi.ConfigTypeString, Check: goapi.ConfigCheckUrl},
+		{Path: "gvabe.db.mongodb.db", Type: goapi.ConfigTypeString, RequiredIf: dbTypeIn(dbTypesMongodb)},
+		{Path: "gvabe.db.mongodb.url", Type: goapi.ConfigTypeString, RequiredIf: dbTypeIn(dbTypesMongodb)},
+
+		{Path: "gvabe.i18n.default_locale", Type: goapi.ConfigTypeString},
+		{Path: "gvabe.i18n.i18n_file_or_directory", Type: goapi.ConfigTypeString, Check: goapi.ConfigCheckFileExists},
+
+		{Path: "gvabe.exter.app_id", Type: goapi.ConfigTypeString},
+		{Path: "gvabe.exter.base_url", Type: goapi.ConfigTypeString, RequiredIf: exterEnabled, Check: goapi.ConfigCheckUrl},
+
+		{Path: "gvabe.keys.rsa_privkey_file", Type: goapi.ConfigTypeString, Check: goapi.ConfigCheckFileExists},
+		{Path: "gvabe.keys.rsa_privkey_passphrase", Type: goapi.ConfigTypeString},
+
+		{Path: "gvabe.audit.enabled", Type: goapi.ConfigTypeBool},
+		{Path: "gvabe.audit.apis", Type: goapi.ConfigTypeList},
+		{Path: "gvabe.audit.target_params", Type: goapi.ConfigTypeList},
+		{Path: "gvabe.audit.retention", Type: goapi.ConfigTypeDuration},
+		{Path: "gvabe.audit.prune_interval", Type: goapi.ConfigTypeDuration, Min: "1s"},
+
+		{Path: "gvabe.validation.enabled", Type: goapi.ConfigTypeBool},
+
+		{Path: "gvabe.idempotency.enabled", Type: goapi.ConfigTypeBool},
+		{Path: "gvabe.idempotency.apis", Type: goapi.ConfigTypeList},
+		{Path: "gvabe.idempotency.ttl", Type: goapi.ConfigTypeDuration, Min: "1s"},
+		{Path: "gvabe.idempotency.storage", Type: goapi.ConfigTypeString, Enum: []string{"memory", "database", "db"}},
+		{Path: "gvabe.idempotency.memory_max_entries", Type: goapi.ConfigTypeInt, Min: "1"},
+		{Path: "gvabe.idempotency.prune_interval", Type: goapi.ConfigTypeDuration, Min: "1s"},
+
+		{Path: "gvabe.blog.vote_reconcile_interval", Type: goapi.ConfigTypeDuration},
+
+		{Path: "gvabe.cache.enabled", Type: goapi.ConfigTypeBool},
+		{Path: "gvabe.cache.storage", Type: goapi.ConfigTypeString},
+		{Path: "gvabe.cache.ttl", Type: goapi.ConfigTypeDuration, Min: "1s"},
+		{Path: "gvabe.cache.size", Type: goapi.ConfigTypeInt, Min: "1"},
+	}
+}
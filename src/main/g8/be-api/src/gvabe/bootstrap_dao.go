@@ -1,9 +1,9 @@
 package gvabe
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"log"
 	"os"
 	"strings"
 	"time"
@@ -14,13 +14,13 @@ import (
 	promdynamodb "github.com/btnguyen2k/prom/dynamodb"
 	prommongo "github.com/btnguyen2k/prom/mongo"
 	promsql "github.com/btnguyen2k/prom/sql"
-	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"main/src/goapi"
+	"main/src/gvabe/bov2/audit"
 	"main/src/gvabe/bov2/blog"
 	"main/src/gvabe/bov2/user"
+	"main/src/logging"
+	"main/src/memdb"
 	"main/src/utils"
 
 	_ "github.com/btnguyen2k/gocosmos"
@@ -34,12 +34,12 @@ func _createDynamodbConnect(dbtype string) *promdynamodb.AwsDynamodbConnect {
 	var err error
 	switch strings.ToLower(dbtype) {
 	case "dynamo", "dynamodb", "awsdynamo", "awsdynamodb":
-		region := goapi.AppConfig.GetString("gvabe.db.dynamodb.region")
+		region := goapi.AppConfig().GetString("gvabe.db.dynamodb.region")
 		cfg := &aws.Config{
 			Region:      aws.String(region),
 			Credentials: credentials.NewEnvCredentials(),
 		}
-		endpoint := goapi.AppConfig.GetString("gvabe.db.dynamodb.endpoint")
+		endpoint := goapi.AppConfig().GetString("gvabe.db.dynamodb.endpoint")
 		if endpoint != "" {
 			cfg.Endpoint = aws.String(endpoint)
 			if strings.HasPrefix(strings.ToLower(endpoint), "http://") {
@@ -59,22 +59,25 @@ func _createSqlConnect(dbtype string) *promsql.SqlConnect {
 	var flavor promsql.DbFlavor
 	switch strings.ToLower(dbtype) {
 	case "sqlite", "sqlite3":
-		dir := goapi.AppConfig.GetString("gvabe.db.sqlite.directory")
-		dbname := goapi.AppConfig.GetString("gvabe.db.sqlite.dbname")
+		dir := goapi.AppConfig().GetString("gvabe.db.sqlite.directory")
+		dbname := goapi.AppConfig().GetString("gvabe.db.sqlite.dbname")
 		err := os.MkdirAll(dir, 0711)
 		if err != nil {
 			panic(err)
 		}
 		flavor, driver, url = promsql.FlavorSqlite, "sqlite3", dir+"/"+dbname+".db"
 	case "pg", "pgsql", "postgres", "postgresql":
-		flavor, driver, url = promsql.FlavorPgSql, "pgx", goapi.AppConfig.GetString("gvabe.db.pgsql.url")
+		flavor, driver, url = promsql.FlavorPgSql, "pgx", goapi.AppConfig().GetString("gvabe.db.pgsql.url")
 	case "mysql":
-		flavor, driver, url = promsql.FlavorMySql, "mysql", goapi.AppConfig.GetString("gvabe.db.mysql.url")
+		flavor, driver, url = promsql.FlavorMySql, "mysql", goapi.AppConfig().GetString("gvabe.db.mysql.url")
 	case "cosmos", "cosmosdb":
-		flavor, driver, url = promsql.FlavorCosmosDb, "gocosmos", goapi.AppConfig.GetString("gvabe.db.cosmosdb.url")
+		flavor, driver, url = promsql.FlavorCosmosDb, "gocosmos", goapi.AppConfig().GetString("gvabe.db.cosmosdb.url")
+	default:
+		// not a SQL database
+		return nil
 	}
 
-	timezone := goapi.AppConfig.GetString("timezone")
+	timezone := goapi.AppConfig().GetString("timezone")
 	urlTimezone := strings.ReplaceAll(timezone, "/", "%2f")
 	url = strings.ReplaceAll(url, "${loc}", urlTimezone)
 	url = strings.ReplaceAll(url, "${tz}", urlTimezone)
@@ -95,8 +98,8 @@ func _createMongoConnect(dbtype string) *prommongo.MongoConnect {
 	var err error
 	switch strings.ToLower(dbtype) {
 	case "mongo", "mongodb":
-		db := goapi.AppConfig.GetString("gvabe.db.mongodb.db")
-		url := goapi.AppConfig.GetString("gvabe.db.mongodb.url")
+		db := goapi.AppConfig().GetString("gvabe.db.mongodb.db")
+		url := goapi.AppConfig().GetString("gvabe.db.mongodb.url")
 		mc, err = prommongo.NewMongoConnect(url, db, 10000)
 	}
 	if err != nil {
@@ -105,6 +108,14 @@ func _createMongoConnect(dbtype string) *prommongo.MongoConnect {
 	return mc
 }
 
+func _createMemoryDb(dbtype string) *memdb.MemoryDb {
+	switch strings.ToLower(dbtype) {
+	case "memory", "inmem":
+		return memdb.NewMemoryDb()
+	}
+	return nil
+}
+
 func _createUserDaoSql(sqlc *promsql.SqlConnect) user.UserDao {
 	if sqlc.GetDbFlavor() == promsql.FlavorCosmosDb {
 		return user.NewUserDaoCosmosdb(sqlc, user.TableUser, true)
@@ -118,6 +129,9 @@ func _createUserDaoMongo(mc *prommongo.MongoConnect) user.UserDao {
 	url := strings.ToLower(mc.GetUrl())
 	return user.NewUserDaoMongo(mc, user.TableUser, strings.Index(url, "replicaset=") >= 0)
 }
+func _createUserDaoMemory(db *memdb.MemoryDb) user.UserDao {
+	return user.NewUserDaoMemory(db, user.TableUser)
+}
 
 func _createBlogPostDaoSql(sqlc *promsql.SqlConnect) blog.BlogPostDao {
 	if sqlc.GetDbFlavor() == promsql.FlavorCosmosDb {
@@ -132,6 +146,9 @@ func _createBlogPostDaoMongo(mc *prommongo.MongoConnect) blog.BlogPostDao {
 	url := strings.ToLower(mc.GetUrl())
 	return blog.NewBlogPostDaoMongo(mc, blog.TableBlogPost, strings.Index(url, "replicaset=") >= 0)
 }
+func _createBlogPostDaoMemory(db *memdb.MemoryDb) blog.BlogPostDao {
+	return blog.NewBlogPostDaoMemory(db, blog.TableBlogPost)
+}
 
 func _createBlogCommentDaoSql(sqlc *promsql.SqlConnect) blog.BlogCommentDao {
 	if sqlc.GetDbFlavor() == promsql.FlavorCosmosDb {
@@ -146,6 +163,9 @@ func _createBlogCommentDaoMongo(mc *prommongo.MongoConnect) blog.BlogCommentDao
 	url := strings.ToLower(mc.GetUrl())
 	return blog.NewBlogCommentDaoMongo(mc, blog.TableBlogComment, strings.Index(url, "replicaset=") >= 0)
 }
+func _createBlogCommentDaoMemory(db *memdb.MemoryDb) blog.BlogCommentDao {
+	return blog.NewBlogCommentDaoMemory(db, blog.TableBlogComment)
+}
 
 func _createBlogVoteDaoSql(sqlc *promsql.SqlConnect) blog.BlogVoteDao {
 	if sqlc.GetDbFlavor() == promsql.FlavorCosmosDb {
@@ -160,295 +180,248 @@ func _createBlogVoteDaoMongo(mc *prommongo.MongoConnect) blog.BlogVoteDao {
 	url := strings.ToLower(mc.GetUrl())
 	return blog.NewBlogVoteDaoMongo(mc, blog.TableBlogVote, strings.Index(url, "replicaset=") >= 0)
 }
-
-var _sqliteTableSchema = map[string]map[string]string{
-	user.TableUser:        {user.UserColMaskUid: "VARCHAR(32)"},
-	blog.TableBlogPost:    {blog.PostColOwnerId: "VARCHAR(32)", blog.PostColIsPublic: "INT"},
-	blog.TableBlogComment: {blog.CommentColOwnerId: "VARCHAR(32)", blog.CommentColPostId: "VARCHAR(32)", blog.CommentColParentId: "VARCHAR(32)"},
-	blog.TableBlogVote:    {blog.VoteColOwnerId: "VARCHAR(32)", blog.VoteColTargetId: "VARCHAR(32)", blog.VoteColValue: "INT"},
+func _createBlogVoteDaoMemory(db *memdb.MemoryDb) blog.BlogVoteDao {
+	return blog.NewBlogVoteDaoMemory(db, blog.TableBlogVote)
 }
 
-var _mysqlTableSchema = map[string]map[string]string{
-	user.TableUser:        {user.UserColMaskUid: "VARCHAR(32)"},
-	blog.TableBlogPost:    {blog.PostColOwnerId: "VARCHAR(32)", blog.PostColIsPublic: "INT"},
-	blog.TableBlogComment: {blog.CommentColOwnerId: "VARCHAR(32)", blog.CommentColPostId: "VARCHAR(32)", blog.CommentColParentId: "VARCHAR(32)"},
-	blog.TableBlogVote:    {blog.VoteColOwnerId: "VARCHAR(32)", blog.VoteColTargetId: "VARCHAR(32)", blog.VoteColValue: "INT"},
+func _createAuditEventDaoSql(sqlc *promsql.SqlConnect) audit.AuditEventDao {
+	if sqlc.GetDbFlavor() == promsql.FlavorCosmosDb {
+		return audit.NewAuditEventDaoCosmosdb(sqlc, audit.TableAuditEvent, true)
+	}
+	return audit.NewAuditEventDaoSql(sqlc, audit.TableAuditEvent, true)
 }
-
-var _pgsqlTableSchema = map[string]map[string]string{
-	user.TableUser:        {user.UserColMaskUid: "VARCHAR(32)"},
-	blog.TableBlogPost:    {blog.PostColOwnerId: "VARCHAR(32)", blog.PostColIsPublic: "INT"},
-	blog.TableBlogComment: {blog.CommentColOwnerId: "VARCHAR(32)", blog.CommentColPostId: "VARCHAR(32)", blog.CommentColParentId: "VARCHAR(32)"},
-	blog.TableBlogVote:    {blog.VoteColOwnerId: "VARCHAR(32)", blog.VoteColTargetId: "VARCHAR(32)", blog.VoteColValue: "INT"},
+func _createAuditEventDaoDynamodb(adc *promdynamodb.AwsDynamodbConnect) audit.AuditEventDao {
+	return audit.NewAuditEventDaoDynamodb(adc, audit.TableAuditEvent)
 }
-
-var _cosmosdbTableSpec = map[string]*henge.CosmosdbCollectionSpec{
-	user.TableUser:        {Pk: henge.CosmosdbColId, Uk: [][]string{{"/" + user.UserFieldMaskId}}},
-	blog.TableBlogPost:    {Pk: henge.CosmosdbColId},
-	blog.TableBlogComment: {Pk: henge.CosmosdbColId},
-	blog.TableBlogVote:    {Pk: henge.CosmosdbColId, Uk: [][]string{{"/" + blog.VoteFieldOwnerId, "/" + blog.VoteFieldTargetId}}},
+func _createAuditEventDaoMongo(mc *prommongo.MongoConnect) audit.AuditEventDao {
+	url := strings.ToLower(mc.GetUrl())
+	return audit.NewAuditEventDaoMongo(mc, audit.TableAuditEvent, strings.Index(url, "replicaset=") >= 0)
+}
+func _createAuditEventDaoMemory(db *memdb.MemoryDb) audit.AuditEventDao {
+	return audit.NewAuditEventDaoMemory(db, audit.TableAuditEvent)
 }
 
-func _createSqlTables(sqlc *promsql.SqlConnect, dbtype string) {
-	switch sqlc.GetDbFlavor() {
-	case promsql.FlavorSqlite:
-		for tbl, schema := range _sqliteTableSchema {
-			if err := henge.InitSqliteTable(sqlc, tbl, schema); err != nil {
-				log.Printf("[WARN] creating table %s (%s): %s\n", tbl, dbtype, err)
-			}
-		}
-	case promsql.FlavorPgSql:
-		for tbl, schema := range _pgsqlTableSchema {
-			if err := henge.InitPgsqlTable(sqlc, tbl, schema); err != nil {
-				log.Printf("[WARN] creating table %s (%s): %s\n", tbl, dbtype, err)
-			}
-		}
-	case promsql.FlavorMySql:
-		for tbl, schema := range _mysqlTableSchema {
-			if err := henge.InitMysqlTable(sqlc, tbl, schema); err != nil {
-				log.Printf("[WARN] creating table %s (%s): %s\n", tbl, dbtype, err)
-			}
-		}
-	case promsql.FlavorCosmosDb:
-		for tbl, spec := range _cosmosdbTableSpec {
-			if err := henge.InitCosmosdbCollection(sqlc, tbl, spec); err != nil {
-				log.Printf("[WARN] creating table %s (%s): %s\n", tbl, dbtype, err)
-			}
-		}
-	}
-
+func _createIdempotencyDaoSql(sqlc *promsql.SqlConnect) henge.UniversalDao {
 	if sqlc.GetDbFlavor() == promsql.FlavorCosmosDb {
-		return
+		spec := &henge.CosmosdbDaoSpec{PkName: henge.CosmosdbColId, TxModeOnWrite: true}
+		return henge.NewUniversalDaoCosmosdbSql(sqlc, TableIdempotency, spec)
 	}
+	return henge.NewUniversalDaoSql(sqlc, TableIdempotency, true, map[string]string{idempotencyColExpiry: idempotencyFieldExpiry})
+}
+func _createIdempotencyDaoDynamodb(adc *promdynamodb.AwsDynamodbConnect) henge.UniversalDao {
+	return henge.NewUniversalDaoDynamodb(adc, TableIdempotency, &henge.DynamodbDaoSpec{})
+}
+func _createIdempotencyDaoMongo(mc *prommongo.MongoConnect) henge.UniversalDao {
+	url := strings.ToLower(mc.GetUrl())
+	return henge.NewUniversalDaoMongo(mc, TableIdempotency, strings.Index(url, "replicaset=") >= 0)
+}
+func _createIdempotencyDaoMemory(db *memdb.MemoryDb) henge.UniversalDao {
+	return memdb.NewUniversalDaoMemory(db, TableIdempotency)
+}
 
-	// user
-	if err := henge.CreateIndexSql(sqlc, user.TableUser, true, []string{user.UserColMaskUid}); err != nil {
-		log.Printf("[WARN] creating table index %s/%s (%s): %s\n", user.TableUser, user.UserColMaskUid, dbtype, err)
+func _createSchemaVersionDaoSql(sqlc *promsql.SqlConnect) henge.UniversalDao {
+	if sqlc.GetDbFlavor() == promsql.FlavorCosmosDb {
+		spec := &henge.CosmosdbDaoSpec{PkName: henge.CosmosdbColId, TxModeOnWrite: true}
+		return henge.NewUniversalDaoCosmosdbSql(sqlc, TableSchemaVersion, spec)
 	}
+	return henge.NewUniversalDaoSql(sqlc, TableSchemaVersion, true, nil)
+}
+func _createSchemaVersionDaoDynamodb(adc *promdynamodb.AwsDynamodbConnect) henge.UniversalDao {
+	return henge.NewUniversalDaoDynamodb(adc, TableSchemaVersion, &henge.DynamodbDaoSpec{})
+}
+func _createSchemaVersionDaoMongo(mc *prommongo.MongoConnect) henge.UniversalDao {
+	url := strings.ToLower(mc.GetUrl())
+	return henge.NewUniversalDaoMongo(mc, TableSchemaVersion, strings.Index(url, "replicaset=") >= 0)
+}
+func _createSchemaVersionDaoMemory(db *memdb.MemoryDb) henge.UniversalDao {
+	return memdb.NewUniversalDaoMemory(db, TableSchemaVersion)
+}
 
-	// blog post
-	if err := henge.CreateIndexSql(sqlc, blog.TableBlogPost, false, []string{blog.PostColOwnerId}); err != nil {
-		log.Printf("[WARN] creating table index %s/%s (%s): %s\n", blog.TableBlogPost, blog.PostColOwnerId, dbtype, err)
-	}
+// database connections created by initDaos, closed by closeDaos
+var (
+	sqlConnect      *promsql.SqlConnect
+	mongoConnect    *prommongo.MongoConnect
+	dynamodbConnect *promdynamodb.AwsDynamodbConnect
+	memoryDb        *memdb.MemoryDb
+)
 
-	// blog comment
-	if err := henge.CreateIndexSql(sqlc, blog.TableBlogComment, false, []string{blog.CommentColOwnerId}); err != nil {
-		log.Printf("[WARN] creating table index %s/%s (%s): %s\n", blog.TableBlogComment, blog.CommentColOwnerId, dbtype, err)
-	}
-	if err := henge.CreateIndexSql(sqlc, blog.TableBlogComment, false, []string{blog.CommentColPostId, blog.CommentColParentId}); err != nil {
-		log.Printf("[WARN] creating table index %s/%s (%s): %s\n", blog.TableBlogComment, blog.CommentColPostId+":"+blog.CommentColParentId, dbtype, err)
-	}
+// decorators of the blog post DAO and voter, registered by DecorateBlogPostDao and DecorateBlogPostVoter
+var (
+	blogPostDaoDecorators   []func(dao blog.BlogPostDao) blog.BlogPostDao
+	blogPostVoterDecorators []func(voter blog.BlogPostVoter) blog.BlogPostVoter
+)
 
-	// blog vote
-	if err := henge.CreateIndexSql(sqlc, blog.TableBlogVote, true, []string{blog.VoteColOwnerId, blog.VoteColTargetId}); err != nil {
-		log.Printf("[WARN] creating table index %s/%s (%s): %s\n", blog.TableBlogVote, blog.VoteColOwnerId+":"+blog.VoteColTargetId, dbtype, err)
-	}
-	if err := henge.CreateIndexSql(sqlc, blog.TableBlogVote, false, []string{blog.VoteColTargetId, blog.VoteColValue}); err != nil {
-		log.Printf("[WARN] creating table index %s/%s (%s): %s\n", blog.TableBlogVote, blog.VoteColTargetId+":"+blog.VoteColValue, dbtype, err)
-	}
+// DecorateBlogPostDao registers a decorator of the blog post DAO created by the bootstrapper (e.g. to instrument the DAO,
+// or to inject faults in tests). Decorators are applied in registration order, beneath the caching decorator (see
+// RegisterDaoCache). It must be called before the application is bootstrapped.
+//
+// available since template-v0.5.0
+func DecorateBlogPostDao(decorator func(dao blog.BlogPostDao) blog.BlogPostDao) {
+	blogPostDaoDecorators = append(blogPostDaoDecorators, decorator)
 }
 
-func _dynamodbWaitforGSI(adc *promdynamodb.AwsDynamodbConnect, table, gsi string, timeout time.Duration) error {
-	t := time.Now()
-	for status, err := adc.GetGlobalSecondaryIndexStatus(nil, table, gsi); ; {
-		if err != nil {
-			return err
-		}
-		if strings.ToUpper(status) == "ACTIVE" {
-			return nil
-		}
-		if time.Now().Sub(t).Milliseconds() > timeout.Milliseconds() {
-			return errors.New("")
-		}
-	}
+// DecorateBlogPostVoter registers a decorator of the blog post voter created by the bootstrapper (e.g. to instrument the
+// voter, or to inject faults in tests). Decorators are applied in registration order. It must be called before the
+// application is bootstrapped.
+//
+// available since template-v0.5.0
+func DecorateBlogPostVoter(decorator func(voter blog.BlogPostVoter) blog.BlogPostVoter) {
+	blogPostVoterDecorators = append(blogPostVoterDecorators, decorator)
 }
 
-func _createDynamodbTables(adc *promdynamodb.AwsDynamodbConnect) {
-	if err := blog.InitBlogCommentTableDynamodb(adc, blog.TableBlogComment); err != nil {
-		panic(err)
-	}
-	if err := blog.InitBlogPostTableDynamodb(adc, blog.TableBlogPost); err != nil {
-		panic(err)
-	}
-	if err := blog.InitBlogVoteTableDynamodb(adc, blog.TableBlogVote); err != nil {
-		panic(err)
-	}
-
-	spec := &henge.DynamodbTablesSpec{MainTableRcu: 2, MainTableWcu: 1, CreateUidxTable: true, UidxTableRcu: 2, UidxTableWcu: 1}
-	if err := henge.InitDynamodbTables(adc, user.TableUser, spec); err != nil {
-		log.Printf("[WARN] creating tableName %s (%s): %s\n", user.TableUser, "DynamoDB", err)
-	}
-
-	var tableName, gsiName, colName string
-
-	// user
-	tableName, colName, gsiName = user.TableUser, user.UserFieldMaskId, "gsi_"+colName
-	if err := adc.CreateGlobalSecondaryIndex(nil, tableName, gsiName, 2, 1,
-		[]promdynamodb.AwsDynamodbNameAndType{{Name: colName, Type: promdynamodb.AwsAttrTypeString}},
-		[]promdynamodb.AwsDynamodbNameAndType{{Name: colName, Type: promdynamodb.AwsKeyTypePartition}}); err != nil {
-		log.Printf("[WARN] creating GSI %s/%s (%s): %s\n", tableName, colName, "DynamoDB", err)
-	} else if err := _dynamodbWaitforGSI(adc, tableName, gsiName, 10*time.Second); err != nil {
-		log.Printf("[WARN] creating GSI %s/%s (%s): %s\n", tableName, colName, "DynamoDB", err)
-	}
-}
-
-func _createMongoCollections(mc *prommongo.MongoConnect) {
-	if err := henge.InitMongoCollection(mc, user.TableUser); err != nil {
-		log.Printf("[WARN] creating collection %s (%s): %s\n", user.TableUser, "MongoDB", err)
-	}
-	if err := henge.InitMongoCollection(mc, blog.TableBlogPost); err != nil {
-		log.Printf("[WARN] creating collection %s (%s): %s\n", blog.TableBlogPost, "MongoDB", err)
-	}
-	if err := henge.InitMongoCollection(mc, blog.TableBlogComment); err != nil {
-		log.Printf("[WARN] creating collection %s (%s): %s\n", blog.TableBlogComment, "MongoDB", err)
-	}
-	if err := henge.InitMongoCollection(mc, blog.TableBlogVote); err != nil {
-		log.Printf("[WARN] creating collection %s (%s): %s\n", blog.TableBlogVote, "MongoDB", err)
-	}
-
-	unique := true
-	nonUnique := false
-	var idxName string
-
-	// user
-	idxName = "idx_" + user.UserFieldMaskId
-	if _, err := mc.CreateCollectionIndexes(user.TableUser, []interface{}{mongo.IndexModel{
-		Keys: bson.D{
-			{user.UserFieldMaskId, 1},
-		},
-		Options: &options.IndexOptions{
-			Name:   &idxName,
-			Unique: &unique,
-		},
-	}}); err != nil {
-		log.Printf("[WARN] creating collection index %s/%s (%s): %s\n", user.TableUser, user.UserFieldMaskId, "MongoDB", err)
-	}
-
-	// blog post
-	idxName = "idx_" + blog.PostFieldOwnerId
-	if _, err := mc.CreateCollectionIndexes(blog.TableBlogPost, []interface{}{mongo.IndexModel{
-		Keys: bson.D{
-			{blog.PostFieldOwnerId, 1},
-		},
-		Options: &options.IndexOptions{
-			Name:   &idxName,
-			Unique: &nonUnique,
-		},
-	}}); err != nil {
-		log.Printf("[WARN] creating collection index %s/%s (%s): %s\n", blog.TableBlogPost, blog.PostFieldOwnerId, "MongoDB", err)
-	}
-
-	// blog comment
-	idxName = "idx_" + blog.CommentFieldOwnerId
-	if _, err := mc.CreateCollectionIndexes(blog.TableBlogComment, []interface{}{mongo.IndexModel{
-		Keys: bson.D{
-			{blog.CommentFieldOwnerId, 1},
-		},
-		Options: &options.IndexOptions{
-			Name:   &idxName,
-			Unique: &nonUnique,
-		},
-	}}); err != nil {
-		log.Printf("[WARN] creating collection index %s/%s (%s): %s\n", blog.TableBlogComment, blog.CommentFieldOwnerId, "MongoDB", err)
-	}
-	idxName = "idx_" + blog.CommentFieldPostId + "_" + blog.CommentFieldParentId
-	if _, err := mc.CreateCollectionIndexes(blog.TableBlogComment, []interface{}{mongo.IndexModel{
-		Keys: bson.D{
-			{blog.CommentFieldPostId, 1},
-			{blog.CommentFieldParentId, 1},
-		},
-		Options: &options.IndexOptions{
-			Name:   &idxName,
-			Unique: &nonUnique,
-		},
-	}}); err != nil {
-		log.Printf("[WARN] creating collection index %s/%s (%s): %s\n", blog.TableBlogComment, blog.CommentFieldPostId+":"+blog.CommentFieldParentId, "MongoDB", err)
-	}
-
-	// blog vote
-	idxName = "idx_" + blog.VoteFieldOwnerId + "_" + blog.VoteFieldTargetId
-	if _, err := mc.CreateCollectionIndexes(blog.TableBlogVote, []interface{}{mongo.IndexModel{
-		Keys: bson.D{
-			{blog.VoteFieldOwnerId, 1},
-			{blog.VoteFieldTargetId, 1},
-		},
-		Options: &options.IndexOptions{
-			Name:   &idxName,
-			Unique: &unique,
-		},
-	}}); err != nil {
-		log.Printf("[WARN] creating collection index %s/%s (%s): %s\n", blog.TableBlogVote, blog.VoteFieldOwnerId+":"+blog.VoteFieldTargetId, "MongoDB", err)
-	}
-	idxName = "idx_" + blog.VoteFieldTargetId + "_" + blog.VoteFieldValue
-	if _, err := mc.CreateCollectionIndexes(blog.TableBlogVote, []interface{}{mongo.IndexModel{
-		Keys: bson.D{
-			{blog.VoteFieldTargetId, 1},
-			{blog.VoteFieldValue, 1},
-		},
-		Options: &options.IndexOptions{
-			Name:   &idxName,
-			Unique: &nonUnique,
-		},
-	}}); err != nil {
-		log.Printf("[WARN] creating collection index %s/%s (%s): %s\n", blog.TableBlogVote, blog.VoteFieldTargetId+":"+blog.VoteFieldValue, "MongoDB", err)
+func initDaos() {
+	openDaos()
+	// in-memory database always starts empty, hence migrations are always applied
+	if memoryDb != nil || goapi.AppConfig().GetBoolean("gvabe.db.auto_migrate", true) {
+		if _, _, err := migrateDatabase(0, false, logging.Infof); err != nil {
+			panic(err)
+		}
+	} else if current, err := getSchemaVersion(); err != nil {
+		panic(fmt.Sprintf("cannot read database schema version (run command \"db migrate\" or enable [gvabe.db.auto_migrate]): %s", err))
+	} else if latest := latestSchemaVersion(); current < latest {
+		panic(fmt.Sprintf("database schema version is %d but %d is required, run command \"db migrate\" or enable [gvabe.db.auto_migrate]", current, latest))
 	}
+	_initUsers()
+	_initBlog()
 }
 
-func initDaos() {
-	dbtype := strings.ToLower(goapi.AppConfig.GetString("gvabe.db.type"))
+// openDaos creates database connections and DAO instances, without creating tables/collections or initial data.
+//
+// available since template-v0.5.0
+func openDaos() {
+	dbtype := strings.ToLower(goapi.AppConfig().GetString("gvabe.db.type"))
 	if DEBUG_MODE {
-		log.Printf("[DEUBG] db-type: %s", dbtype)
+		logging.Debugf("db-type: %s", dbtype)
 	}
 
 	// create DB connect instance
 	sqlc := _createSqlConnect(dbtype)
 	mc := _createMongoConnect(dbtype)
 	adc := _createDynamodbConnect(dbtype)
-	if sqlc == nil && mc == nil && adc == nil {
+	mdb := _createMemoryDb(dbtype)
+	if sqlc == nil && mc == nil && adc == nil && mdb == nil {
 		panic(fmt.Sprintf("unknown databbase type: %s", dbtype))
 	}
+	sqlConnect, mongoConnect, dynamodbConnect, memoryDb = sqlc, mc, adc, mdb
 
 	if sqlc != nil {
-		// create database tables
-		_createSqlTables(sqlc, dbtype)
-
 		// create DAO instances
 		userDaov2 = _createUserDaoSql(sqlc)
 		blogPostDaov2 = _createBlogPostDaoSql(sqlc)
 		blogCommentDaov2 = _createBlogCommentDaoSql(sqlc)
 		blogVoteDaov2 = _createBlogVoteDaoSql(sqlc)
+		auditEventDaov2 = _createAuditEventDaoSql(sqlc)
+		idempotencyDao = _createIdempotencyDaoSql(sqlc)
+		schemaVersionDao = _createSchemaVersionDaoSql(sqlc)
 	}
 	if adc != nil {
-		// create AWS DynamoDB tables
-		_createDynamodbTables(adc)
-
 		// create DAO instances
 		userDaov2 = _createUserDaoDynamodb(adc)
 		blogPostDaov2 = _createBlogPostDaoDynamodb(adc)
 		blogCommentDaov2 = _createBlogCommentDaoDynamodb(adc)
 		blogVoteDaov2 = _createBlogVoteDaoDynamodb(adc)
+		auditEventDaov2 = _createAuditEventDaoDynamodb(adc)
+		idempotencyDao = _createIdempotencyDaoDynamodb(adc)
+		schemaVersionDao = _createSchemaVersionDaoDynamodb(adc)
 	}
 	if mc != nil {
-		// create MongoDB collections
-		_createMongoCollections(mc)
-
 		// create DAO instances
 		userDaov2 = _createUserDaoMongo(mc)
 		blogPostDaov2 = _createBlogPostDaoMongo(mc)
 		blogCommentDaov2 = _createBlogCommentDaoMongo(mc)
 		blogVoteDaov2 = _createBlogVoteDaoMongo(mc)
+		auditEventDaov2 = _createAuditEventDaoMongo(mc)
+		idempotencyDao = _createIdempotencyDaoMongo(mc)
+		schemaVersionDao = _createSchemaVersionDaoMongo(mc)
+	}
+	if mdb != nil {
+		// create DAO instances
+		userDaov2 = _createUserDaoMemory(mdb)
+		blogPostDaov2 = _createBlogPostDaoMemory(mdb)
+		blogCommentDaov2 = _createBlogCommentDaoMemory(mdb)
+		blogVoteDaov2 = _createBlogVoteDaoMemory(mdb)
+		auditEventDaov2 = _createAuditEventDaoMemory(mdb)
+		idempotencyDao = _createIdempotencyDaoMemory(mdb)
+		schemaVersionDao = _createSchemaVersionDaoMemory(mdb)
+	}
+	for _, decorator := range blogPostDaoDecorators {
+		blogPostDaov2 = decorator(blogPostDaov2)
+	}
+	initDaoCache()
+	blogPostVoter = _createBlogPostVoter(sqlc)
+	for _, decorator := range blogPostVoterDecorators {
+		blogPostVoter = decorator(blogPostVoter)
+	}
+}
+
+// _createBlogPostVoter creates the BlogPostVoter that records votes for blog posts: votes are written within
+// transactions on SQL databases, and with conditional writes on other databases (Cosmos DB included).
+//
+// available since template-v0.5.0
+func _createBlogPostVoter(sqlc *promsql.SqlConnect) blog.BlogPostVoter {
+	if sqlc != nil && sqlc.GetDbFlavor() != promsql.FlavorCosmosDb {
+		return blog.NewBlogPostVoterSql(sqlc, blog.TableBlogPost, blog.TableBlogVote)
 	}
+	return blog.NewBlogPostVoter(blogPostDaov2, blogVoteDaov2)
+}
 
-	_initUsers()
-	_initBlog()
+// pingDatabase checks if the database opened by openDaos is reachable.
+//
+// available since template-v0.5.0
+func pingDatabase(ctx context.Context) error {
+	switch {
+	case sqlConnect != nil:
+		return sqlConnect.Ping(ctx)
+	case mongoConnect != nil:
+		return mongoConnect.Ping(ctx)
+	case dynamodbConnect != nil:
+		_, err := dynamodbConnect.ListTables(ctx)
+		return err
+	case memoryDb != nil:
+		return memoryDb.Ping()
+	}
+	return errors.New("no database connection")
+}
+
+// closeDaos closes database connections created by initDaos.
+//
+// available since template-v0.5.0
+func closeDaos(ctx context.Context) error {
+	var errs []string
+	if sqlConnect != nil {
+		if err := sqlConnect.Close(); err != nil {
+			errs = append(errs, fmt.Sprintf("closing SQL connection: %s", err))
+		}
+		sqlConnect = nil
+	}
+	if mongoConnect != nil {
+		if err := mongoConnect.Close(ctx); err != nil {
+			errs = append(errs, fmt.Sprintf("closing MongoDB connection: %s", err))
+		}
+		mongoConnect = nil
+	}
+	if dynamodbConnect != nil {
+		if err := dynamodbConnect.Close(); err != nil {
+			errs = append(errs, fmt.Sprintf("closing DynamoDB connection: %s", err))
+		}
+		dynamodbConnect = nil
+	}
+	if memoryDb != nil {
+		memoryDb.Close()
+		memoryDb = nil
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
 }
 
 func _initUsers() {
-	adminUserId := goapi.AppConfig.GetString("gvabe.init.admin_user_id")
-	adminUserPwd := goapi.AppConfig.GetString("gvabe.init.admin_user_pwd")
-	adminUserName := goapi.AppConfig.GetString("gvabe.init.admin_user_name")
+	adminUserId := goapi.AppConfig().GetString("gvabe.init.admin_user_id")
+	adminUserPwd := goapi.AppConfig().GetString("gvabe.init.admin_user_pwd")
+	adminUserName := goapi.AppConfig().GetString("gvabe.init.admin_user_name")
 	if adminUserId == "" || adminUserPwd == "" || adminUserName == "" {
-		log.Printf("[WARN] Admin user-id/password/display-name not found at config [gvabe.init.admin_user_id/admin_user_pwd/admin_user_name], will not create admin account")
+		logging.Warnf("Admin user-id/password/display-name not found at config [gvabe.init.admin_user_id/admin_user_pwd/admin_user_name], will not create admin account")
 		return
 	}
 	adminUser, err := userDaov2.Get(adminUserId)
@@ -458,19 +431,19 @@ func _initUsers() {
 	if adminUser == nil {
 		adminUser = user.NewUser(goapi.AppVersionNumber, adminUserId, utils.UniqueId())
 		adminUser.SetPassword(encryptPassword(adminUserId, adminUserPwd)).SetDisplayName(adminUserName).SetAdmin(true)
-		log.Printf("[INFO] Admin user [%s] not found, creating one...(%s)", adminUserId, adminUser.GetMaskId())
+		logging.Infof("Admin user [%s] not found, creating one...(%s)", adminUserId, adminUser.GetMaskId())
 		result, err := userDaov2.Create(adminUser)
 		if err != nil {
 			panic(fmt.Sprintf("error while creating user [%s]: %e", adminUserId, err))
 		}
 		if !result {
-			log.Printf("[ERROR] Cannot create user [%s]", adminUserId)
+			logging.Errorf("Cannot create user [%s]", adminUserId)
 		}
 	}
 }
 
 func _initBlog() {
-	adminUserId := goapi.AppConfig.GetString("gvabe.init.admin_user_id")
+	adminUserId := goapi.AppConfig().GetString("gvabe.init.admin_user_id")
 	adminUser, err := userDaov2.Get(adminUserId)
 	if err != nil {
 		panic(fmt.Sprintf("error while getting user [%s]: %e", adminUserId, err))
@@ -482,8 +455,8 @@ func _initBlog() {
 		panic(fmt.Sprintf("error while getting blog post [%s]: %e", postId, err))
 	}
 	if introBlogPost == nil {
-		log.Printf("[INFO] Introduction blog post [%s] not found, creating one...", postId)
-		appName := goapi.AppConfig.GetString("app.name")
+		logging.Infof("Introduction blog post [%s] not found, creating one...", postId)
+		appName := goapi.AppConfig().GetString("app.name")
 		title := "Welcome to " + appName + " v" + goapi.AppVersion
 		content := `This is the introduction blog post. It will quickly introduce highlighted features.
 
@@ -505,7 +478,7 @@ _Public_ posts are visible to all users for _commenting_ (coming soon) and _voti
 			panic(fmt.Sprintf("error while creating blog post [%s]: %e", postId, err))
 		}
 		if !result {
-			log.Printf("[ERROR] Cannot create blog post [%s]", postId)
+			logging.Errorf("Cannot create blog post [%s]", postId)
 		}
 	}
 }
@@ -0,0 +1,507 @@
+package gvabe
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/btnguyen2k/consu/reddo"
+	"github.com/btnguyen2k/henge"
+	promdynamodb "github.com/btnguyen2k/prom/dynamodb"
+	prommongo "github.com/btnguyen2k/prom/mongo"
+	promsql "github.com/btnguyen2k/prom/sql"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"main/src/goapi"
+	"main/src/gvabe/bov2/audit"
+	"main/src/gvabe/bov2/blog"
+	"main/src/gvabe/bov2/user"
+	"main/src/memdb"
+)
+
+const (
+	// TableSchemaVersion is the table/collection that stores the database schema version record.
+	TableSchemaVersion = "gva_schema_version"
+
+	schemaVersionRecordId    = "gvabe"
+	schemaVersionAttrVersion = "version"
+	schemaVersionAttrHistory = "history"
+)
+
+// underlying storage of the schema version record, created by openDaos
+var schemaVersionDao henge.UniversalDao
+
+// migrationStep is a single operation of a migration, e.g. creating a table or an index.
+type migrationStep struct {
+	Desc string // human-readable description, printed in dry-run mode
+	Run  func() error
+}
+
+// migration is a versioned change of the database schema.
+//
+// A migration provides steps for each supported backend; nil means the migration has nothing to do on that backend
+// (the schema version is still bumped). Steps should be safe to re-run: "already exists" errors are reported and
+// ignored (see _isAlreadyExistsError), other errors abort the migration.
+//
+// available since template-v0.5.0
+type migration struct {
+	Version     int
+	Description string
+	Sql         func(sqlc *promsql.SqlConnect) []*migrationStep // SQLite, PostgreSQL and MySQL
+	Cosmosdb    func(sqlc *promsql.SqlConnect) []*migrationStep
+	Dynamodb    func(adc *promdynamodb.AwsDynamodbConnect) []*migrationStep
+	Mongodb     func(mc *prommongo.MongoConnect) []*migrationStep
+	Memory      func(db *memdb.MemoryDb) []*migrationStep
+}
+
+// migrations is the ordered list of schema migrations. Append new migrations to the end of the list with the next
+// version number; never change a migration once it has been released, add a new one instead.
+var migrations = []*migration{
+	{
+		Version:     1,
+		Description: "create tables/collections and indexes",
+		Sql:         _migration1Sql,
+		Cosmosdb:    _migration1Cosmosdb,
+		Dynamodb:    _migration1Dynamodb,
+		Mongodb:     _migration1Mongodb,
+		Memory:      _migration1Memory,
+	},
+	{
+		Version:     2,
+		Description: "index blog posts by visibility",
+		Sql: func(sqlc *promsql.SqlConnect) []*migrationStep {
+			return []*migrationStep{_sqlIndexStep(sqlc, blog.TableBlogPost, false, blog.PostColIsPublic)}
+		},
+		Mongodb: func(mc *prommongo.MongoConnect) []*migrationStep {
+			return []*migrationStep{_mongoIndexStep(mc, blog.TableBlogPost, false, bson.D{{Key: blog.PostFieldIsPublic, Value: 1}})}
+		},
+	},
+}
+
+// latestSchemaVersion returns the version of the last migration.
+func latestSchemaVersion() int {
+	return migrations[len(migrations)-1].Version
+}
+
+// _isAlreadyExistsError checks if err is caused by creating a table/collection/index that already exists.
+func _isAlreadyExistsError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "already exists") || // SQLite, PostgreSQL, MongoDB, DynamoDB
+		strings.Contains(msg, "duplicate key name") // MySQL
+}
+
+/*----------------------------------------------------------------------*/
+
+// getSchemaVersion returns the current schema version of the database opened by openDaos (0 if no migration has been applied).
+//
+// available since template-v0.5.0
+func getSchemaVersion() (int, error) {
+	ubo, err := schemaVersionDao.Get(schemaVersionRecordId)
+	if err != nil || ubo == nil {
+		return 0, err
+	}
+	v, err := ubo.GetDataAttrAs(schemaVersionAttrVersion, reddo.TypeInt)
+	if err != nil || v == nil {
+		return 0, err
+	}
+	return int(v.(int64)), nil
+}
+
+// getSchemaHistory returns the applied migrations recorded in the schema version record, oldest first.
+func getSchemaHistory() ([]map[string]interface{}, error) {
+	ubo, err := schemaVersionDao.Get(schemaVersionRecordId)
+	if err != nil || ubo == nil {
+		return nil, err
+	}
+	v, err := ubo.GetDataAttr(schemaVersionAttrHistory)
+	if err != nil {
+		return nil, err
+	}
+	history := make([]map[string]interface{}, 0)
+	if list, ok := v.([]interface{}); ok {
+		for _, item := range list {
+			if entry, ok := item.(map[string]interface{}); ok {
+				history = append(history, entry)
+			}
+		}
+	}
+	return history, nil
+}
+
+// setSchemaVersion records that migration m has been applied.
+func setSchemaVersion(m *migration) error {
+	ubo, err := schemaVersionDao.Get(schemaVersionRecordId)
+	if err != nil {
+		return err
+	}
+	history := make([]interface{}, 0)
+	if ubo == nil {
+		ubo = henge.NewUniversalBo(schemaVersionRecordId, goapi.AppVersionNumber)
+	} else if v, _ := ubo.GetDataAttr(schemaVersionAttrHistory); v != nil {
+		if list, ok := v.([]interface{}); ok {
+			history = list
+		}
+	}
+	history = append(history, map[string]interface{}{
+		"version":     m.Version,
+		"description": m.Description,
+		"app_version": goapi.AppVersion,
+		"t_applied":   time.Now().Format(time.RFC3339),
+	})
+	ubo.SetDataAttr(schemaVersionAttrVersion, m.Version)
+	ubo.SetDataAttr(schemaVersionAttrHistory, history)
+	_, _, err = schemaVersionDao.Save(ubo.Sync())
+	return err
+}
+
+// _schemaVersionTableStep creates the table/collection that stores the schema version record.
+func _schemaVersionTableStep() *migrationStep {
+	step := &migrationStep{Desc: "create table " + TableSchemaVersion}
+	switch {
+	case sqlConnect != nil:
+		sqlc := sqlConnect
+		step.Run = func() error {
+			switch sqlc.GetDbFlavor() {
+			case promsql.FlavorSqlite:
+				return henge.InitSqliteTable(sqlc, TableSchemaVersion, nil)
+			case promsql.FlavorPgSql:
+				return henge.InitPgsqlTable(sqlc, TableSchemaVersion, nil)
+			case promsql.FlavorMySql:
+				return henge.InitMysqlTable(sqlc, TableSchemaVersion, nil)
+			case promsql.FlavorCosmosDb:
+				return henge.InitCosmosdbCollection(sqlc, TableSchemaVersion, &henge.CosmosdbCollectionSpec{Pk: henge.CosmosdbColId})
+			}
+			return fmt.Errorf("unsupported SQL flavor: %v", sqlc.GetDbFlavor())
+		}
+	case dynamodbConnect != nil:
+		adc := dynamodbConnect
+		step.Run = func() error {
+			return henge.InitDynamodbTables(adc, TableSchemaVersion, &henge.DynamodbTablesSpec{MainTableRcu: 1, MainTableWcu: 1})
+		}
+	case mongoConnect != nil:
+		mc := mongoConnect
+		step.Run = func() error { return henge.InitMongoCollection(mc, TableSchemaVersion) }
+	case memoryDb != nil:
+		db := memoryDb
+		step.Run = func() error { return db.CreateTable(TableSchemaVersion) }
+	default:
+		step.Run = func() error { return errors.New("no database connection") }
+	}
+	return step
+}
+
+// _migrationSteps returns the steps of migration m for the database opened by openDaos.
+func _migrationSteps(m *migration) []*migrationStep {
+	switch {
+	case sqlConnect != nil && sqlConnect.GetDbFlavor() == promsql.FlavorCosmosDb:
+		if m.Cosmosdb != nil {
+			return m.Cosmosdb(sqlConnect)
+		}
+	case sqlConnect != nil:
+		if m.Sql != nil {
+			return m.Sql(sqlConnect)
+		}
+	case dynamodbConnect != nil:
+		if m.Dynamodb != nil {
+			return m.Dynamodb(dynamodbConnect)
+		}
+	case mongoConnect != nil:
+		if m.Mongodb != nil {
+			return m.Mongodb(mongoConnect)
+		}
+	case memoryDb != nil:
+		if m.Memory != nil {
+			return m.Memory(memoryDb)
+		}
+	}
+	return nil
+}
+
+// pendingMigrations returns migrations with version in range (current, target], in order.
+//
+// available since template-v0.5.0
+func pendingMigrations(current, target int) []*migration {
+	result := make([]*migration, 0)
+	for _, m := range migrations {
+		if m.Version > current && m.Version <= target {
+			result = append(result, m)
+		}
+	}
+	return result
+}
+
+// migrateDatabase applies pending migrations on the database opened by openDaos, up to version target (0 means the
+// latest version). Progress is reported via report. Migrations are applied one by one and the schema version is
+// recorded after each, so that a failed run can be resumed.
+//
+// If dryRun is true, steps are reported but neither executed nor recorded.
+//
+// This function returns the schema version before and after migrating.
+//
+// available since template-v0.5.0
+func migrateDatabase(target int, dryRun bool, report func(format string, args ...interface{})) (int, int, error) {
+	latest := latestSchemaVersion()
+	if target <= 0 {
+		target = latest
+	}
+	if target > latest {
+		return 0, 0, fmt.Errorf("unknown schema version %d (latest version is %d)", target, latest)
+	}
+	if !dryRun {
+		step := _schemaVersionTableStep()
+		if err := step.Run(); err != nil && !_isAlreadyExistsError(err) {
+			return 0, 0, fmt.Errorf("%s: %s", step.Desc, err)
+		}
+	}
+	current, err := getSchemaVersion()
+	if err != nil {
+		if !dryRun {
+			return 0, 0, fmt.Errorf("cannot read schema version: %s", err)
+		}
+		report("Cannot read schema version (%s), assuming no migration has been applied", err)
+	}
+	if target < current {
+		return current, current, fmt.Errorf("schema version is %d, migrating down to version %d is not supported", current, target)
+	}
+	pending := pendingMigrations(current, target)
+	if len(pending) == 0 {
+		report("Database schema is up to date (version %d)", current)
+		return current, current, nil
+	}
+	from := current
+	for _, m := range pending {
+		report("Migration %d: %s", m.Version, m.Description)
+		steps := _migrationSteps(m)
+		if len(steps) == 0 {
+			report("  - nothing to do on this database")
+		}
+		for _, step := range steps {
+			if dryRun {
+				report("  - %s", step.Desc)
+				continue
+			}
+			if err := step.Run(); _isAlreadyExistsError(err) {
+				report("  - %s: already exists, skipped", step.Desc)
+			} else if err != nil {
+				return from, current, fmt.Errorf("migration %d (%s) failed at step [%s]: %s", m.Version, m.Description, step.Desc, err)
+			} else {
+				report("  - %s: done", step.Desc)
+			}
+		}
+		if !dryRun {
+			if err := setSchemaVersion(m); err != nil {
+				return from, current, fmt.Errorf("migration %d (%s) applied but cannot be recorded: %s", m.Version, m.Description, err)
+			}
+		}
+		current = m.Version
+	}
+	if dryRun {
+		report("Dry run: database schema would be migrated from version %d to %d", from, current)
+	} else {
+		report("Database schema migrated from version %d to %d", from, current)
+	}
+	return from, current, nil
+}
+
+/*----------------------------------------------------------------------*/
+
+// _sqlIndexStep creates an index on table's columns (see henge.CreateIndexSql).
+func _sqlIndexStep(sqlc *promsql.SqlConnect, table string, unique bool, cols ...string) *migrationStep {
+	desc := "create index on " + table + "(" + strings.Join(cols, ",") + ")"
+	if unique {
+		desc = "create unique index on " + table + "(" + strings.Join(cols, ",") + ")"
+	}
+	return &migrationStep{Desc: desc, Run: func() error { return henge.CreateIndexSql(sqlc, table, unique, cols) }}
+}
+
+// _mongoIndexStep creates an index on collection's keys, index is named after keys' names.
+func _mongoIndexStep(mc *prommongo.MongoConnect, collection string, unique bool, keys bson.D) *migrationStep {
+	names := make([]string, len(keys))
+	for i, k := range keys {
+		names[i] = k.Key
+	}
+	idxName := "idx_" + strings.Join(names, "_")
+	desc := "create index " + idxName + " on " + collection
+	if unique {
+		desc = "create unique index " + idxName + " on " + collection
+	}
+	return &migrationStep{Desc: desc, Run: func() error {
+		_, err := mc.CreateCollectionIndexes(collection, []interface{}{mongo.IndexModel{
+			Keys:    keys,
+			Options: &options.IndexOptions{Name: &idxName, Unique: &unique},
+		}})
+		return err
+	}}
+}
+
+// _sortedTableNames returns the table names of a schema map in alphabetical order, so that steps are listed in a stable order.
+func _sortedTableNames(m interface{}) []string {
+	names := make([]string, 0)
+	switch schema := m.(type) {
+	case map[string]map[string]string:
+		for name := range schema {
+			names = append(names, name)
+		}
+	case map[string]*henge.CosmosdbCollectionSpec:
+		for name := range schema {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+/*----------------------------------------------------------------------*/
+
+// Table schemas created by migration 1; do not modify, add a new migration instead.
+
+var _sqliteTableSchema = map[string]map[string]string{
+	user.TableUser:        {user.UserColMaskUid: "VARCHAR(32)"},
+	blog.TableBlogPost:    {blog.PostColOwnerId: "VARCHAR(32)", blog.PostColIsPublic: "INT"},
+	blog.TableBlogComment: {blog.CommentColOwnerId: "VARCHAR(32)", blog.CommentColPostId: "VARCHAR(32)", blog.CommentColParentId: "VARCHAR(32)"},
+	blog.TableBlogVote:    {blog.VoteColOwnerId: "VARCHAR(32)", blog.VoteColTargetId: "VARCHAR(32)", blog.VoteColValue: "INT"},
+	audit.TableAuditEvent: {audit.AuditColActor: "VARCHAR(64)", audit.AuditColApi: "VARCHAR(64)", audit.AuditColTargetId: "VARCHAR(64)", audit.AuditColStatus: "INT", audit.AuditColTimestamp: "BIGINT"},
+	TableIdempotency:      {idempotencyColExpiry: "BIGINT"},
+}
+
+var _mysqlTableSchema = map[string]map[string]string{
+	user.TableUser:        {user.UserColMaskUid: "VARCHAR(32)"},
+	blog.TableBlogPost:    {blog.PostColOwnerId: "VARCHAR(32)", blog.PostColIsPublic: "INT"},
+	blog.TableBlogComment: {blog.CommentColOwnerId: "VARCHAR(32)", blog.CommentColPostId: "VARCHAR(32)", blog.CommentColParentId: "VARCHAR(32)"},
+	blog.TableBlogVote:    {blog.VoteColOwnerId: "VARCHAR(32)", blog.VoteColTargetId: "VARCHAR(32)", blog.VoteColValue: "INT"},
+	audit.TableAuditEvent: {audit.AuditColActor: "VARCHAR(64)", audit.AuditColApi: "VARCHAR(64)", audit.AuditColTargetId: "VARCHAR(64)", audit.AuditColStatus: "INT", audit.AuditColTimestamp: "BIGINT"},
+	TableIdempotency:      {idempotencyColExpiry: "BIGINT"},
+}
+
+var _pgsqlTableSchema = map[string]map[string]string{
+	user.TableUser:        {user.UserColMaskUid: "VARCHAR(32)"},
+	blog.TableBlogPost:    {blog.PostColOwnerId: "VARCHAR(32)", blog.PostColIsPublic: "INT"},
+	blog.TableBlogComment: {blog.CommentColOwnerId: "VARCHAR(32)", blog.CommentColPostId: "VARCHAR(32)", blog.CommentColParentId: "VARCHAR(32)"},
+	blog.TableBlogVote:    {blog.VoteColOwnerId: "VARCHAR(32)", blog.VoteColTargetId: "VARCHAR(32)", blog.VoteColValue: "INT"},
+	audit.TableAuditEvent: {audit.AuditColActor: "VARCHAR(64)", audit.AuditColApi: "VARCHAR(64)", audit.AuditColTargetId: "VARCHAR(64)", audit.AuditColStatus: "INT", audit.AuditColTimestamp: "BIGINT"},
+	TableIdempotency:      {idempotencyColExpiry: "BIGINT"},
+}
+
+var _cosmosdbTableSpec = map[string]*henge.CosmosdbCollectionSpec{
+	user.TableUser:        {Pk: henge.CosmosdbColId, Uk: [][]string{{"/" + user.UserFieldMaskId}}},
+	blog.TableBlogPost:    {Pk: henge.CosmosdbColId},
+	blog.TableBlogComment: {Pk: henge.CosmosdbColId},
+	blog.TableBlogVote:    {Pk: henge.CosmosdbColId, Uk: [][]string{{"/" + blog.VoteFieldOwnerId, "/" + blog.VoteFieldTargetId}}},
+	audit.TableAuditEvent: {Pk: henge.CosmosdbColId},
+	TableIdempotency:      {Pk: henge.CosmosdbColId},
+}
+
+func _migration1Sql(sqlc *promsql.SqlConnect) []*migrationStep {
+	var tableSchema map[string]map[string]string
+	var initTable func(sqlc *promsql.SqlConnect, tableName string, extraCols map[string]string) error
+	switch sqlc.GetDbFlavor() {
+	case promsql.FlavorSqlite:
+		tableSchema, initTable = _sqliteTableSchema, henge.InitSqliteTable
+	case promsql.FlavorPgSql:
+		tableSchema, initTable = _pgsqlTableSchema, henge.InitPgsqlTable
+	case promsql.FlavorMySql:
+		tableSchema, initTable = _mysqlTableSchema, henge.InitMysqlTable
+	}
+	steps := make([]*migrationStep, 0)
+	for _, tbl := range _sortedTableNames(tableSchema) {
+		tbl, schema := tbl, tableSchema[tbl]
+		steps = append(steps, &migrationStep{Desc: "create table " + tbl, Run: func() error { return initTable(sqlc, tbl, schema) }})
+	}
+	return append(steps,
+		// user
+		_sqlIndexStep(sqlc, user.TableUser, true, user.UserColMaskUid),
+		// blog post
+		_sqlIndexStep(sqlc, blog.TableBlogPost, false, blog.PostColOwnerId),
+		// blog comment
+		_sqlIndexStep(sqlc, blog.TableBlogComment, false, blog.CommentColOwnerId),
+		_sqlIndexStep(sqlc, blog.TableBlogComment, false, blog.CommentColPostId, blog.CommentColParentId),
+		// blog vote
+		_sqlIndexStep(sqlc, blog.TableBlogVote, true, blog.VoteColOwnerId, blog.VoteColTargetId),
+		_sqlIndexStep(sqlc, blog.TableBlogVote, false, blog.VoteColTargetId, blog.VoteColValue),
+		// audit event
+		_sqlIndexStep(sqlc, audit.TableAuditEvent, false, audit.AuditColTimestamp),
+		_sqlIndexStep(sqlc, audit.TableAuditEvent, false, audit.AuditColActor, audit.AuditColTimestamp),
+		// idempotency record
+		_sqlIndexStep(sqlc, TableIdempotency, false, idempotencyColExpiry),
+	)
+}
+
+func _migration1Cosmosdb(sqlc *promsql.SqlConnect) []*migrationStep {
+	steps := make([]*migrationStep, 0)
+	for _, tbl := range _sortedTableNames(_cosmosdbTableSpec) {
+		tbl, spec := tbl, _cosmosdbTableSpec[tbl]
+		steps = append(steps, &migrationStep{Desc: "create collection " + tbl, Run: func() error { return henge.InitCosmosdbCollection(sqlc, tbl, spec) }})
+	}
+	return steps
+}
+
+func _migration1Dynamodb(adc *promdynamodb.AwsDynamodbConnect) []*migrationStep {
+	tableName, colName := user.TableUser, user.UserFieldMaskId
+	gsiName := "gsi_" + colName
+	return []*migrationStep{
+		{Desc: "create table " + blog.TableBlogComment, Run: func() error { return blog.InitBlogCommentTableDynamodb(adc, blog.TableBlogComment) }},
+		{Desc: "create table " + blog.TableBlogPost, Run: func() error { return blog.InitBlogPostTableDynamodb(adc, blog.TableBlogPost) }},
+		{Desc: "create table " + blog.TableBlogVote, Run: func() error { return blog.InitBlogVoteTableDynamodb(adc, blog.TableBlogVote) }},
+		{Desc: "create table " + audit.TableAuditEvent, Run: func() error { return audit.InitAuditEventTableDynamodb(adc, audit.TableAuditEvent) }},
+		{Desc: "create table " + TableIdempotency, Run: func() error {
+			return henge.InitDynamodbTables(adc, TableIdempotency, &henge.DynamodbTablesSpec{MainTableRcu: 2, MainTableWcu: 1})
+		}},
+		{Desc: "create table " + user.TableUser, Run: func() error {
+			spec := &henge.DynamodbTablesSpec{MainTableRcu: 2, MainTableWcu: 1, CreateUidxTable: true, UidxTableRcu: 2, UidxTableWcu: 1}
+			return henge.InitDynamodbTables(adc, user.TableUser, spec)
+		}},
+		{Desc: "create GSI " + gsiName + " on " + tableName, Run: func() error {
+			if err := adc.CreateGlobalSecondaryIndex(nil, tableName, gsiName, 2, 1,
+				[]promdynamodb.AwsDynamodbNameAndType{{Name: colName, Type: promdynamodb.AwsAttrTypeString}},
+				[]promdynamodb.AwsDynamodbNameAndType{{Name: colName, Type: promdynamodb.AwsKeyTypePartition}}); err != nil {
+				return err
+			}
+			return promdynamodb.AwsDynamodbWaitForGsiStatus(adc, tableName, gsiName, []string{"ACTIVE"}, 1*time.Second, 60*time.Second)
+		}},
+	}
+}
+
+func _migration1Mongodb(mc *prommongo.MongoConnect) []*migrationStep {
+	steps := make([]*migrationStep, 0)
+	for _, collection := range []string{user.TableUser, blog.TableBlogPost, blog.TableBlogComment, blog.TableBlogVote, audit.TableAuditEvent, TableIdempotency} {
+		collection := collection
+		steps = append(steps, &migrationStep{Desc: "create collection " + collection, Run: func() error { return henge.InitMongoCollection(mc, collection) }})
+	}
+	return append(steps,
+		// user
+		_mongoIndexStep(mc, user.TableUser, true, bson.D{{Key: user.UserFieldMaskId, Value: 1}}),
+		// blog post
+		_mongoIndexStep(mc, blog.TableBlogPost, false, bson.D{{Key: blog.PostFieldOwnerId, Value: 1}}),
+		// blog comment
+		_mongoIndexStep(mc, blog.TableBlogComment, false, bson.D{{Key: blog.CommentFieldOwnerId, Value: 1}}),
+		_mongoIndexStep(mc, blog.TableBlogComment, false, bson.D{{Key: blog.CommentFieldPostId, Value: 1}, {Key: blog.CommentFieldParentId, Value: 1}}),
+		// blog vote
+		_mongoIndexStep(mc, blog.TableBlogVote, true, bson.D{{Key: blog.VoteFieldOwnerId, Value: 1}, {Key: blog.VoteFieldTargetId, Value: 1}}),
+		_mongoIndexStep(mc, blog.TableBlogVote, false, bson.D{{Key: blog.VoteFieldTargetId, Value: 1}, {Key: blog.VoteFieldValue, Value: 1}}),
+		// audit event
+		_mongoIndexStep(mc, audit.TableAuditEvent, false, bson.D{{Key: audit.AuditFieldTimestamp, Value: -1}}),
+	)
+}
+
+func _migration1Memory(db *memdb.MemoryDb) []*migrationStep {
+	steps := make([]*migrationStep, 0)
+	for _, table := range []string{user.TableUser, blog.TableBlogPost, blog.TableBlogComment, blog.TableBlogVote, audit.TableAuditEvent, TableIdempotency} {
+		table := table
+		steps = append(steps, &migrationStep{Desc: "create table " + table, Run: func() error { return db.CreateTable(table) }})
+	}
+	return append(steps,
+		&migrationStep{Desc: "create unique index on " + user.TableUser + "(" + user.UserFieldMaskId + ")", Run: func() error {
+			return db.CreateUniqueIndex(user.TableUser, user.UserFieldMaskId)
+		}},
+		&migrationStep{Desc: "create unique index on " + blog.TableBlogVote + "(" + blog.VoteFieldOwnerId + "," + blog.VoteFieldTargetId + ")", Run: func() error {
+			return db.CreateUniqueIndex(blog.TableBlogVote, blog.VoteFieldOwnerId, blog.VoteFieldTargetId)
+		}},
+	)
+}
@@ -0,0 +1,356 @@
+/*
+Package audit contains the audit-trail business object and its DAO implementations.
+
+@author Thanh Nguyen <btnguyen2k@gmail.com>
+@since template-v0.5.0
+*/
+package audit
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/btnguyen2k/consu/reddo"
+	"github.com/btnguyen2k/henge"
+
+	"main/src/utils"
+)
+
+// NewAuditEvent is helper function to create new AuditEvent bo.
+//
+// Available since template-v0.5.0
+func NewAuditEvent(appVersion uint64, actor, api, targetId string, status int) *AuditEvent {
+	event := &AuditEvent{
+		UniversalBo: henge.NewUniversalBo(utils.UniqueId(), appVersion),
+		actor:       strings.TrimSpace(strings.ToLower(actor)),
+		api:         strings.TrimSpace(api),
+		targetId:    strings.TrimSpace(strings.ToLower(targetId)),
+		status:      status,
+	}
+	event.timestamp = fromMillis(toMillis(event.GetTimeCreated()))
+	return event.sync()
+}
+
+// NewAuditEventFromUbo is helper function to create AuditEvent bo from a universal bo.
+//
+// Available since template-v0.5.0
+func NewAuditEventFromUbo(ubo *henge.UniversalBo) *AuditEvent {
+	if ubo == nil {
+		return nil
+	}
+	ubo = ubo.Clone()
+	event := &AuditEvent{UniversalBo: ubo}
+	if v, err := ubo.GetExtraAttrAs(AuditFieldActor, reddo.TypeString); err != nil {
+		return nil
+	} else {
+		event.actor = v.(string)
+	}
+	if v, err := ubo.GetExtraAttrAs(AuditFieldApi, reddo.TypeString); err != nil {
+		return nil
+	} else {
+		event.api = v.(string)
+	}
+	if v, err := ubo.GetExtraAttrAs(AuditFieldTargetId, reddo.TypeString); err != nil {
+		return nil
+	} else {
+		event.targetId = v.(string)
+	}
+	if v, err := ubo.GetExtraAttrAs(AuditFieldStatus, reddo.TypeInt); err != nil {
+		return nil
+	} else {
+		event.status = int(v.(int64))
+	}
+	if v, err := ubo.GetExtraAttrAs(AuditFieldTimestamp, reddo.TypeInt); err != nil {
+		return nil
+	} else {
+		event.timestamp = fromMillis(v.(int64))
+	}
+	if v, err := ubo.GetDataAttrAs(AuditAttrClientIp, reddo.TypeString); err != nil {
+		return nil
+	} else {
+		event.clientIp, _ = v.(string)
+	}
+	if v, err := ubo.GetDataAttrAs(AuditAttrGateway, reddo.TypeString); err != nil {
+		return nil
+	} else {
+		event.gateway, _ = v.(string)
+	}
+	if v, err := ubo.GetDataAttrAs(AuditAttrRequestId, reddo.TypeString); err != nil {
+		return nil
+	} else {
+		event.requestId, _ = v.(string)
+	}
+	if v, err := ubo.GetDataAttrAs(AuditAttrMessage, reddo.TypeString); err != nil {
+		return nil
+	} else {
+		event.message, _ = v.(string)
+	}
+	return event.sync()
+}
+
+const (
+	// AuditFieldActor is id of the user who performed the action (empty if the action was performed anonymously).
+	AuditFieldActor = "actor"
+
+	// AuditFieldApi is name of the API that was invoked.
+	AuditFieldApi = "api"
+
+	// AuditFieldTargetId is id of the object the action was performed on (e.g. id of a blog post).
+	AuditFieldTargetId = "tid"
+
+	// AuditFieldStatus is the outcome of the action (status of the API result).
+	AuditFieldStatus = "status"
+
+	// AuditFieldTimestamp is the time the action was performed, stored as UNIX timestamp in milliseconds
+	// so that it can be filtered by range uniformly across all backends.
+	AuditFieldTimestamp = "ts"
+
+	// AuditAttrClientIp is IP address of the client that performed the action.
+	AuditAttrClientIp = "cip"
+
+	// AuditAttrGateway is name of the API gateway (e.g. HTTP, GRPC) the action came from.
+	AuditAttrGateway = "gw"
+
+	// AuditAttrRequestId is id of the API context, used to correlate the audit event with log entries.
+	AuditAttrRequestId = "rid"
+
+	// AuditAttrMessage is the message of the API result.
+	AuditAttrMessage = "msg"
+
+	// auditAttr_Ubo is for internal use only!
+	auditAttr_Ubo = "_ubo"
+)
+
+func toMillis(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
+
+func fromMillis(ms int64) time.Time {
+	return time.Unix(0, ms*int64(time.Millisecond))
+}
+
+// AuditEvent is the business object.
+//   - AuditEvent inherits unique id from bo.UniversalBo
+//
+// Available since template-v0.5.0
+type AuditEvent struct {
+	*henge.UniversalBo `json:"_ubo"`
+	actor              string
+	api                string
+	targetId           string
+	status             int
+	timestamp          time.Time
+	clientIp           string
+	gateway            string
+	requestId          string
+	message            string
+}
+
+// ToMap transforms audit event's attributes to a map.
+func (e *AuditEvent) ToMap(postFunc henge.FuncPostUboToMap) map[string]interface{} {
+	result := map[string]interface{}{
+		henge.FieldId:       e.GetId(),
+		AuditFieldActor:     e.actor,
+		AuditFieldApi:       e.api,
+		AuditFieldTargetId:  e.targetId,
+		AuditFieldStatus:    e.status,
+		AuditFieldTimestamp: e.timestamp,
+		AuditAttrClientIp:   e.clientIp,
+		AuditAttrGateway:    e.gateway,
+		AuditAttrRequestId:  e.requestId,
+		AuditAttrMessage:    e.message,
+	}
+	if postFunc != nil {
+		result = postFunc(result)
+	}
+	return result
+}
+
+// MarshalJSON implements json.encode.Marshaler.MarshalJSON.
+func (e *AuditEvent) MarshalJSON() ([]byte, error) {
+	e.sync()
+	m := map[string]interface{}{
+		auditAttr_Ubo: e.UniversalBo.Clone(),
+		"_cols": map[string]interface{}{
+			AuditFieldActor:     e.actor,
+			AuditFieldApi:       e.api,
+			AuditFieldTargetId:  e.targetId,
+			AuditFieldStatus:    e.status,
+			AuditFieldTimestamp: toMillis(e.timestamp),
+		},
+		"_attrs": map[string]interface{}{
+			AuditAttrClientIp:  e.clientIp,
+			AuditAttrGateway:   e.gateway,
+			AuditAttrRequestId: e.requestId,
+			AuditAttrMessage:   e.message,
+		},
+	}
+	return json.Marshal(m)
+}
+
+// UnmarshalJSON implements json.decode.Unmarshaler.UnmarshalJSON.
+func (e *AuditEvent) UnmarshalJSON(data []byte) error {
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+	var err error
+	if m[auditAttr_Ubo] != nil {
+		js, _ := json.Marshal(m[auditAttr_Ubo])
+		if err = json.Unmarshal(js, &e.UniversalBo); err != nil {
+			return err
+		}
+	}
+	if _cols, ok := m["_cols"].(map[string]interface{}); ok {
+		if e.actor, err = reddo.ToString(_cols[AuditFieldActor]); err != nil {
+			return err
+		}
+		if e.api, err = reddo.ToString(_cols[AuditFieldApi]); err != nil {
+			return err
+		}
+		if e.targetId, err = reddo.ToString(_cols[AuditFieldTargetId]); err != nil {
+			return err
+		}
+		if v, err := reddo.ToInt(_cols[AuditFieldStatus]); err != nil {
+			return err
+		} else {
+			e.status = int(v)
+		}
+		if v, err := reddo.ToInt(_cols[AuditFieldTimestamp]); err != nil {
+			return err
+		} else {
+			e.timestamp = fromMillis(v)
+		}
+	}
+	if _attrs, ok := m["_attrs"].(map[string]interface{}); ok {
+		if e.clientIp, err = reddo.ToString(_attrs[AuditAttrClientIp]); err != nil {
+			return err
+		}
+		if e.gateway, err = reddo.ToString(_attrs[AuditAttrGateway]); err != nil {
+			return err
+		}
+		if e.requestId, err = reddo.ToString(_attrs[AuditAttrRequestId]); err != nil {
+			return err
+		}
+		if e.message, err = reddo.ToString(_attrs[AuditAttrMessage]); err != nil {
+			return err
+		}
+	}
+	e.sync()
+	return nil
+}
+
+// GetActor returns value of audit event's 'actor' attribute.
+func (e *AuditEvent) GetActor() string {
+	return e.actor
+}
+
+// SetActor sets value of audit event's 'actor' attribute.
+func (e *AuditEvent) SetActor(v string) *AuditEvent {
+	e.actor = strings.TrimSpace(strings.ToLower(v))
+	return e
+}
+
+// GetApi returns value of audit event's 'api' attribute.
+func (e *AuditEvent) GetApi() string {
+	return e.api
+}
+
+// SetApi sets value of audit event's 'api' attribute.
+func (e *AuditEvent) SetApi(v string) *AuditEvent {
+	e.api = strings.TrimSpace(v)
+	return e
+}
+
+// GetTargetId returns value of audit event's 'target-id' attribute.
+func (e *AuditEvent) GetTargetId() string {
+	return e.targetId
+}
+
+// SetTargetId sets value of audit event's 'target-id' attribute.
+func (e *AuditEvent) SetTargetId(v string) *AuditEvent {
+	e.targetId = strings.TrimSpace(strings.ToLower(v))
+	return e
+}
+
+// GetStatus returns value of audit event's 'status' attribute.
+func (e *AuditEvent) GetStatus() int {
+	return e.status
+}
+
+// SetStatus sets value of audit event's 'status' attribute.
+func (e *AuditEvent) SetStatus(v int) *AuditEvent {
+	e.status = v
+	return e
+}
+
+// GetTimestamp returns value of audit event's 'timestamp' attribute.
+func (e *AuditEvent) GetTimestamp() time.Time {
+	return e.timestamp
+}
+
+// SetTimestamp sets value of audit event's 'timestamp' attribute (truncated to milliseconds).
+func (e *AuditEvent) SetTimestamp(v time.Time) *AuditEvent {
+	e.timestamp = fromMillis(toMillis(v))
+	return e
+}
+
+// GetClientIp returns value of audit event's 'client-ip' attribute.
+func (e *AuditEvent) GetClientIp() string {
+	return e.clientIp
+}
+
+// SetClientIp sets value of audit event's 'client-ip' attribute.
+func (e *AuditEvent) SetClientIp(v string) *AuditEvent {
+	e.clientIp = strings.TrimSpace(v)
+	return e
+}
+
+// GetGateway returns value of audit event's 'gateway' attribute.
+func (e *AuditEvent) GetGateway() string {
+	return e.gateway
+}
+
+// SetGateway sets value of audit event's 'gateway' attribute.
+func (e *AuditEvent) SetGateway(v string) *AuditEvent {
+	e.gateway = strings.TrimSpace(v)
+	return e
+}
+
+// GetRequestId returns value of audit event's 'request-id' attribute.
+func (e *AuditEvent) GetRequestId() string {
+	return e.requestId
+}
+
+// SetRequestId sets value of audit event's 'request-id' attribute.
+func (e *AuditEvent) SetRequestId(v string) *AuditEvent {
+	e.requestId = strings.TrimSpace(v)
+	return e
+}
+
+// GetMessage returns value of audit event's 'message' attribute.
+func (e *AuditEvent) GetMessage() string {
+	return e.message
+}
+
+// SetMessage sets value of audit event's 'message' attribute.
+func (e *AuditEvent) SetMessage(v string) *AuditEvent {
+	e.message = v
+	return e
+}
+
+// sync is called to synchronize BO's attributes to its UniversalBo.
+func (e *AuditEvent) sync() *AuditEvent {
+	e.SetDataAttr(AuditAttrClientIp, e.clientIp)
+	e.SetDataAttr(AuditAttrGateway, e.gateway)
+	e.SetDataAttr(AuditAttrRequestId, e.requestId)
+	e.SetDataAttr(AuditAttrMessage, e.message)
+	e.SetExtraAttr(AuditFieldActor, e.actor)
+	e.SetExtraAttr(AuditFieldApi, e.api)
+	e.SetExtraAttr(AuditFieldTargetId, e.targetId)
+	e.SetExtraAttr(AuditFieldStatus, e.status)
+	e.SetExtraAttr(AuditFieldTimestamp, toMillis(e.timestamp))
+	e.UniversalBo.Sync()
+	return e
+}
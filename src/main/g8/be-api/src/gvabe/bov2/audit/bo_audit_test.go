@@ -0,0 +1,89 @@
+package audit
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/btnguyen2k/henge"
+	"main/src/utils"
+)
+
+func TestNewAuditEvent(t *testing.T) {
+	name := "TestNewAuditEvent"
+	_tagVersion := uint64(1337)
+	event := NewAuditEvent(_tagVersion, "Admin@Local", "deleteBlogPost", "POST-ID", 200)
+	if event == nil {
+		t.Fatalf("%s failed: nil", name)
+	}
+	if tagVersion := event.GetTagVersion(); tagVersion != _tagVersion {
+		t.Fatalf("%s failed: expected tag-version to be %#v but received %#v", name, _tagVersion, tagVersion)
+	}
+	if actor := event.GetActor(); actor != "admin@local" {
+		t.Fatalf("%s failed: expected actor to be %#v but received %#v", name, "admin@local", actor)
+	}
+	if api := event.GetApi(); api != "deleteBlogPost" {
+		t.Fatalf("%s failed: expected api to be %#v but received %#v", name, "deleteBlogPost", api)
+	}
+	if targetId := event.GetTargetId(); targetId != "post-id" {
+		t.Fatalf("%s failed: expected target-id to be %#v but received %#v", name, "post-id", targetId)
+	}
+	if status := event.GetStatus(); status != 200 {
+		t.Fatalf("%s failed: expected status to be %#v but received %#v", name, 200, status)
+	}
+	if ts := event.GetTimestamp(); ts.IsZero() || ts.Sub(event.GetTimeCreated()) > time.Millisecond {
+		t.Fatalf("%s failed: expected timestamp to be %#v but received %#v", name, event.GetTimeCreated(), ts)
+	}
+}
+
+func TestNewAuditEventFromUbo(t *testing.T) {
+	name := "TestNewAuditEventFromUbo"
+	if NewAuditEventFromUbo(nil) != nil {
+		t.Fatalf("%s failed: NewAuditEventFromUbo(nil) should return nil", name)
+	}
+	_id := utils.UniqueId()
+	_ts := time.Now().Add(-time.Hour)
+	ubo := henge.NewUniversalBo(_id, 1337)
+	ubo.SetExtraAttr(AuditFieldActor, "admin@local")
+	ubo.SetExtraAttr(AuditFieldApi, "login")
+	ubo.SetExtraAttr(AuditFieldTargetId, "")
+	ubo.SetExtraAttr(AuditFieldStatus, 403)
+	ubo.SetExtraAttr(AuditFieldTimestamp, toMillis(_ts))
+	ubo.SetDataAttr(AuditAttrClientIp, "127.0.0.1")
+	event := NewAuditEventFromUbo(ubo)
+	if event == nil {
+		t.Fatalf("%s failed: nil", name)
+	}
+	if id := event.GetId(); id != _id {
+		t.Fatalf("%s failed: expected id to be %#v but received %#v", name, _id, id)
+	}
+	if status := event.GetStatus(); status != 403 {
+		t.Fatalf("%s failed: expected status to be %#v but received %#v", name, 403, status)
+	}
+	if ts := event.GetTimestamp(); toMillis(ts) != toMillis(_ts) {
+		t.Fatalf("%s failed: expected timestamp to be %#v but received %#v", name, _ts, ts)
+	}
+	if clientIp := event.GetClientIp(); clientIp != "127.0.0.1" {
+		t.Fatalf("%s failed: expected client-ip to be %#v but received %#v", name, "127.0.0.1", clientIp)
+	}
+}
+
+func TestAuditEvent_json(t *testing.T) {
+	name := "TestAuditEvent_json"
+	event1 := NewAuditEvent(1337, "admin@local", "voteForPost", "post-id", 200).
+		SetClientIp("10.0.0.1").SetGateway("HTTP").SetRequestId("req-id").SetMessage("ok")
+	js1, _ := json.Marshal(event1)
+	var event2 *AuditEvent
+	if err := json.Unmarshal(js1, &event2); err != nil {
+		t.Fatalf("%s failed: %s", name, err)
+	}
+	if event1.GetChecksum() != event2.GetChecksum() {
+		t.Fatalf("%s failed: expected checksum to be %#v but received %#v", name, event1.GetChecksum(), event2.GetChecksum())
+	}
+	if !event1.GetTimestamp().Equal(event2.GetTimestamp()) {
+		t.Fatalf("%s failed: expected timestamp to be %#v but received %#v", name, event1.GetTimestamp(), event2.GetTimestamp())
+	}
+	if event1.GetRequestId() != event2.GetRequestId() {
+		t.Fatalf("%s failed: expected request-id to be %#v but received %#v", name, event1.GetRequestId(), event2.GetRequestId())
+	}
+}
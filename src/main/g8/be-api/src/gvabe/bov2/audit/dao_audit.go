@@ -0,0 +1,155 @@
+package audit
+
+import (
+	"time"
+
+	"github.com/btnguyen2k/godal"
+	"github.com/btnguyen2k/henge"
+)
+
+const (
+	TableAuditEvent   = "gva_audit_event"
+	AuditColActor     = "zactor"
+	AuditColApi       = "zapi"
+	AuditColTargetId  = "ztid"
+	AuditColStatus    = "zstatus"
+	AuditColTimestamp = "zts"
+)
+
+// deleteBatchSize is the number of audit events fetched and deleted at a time by DeleteBefore.
+const deleteBatchSize = 100
+
+// AuditQuery holds criteria to search for audit events. Empty/zero-valued criteria are ignored.
+//
+// Available since template-v0.5.0
+type AuditQuery struct {
+	Actor    string    // match audit events performed by this user
+	Api      string    // match audit events of this API
+	TargetId string    // match audit events performed on this target object
+	Status   int       // match audit events with this outcome
+	From     time.Time // match audit events that happened at or after this time
+	To       time.Time // match audit events that happened before this time
+}
+
+// BuildFilter builds the godal.FilterOpt from the query criteria (nil if the query has no criteria).
+func (q *AuditQuery) BuildFilter() godal.FilterOpt {
+	if q == nil {
+		return nil
+	}
+	filter := &godal.FilterOptAnd{}
+	if q.Actor != "" {
+		filter.Add(&godal.FilterOptFieldOpValue{FieldName: AuditFieldActor, Operator: godal.FilterOpEqual, Value: q.Actor})
+	}
+	if q.Api != "" {
+		filter.Add(&godal.FilterOptFieldOpValue{FieldName: AuditFieldApi, Operator: godal.FilterOpEqual, Value: q.Api})
+	}
+	if q.TargetId != "" {
+		filter.Add(&godal.FilterOptFieldOpValue{FieldName: AuditFieldTargetId, Operator: godal.FilterOpEqual, Value: q.TargetId})
+	}
+	if q.Status != 0 {
+		filter.Add(&godal.FilterOptFieldOpValue{FieldName: AuditFieldStatus, Operator: godal.FilterOpEqual, Value: q.Status})
+	}
+	if !q.From.IsZero() {
+		filter.Add(&godal.FilterOptFieldOpValue{FieldName: AuditFieldTimestamp, Operator: godal.FilterOpGreaterOrEqual, Value: toMillis(q.From)})
+	}
+	if !q.To.IsZero() {
+		filter.Add(&godal.FilterOptFieldOpValue{FieldName: AuditFieldTimestamp, Operator: godal.FilterOpLess, Value: toMillis(q.To)})
+	}
+	if len(filter.Filters) == 0 {
+		return nil
+	}
+	return filter
+}
+
+// AuditEventDao defines API to access AuditEvent storage.
+//
+// Available since template-v0.5.0
+type AuditEventDao interface {
+	// Delete removes the specified business object from storage.
+	Delete(bo *AuditEvent) (bool, error)
+
+	// Create persists a new business object to storage.
+	Create(bo *AuditEvent) (bool, error)
+
+	// Get retrieves a business object from storage.
+	Get(id string) (*AuditEvent, error)
+
+	// FindN retrieves N audit events matching the query, latest events first.
+	FindN(query *AuditQuery, fromOffset, maxNumRows int) ([]*AuditEvent, error)
+
+	// DeleteBefore removes all audit events that happened before the specified time, returns number of removed events.
+	DeleteBefore(t time.Time) (int, error)
+}
+
+// BaseAuditEventDaoImpl is a generic implementation of AuditEventDao.
+//
+// Available since template-v0.5.0
+type BaseAuditEventDaoImpl struct {
+	henge.UniversalDao
+}
+
+// Delete implements AuditEventDao.Delete
+func (dao *BaseAuditEventDaoImpl) Delete(event *AuditEvent) (bool, error) {
+	return dao.UniversalDao.Delete(event.sync().UniversalBo)
+}
+
+// Create implements AuditEventDao.Create
+func (dao *BaseAuditEventDaoImpl) Create(event *AuditEvent) (bool, error) {
+	return dao.UniversalDao.Create(event.sync().UniversalBo)
+}
+
+// Get implements AuditEventDao.Get
+func (dao *BaseAuditEventDaoImpl) Get(id string) (*AuditEvent, error) {
+	ubo, err := dao.UniversalDao.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	return NewAuditEventFromUbo(ubo), nil
+}
+
+// FindN implements AuditEventDao.FindN
+func (dao *BaseAuditEventDaoImpl) FindN(query *AuditQuery, fromOffset, maxNumRows int) ([]*AuditEvent, error) {
+	sorting := (&godal.SortingField{FieldName: AuditFieldTimestamp, Descending: true}).ToSortingOpt()
+	uboList, err := dao.UniversalDao.GetN(fromOffset, maxNumRows, query.BuildFilter(), sorting)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*AuditEvent, 0)
+	for _, ubo := range uboList {
+		result = append(result, NewAuditEventFromUbo(ubo))
+	}
+	return result, nil
+}
+
+// filterBefore builds the filter that matches audit events that happened before the specified time.
+func filterBefore(t time.Time) godal.FilterOpt {
+	return &godal.FilterOptFieldOpValue{FieldName: AuditFieldTimestamp, Operator: godal.FilterOpLess, Value: toMillis(t)}
+}
+
+// DeleteBefore implements AuditEventDao.DeleteBefore
+//
+// Matching events are fetched and deleted page by page, so that a large backlog is never loaded into memory at once.
+func (dao *BaseAuditEventDaoImpl) DeleteBefore(t time.Time) (int, error) {
+	filter := filterBefore(t)
+	count := 0
+	for {
+		// deleted events no longer match the filter, hence the next page always starts at offset 0
+		uboList, err := dao.UniversalDao.GetN(0, deleteBatchSize, filter, nil)
+		if err != nil {
+			return count, err
+		}
+		numDeleted := 0
+		for _, ubo := range uboList {
+			if ok, err := dao.UniversalDao.Delete(ubo); err != nil {
+				return count, err
+			} else if ok {
+				numDeleted++
+			}
+		}
+		count += numDeleted
+		if len(uboList) < deleteBatchSize || numDeleted == 0 {
+			// numDeleted == 0: events are being deleted concurrently (e.g. by another application instance), leave them to it
+			return count, nil
+		}
+	}
+}
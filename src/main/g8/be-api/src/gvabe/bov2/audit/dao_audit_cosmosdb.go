@@ -0,0 +1,21 @@
+package audit
+
+import (
+	"github.com/btnguyen2k/henge"
+	promsql "github.com/btnguyen2k/prom/sql"
+)
+
+// NewAuditEventDaoCosmosdb is helper method to create Azure Cosmos DB-implementation of AuditEventDao.
+//
+// Note: txModeOnWrite is not currently used!
+//
+// Available since template-v0.5.0
+func NewAuditEventDaoCosmosdb(sqlc *promsql.SqlConnect, tableName string, txModeOnWrite bool) AuditEventDao {
+	dao := &BaseAuditEventDaoImpl{}
+	spec := &henge.CosmosdbDaoSpec{
+		PkName:        henge.CosmosdbColId,
+		TxModeOnWrite: txModeOnWrite,
+	}
+	dao.UniversalDao = henge.NewUniversalDaoCosmosdbSql(sqlc, tableName, spec)
+	return dao
+}
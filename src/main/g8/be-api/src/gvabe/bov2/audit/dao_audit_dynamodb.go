@@ -0,0 +1,59 @@
+package audit
+
+import (
+	"sort"
+
+	"github.com/btnguyen2k/henge"
+	promdynamodb "github.com/btnguyen2k/prom/dynamodb"
+)
+
+// InitAuditEventTableDynamodb is helper method to initialize AWS DynamoDB table to store audit events.
+//
+// Available since template-v0.5.0
+func InitAuditEventTableDynamodb(adc *promdynamodb.AwsDynamodbConnect, tableName string) error {
+	spec := &henge.DynamodbTablesSpec{MainTableRcu: 2, MainTableWcu: 1}
+	return henge.InitDynamodbTables(adc, tableName, spec)
+}
+
+// NewAuditEventDaoDynamodb is helper method to create AWS DynamoDB-implementation of AuditEventDao.
+//
+// Available since template-v0.5.0
+func NewAuditEventDaoDynamodb(adc *promdynamodb.AwsDynamodbConnect, tableName string) AuditEventDao {
+	dao := &DynamodbAuditEventDaoImpl{&BaseAuditEventDaoImpl{}}
+	spec := &henge.DynamodbDaoSpec{}
+	dao.UniversalDao = henge.NewUniversalDaoDynamodb(adc, tableName, spec)
+	return dao
+}
+
+// DynamodbAuditEventDaoImpl is AWS DynamoDB-implementation of AuditEventDao.
+//
+// DynamoDB does not support sorting on scan, hence matching events are sorted and paged in memory.
+type DynamodbAuditEventDaoImpl struct {
+	*BaseAuditEventDaoImpl
+}
+
+// FindN implements AuditEventDao.FindN
+func (dao *DynamodbAuditEventDaoImpl) FindN(query *AuditQuery, fromOffset, maxNumRows int) ([]*AuditEvent, error) {
+	uboList, err := dao.UniversalDao.GetAll(query.BuildFilter(), nil)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*AuditEvent, 0)
+	for _, ubo := range uboList {
+		result = append(result, NewAuditEventFromUbo(ubo))
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].GetTimestamp().After(result[j].GetTimestamp())
+	})
+	if fromOffset < 0 {
+		fromOffset = 0
+	}
+	if fromOffset >= len(result) {
+		return make([]*AuditEvent, 0), nil
+	}
+	result = result[fromOffset:]
+	if maxNumRows > 0 && maxNumRows < len(result) {
+		result = result[:maxNumRows]
+	}
+	return result, nil
+}
@@ -0,0 +1,14 @@
+package audit
+
+import (
+	"main/src/memdb"
+)
+
+// NewAuditEventDaoMemory is helper method to create in-memory implementation of AuditEventDao.
+//
+// Available since template-v0.5.0
+func NewAuditEventDaoMemory(db *memdb.MemoryDb, tableName string) AuditEventDao {
+	dao := &BaseAuditEventDaoImpl{}
+	dao.UniversalDao = memdb.NewUniversalDaoMemory(db, tableName)
+	return dao
+}
@@ -0,0 +1,38 @@
+package audit
+
+import (
+	"testing"
+	"time"
+
+	"main/src/memdb"
+)
+
+func TestAuditEventDaoMemory_DeleteBefore(t *testing.T) {
+	name := "TestAuditEventDaoMemory_DeleteBefore"
+	db := memdb.NewMemoryDb()
+	if err := db.CreateTable(TableAuditEvent); err != nil {
+		t.Fatalf("%s failed: %s", name, err)
+	}
+	dao := NewAuditEventDaoMemory(db, TableAuditEvent)
+	now := time.Now()
+	// more expired events than one page of deleteBatchSize
+	numExpired, numKept := 2*deleteBatchSize+5, 7
+	for i := 0; i < numExpired+numKept; i++ {
+		ts := now.Add(-time.Duration(i) * time.Minute)
+		if i >= numKept {
+			ts = ts.Add(-24 * time.Hour)
+		}
+		if ok, err := dao.Create(NewAuditEvent(1337, "user", "login", "", 200).SetTimestamp(ts)); err != nil || !ok {
+			t.Fatalf("%s failed: %#v / %s", name, ok, err)
+		}
+	}
+	if n, err := dao.DeleteBefore(now.Add(-12 * time.Hour)); err != nil || n != numExpired {
+		t.Fatalf("%s failed: expected %#v deleted events but received %#v / %s", name, numExpired, n, err)
+	}
+	if events, err := dao.FindN(nil, 0, 0); err != nil || len(events) != numKept {
+		t.Fatalf("%s failed: expected %#v events but received %#v / %s", name, numKept, len(events), err)
+	}
+	if n, err := dao.DeleteBefore(now.Add(-12 * time.Hour)); err != nil || n != 0 {
+		t.Fatalf("%s failed: expected %#v deleted events but received %#v / %s", name, 0, n, err)
+	}
+}
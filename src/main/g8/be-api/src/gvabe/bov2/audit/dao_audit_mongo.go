@@ -0,0 +1,15 @@
+package audit
+
+import (
+	"github.com/btnguyen2k/henge"
+	prommongo "github.com/btnguyen2k/prom/mongo"
+)
+
+// NewAuditEventDaoMongo is helper method to create MongoDB-implementation of AuditEventDao.
+//
+// Available since template-v0.5.0
+func NewAuditEventDaoMongo(mc *prommongo.MongoConnect, collectionName string, txModeOnWrite bool) AuditEventDao {
+	dao := &BaseAuditEventDaoImpl{}
+	dao.UniversalDao = henge.NewUniversalDaoMongo(mc, collectionName, txModeOnWrite)
+	return dao
+}
@@ -0,0 +1,48 @@
+package audit
+
+import (
+	"time"
+
+	"github.com/btnguyen2k/henge"
+	promsql "github.com/btnguyen2k/prom/sql"
+)
+
+// NewAuditEventDaoSql is helper method to create SQL-implementation of AuditEventDao.
+//
+// Available since template-v0.5.0
+func NewAuditEventDaoSql(sqlc *promsql.SqlConnect, tableName string, txModeOnWrite bool) AuditEventDao {
+	dao := &SqlAuditEventDaoImpl{BaseAuditEventDaoImpl: &BaseAuditEventDaoImpl{}, tableName: tableName}
+	dao.UniversalDao = henge.NewUniversalDaoSql(
+		sqlc, tableName, txModeOnWrite,
+		map[string]string{
+			AuditColActor:     AuditFieldActor,
+			AuditColApi:       AuditFieldApi,
+			AuditColTargetId:  AuditFieldTargetId,
+			AuditColStatus:    AuditFieldStatus,
+			AuditColTimestamp: AuditFieldTimestamp,
+		})
+	return dao
+}
+
+// SqlAuditEventDaoImpl is SQL-implementation of AuditEventDao.
+//
+// Available since template-v0.5.0
+type SqlAuditEventDaoImpl struct {
+	*BaseAuditEventDaoImpl
+	tableName string
+}
+
+// DeleteBefore implements AuditEventDao.DeleteBefore, removing matching events with one "DELETE ... WHERE" statement.
+func (dao *SqlAuditEventDaoImpl) DeleteBefore(t time.Time) (int, error) {
+	udao := dao.UniversalDao.(*henge.UniversalDaoSql)
+	filter, err := udao.BuildFilter(dao.tableName, filterBefore(t))
+	if err != nil {
+		return 0, err
+	}
+	result, err := udao.SqlDelete(nil, nil, dao.tableName, filter)
+	if err != nil {
+		return 0, err
+	}
+	numRows, err := result.RowsAffected()
+	return int(numRows), err
+}
@@ -0,0 +1,101 @@
+package audit
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/btnguyen2k/henge"
+	promsql "github.com/btnguyen2k/prom/sql"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const (
+	testSqlTableAudit = "test_audit"
+	envSqliteDriver   = "SQLITE_DRIVER"
+	envSqliteUrl      = "SQLITE_URL"
+)
+
+func initAuditEventDaoSqlite(t *testing.T, testName string) (*promsql.SqlConnect, AuditEventDao) {
+	driver := strings.Trim(os.Getenv(envSqliteDriver), `"`)
+	url := strings.Trim(os.Getenv(envSqliteUrl), `"`)
+	if driver == "" || url == "" {
+		t.Skipf("%s skipped", testName)
+	}
+	sqlc, err := promsql.NewSqlConnectWithFlavor(driver, url, 10000, nil, promsql.FlavorSqlite)
+	if err != nil {
+		t.Fatalf("%s failed: error [%s]", testName, err)
+	}
+	sqlc.GetDB().Exec(fmt.Sprintf("DROP TABLE %s", testSqlTableAudit))
+	extraCols := map[string]string{AuditColActor: "VARCHAR(64)", AuditColApi: "VARCHAR(64)", AuditColTargetId: "VARCHAR(64)", AuditColStatus: "INT", AuditColTimestamp: "BIGINT"}
+	if err := henge.InitSqliteTable(sqlc, testSqlTableAudit, extraCols); err != nil {
+		t.Fatalf("%s failed: error [%s]", testName, err)
+	}
+	return sqlc, NewAuditEventDaoSql(sqlc, testSqlTableAudit, true)
+}
+
+func TestAuditEventDaoSql_CreateGet(t *testing.T) {
+	name := "TestAuditEventDaoSql_CreateGet"
+	sqlc, dao := initAuditEventDaoSqlite(t, name)
+	defer sqlc.Close()
+	event := NewAuditEvent(1337, "admin@local", "login", "", 200).SetClientIp("127.0.0.1")
+	if ok, err := dao.Create(event); err != nil || !ok {
+		t.Fatalf("%s failed: %#v / %s", name, ok, err)
+	}
+	if e, err := dao.Get(event.GetId()); err != nil || e == nil {
+		t.Fatalf("%s failed: %#v / %s", name, e, err)
+	} else if e.GetChecksum() != event.GetChecksum() {
+		t.Fatalf("%s failed: expected checksum %#v but received %#v", name, event.GetChecksum(), e.GetChecksum())
+	}
+}
+
+func TestAuditEventDaoSql_FindN(t *testing.T) {
+	name := "TestAuditEventDaoSql_FindN"
+	sqlc, dao := initAuditEventDaoSqlite(t, name)
+	defer sqlc.Close()
+	now := time.Now()
+	for i := 0; i < 10; i++ {
+		actor := "user1"
+		if i%2 == 0 {
+			actor = "user2"
+		}
+		event := NewAuditEvent(1337, actor, "voteForPost", "post", 200).SetTimestamp(now.Add(-time.Duration(i) * time.Hour))
+		dao.Create(event)
+	}
+	events, err := dao.FindN(&AuditQuery{Actor: "user1"}, 0, 0)
+	if err != nil || len(events) != 5 {
+		t.Fatalf("%s failed: expected %#v events but received %#v / %s", name, 5, len(events), err)
+	}
+	for i := 1; i < len(events); i++ {
+		if events[i].GetTimestamp().After(events[i-1].GetTimestamp()) {
+			t.Fatalf("%s failed: events are not sorted latest first", name)
+		}
+	}
+	events, err = dao.FindN(&AuditQuery{From: now.Add(-3*time.Hour - time.Minute), To: now.Add(-time.Minute)}, 0, 0)
+	if err != nil || len(events) != 3 {
+		t.Fatalf("%s failed: expected %#v events but received %#v / %s", name, 3, len(events), err)
+	}
+	events, err = dao.FindN(nil, 2, 3)
+	if err != nil || len(events) != 3 {
+		t.Fatalf("%s failed: expected %#v events but received %#v / %s", name, 3, len(events), err)
+	}
+}
+
+func TestAuditEventDaoSql_DeleteBefore(t *testing.T) {
+	name := "TestAuditEventDaoSql_DeleteBefore"
+	sqlc, dao := initAuditEventDaoSqlite(t, name)
+	defer sqlc.Close()
+	now := time.Now()
+	for i := 0; i < 10; i++ {
+		dao.Create(NewAuditEvent(1337, "user", "login", "", 200).SetTimestamp(now.Add(-time.Duration(i) * 24 * time.Hour)))
+	}
+	if n, err := dao.DeleteBefore(now.Add(-7*24*time.Hour + time.Minute)); err != nil || n != 3 {
+		t.Fatalf("%s failed: expected %#v deleted events but received %#v / %s", name, 3, n, err)
+	}
+	if events, err := dao.FindN(nil, 0, 0); err != nil || len(events) != 7 {
+		t.Fatalf("%s failed: expected %#v events but received %#v / %s", name, 7, len(events), err)
+	}
+}
@@ -1,7 +1,9 @@
 package blog
 
 import (
+	"crypto/md5"
 	"encoding/json"
+	"fmt"
 	"strings"
 
 	"github.com/btnguyen2k/consu/reddo"
@@ -205,6 +207,17 @@ func (p *BlogPost) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// GetVersion returns version of the blog post's content (title, content and visibility).
+//
+// Unlike the checksum, the version does not change when counters (comments, votes) change, hence it is used to detect
+// conflicting edits (see BlogPostDao.UpdateIfVersion).
+//
+// Available since template-v0.5.0
+func (p *BlogPost) GetVersion() string {
+	js, _ := json.Marshal([]interface{}{p.title, p.content, p.isPublic})
+	return fmt.Sprintf("%x", md5.Sum(js))
+}
+
 // GetOwnerId returns value of blog post's 'owner-id' attribute
 func (p *BlogPost) GetOwnerId() string {
 	return p.ownerId
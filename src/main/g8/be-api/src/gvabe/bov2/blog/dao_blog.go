@@ -1,9 +1,12 @@
 package blog
 
 import (
+	"errors"
+
 	"github.com/btnguyen2k/godal"
 	"github.com/btnguyen2k/henge"
 	"main/src/gvabe/bov2/user"
+	"main/src/memdb"
 )
 
 const (
@@ -112,6 +115,16 @@ type BlogPostDao interface {
 	// Get retrieves a business object from storage.
 	Get(id string) (*BlogPost, error)
 
+	// GetN retrieves N business objects from storage.
+	//
+	// Available since template-v0.5.0
+	GetN(fromOffset, maxNumRows int, filter godal.FilterOpt, sorting *godal.SortingOpt) ([]*BlogPost, error)
+
+	// GetAll retrieves all available business objects from storage.
+	//
+	// Available since template-v0.5.0
+	GetAll(filter godal.FilterOpt, sorting *godal.SortingOpt) ([]*BlogPost, error)
+
 	// GetUserPostsN retrieves first N user's blog posts of a user, latest posts first.
 	GetUserPostsN(user *user.User, fromOffset, maxNumRows int) ([]*BlogPost, error)
 
@@ -126,13 +139,48 @@ type BlogPostDao interface {
 
 	// Update modifies an existing business object.
 	Update(bo *BlogPost) (bool, error)
+
+	// UpdateIfChecksum modifies an existing business object only if its stored checksum still equals checksum.
+	// It returns false (with nil error) if the business object does not exist or has been modified in the meantime.
+	//
+	// Available since template-v0.5.0
+	UpdateIfChecksum(bo *BlogPost, checksum string) (bool, error)
+
+	// DeleteIfChecksum removes the specified business object only if its stored checksum still equals checksum.
+	// It returns false (with nil error) if the business object does not exist or has been modified in the meantime.
+	//
+	// Available since template-v0.5.0
+	DeleteIfChecksum(bo *BlogPost, checksum string) (bool, error)
+
+	// UpdateIfVersion applies title, content and visibility of bo to the stored blog post only if the stored content
+	// is still at the specified version (see BlogPost.GetVersion). Counters changed in the meantime (e.g. by votes) are
+	// kept and are not conflicts; on success, counters of bo are refreshed from the stored post.
+	// It returns false (with nil error) if the business object does not exist or its content has been modified since
+	// version, ErrPostConflict if it could not be written due to too many concurrent writes.
+	//
+	// Available since template-v0.5.0
+	UpdateIfVersion(bo *BlogPost, version string) (bool, error)
+
+	// DeleteIfVersion removes the specified business object only if its stored content is still at the specified
+	// version (see BlogPost.GetVersion). It returns false (with nil error) if the business object does not exist or its
+	// content has been modified since version, ErrPostConflict if it could not be deleted due to too many concurrent writes.
+	//
+	// Available since template-v0.5.0
+	DeleteIfVersion(bo *BlogPost, version string) (bool, error)
 }
 
+// ErrPostConflict is returned by BlogPostDao.UpdateIfVersion/DeleteIfVersion if the blog post could not be written due
+// to too many concurrent writes.
+//
+// Available since template-v0.5.0
+var ErrPostConflict = errors.New("too many concurrent writes, please retry")
+
 // BaseBlogPostDaoImpl is a generic implementation of BlogPostDao.
 //
 // Available since template-v0.3.0
 type BaseBlogPostDaoImpl struct {
 	henge.UniversalDao
+	tableName string // name of table/collection storing blog posts, used by conditional updates
 }
 
 // // GdaoCreateFilter implements IGenericDao.GdaoCreateFilter
@@ -159,6 +207,25 @@ func (dao *BaseBlogPostDaoImpl) Get(id string) (*BlogPost, error) {
 	return NewBlogPostFromUbo(ubo), nil
 }
 
+// GetN implements BlogPostDao.GetN
+func (dao *BaseBlogPostDaoImpl) GetN(fromOffset, maxNumRows int, filter godal.FilterOpt, sorting *godal.SortingOpt) ([]*BlogPost, error) {
+	uboList, err := dao.UniversalDao.GetN(fromOffset, maxNumRows, filter, sorting)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*BlogPost, 0)
+	for _, ubo := range uboList {
+		app := NewBlogPostFromUbo(ubo)
+		result = append(result, app)
+	}
+	return result, nil
+}
+
+// GetAll implements BlogPostDao.GetAll
+func (dao *BaseBlogPostDaoImpl) GetAll(filter godal.FilterOpt, sorting *godal.SortingOpt) ([]*BlogPost, error) {
+	return dao.GetN(0, 0, filter, sorting)
+}
+
 // GetUserPostsN implements BlogPostDao.GetUserPostsN
 func (dao *BaseBlogPostDaoImpl) GetUserPostsN(user *user.User, fromOffset, maxNumRows int) ([]*BlogPost, error) {
 	filter := &godal.FilterOptFieldOpValue{FieldName: PostFieldOwnerId, Operator: godal.FilterOpEqual, Value: user.GetId()}
@@ -208,6 +275,26 @@ func (dao *BaseBlogPostDaoImpl) Update(post *BlogPost) (bool, error) {
 	return dao.UniversalDao.Update(post.sync().UniversalBo)
 }
 
+// UpdateIfChecksum implements BlogPostDao.UpdateIfChecksum
+func (dao *BaseBlogPostDaoImpl) UpdateIfChecksum(post *BlogPost, checksum string) (bool, error) {
+	return updateIfChecksum(dao.UniversalDao, dao.tableName, post.sync().UniversalBo, checksum)
+}
+
+// DeleteIfChecksum implements BlogPostDao.DeleteIfChecksum
+func (dao *BaseBlogPostDaoImpl) DeleteIfChecksum(post *BlogPost, checksum string) (bool, error) {
+	return deleteIfChecksum(dao.UniversalDao, dao.tableName, post.sync().UniversalBo, checksum)
+}
+
+// UpdateIfVersion implements BlogPostDao.UpdateIfVersion
+func (dao *BaseBlogPostDaoImpl) UpdateIfVersion(post *BlogPost, version string) (bool, error) {
+	return updateIfVersion(dao, post, version)
+}
+
+// DeleteIfVersion implements BlogPostDao.DeleteIfVersion
+func (dao *BaseBlogPostDaoImpl) DeleteIfVersion(post *BlogPost, version string) (bool, error) {
+	return deleteIfVersion(dao, post, version)
+}
+
 /*----------------------------------------------------------------------*/
 
 const (
@@ -241,6 +328,12 @@ type BlogVoteDao interface {
 
 	// Update modifies an existing business object.
 	Update(bo *BlogVote) (bool, error)
+
+	// UpdateIfChecksum modifies an existing business object only if its stored checksum still equals checksum.
+	// It returns false (with nil error) if the business object does not exist or has been modified in the meantime.
+	//
+	// Available since template-v0.5.0
+	UpdateIfChecksum(bo *BlogVote, checksum string) (bool, error)
 }
 
 // BaseBlogVoteDaoImpl is a generic implementation of BlogVoteDao.
@@ -248,6 +341,7 @@ type BlogVoteDao interface {
 // Available since template-v0.3.0
 type BaseBlogVoteDaoImpl struct {
 	henge.UniversalDao
+	tableName string // name of table/collection storing votes, used by conditional updates
 }
 
 // // GdaoCreateFilter implements IGenericDao.GdaoCreateFilter
@@ -315,3 +409,104 @@ func (dao *BaseBlogVoteDaoImpl) GetAll(filter godal.FilterOpt, sorting *godal.So
 func (dao *BaseBlogVoteDaoImpl) Update(vote *BlogVote) (bool, error) {
 	return dao.UniversalDao.Update(vote.sync().UniversalBo)
 }
+
+// UpdateIfChecksum implements BlogVoteDao.UpdateIfChecksum
+func (dao *BaseBlogVoteDaoImpl) UpdateIfChecksum(vote *BlogVote, checksum string) (bool, error) {
+	return updateIfChecksum(dao.UniversalDao, dao.tableName, vote.sync().UniversalBo, checksum)
+}
+
+/*----------------------------------------------------------------------*/
+
+// updateIfChecksum updates ubo only if its stored checksum still equals checksum, using a conditional write of the
+// backend of udao: "UPDATE ... WHERE id=? AND checksum=?" on SQL, "replaceOne" filtered by id and checksum on MongoDB,
+// conditional "UpdateItem" on DynamoDB and "If-Match" on the document's etag on Cosmos DB.
+//
+// Other implementations of henge.UniversalDao fall back to compare-then-update, which is not atomic.
+func updateIfChecksum(udao henge.UniversalDao, tableName string, ubo *henge.UniversalBo, checksum string) (bool, error) {
+	switch d := udao.(type) {
+	case *henge.UniversalDaoCosmosdbSql:
+		return updateIfChecksumCosmosdb(d, tableName, ubo, checksum)
+	case *henge.UniversalDaoSql:
+		return updateIfChecksumSql(nil, nil, d, tableName, ubo, checksum)
+	case *henge.UniversalDaoMongo:
+		return updateIfChecksumMongo(d, tableName, ubo, checksum)
+	case *henge.UniversalDaoDynamodb:
+		return updateIfChecksumDynamodb(d, ubo, checksum)
+	case *memdb.UniversalDaoMemory:
+		return d.UpdateIfChecksum(ubo, checksum)
+	}
+	existing, err := udao.Get(ubo.GetId())
+	if err != nil || existing == nil || existing.GetChecksum() != checksum {
+		return false, err
+	}
+	return udao.Update(ubo)
+}
+
+// deleteIfChecksum deletes ubo only if its stored checksum still equals checksum, using a conditional write of the
+// backend of udao (see updateIfChecksum).
+//
+// Other implementations of henge.UniversalDao fall back to compare-then-delete, which is not atomic.
+func deleteIfChecksum(udao henge.UniversalDao, tableName string, ubo *henge.UniversalBo, checksum string) (bool, error) {
+	switch d := udao.(type) {
+	case *henge.UniversalDaoCosmosdbSql:
+		return deleteIfChecksumCosmosdb(d, tableName, ubo, checksum)
+	case *henge.UniversalDaoSql:
+		return deleteIfChecksumSql(d, tableName, ubo, checksum)
+	case *henge.UniversalDaoMongo:
+		return deleteIfChecksumMongo(d, tableName, ubo, checksum)
+	case *henge.UniversalDaoDynamodb:
+		return deleteIfChecksumDynamodb(d, ubo, checksum)
+	case *memdb.UniversalDaoMemory:
+		return d.DeleteIfChecksum(ubo, checksum)
+	}
+	existing, err := udao.Get(ubo.GetId())
+	if err != nil || existing == nil || existing.GetChecksum() != checksum {
+		return false, err
+	}
+	return udao.Delete(ubo)
+}
+
+// updateIfVersion implements BlogPostDao.UpdateIfVersion on top of dao's Get and UpdateIfChecksum: the version is checked
+// against the stored post and the post is written conditioned on the checksum just read, so that a concurrent write in
+// between is detected. If only counters changed, the post is reloaded and attempted again.
+func updateIfVersion(dao BlogPostDao, post *BlogPost, version string) (bool, error) {
+	for attempt := 0; attempt < writeMaxAttempts; attempt++ {
+		if attempt > 0 {
+			writeBackoff(attempt)
+		}
+		current, err := dao.Get(post.GetId())
+		if err != nil || current == nil || current.GetVersion() != version {
+			return false, err
+		}
+		checksum := current.GetChecksum()
+		current.SetPublic(post.IsPublic()).SetTitle(post.GetTitle()).SetContent(post.GetContent())
+		current.SetTimeUpdated(post.GetTimeUpdated())
+		ok, err := dao.UpdateIfChecksum(current, checksum)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			post.SetNumComments(current.GetNumComments()).SetNumVotesUp(current.GetNumVotesUp()).SetNumVotesDown(current.GetNumVotesDown())
+			return true, nil
+		}
+	}
+	return false, ErrPostConflict
+}
+
+// deleteIfVersion implements BlogPostDao.DeleteIfVersion on top of dao's Get and DeleteIfChecksum, see updateIfVersion.
+func deleteIfVersion(dao BlogPostDao, post *BlogPost, version string) (bool, error) {
+	for attempt := 0; attempt < writeMaxAttempts; attempt++ {
+		if attempt > 0 {
+			writeBackoff(attempt)
+		}
+		current, err := dao.Get(post.GetId())
+		if err != nil || current == nil || current.GetVersion() != version {
+			return false, err
+		}
+		ok, err := dao.DeleteIfChecksum(current, current.GetChecksum())
+		if err != nil || ok {
+			return ok, err
+		}
+	}
+	return false, ErrPostConflict
+}
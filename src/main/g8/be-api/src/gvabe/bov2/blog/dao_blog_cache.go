@@ -0,0 +1,143 @@
+package blog
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/btnguyen2k/godal"
+
+	"main/src/cache"
+	"main/src/gvabe/bov2/user"
+	"main/src/utils"
+)
+
+// CachedBlogPostDao is a read-through caching decorator of BlogPostDao.
+//
+//   - Get looks up the cache first and caches posts loaded from the wrapped DAO; posts not found are not cached.
+//   - Entries are invalidated after Update and Delete; lists and feeds are always read from the wrapped DAO.
+//   - Posts are cached in serialized form, hence callers never share instances with the cache.
+//
+// Available since template-v0.5.0
+type CachedBlogPostDao struct {
+	dao       BlogPostDao
+	cache     cache.Cache
+	ttl       time.Duration
+	keyPrefix string
+}
+
+// NewBlogPostDaoCache is helper method to create a read-through caching decorator of BlogPostDao.
+//
+//   - cache: where posts are cached, keyed by "<keyPrefix><post-id>".
+//   - ttl: how long posts are cached (0 or negative value means the cache's default).
+//
+// Available since template-v0.5.0
+func NewBlogPostDaoCache(dao BlogPostDao, cache cache.Cache, ttl time.Duration, keyPrefix string) *CachedBlogPostDao {
+	return &CachedBlogPostDao{dao: dao, cache: cache, ttl: ttl, keyPrefix: keyPrefix}
+}
+
+// Unwrap returns the wrapped DAO.
+func (dao *CachedBlogPostDao) Unwrap() BlogPostDao {
+	return dao.dao
+}
+
+func (dao *CachedBlogPostDao) _invalidate(id string) {
+	// stale entry expires after ttl if it cannot be removed
+	dao.cache.Delete(dao.keyPrefix + id)
+}
+
+// Evict removes a post from the cache, to be called when the post is modified in storage without going through this DAO.
+func (dao *CachedBlogPostDao) Evict(id string) {
+	dao._invalidate(id)
+}
+
+// Delete implements BlogPostDao.Delete
+func (dao *CachedBlogPostDao) Delete(post *BlogPost) (bool, error) {
+	defer dao._invalidate(post.GetId())
+	return dao.dao.Delete(post)
+}
+
+// Create implements BlogPostDao.Create
+func (dao *CachedBlogPostDao) Create(post *BlogPost) (bool, error) {
+	return dao.dao.Create(post)
+}
+
+// Get implements BlogPostDao.Get
+func (dao *CachedBlogPostDao) Get(id string) (*BlogPost, error) {
+	key := dao.keyPrefix + id
+	if data, err := dao.cache.Get(key); err == nil && data != nil {
+		if ubo, err := utils.UboFromJson(data); err == nil {
+			if post := NewBlogPostFromUbo(ubo); post != nil {
+				return post, nil
+			}
+		}
+	}
+	post, err := dao.dao.Get(id)
+	if err == nil && post != nil {
+		if data, err := json.Marshal(post.sync().UniversalBo); err == nil {
+			dao.cache.Set(key, data, dao.ttl)
+		}
+	}
+	return post, err
+}
+
+// GetN implements BlogPostDao.GetN
+func (dao *CachedBlogPostDao) GetN(fromOffset, maxNumRows int, filter godal.FilterOpt, sorting *godal.SortingOpt) ([]*BlogPost, error) {
+	return dao.dao.GetN(fromOffset, maxNumRows, filter, sorting)
+}
+
+// GetAll implements BlogPostDao.GetAll
+func (dao *CachedBlogPostDao) GetAll(filter godal.FilterOpt, sorting *godal.SortingOpt) ([]*BlogPost, error) {
+	return dao.dao.GetAll(filter, sorting)
+}
+
+// GetUserPostsN implements BlogPostDao.GetUserPostsN
+func (dao *CachedBlogPostDao) GetUserPostsN(user *user.User, fromOffset, maxNumRows int) ([]*BlogPost, error) {
+	return dao.dao.GetUserPostsN(user, fromOffset, maxNumRows)
+}
+
+// GetUserPostsAll implements BlogPostDao.GetUserPostsAll
+func (dao *CachedBlogPostDao) GetUserPostsAll(user *user.User) ([]*BlogPost, error) {
+	return dao.dao.GetUserPostsAll(user)
+}
+
+// GetUserFeedN implements BlogPostDao.GetUserFeedN
+func (dao *CachedBlogPostDao) GetUserFeedN(user *user.User, fromOffset, maxNumRows int) ([]*BlogPost, error) {
+	return dao.dao.GetUserFeedN(user, fromOffset, maxNumRows)
+}
+
+// GetUserFeedAll implements BlogPostDao.GetUserFeedAll
+func (dao *CachedBlogPostDao) GetUserFeedAll(user *user.User) ([]*BlogPost, error) {
+	return dao.dao.GetUserFeedAll(user)
+}
+
+// Update implements BlogPostDao.Update
+func (dao *CachedBlogPostDao) Update(post *BlogPost) (bool, error) {
+	defer dao._invalidate(post.GetId())
+	return dao.dao.Update(post)
+}
+
+// UpdateIfChecksum implements BlogPostDao.UpdateIfChecksum
+//
+// The entry is invalidated whether the update succeeds or not: a failed update usually means the cached post is stale.
+func (dao *CachedBlogPostDao) UpdateIfChecksum(post *BlogPost, checksum string) (bool, error) {
+	defer dao._invalidate(post.GetId())
+	return dao.dao.UpdateIfChecksum(post, checksum)
+}
+
+// DeleteIfChecksum implements BlogPostDao.DeleteIfChecksum
+func (dao *CachedBlogPostDao) DeleteIfChecksum(post *BlogPost, checksum string) (bool, error) {
+	defer dao._invalidate(post.GetId())
+	return dao.dao.DeleteIfChecksum(post, checksum)
+}
+
+// UpdateIfVersion implements BlogPostDao.UpdateIfVersion
+func (dao *CachedBlogPostDao) UpdateIfVersion(post *BlogPost, version string) (bool, error) {
+	defer dao._invalidate(post.GetId())
+	return dao.dao.UpdateIfVersion(post, version)
+}
+
+// DeleteIfVersion implements BlogPostDao.DeleteIfVersion
+func (dao *CachedBlogPostDao) DeleteIfVersion(post *BlogPost, version string) (bool, error) {
+	defer dao._invalidate(post.GetId())
+	return dao.dao.DeleteIfVersion(post, version)
+}
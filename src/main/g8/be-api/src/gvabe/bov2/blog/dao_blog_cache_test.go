@@ -0,0 +1,99 @@
+package blog
+
+import (
+	"testing"
+	"time"
+
+	"main/src/cache"
+	"main/src/gvabe/bov2/user"
+)
+
+func initBlogPostDaoCache(t *testing.T, testName string) BlogPostDao {
+	return NewBlogPostDaoCache(initBlogPostDaoMemory(t, testName), cache.NewLruCache(1000, time.Minute), 0, "post:")
+}
+
+func TestPostDaoCache_CreateGet(t *testing.T) {
+	name := "TestPostDaoCache_CreateGet"
+	dao := initBlogPostDaoCache(t, name)
+	doTestPostDaoCreateGet(t, name, dao)
+}
+
+func TestPostDaoCache_CreateUpdateGet(t *testing.T) {
+	name := "TestPostDaoCache_CreateUpdateGet"
+	dao := initBlogPostDaoCache(t, name)
+	doTestPostDaoCreateUpdateGet(t, name, dao)
+}
+
+func TestPostDaoCache_CreateDelete(t *testing.T) {
+	name := "TestPostDaoCache_CreateDelete"
+	dao := initBlogPostDaoCache(t, name)
+	doTestPostDaoCreateDelete(t, name, dao)
+}
+
+func TestPostDaoCache_GetUserFeedN(t *testing.T) {
+	name := "TestPostDaoCache_GetUserFeedN"
+	dao := initBlogPostDaoCache(t, name)
+	doTestPostDaoGetUserFeedN(t, name, dao)
+}
+
+func TestBlogDaoCache_Conformance(t *testing.T) {
+	name := "TestBlogDaoCache_Conformance"
+	runBlogDaoConformance(t, name, &blogDaoFactory{
+		newCommentDao: initBlogCommentDaoMemory,
+		newPostDao:    initBlogPostDaoCache,
+		newVoteDao:    initBlogVoteDaoMemory,
+	})
+}
+
+func TestPostDaoCache_ReadThrough(t *testing.T) {
+	name := "TestPostDaoCache_ReadThrough"
+	lru := cache.NewMeteredCache(cache.NewLruCache(1000, time.Minute))
+	backend := initBlogPostDaoMemory(t, name)
+	dao := NewBlogPostDaoCache(backend, lru, 0, "post:")
+	owner := user.NewUser(1337, "user@local", "user")
+	post := NewBlogPost(1337, owner, true, "title", "content")
+	post.SetNumVotesUp(1)
+	// a timestamp in the past: cached reads must return the stored value, not the time of reading
+	post.SetTimeUpdated(time.Now().Add(-time.Hour))
+	if ok, err := dao.Create(post); err != nil || !ok {
+		t.Fatalf("%s failed: %#v / %s", name+"/Create", ok, err)
+	}
+	p, err := dao.Get(post.GetId())
+	if err != nil || p == nil {
+		t.Fatalf("%s failed: nil or error %s", name, err)
+	}
+	checksum, timeCreated, timeUpdated := p.GetChecksum(), p.GetTimeCreated(), p.GetTimeUpdated()
+
+	// modifying the returned post must not affect the cached one
+	p.IncNumVotesUp(1)
+	// changes made directly to the backend are not visible until the entry is invalidated
+	p0, _ := backend.Get(post.GetId())
+	p0.SetTitle("backend")
+	backend.Update(p0)
+	if p, _ := dao.Get(post.GetId()); p == nil || p.GetTitle() != "title" || p.GetNumVotesUp() != 1 {
+		t.Fatalf("%s failed: expected cached post but received %#v", name, p)
+	} else if p.GetChecksum() != checksum || !p.GetTimeCreated().Equal(timeCreated) || !p.GetTimeUpdated().Equal(timeUpdated) || p.GetOwnerId() != owner.GetId() || !p.IsPublic() {
+		t.Fatalf("%s failed: cached post does not match stored one %#v", name, p)
+	}
+
+	// updating via the decorator invalidates the entry
+	if ok, err := dao.Update(p); err != nil || !ok {
+		t.Fatalf("%s failed: %#v / %s", name+"/Update", ok, err)
+	}
+	if p, _ := dao.Get(post.GetId()); p == nil || p.GetNumVotesUp() != 2 {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, 2, p)
+	}
+
+	// deleting via the decorator invalidates the entry
+	if ok, err := dao.Delete(p); err != nil || !ok {
+		t.Fatalf("%s failed: %#v / %s", name+"/Delete", ok, err)
+	}
+	if p, _ := dao.Get(post.GetId()); p != nil {
+		t.Fatalf("%s failed: expected nil but received %#v", name, p)
+	}
+
+	expected := cache.Stats{Hits: 1, Misses: 3, Sets: 2, Invalidations: 2}
+	if stats := lru.Stats(); stats != expected {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, expected, stats)
+	}
+}
@@ -1,6 +1,13 @@
 package blog
 
 import (
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/btnguyen2k/gocosmos"
+	"github.com/btnguyen2k/godal"
 	"github.com/btnguyen2k/henge"
 	promsql "github.com/btnguyen2k/prom/sql"
 )
@@ -26,7 +33,7 @@ func NewBlogCommentDaoCosmosdb(sqlc *promsql.SqlConnect, tableName string, txMod
 //
 // Available since template-v0.3.0
 func NewBlogPostDaoCosmosdb(sqlc *promsql.SqlConnect, tableName string, txModeOnWrite bool) BlogPostDao {
-	dao := &BaseBlogPostDaoImpl{}
+	dao := &BaseBlogPostDaoImpl{tableName: tableName}
 	spec := &henge.CosmosdbDaoSpec{
 		PkName:        henge.CosmosdbColId,
 		TxModeOnWrite: txModeOnWrite,
@@ -41,7 +48,7 @@ func NewBlogPostDaoCosmosdb(sqlc *promsql.SqlConnect, tableName string, txModeOn
 //
 // Available since template-v0.3.0
 func NewBlogVoteDaoCosmosdb(sqlc *promsql.SqlConnect, tableName string, txModeOnWrite bool) BlogVoteDao {
-	dao := &BaseBlogVoteDaoImpl{}
+	dao := &BaseBlogVoteDaoImpl{tableName: tableName}
 	spec := &henge.CosmosdbDaoSpec{
 		PkName:        henge.CosmosdbColId,
 		TxModeOnWrite: txModeOnWrite,
@@ -49,3 +56,124 @@ func NewBlogVoteDaoCosmosdb(sqlc *promsql.SqlConnect, tableName string, txModeOn
 	dao.UniversalDao = henge.NewUniversalDaoCosmosdbSql(sqlc, tableName, spec)
 	return dao
 }
+
+/*----------------------------------------------------------------------*/
+
+// Cosmos DB's SQL API can not express conditional writes, hence conditional updates are done via the REST API:
+// the document is read, its checksum is compared and the document is replaced only if its etag has not changed since.
+
+var (
+	cosmosdbRestClients     = make(map[string]*gocosmos.RestClient) // REST clients, keyed by connection string
+	cosmosdbRestClientsLock sync.Mutex
+)
+
+// cosmosdbRestClient returns the REST client and the database name for the connection string of sqlc.
+func cosmosdbRestClient(sqlc *promsql.SqlConnect) (*gocosmos.RestClient, string, error) {
+	dsn := sqlc.GetDsn()
+	dbName := ""
+	for _, part := range strings.Split(dsn, ";") {
+		if tokens := strings.SplitN(part, "=", 2); len(tokens) == 2 && strings.EqualFold(strings.TrimSpace(tokens[0]), "Db") {
+			dbName = strings.TrimSpace(tokens[1])
+		}
+	}
+	if dbName == "" {
+		return nil, "", errors.New("database name (Db) not found in connection string")
+	}
+	cosmosdbRestClientsLock.Lock()
+	defer cosmosdbRestClientsLock.Unlock()
+	if client, ok := cosmosdbRestClients[dsn]; ok {
+		return client, dbName, nil
+	}
+	client, err := gocosmos.NewRestClient(nil, dsn)
+	if err != nil {
+		return nil, "", err
+	}
+	cosmosdbRestClients[dsn] = client
+	return client, dbName, nil
+}
+
+// cosmosdbDocument returns the document of ubo and its partition key values.
+func cosmosdbDocument(udao *henge.UniversalDaoCosmosdbSql, collName string, ubo *henge.UniversalBo) (map[string]interface{}, []interface{}, error) {
+	row, err := udao.GetRowMapper().ToRow(collName, udao.ToGenericBo(ubo))
+	if err != nil {
+		return nil, nil, err
+	}
+	doc, ok := row.(map[string]interface{})
+	if !ok {
+		return nil, nil, errors.New("row data must be a map")
+	}
+	pkValue := interface{}(udao.GetPkValue())
+	if udao.GetPkValue() == "" {
+		pkValue = doc[udao.GetPkName()]
+	} else {
+		doc[udao.GetPkName()] = pkValue
+	}
+	return doc, []interface{}{pkValue}, nil
+}
+
+// cosmosdbEtagIfChecksum reads the document specified by req and returns its etag if its stored checksum equals
+// checksum, or "" if the document does not exist or has a different checksum.
+func cosmosdbEtagIfChecksum(client *gocosmos.RestClient, req gocosmos.DocReq, checksum string) (string, error) {
+	getResult := client.GetDocument(req)
+	if getResult.StatusCode == http.StatusNotFound {
+		return "", nil
+	}
+	if err := getResult.Error(); err != nil {
+		return "", err
+	}
+	if storedChecksum, _ := getResult.DocInfo[henge.FieldChecksum].(string); storedChecksum != checksum {
+		return "", nil
+	}
+	return getResult.DocInfo.Etag(), nil
+}
+
+// updateIfChecksumCosmosdb replaces the document of ubo if its stored checksum equals checksum, guarded by the
+// document's etag so that concurrent writes in between are detected.
+func updateIfChecksumCosmosdb(udao *henge.UniversalDaoCosmosdbSql, collName string, ubo *henge.UniversalBo, checksum string) (bool, error) {
+	client, dbName, err := cosmosdbRestClient(udao.GetSqlConnect())
+	if err != nil {
+		return false, err
+	}
+	doc, pkValues, err := cosmosdbDocument(udao, collName, ubo)
+	if err != nil {
+		return false, err
+	}
+	req := gocosmos.DocReq{DbName: dbName, CollName: collName, DocId: ubo.GetId(), PartitionKeyValues: pkValues}
+	etag, err := cosmosdbEtagIfChecksum(client, req, checksum)
+	if err != nil || etag == "" {
+		return false, err
+	}
+	spec := gocosmos.DocumentSpec{DbName: dbName, CollName: collName, PartitionKeyValues: pkValues, DocumentData: doc}
+	replaceResult := client.ReplaceDocument(etag, spec)
+	if replaceResult.StatusCode == http.StatusPreconditionFailed || replaceResult.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if replaceResult.StatusCode == http.StatusConflict {
+		return false, godal.ErrGdaoDuplicatedEntry
+	}
+	return replaceResult.Error() == nil, replaceResult.Error()
+}
+
+// deleteIfChecksumCosmosdb deletes the document of ubo if its stored checksum equals checksum, guarded by the
+// document's etag so that concurrent writes in between are detected.
+func deleteIfChecksumCosmosdb(udao *henge.UniversalDaoCosmosdbSql, collName string, ubo *henge.UniversalBo, checksum string) (bool, error) {
+	client, dbName, err := cosmosdbRestClient(udao.GetSqlConnect())
+	if err != nil {
+		return false, err
+	}
+	_, pkValues, err := cosmosdbDocument(udao, collName, ubo)
+	if err != nil {
+		return false, err
+	}
+	req := gocosmos.DocReq{DbName: dbName, CollName: collName, DocId: ubo.GetId(), PartitionKeyValues: pkValues}
+	etag, err := cosmosdbEtagIfChecksum(client, req, checksum)
+	if err != nil || etag == "" {
+		return false, err
+	}
+	req.MatchEtag = etag
+	deleteResult := client.DeleteDocument(req)
+	if deleteResult.StatusCode == http.StatusPreconditionFailed || deleteResult.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	return deleteResult.Error() == nil, deleteResult.Error()
+}
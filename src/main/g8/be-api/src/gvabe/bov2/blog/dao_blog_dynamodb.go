@@ -1,13 +1,16 @@
 package blog
 
 import (
-	"log"
+	"errors"
 	"sort"
 
+	awsdynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
 	"github.com/btnguyen2k/godal"
 	"github.com/btnguyen2k/henge"
 	promdynamodb "github.com/btnguyen2k/prom/dynamodb"
 	"main/src/gvabe/bov2/user"
+	"main/src/logging"
 )
 
 // InitBlogCommentTableDynamodb is helper method to initialize AWS DynamoDB table to store blog comments.
@@ -16,7 +19,7 @@ import (
 func InitBlogCommentTableDynamodb(adc *promdynamodb.AwsDynamodbConnect, tableName string) error {
 	spec := &henge.DynamodbTablesSpec{MainTableRcu: 2, MainTableWcu: 1}
 	if err := henge.InitDynamodbTables(adc, tableName, spec); err != nil {
-		log.Printf("[WARN] error creating table %s (%s): %s\n", tableName, "DynamoDB", err)
+		logging.Warnf("error creating table %s (%s): %s", tableName, "DynamoDB", err)
 		return err
 	}
 
@@ -27,10 +30,10 @@ func InitBlogCommentTableDynamodb(adc *promdynamodb.AwsDynamodbConnect, tableNam
 	// if err := adc.CreateGlobalSecondaryIndex(nil, tableName, gsiName, 2, 1,
 	// 	[]prom.AwsDynamodbNameAndType{{Name: CommentFieldOwnerId, Type: prom.AwsAttrTypeString}},
 	// 	[]prom.AwsDynamodbNameAndType{{Name: CommentFieldOwnerId, Type: prom.AwsKeyTypePartition}}); err != nil {
-	// 	log.Printf("[WARN] error creating GSI %s/%s (%s): %s\n", tableName, colName, "DynamoDB", err)
+	// 	logging.Warnf("error creating GSI %s/%s (%s): %s", tableName, colName, "DynamoDB", err)
 	// 	return err
 	// } else if err := prom.AwsDynamodbWaitForGsiStatus(adc, tableName, gsiName, []string{"ACTIVE"}, 1*time.Second, 10*time.Second); err != nil {
-	// 	log.Printf("[WARN] error waiting GSI for to be ACTIVE %s/%s (%s): %s\n", tableName, colName, "DynamoDB", err)
+	// 	logging.Warnf("error waiting GSI for to be ACTIVE %s/%s (%s): %s", tableName, colName, "DynamoDB", err)
 	// 	return err
 	// }
 	//
@@ -39,10 +42,10 @@ func InitBlogCommentTableDynamodb(adc *promdynamodb.AwsDynamodbConnect, tableNam
 	// if err := adc.CreateGlobalSecondaryIndex(nil, tableName, gsiName, 2, 1,
 	// 	[]prom.AwsDynamodbNameAndType{{Name: CommentFieldPostId, Type: prom.AwsAttrTypeString}},
 	// 	[]prom.AwsDynamodbNameAndType{{Name: CommentFieldPostId, Type: prom.AwsKeyTypePartition}}); err != nil {
-	// 	log.Printf("[WARN] error creating GSI %s/%s (%s): %s\n", tableName, colName, "DynamoDB", err)
+	// 	logging.Warnf("error creating GSI %s/%s (%s): %s", tableName, colName, "DynamoDB", err)
 	// 	return err
 	// } else if err := prom.AwsDynamodbWaitForGsiStatus(adc, tableName, gsiName, []string{"ACTIVE"}, 1*time.Second, 10*time.Second); err != nil {
-	// 	log.Printf("[WARN] waiting GSI %s/%s (%s): %s\n", tableName, colName, "DynamoDB", err)
+	// 	logging.Warnf("waiting GSI %s/%s (%s): %s", tableName, colName, "DynamoDB", err)
 	// 	return err
 	// }
 
@@ -67,7 +70,7 @@ func NewBlogCommentDaoDynamodb(adc *promdynamodb.AwsDynamodbConnect, tableName s
 func InitBlogPostTableDynamodb(adc *promdynamodb.AwsDynamodbConnect, tableName string) error {
 	spec := &henge.DynamodbTablesSpec{MainTableRcu: 2, MainTableWcu: 1}
 	if err := henge.InitDynamodbTables(adc, tableName, spec); err != nil {
-		log.Printf("[WARN] creating table %s (%s): %s\n", tableName, "DynamoDB", err)
+		logging.Warnf("creating table %s (%s): %s", tableName, "DynamoDB", err)
 		return err
 	}
 
@@ -78,10 +81,10 @@ func InitBlogPostTableDynamodb(adc *promdynamodb.AwsDynamodbConnect, tableName s
 	// if err := adc.CreateGlobalSecondaryIndex(nil, tableName, gsiName, 2, 1,
 	// 	[]prom.AwsDynamodbNameAndType{{Name: henge.FieldTimeCreated, Type: prom.AwsAttrTypeString}},
 	// 	[]prom.AwsDynamodbNameAndType{{Name: PostFieldOwnerId, Type: prom.AwsKeyTypePartition}}); err != nil {
-	// 	log.Printf("[WARN] error creating GSI %s/%s (%s): %s\n", tableName, colName, "DynamoDB", err)
+	// 	logging.Warnf("error creating GSI %s/%s (%s): %s", tableName, colName, "DynamoDB", err)
 	// 	return err
 	// } else if err := prom.AwsDynamodbWaitForGsiStatus(adc, tableName, gsiName, []string{"ACTIVE"}, 1*time.Second, 10*time.Second); err != nil {
-	// 	log.Printf("[WARN] error waiting GSI for to be ACTIVE %s/%s (%s): %s\n", tableName, colName, "DynamoDB", err)
+	// 	logging.Warnf("error waiting GSI for to be ACTIVE %s/%s (%s): %s", tableName, colName, "DynamoDB", err)
 	// 	return err
 	// }
 
@@ -90,10 +93,10 @@ func InitBlogPostTableDynamodb(adc *promdynamodb.AwsDynamodbConnect, tableName s
 	// if err := adc.CreateGlobalSecondaryIndex(nil, tableName, gsiName, 2, 1,
 	// 	[]prom.AwsDynamodbNameAndType{{Name: PostFieldOwnerId, Type: prom.AwsAttrTypeString}},
 	// 	[]prom.AwsDynamodbNameAndType{{Name: PostFieldOwnerId, Type: prom.AwsKeyTypePartition}}); err != nil {
-	// 	log.Printf("[WARN] error creating GSI %s/%s (%s): %s\n", tableName, colName, "DynamoDB", err)
+	// 	logging.Warnf("error creating GSI %s/%s (%s): %s", tableName, colName, "DynamoDB", err)
 	// 	return err
 	// } else if err := prom.AwsDynamodbWaitForGsiStatus(adc, tableName, gsiName, []string{"ACTIVE"}, 1*time.Second, 10*time.Second); err != nil {
-	// 	log.Printf("[WARN] error waiting GSI for to be ACTIVE %s/%s (%s): %s\n", tableName, colName, "DynamoDB", err)
+	// 	logging.Warnf("error waiting GSI for to be ACTIVE %s/%s (%s): %s", tableName, colName, "DynamoDB", err)
 	// 	return err
 	// }
 
@@ -102,10 +105,10 @@ func InitBlogPostTableDynamodb(adc *promdynamodb.AwsDynamodbConnect, tableName s
 	// if err := adc.CreateGlobalSecondaryIndex(nil, tableName, gsiName, 2, 1,
 	// 	[]prom.AwsDynamodbNameAndType{{Name: PostFieldIsPublic, Type: prom.AwsAttrTypeNumber}},
 	// 	[]prom.AwsDynamodbNameAndType{{Name: PostFieldIsPublic, Type: prom.AwsKeyTypePartition}}); err != nil {
-	// 	log.Printf("[WARN] error creating GSI %s/%s (%s): %s\n", tableName, colName, "DynamoDB", err)
+	// 	logging.Warnf("error creating GSI %s/%s (%s): %s", tableName, colName, "DynamoDB", err)
 	// 	return err
 	// } else if err := prom.AwsDynamodbWaitForGsiStatus(adc, tableName, gsiName, []string{"ACTIVE"}, 1*time.Second, 10*time.Second); err != nil {
-	// 	log.Printf("[WARN] error waiting GSI for to be ACTIVE %s/%s (%s): %s\n", tableName, colName, "DynamoDB", err)
+	// 	logging.Warnf("error waiting GSI for to be ACTIVE %s/%s (%s): %s", tableName, colName, "DynamoDB", err)
 	// 	return err
 	// }
 
@@ -116,7 +119,7 @@ func InitBlogPostTableDynamodb(adc *promdynamodb.AwsDynamodbConnect, tableName s
 //
 // Available since template-v0.3.0
 func NewBlogPostDaoDynamodb(adc *promdynamodb.AwsDynamodbConnect, tableName string) BlogPostDao {
-	dao := &DynamodbBlogPostDaoImpl{&BaseBlogPostDaoImpl{}}
+	dao := &DynamodbBlogPostDaoImpl{&BaseBlogPostDaoImpl{tableName: tableName}}
 	spec := &henge.DynamodbDaoSpec{}
 	udaoDynamodb := henge.NewUniversalDaoDynamodb(adc, tableName, spec)
 	{
@@ -133,12 +136,10 @@ type DynamodbBlogPostDaoImpl struct {
 	*BaseBlogPostDaoImpl
 }
 
-// GetUserFeedN implements BlogPostDao.GetUserFeedN
-func (dao *DynamodbBlogPostDaoImpl) GetUserFeedN(user *user.User, fromOffset, maxNumRows int) ([]*BlogPost, error) {
-	filter := (&godal.FilterOptOr{}).
-		Add(&godal.FilterOptFieldOpValue{FieldName: PostFieldOwnerId, Operator: godal.FilterOpEqual, Value: user.GetId()}).
-		Add(&godal.FilterOptFieldOpValue{FieldName: PostFieldIsPublic, Operator: godal.FilterOpEqual, Value: 1})
-	uboList, err := dao.UniversalDao.GetN(fromOffset, maxNumRows, filter, nil)
+// _getSortedN fetches all blog posts matching filter, sorts them by creation time (newest first) and returns the
+// requested page. DynamoDB can not sort a scan, hence sorting must be done before paging.
+func (dao *DynamodbBlogPostDaoImpl) _getSortedN(filter godal.FilterOpt, fromOffset, maxNumRows int) ([]*BlogPost, error) {
+	uboList, err := dao.UniversalDao.GetAll(filter, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -147,12 +148,30 @@ func (dao *DynamodbBlogPostDaoImpl) GetUserFeedN(user *user.User, fromOffset, ma
 		app := NewBlogPostFromUbo(ubo)
 		result = append(result, app)
 	}
-	sort.Slice(result, func(i, j int) bool {
+	sort.SliceStable(result, func(i, j int) bool {
 		return result[i].GetTimeCreated().After(result[j].GetTimeCreated())
 	})
+	if fromOffset < 0 {
+		fromOffset = 0
+	}
+	if fromOffset > len(result) {
+		fromOffset = len(result)
+	}
+	result = result[fromOffset:]
+	if maxNumRows > 0 && maxNumRows < len(result) {
+		result = result[:maxNumRows]
+	}
 	return result, nil
 }
 
+// GetUserFeedN implements BlogPostDao.GetUserFeedN
+func (dao *DynamodbBlogPostDaoImpl) GetUserFeedN(user *user.User, fromOffset, maxNumRows int) ([]*BlogPost, error) {
+	filter := (&godal.FilterOptOr{}).
+		Add(&godal.FilterOptFieldOpValue{FieldName: PostFieldOwnerId, Operator: godal.FilterOpEqual, Value: user.GetId()}).
+		Add(&godal.FilterOptFieldOpValue{FieldName: PostFieldIsPublic, Operator: godal.FilterOpEqual, Value: 1})
+	return dao._getSortedN(filter, fromOffset, maxNumRows)
+}
+
 // GetUserFeedAll implements BlogPostDao.GetUserFeedAll
 func (dao *DynamodbBlogPostDaoImpl) GetUserFeedAll(user *user.User) ([]*BlogPost, error) {
 	return dao.GetUserFeedN(user, 0, 0)
@@ -161,19 +180,7 @@ func (dao *DynamodbBlogPostDaoImpl) GetUserFeedAll(user *user.User) ([]*BlogPost
 // GetUserPostsN implements BlogPostDao.GetUserPostsN
 func (dao *DynamodbBlogPostDaoImpl) GetUserPostsN(user *user.User, fromOffset, maxNumRows int) ([]*BlogPost, error) {
 	filter := &godal.FilterOptFieldOpValue{FieldName: PostFieldOwnerId, Operator: godal.FilterOpEqual, Value: user.GetId()}
-	uboList, err := dao.UniversalDao.GetN(fromOffset, maxNumRows, filter, nil)
-	if err != nil {
-		return nil, err
-	}
-	result := make([]*BlogPost, 0)
-	for _, ubo := range uboList {
-		app := NewBlogPostFromUbo(ubo)
-		result = append(result, app)
-	}
-	sort.Slice(result, func(i, j int) bool {
-		return result[i].GetTimeCreated().After(result[j].GetTimeCreated())
-	})
-	return result, nil
+	return dao._getSortedN(filter, fromOffset, maxNumRows)
 }
 
 // GetUserPostsAll implements BlogPostDao.GetUserPostsAll
@@ -189,7 +196,7 @@ func (dao *DynamodbBlogPostDaoImpl) GetUserPostsAll(user *user.User) ([]*BlogPos
 func InitBlogVoteTableDynamodb(adc *promdynamodb.AwsDynamodbConnect, tableName string) error {
 	spec := &henge.DynamodbTablesSpec{MainTableRcu: 2, MainTableWcu: 1, CreateUidxTable: true, UidxTableRcu: 2, UidxTableWcu: 1}
 	if err := henge.InitDynamodbTables(adc, tableName, spec); err != nil {
-		log.Printf("[WARN] creating table %s (%s): %s\n", tableName, "DynamoDB", err)
+		logging.Warnf("creating table %s (%s): %s", tableName, "DynamoDB", err)
 		return err
 	}
 
@@ -200,10 +207,10 @@ func InitBlogVoteTableDynamodb(adc *promdynamodb.AwsDynamodbConnect, tableName s
 	// if err := adc.CreateGlobalSecondaryIndex(nil, tableName, gsiName, 2, 1,
 	// 	[]prom.AwsDynamodbNameAndType{{Name: VoteFieldOwnerId, Type: prom.AwsAttrTypeString}, {Name: VoteFieldTargetId, Type: prom.AwsAttrTypeString}},
 	// 	[]prom.AwsDynamodbNameAndType{{Name: VoteFieldOwnerId, Type: prom.AwsKeyTypePartition}, {Name: VoteFieldTargetId, Type: prom.AwsKeyTypeSort}}); err != nil {
-	// 	log.Printf("[WARN] error creating GSI %s/%s (%s): %s\n", tableName, colName, "DynamoDB", err)
+	// 	logging.Warnf("error creating GSI %s/%s (%s): %s", tableName, colName, "DynamoDB", err)
 	// 	return err
 	// } else if err := prom.AwsDynamodbWaitForGsiStatus(adc, tableName, gsiName, []string{"ACTIVE"}, 1*time.Second, 10*time.Second); err != nil {
-	// 	log.Printf("[WARN] error waiting GSI for to be ACTIVE %s/%s (%s): %s\n", tableName, colName, "DynamoDB", err)
+	// 	logging.Warnf("error waiting GSI for to be ACTIVE %s/%s (%s): %s", tableName, colName, "DynamoDB", err)
 	// 	return err
 	// }
 	//
@@ -212,10 +219,10 @@ func InitBlogVoteTableDynamodb(adc *promdynamodb.AwsDynamodbConnect, tableName s
 	// if err := adc.CreateGlobalSecondaryIndex(nil, tableName, gsiName, 2, 1,
 	// 	[]prom.AwsDynamodbNameAndType{{Name: VoteFieldTargetId, Type: prom.AwsAttrTypeString}, {Name: VoteFieldValue, Type: prom.AwsAttrTypeNumber}},
 	// 	[]prom.AwsDynamodbNameAndType{{Name: VoteFieldTargetId, Type: prom.AwsKeyTypePartition}, {Name: VoteFieldValue, Type: prom.AwsKeyTypeSort}}); err != nil {
-	// 	log.Printf("[WARN] error creating GSI %s/%s (%s): %s\n", tableName, colName, "DynamoDB", err)
+	// 	logging.Warnf("error creating GSI %s/%s (%s): %s", tableName, colName, "DynamoDB", err)
 	// 	return err
 	// } else if err := prom.AwsDynamodbWaitForGsiStatus(adc, tableName, gsiName, []string{"ACTIVE"}, 1*time.Second, 10*time.Second); err != nil {
-	// 	log.Printf("[WARN] error waiting GSI for to be ACTIVE %s/%s (%s): %s\n", tableName, colName, "DynamoDB", err)
+	// 	logging.Warnf("error waiting GSI for to be ACTIVE %s/%s (%s): %s", tableName, colName, "DynamoDB", err)
 	// 	return err
 	// }
 
@@ -226,8 +233,61 @@ func InitBlogVoteTableDynamodb(adc *promdynamodb.AwsDynamodbConnect, tableName s
 //
 // Available since template-v0.3.0
 func NewBlogVoteDaoDynamodb(adc *promdynamodb.AwsDynamodbConnect, tableName string) BlogVoteDao {
-	dao := &BaseBlogVoteDaoImpl{}
+	dao := &BaseBlogVoteDaoImpl{tableName: tableName}
 	spec := &henge.DynamodbDaoSpec{UidxAttrs: [][]string{{VoteFieldOwnerId, VoteFieldTargetId}}}
 	dao.UniversalDao = henge.NewUniversalDaoDynamodb(adc, tableName, spec)
 	return dao
 }
+
+/*----------------------------------------------------------------------*/
+
+// updateIfChecksumDynamodb updates the item of ubo with an "UpdateItem" request conditioned on the stored checksum.
+//
+// Note: items' unique-index entries are not touched, hence attributes used by unique indexes must not be changed.
+func updateIfChecksumDynamodb(udao *henge.UniversalDaoDynamodb, ubo *henge.UniversalBo, checksum string) (bool, error) {
+	tableName := udao.GetTableName()
+	row, err := udao.GetRowMapper().ToRow(tableName, udao.ToGenericBo(ubo))
+	if err != nil {
+		return false, err
+	}
+	item, ok := row.(map[string]interface{})
+	if !ok {
+		return false, errors.New("row data must be a map")
+	}
+	keyFilter := make(map[string]interface{})
+	for _, pk := range udao.GetRowMapper().ColumnsList(tableName) {
+		keyFilter[pk] = item[pk]
+		delete(item, pk)
+	}
+	condition := expression.Name(henge.FieldChecksum).Equal(expression.Value(checksum))
+	_, err = udao.GetAwsDynamodbConnect().UpdateItem(nil, tableName, keyFilter, &condition, nil, item, nil, nil)
+	if promdynamodb.IsAwsError(err, awsdynamodb.ErrCodeConditionalCheckFailedException) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// deleteIfChecksumDynamodb deletes the item of ubo with a "DeleteItem" request conditioned on the stored checksum.
+//
+// Note: items' unique-index entries are not touched, hence this function must not be used for tables with unique indexes.
+func deleteIfChecksumDynamodb(udao *henge.UniversalDaoDynamodb, ubo *henge.UniversalBo, checksum string) (bool, error) {
+	tableName := udao.GetTableName()
+	row, err := udao.GetRowMapper().ToRow(tableName, udao.ToGenericBo(ubo))
+	if err != nil {
+		return false, err
+	}
+	item, ok := row.(map[string]interface{})
+	if !ok {
+		return false, errors.New("row data must be a map")
+	}
+	keyFilter := make(map[string]interface{})
+	for _, pk := range udao.GetRowMapper().ColumnsList(tableName) {
+		keyFilter[pk] = item[pk]
+	}
+	condition := expression.Name(henge.FieldChecksum).Equal(expression.Value(checksum))
+	_, err = udao.GetAwsDynamodbConnect().DeleteItem(nil, tableName, keyFilter, &condition)
+	if promdynamodb.IsAwsError(err, awsdynamodb.ErrCodeConditionalCheckFailedException) {
+		return false, nil
+	}
+	return err == nil, err
+}
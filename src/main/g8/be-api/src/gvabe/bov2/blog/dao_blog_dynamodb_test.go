@@ -123,6 +123,42 @@ var teardownTestDynamodb = func(t *testing.T, testName string) {
 
 /*----------------------------------------------------------------------*/
 
+func TestBlogDaoDynamodb_Conformance(t *testing.T) {
+	name := "TestBlogDaoDynamodb_Conformance"
+	adc := _createAwsDynamodbConnect(t, name)
+	defer adc.Close()
+	defer func() {
+		_adbDeleteTableAndWait(adc, testDynamodbTableComment)
+		_adbDeleteTableAndWait(adc, testDynamodbTablePost)
+		_adbDeleteTableAndWait(adc, testDynamodbTableVote)
+	}()
+	runBlogDaoConformance(t, name, &blogDaoFactory{
+		newCommentDao: func(t *testing.T, testName string) BlogCommentDao {
+			_adbDeleteTableAndWait(adc, testDynamodbTableComment)
+			if err := InitBlogCommentTableDynamodb(adc, testDynamodbTableComment); err != nil {
+				t.Fatalf("%s failed: error [%s]", testName+"/InitBlogCommentTableDynamodb", err)
+			}
+			return initBlogCommentDaoDynamodb(adc)
+		},
+		newPostDao: func(t *testing.T, testName string) BlogPostDao {
+			_adbDeleteTableAndWait(adc, testDynamodbTablePost)
+			if err := InitBlogPostTableDynamodb(adc, testDynamodbTablePost); err != nil {
+				t.Fatalf("%s failed: error [%s]", testName+"/InitBlogPostTableDynamodb", err)
+			}
+			return initBlogPostDaoDynamodb(adc)
+		},
+		newVoteDao: func(t *testing.T, testName string) BlogVoteDao {
+			_adbDeleteTableAndWait(adc, testDynamodbTableVote)
+			if err := InitBlogVoteTableDynamodb(adc, testDynamodbTableVote); err != nil {
+				t.Fatalf("%s failed: error [%s]", testName+"/InitBlogVoteTableDynamodb", err)
+			}
+			return initBlogVoteDaoDynamodb(adc)
+		},
+	})
+}
+
+/*----------------------------------------------------------------------*/
+
 func TestNewCommentDaoDynamodb(t *testing.T) {
 	testName := "TestNewCommentDaoDynamodb"
 	adc := _createAwsDynamodbConnect(t, testName)
@@ -207,6 +243,20 @@ func TestPostDaoDynamodb_CreateDelete(t *testing.T) {
 	doTestPostDaoCreateDelete(t, testName, testDaoPost)
 }
 
+func TestPostDaoDynamodb_GetAll(t *testing.T) {
+	testName := "TestPostDaoDynamodb_GetAll"
+	teardownTest := setupTest(t, testName, setupTestDynamodb, teardownTestDynamodb)
+	defer teardownTest(t)
+	doTestPostDaoGetAll(t, testName, testDaoPost)
+}
+
+func TestPostDaoDynamodb_GetN(t *testing.T) {
+	testName := "TestPostDaoDynamodb_GetN"
+	teardownTest := setupTest(t, testName, setupTestDynamodb, teardownTestDynamodb)
+	defer teardownTest(t)
+	doTestPostDaoGetN(t, testName, testDaoPost)
+}
+
 func TestPostDaoDynamodb_GetUserPostsAll(t *testing.T) {
 	testName := "TestPostDaoDynamodb_GetUserPostsAll"
 	teardownTest := setupTest(t, testName, setupTestDynamodb, teardownTestDynamodb)
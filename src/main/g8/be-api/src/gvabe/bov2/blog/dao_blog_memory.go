@@ -0,0 +1,32 @@
+package blog
+
+import (
+	"main/src/memdb"
+)
+
+// NewBlogCommentDaoMemory is helper method to create in-memory implementation of BlogCommentDao.
+//
+// Available since template-v0.5.0
+func NewBlogCommentDaoMemory(db *memdb.MemoryDb, tableName string) BlogCommentDao {
+	dao := &BaseBlogCommentDaoImpl{}
+	dao.UniversalDao = memdb.NewUniversalDaoMemory(db, tableName)
+	return dao
+}
+
+// NewBlogPostDaoMemory is helper method to create in-memory implementation of BlogPostDao.
+//
+// Available since template-v0.5.0
+func NewBlogPostDaoMemory(db *memdb.MemoryDb, tableName string) BlogPostDao {
+	dao := &BaseBlogPostDaoImpl{tableName: tableName}
+	dao.UniversalDao = memdb.NewUniversalDaoMemory(db, tableName)
+	return dao
+}
+
+// NewBlogVoteDaoMemory is helper method to create in-memory implementation of BlogVoteDao.
+//
+// Available since template-v0.5.0
+func NewBlogVoteDaoMemory(db *memdb.MemoryDb, tableName string) BlogVoteDao {
+	dao := &BaseBlogVoteDaoImpl{tableName: tableName}
+	dao.UniversalDao = memdb.NewUniversalDaoMemory(db, tableName)
+	return dao
+}
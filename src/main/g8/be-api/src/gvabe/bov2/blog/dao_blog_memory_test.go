@@ -0,0 +1,214 @@
+package blog
+
+import (
+	"testing"
+
+	"main/src/gvabe/bov2/user"
+	"main/src/memdb"
+)
+
+func initBlogCommentDaoMemory(t *testing.T, testName string) BlogCommentDao {
+	db := memdb.NewMemoryDb()
+	if err := db.CreateTable(TableBlogComment); err != nil {
+		t.Fatalf("%s failed: error [%s]", testName, err)
+	}
+	return NewBlogCommentDaoMemory(db, TableBlogComment)
+}
+
+func initBlogPostDaoMemory(t *testing.T, testName string) BlogPostDao {
+	db := memdb.NewMemoryDb()
+	if err := db.CreateTable(TableBlogPost); err != nil {
+		t.Fatalf("%s failed: error [%s]", testName, err)
+	}
+	return NewBlogPostDaoMemory(db, TableBlogPost)
+}
+
+func initBlogVoteDaoMemory(t *testing.T, testName string) BlogVoteDao {
+	db := memdb.NewMemoryDb()
+	if err := db.CreateTable(TableBlogVote); err != nil {
+		t.Fatalf("%s failed: error [%s]", testName, err)
+	}
+	if err := db.CreateUniqueIndex(TableBlogVote, VoteFieldOwnerId, VoteFieldTargetId); err != nil {
+		t.Fatalf("%s failed: error [%s]", testName, err)
+	}
+	return NewBlogVoteDaoMemory(db, TableBlogVote)
+}
+
+/*----------------------------------------------------------------------*/
+
+func TestCommentDaoMemory_CreateGet(t *testing.T) {
+	name := "TestCommentDaoMemory_CreateGet"
+	dao := initBlogCommentDaoMemory(t, name)
+	doTestCommentDaoCreateGet(t, name, dao)
+}
+
+func TestCommentDaoMemory_CreateUpdateGet(t *testing.T) {
+	name := "TestCommentDaoMemory_CreateUpdateGet"
+	dao := initBlogCommentDaoMemory(t, name)
+	doTestCommentDaoCreateUpdateGet(t, name, dao)
+}
+
+func TestCommentDaoMemory_CreateDelete(t *testing.T) {
+	name := "TestCommentDaoMemory_CreateDelete"
+	dao := initBlogCommentDaoMemory(t, name)
+	doTestCommentDaoCreateDelete(t, name, dao)
+}
+
+func TestCommentDaoMemory_GetAll(t *testing.T) {
+	name := "TestCommentDaoMemory_GetAll"
+	dao := initBlogCommentDaoMemory(t, name)
+	doTestCommentDaoGetAll(t, name, dao)
+}
+
+func TestCommentDaoMemory_GetN(t *testing.T) {
+	name := "TestCommentDaoMemory_GetN"
+	dao := initBlogCommentDaoMemory(t, name)
+	doTestCommentDaoGetN(t, name, dao)
+}
+
+/*----------------------------------------------------------------------*/
+
+func TestPostDaoMemory_CreateGet(t *testing.T) {
+	name := "TestPostDaoMemory_CreateGet"
+	dao := initBlogPostDaoMemory(t, name)
+	doTestPostDaoCreateGet(t, name, dao)
+}
+
+func TestPostDaoMemory_CreateUpdateGet(t *testing.T) {
+	name := "TestPostDaoMemory_CreateUpdateGet"
+	dao := initBlogPostDaoMemory(t, name)
+	doTestPostDaoCreateUpdateGet(t, name, dao)
+}
+
+func TestPostDaoMemory_CreateDelete(t *testing.T) {
+	name := "TestPostDaoMemory_CreateDelete"
+	dao := initBlogPostDaoMemory(t, name)
+	doTestPostDaoCreateDelete(t, name, dao)
+}
+
+func TestPostDaoMemory_GetAll(t *testing.T) {
+	name := "TestPostDaoMemory_GetAll"
+	dao := initBlogPostDaoMemory(t, name)
+	doTestPostDaoGetAll(t, name, dao)
+}
+
+func TestPostDaoMemory_GetN(t *testing.T) {
+	name := "TestPostDaoMemory_GetN"
+	dao := initBlogPostDaoMemory(t, name)
+	doTestPostDaoGetN(t, name, dao)
+}
+
+func TestPostDaoMemory_GetUserPostsAll(t *testing.T) {
+	name := "TestPostDaoMemory_GetUserPostsAll"
+	dao := initBlogPostDaoMemory(t, name)
+	doTestPostDaoGetUserPostsAll(t, name, dao)
+}
+
+func TestPostDaoMemory_GetUserPostsN(t *testing.T) {
+	name := "TestPostDaoMemory_GetUserPostsN"
+	dao := initBlogPostDaoMemory(t, name)
+	doTestPostDaoGetUserPostsN(t, name, dao)
+}
+
+func TestPostDaoMemory_GetUserFeedAll(t *testing.T) {
+	name := "TestPostDaoMemory_GetUserFeedAll"
+	dao := initBlogPostDaoMemory(t, name)
+	doTestPostDaoGetUserFeedAll(t, name, dao)
+}
+
+func TestPostDaoMemory_GetUserFeedN(t *testing.T) {
+	name := "TestPostDaoMemory_GetUserFeedN"
+	dao := initBlogPostDaoMemory(t, name)
+	doTestPostDaoGetUserFeedN(t, name, dao)
+}
+
+/*----------------------------------------------------------------------*/
+
+func TestVoteDaoMemory_CreateGet(t *testing.T) {
+	name := "TestVoteDaoMemory_CreateGet"
+	dao := initBlogVoteDaoMemory(t, name)
+	doTestVoteDaoCreateGet(t, name, dao)
+}
+
+func TestVoteDaoMemory_CreateUpdateGet(t *testing.T) {
+	name := "TestVoteDaoMemory_CreateUpdateGet"
+	dao := initBlogVoteDaoMemory(t, name)
+	doTestVoteDaoCreateUpdateGet(t, name, dao)
+}
+
+func TestVoteDaoMemory_CreateDelete(t *testing.T) {
+	name := "TestVoteDaoMemory_CreateDelete"
+	dao := initBlogVoteDaoMemory(t, name)
+	doTestVoteDaoCreateDelete(t, name, dao)
+}
+
+func TestVoteDaoMemory_GetAll(t *testing.T) {
+	name := "TestVoteDaoMemory_GetAll"
+	dao := initBlogVoteDaoMemory(t, name)
+	doTestVoteDaoGetAll(t, name, dao)
+}
+
+func TestVoteDaoMemory_GetN(t *testing.T) {
+	name := "TestVoteDaoMemory_GetN"
+	dao := initBlogVoteDaoMemory(t, name)
+	doTestVoteDaoGetN(t, name, dao)
+}
+
+func TestVoteDaoMemory_GetUserVoteForTarget(t *testing.T) {
+	name := "TestVoteDaoMemory_GetUserVoteForTarget"
+	dao := initBlogVoteDaoMemory(t, name)
+	doTestVoteDaoGetUserVoteForTarget(t, name, dao)
+}
+
+/*----------------------------------------------------------------------*/
+
+func TestBlogDaoMemory_Conformance(t *testing.T) {
+	name := "TestBlogDaoMemory_Conformance"
+	runBlogDaoConformance(t, name, &blogDaoFactory{
+		newCommentDao: initBlogCommentDaoMemory,
+		newPostDao:    initBlogPostDaoMemory,
+		newVoteDao:    initBlogVoteDaoMemory,
+	})
+}
+
+// interleavedPostDao runs beforeUpdate (once) before the next conditional update of a post.
+type interleavedPostDao struct {
+	BlogPostDao
+	beforeUpdate func()
+}
+
+func (dao *interleavedPostDao) UpdateIfChecksum(post *BlogPost, checksum string) (bool, error) {
+	if f := dao.beforeUpdate; f != nil {
+		dao.beforeUpdate = nil
+		f()
+	}
+	return dao.BlogPostDao.UpdateIfChecksum(post, checksum)
+}
+
+func TestBlogPostVoterMemory_RecountInterleaved(t *testing.T) {
+	name := "TestBlogPostVoterMemory_RecountInterleaved"
+	postDao, voteDao := initBlogPostDaoMemory(t, name), initBlogVoteDaoMemory(t, name)
+	post := NewBlogPost(1337, user.NewUser(1337, "owner@local", "owner"), true, "title", "content")
+	if ok, err := postDao.Create(post); err != nil || !ok {
+		t.Fatalf("%s failed: %#v / %s", name+"/Create", ok, err)
+	}
+
+	// a recount runs after the vote has been written, but before the post's counters are updated
+	recounter := NewBlogPostVoter(postDao, voteDao)
+	var recountChanged bool
+	var recountErr error
+	dao := &interleavedPostDao{BlogPostDao: postDao, beforeUpdate: func() {
+		_, recountChanged, recountErr = recounter.RecountVotes(post.GetId())
+	}}
+	voter := NewBlogPostVoter(dao, voteDao)
+	if _, p, err := voter.Vote(NewBlogVote(1337, user.NewUser(1337, "voter@local", "voter"), post.GetId(), 1)); err != nil {
+		t.Fatalf("%s failed: error %s", name+"/Vote", err)
+	} else {
+		_checkVoteCounters(t, name+"/Vote", p, 1, 0)
+	}
+	if recountErr != nil || recountChanged {
+		t.Fatalf("%s failed: expected %#v but received %#v (error %s)", name+"/RecountVotes", false, recountChanged, recountErr)
+	}
+	p, _ := postDao.Get(post.GetId())
+	_checkVoteCounters(t, name+"/Get", p, 1, 0)
+}
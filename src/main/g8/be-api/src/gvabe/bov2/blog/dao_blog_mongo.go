@@ -1,8 +1,11 @@
 package blog
 
 import (
+	"github.com/btnguyen2k/godal"
 	"github.com/btnguyen2k/henge"
 	prommongo "github.com/btnguyen2k/prom/mongo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 // NewBlogCommentDaoMongo is helper method to create MongoDB-implementation of BlogCommentDao.
@@ -18,7 +21,7 @@ func NewBlogCommentDaoMongo(mc *prommongo.MongoConnect, collectionName string, t
 //
 // Available since template-v0.3.0
 func NewBlogPostDaoMongo(mc *prommongo.MongoConnect, collectionName string, txModeOnWrite bool) BlogPostDao {
-	dao := &BaseBlogPostDaoImpl{}
+	dao := &BaseBlogPostDaoImpl{tableName: collectionName}
 	dao.UniversalDao = henge.NewUniversalDaoMongo(mc, collectionName, txModeOnWrite)
 	return dao
 }
@@ -27,7 +30,36 @@ func NewBlogPostDaoMongo(mc *prommongo.MongoConnect, collectionName string, txMo
 //
 // Available since template-v0.3.0
 func NewBlogVoteDaoMongo(mc *prommongo.MongoConnect, collectionName string, txModeOnWrite bool) BlogVoteDao {
-	dao := &BaseBlogVoteDaoImpl{}
+	dao := &BaseBlogVoteDaoImpl{tableName: collectionName}
 	dao.UniversalDao = henge.NewUniversalDaoMongo(mc, collectionName, txModeOnWrite)
 	return dao
 }
+
+// updateIfChecksumMongo replaces the document of ubo with a "replaceOne" command filtered by id and checksum.
+func updateIfChecksumMongo(udao *henge.UniversalDaoMongo, collectionName string, ubo *henge.UniversalBo, checksum string) (bool, error) {
+	doc, err := udao.GetRowMapper().ToRow(collectionName, udao.ToGenericBo(ubo))
+	if err != nil {
+		return false, err
+	}
+	ctx := udao.GetMongoConnect().NewContext()
+	filter := bson.M{henge.MongoColId: ubo.GetId(), henge.FieldChecksum: checksum}
+	result, err := udao.GetMongoCollection(collectionName).ReplaceOne(ctx, filter, doc)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return false, godal.ErrGdaoDuplicatedEntry
+		}
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
+
+// deleteIfChecksumMongo deletes the document of ubo with a "deleteOne" command filtered by id and checksum.
+func deleteIfChecksumMongo(udao *henge.UniversalDaoMongo, collectionName string, ubo *henge.UniversalBo, checksum string) (bool, error) {
+	ctx := udao.GetMongoConnect().NewContext()
+	filter := bson.M{henge.MongoColId: ubo.GetId(), henge.FieldChecksum: checksum}
+	result, err := udao.GetMongoCollection(collectionName).DeleteOne(ctx, filter)
+	if err != nil {
+		return false, err
+	}
+	return result.DeletedCount > 0, nil
+}
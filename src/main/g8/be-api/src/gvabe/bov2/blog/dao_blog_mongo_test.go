@@ -9,6 +9,9 @@ import (
 
 	"github.com/btnguyen2k/henge"
 	prommongo "github.com/btnguyen2k/prom/mongo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 
 	_ "github.com/btnguyen2k/gocosmos"
 	_ "github.com/denisenkom/go-mssqldb"
@@ -65,6 +68,47 @@ const (
 
 /*----------------------------------------------------------------------*/
 
+func TestBlogDaoMongo_Conformance(t *testing.T) {
+	name := "TestBlogDaoMongo_Conformance"
+	db := os.Getenv(envMongoDb)
+	url := os.Getenv(envMongoUrl)
+	mc, err := newMongoConnect(t, name, db, url)
+	if err != nil {
+		t.Fatalf("%s faied: %s", name, err)
+	}
+	defer mc.Close(nil)
+	runBlogDaoConformance(t, name, &blogDaoFactory{
+		newCommentDao: func(t *testing.T, testName string) BlogCommentDao {
+			if err := mongoInitCollection(mc, testMongoCollectionComment); err != nil {
+				t.Fatalf("%s failed: error [%s]", testName+"/mongoInitCollection", err)
+			}
+			return initBlogCommentDaoMongo(mc)
+		},
+		newPostDao: func(t *testing.T, testName string) BlogPostDao {
+			if err := mongoInitCollection(mc, testMongoCollectionPost); err != nil {
+				t.Fatalf("%s failed: error [%s]", testName+"/mongoInitCollection", err)
+			}
+			return initBlogPostDaoMongo(mc)
+		},
+		newVoteDao: func(t *testing.T, testName string) BlogVoteDao {
+			if err := mongoInitCollection(mc, testMongoCollectionVote); err != nil {
+				t.Fatalf("%s failed: error [%s]", testName+"/mongoInitCollection", err)
+			}
+			// votes are unique per (owner, target), same as the application's schema
+			idxName, unique := "uidx_owner_target", true
+			if _, err := mc.CreateCollectionIndexes(testMongoCollectionVote, []interface{}{mongo.IndexModel{
+				Keys:    bson.D{{Key: VoteFieldOwnerId, Value: 1}, {Key: VoteFieldTargetId, Value: 1}},
+				Options: &options.IndexOptions{Name: &idxName, Unique: &unique},
+			}}); err != nil {
+				t.Fatalf("%s failed: error [%s]", testName+"/CreateCollectionIndexes", err)
+			}
+			return initBlogVoteDaoMongo(mc)
+		},
+	})
+}
+
+/*----------------------------------------------------------------------*/
+
 func TestNewCommentDaoMongo(t *testing.T) {
 	name := "TestNewCommentDaoMongo"
 	db := os.Getenv(envMongoDb)
@@ -250,6 +294,46 @@ func TestPostDaoMongo_CreateDelete(t *testing.T) {
 	doTestPostDaoCreateDelete(t, name, dao)
 }
 
+func TestPostDaoMongo_GetAll(t *testing.T) {
+	name := "TestPostDaoMongo_GetAll"
+	db := os.Getenv(envMongoDb)
+	url := os.Getenv(envMongoUrl)
+	mc, err := newMongoConnect(t, name, db, url)
+	if err != nil {
+		t.Fatalf("%s faied: %s", name, err)
+	}
+	defer mc.Close(nil)
+	err = mongoInitCollection(mc, testMongoCollectionPost)
+	if err != nil {
+		t.Fatalf("%s failed: error [%s]", name+"/mongoInitCollection", err)
+	}
+	dao := initBlogPostDaoMongo(mc)
+	if dao == nil {
+		t.Fatalf("%s failed: nil", name+"/initBlogPostDaoMongo")
+	}
+	doTestPostDaoGetAll(t, name, dao)
+}
+
+func TestPostDaoMongo_GetN(t *testing.T) {
+	name := "TestPostDaoMongo_GetN"
+	db := os.Getenv(envMongoDb)
+	url := os.Getenv(envMongoUrl)
+	mc, err := newMongoConnect(t, name, db, url)
+	if err != nil {
+		t.Fatalf("%s faied: %s", name, err)
+	}
+	defer mc.Close(nil)
+	err = mongoInitCollection(mc, testMongoCollectionPost)
+	if err != nil {
+		t.Fatalf("%s failed: error [%s]", name+"/mongoInitCollection", err)
+	}
+	dao := initBlogPostDaoMongo(mc)
+	if dao == nil {
+		t.Fatalf("%s failed: nil", name+"/initBlogPostDaoMongo")
+	}
+	doTestPostDaoGetN(t, name, dao)
+}
+
 func TestPostDaoMongo_GetUserPostsAll(t *testing.T) {
 	name := "TestPostDaoMongo_GetUserPostsAll"
 	db := os.Getenv(envMongoDb)
@@ -1,6 +1,14 @@
 package blog
 
 import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/btnguyen2k/godal"
 	"github.com/btnguyen2k/henge"
 	promsql "github.com/btnguyen2k/prom/sql"
 )
@@ -20,17 +28,25 @@ func NewBlogCommentDaoSql(sqlc *promsql.SqlConnect, tableName string, txModeOnWr
 	return dao
 }
 
+// mapping {table-column:business-object-field} of extra columns of blog post/vote tables
+var (
+	postSqlColsMapping = map[string]string{
+		PostColOwnerId:  PostFieldOwnerId,
+		PostColIsPublic: PostFieldIsPublic,
+	}
+	voteSqlColsMapping = map[string]string{
+		VoteColOwnerId:  VoteFieldOwnerId,
+		VoteColTargetId: VoteFieldTargetId,
+		VoteColValue:    VoteFieldValue,
+	}
+)
+
 // NewBlogPostDaoSql is helper method to create SQL-implementation of BlogPostDao.
 //
 // Available since template-v0.2.0
 func NewBlogPostDaoSql(sqlc *promsql.SqlConnect, tableName string, txModeOnWrite bool) BlogPostDao {
-	dao := &BaseBlogPostDaoImpl{}
-	dao.UniversalDao = henge.NewUniversalDaoSql(
-		sqlc, tableName, txModeOnWrite,
-		map[string]string{
-			PostColOwnerId:  PostFieldOwnerId,
-			PostColIsPublic: PostFieldIsPublic,
-		})
+	dao := &BaseBlogPostDaoImpl{tableName: tableName}
+	dao.UniversalDao = henge.NewUniversalDaoSql(sqlc, tableName, txModeOnWrite, postSqlColsMapping)
 	return dao
 }
 
@@ -38,13 +54,245 @@ func NewBlogPostDaoSql(sqlc *promsql.SqlConnect, tableName string, txModeOnWrite
 //
 // Available since template-v0.2.0
 func NewBlogVoteDaoSql(sqlc *promsql.SqlConnect, tableName string, txModeOnWrite bool) BlogVoteDao {
-	dao := &BaseBlogVoteDaoImpl{}
-	dao.UniversalDao = henge.NewUniversalDaoSql(
-		sqlc, tableName, txModeOnWrite,
-		map[string]string{
-			VoteColOwnerId:  VoteFieldOwnerId,
-			VoteColTargetId: VoteFieldTargetId,
-			VoteColValue:    VoteFieldValue,
-		})
+	dao := &BaseBlogVoteDaoImpl{tableName: tableName}
+	dao.UniversalDao = henge.NewUniversalDaoSql(sqlc, tableName, txModeOnWrite, voteSqlColsMapping)
 	return dao
 }
+
+// filterIdAndChecksum matches the business object with the specified id and checksum.
+func filterIdAndChecksum(id, checksum string) godal.FilterOpt {
+	return (&godal.FilterOptAnd{}).
+		Add(&godal.FilterOptFieldOpValue{FieldName: henge.FieldId, Operator: godal.FilterOpEqual, Value: id}).
+		Add(&godal.FilterOptFieldOpValue{FieldName: henge.FieldChecksum, Operator: godal.FilterOpEqual, Value: checksum})
+}
+
+// updateIfChecksumSql updates the row of ubo with statement "UPDATE ... WHERE id=? AND checksum=?", within
+// transaction tx if not nil.
+func updateIfChecksumSql(ctx context.Context, tx *sql.Tx, udao *henge.UniversalDaoSql, tableName string, ubo *henge.UniversalBo, checksum string) (bool, error) {
+	row, err := udao.GetRowMapper().ToRow(tableName, udao.ToGenericBo(ubo))
+	if err != nil {
+		return false, err
+	}
+	colsAndVals, ok := row.(map[string]interface{})
+	if !ok {
+		return false, fmt.Errorf("cannot map business object [%s] to a row of table [%s]", ubo.GetId(), tableName)
+	}
+	filter, err := udao.BuildFilter(tableName, filterIdAndChecksum(ubo.GetId(), checksum))
+	if err != nil {
+		return false, err
+	}
+	result, err := udao.SqlUpdate(ctx, tx, tableName, colsAndVals, filter)
+	if err != nil {
+		if udao.IsErrorDuplicatedEntry(err) {
+			return false, godal.ErrGdaoDuplicatedEntry
+		}
+		return false, err
+	}
+	numRows, err := result.RowsAffected()
+	return numRows > 0, err
+}
+
+// deleteIfChecksumSql deletes the row of ubo with statement "DELETE ... WHERE id=? AND checksum=?".
+func deleteIfChecksumSql(udao *henge.UniversalDaoSql, tableName string, ubo *henge.UniversalBo, checksum string) (bool, error) {
+	filter, err := udao.BuildFilter(tableName, filterIdAndChecksum(ubo.GetId(), checksum))
+	if err != nil {
+		return false, err
+	}
+	result, err := udao.SqlDelete(nil, nil, tableName, filter)
+	if err != nil {
+		return false, err
+	}
+	numRows, err := result.RowsAffected()
+	return numRows > 0, err
+}
+
+/*----------------------------------------------------------------------*/
+
+// NewBlogPostVoterSql is helper method to create SQL-implementation of BlogPostVoter.
+//
+// Each vote is written within one transaction together with the change of counters of the target post, so that votes
+// and counters never drift. Rows are updated with "UPDATE ... WHERE id=? AND checksum=?" and the transaction is
+// rolled back and attempted again if a concurrent vote changed the rows in the meantime.
+//
+// Available since template-v0.5.0
+func NewBlogPostVoterSql(sqlc *promsql.SqlConnect, postTableName, voteTableName string) BlogPostVoter {
+	return &SqlBlogPostVoter{
+		sqlc:          sqlc,
+		postDao:       henge.NewUniversalDaoSql(sqlc, postTableName, false, postSqlColsMapping).(*henge.UniversalDaoSql),
+		postTableName: postTableName,
+		voteDao:       henge.NewUniversalDaoSql(sqlc, voteTableName, false, voteSqlColsMapping).(*henge.UniversalDaoSql),
+		voteTableName: voteTableName,
+	}
+}
+
+// SqlBlogPostVoter is SQL-implementation of BlogPostVoter.
+//
+// Available since template-v0.5.0
+type SqlBlogPostVoter struct {
+	sqlc          *promsql.SqlConnect
+	postDao       *henge.UniversalDaoSql
+	postTableName string
+	voteDao       *henge.UniversalDaoSql
+	voteTableName string
+}
+
+// errTxConflict signals that a transaction must be rolled back and attempted again.
+var errTxConflict = errors.New("conflicting concurrent write")
+
+// _inTx executes f within a transaction, attempting again (up to writeMaxAttempts times) if f returns errTxConflict.
+func (v *SqlBlogPostVoter) _inTx(f func(ctx context.Context, tx *sql.Tx) error) error {
+	for attempt := 0; attempt < writeMaxAttempts; attempt++ {
+		if attempt > 0 {
+			writeBackoff(attempt)
+		}
+		err := v._doInTx(f)
+		if err != errTxConflict {
+			return err
+		}
+	}
+	return ErrVoteConflict
+}
+
+func (v *SqlBlogPostVoter) _doInTx(f func(ctx context.Context, tx *sql.Tx) error) error {
+	ctx, cancel := v.sqlc.NewContextWithCancel()
+	defer cancel()
+	tx, err := v.sqlc.GetDB().BeginTx(ctx, nil)
+	if err == nil {
+		if err = f(ctx, tx); err != nil {
+			_ = tx.Rollback()
+		} else {
+			err = tx.Commit()
+		}
+	}
+	if isErrorTxRetryable(v.sqlc.GetDbFlavor(), err) {
+		return errTxConflict
+	}
+	return err
+}
+
+// isErrorTxRetryable checks if the error was caused by concurrent transactions (deadlock, serialization failure or
+// locked database) so that the transaction can be attempted again.
+func isErrorTxRetryable(flavor promsql.DbFlavor, err error) bool {
+	if err == nil {
+		return false
+	}
+	errStr := fmt.Sprintf("%e", err)
+	switch flavor {
+	case promsql.FlavorMySql:
+		return regexp.MustCompile(`\W1213\W|\W1205\W`).FindString(errStr) != ""
+	case promsql.FlavorPgSql:
+		return regexp.MustCompile(`\W40001\W|\W40P01\W`).FindString(errStr) != ""
+	case promsql.FlavorMsSql:
+		return regexp.MustCompile(`\W1205\W`).FindString(errStr) != ""
+	case promsql.FlavorOracle:
+		return regexp.MustCompile(`\WORA\-00060\W|\WORA\-08177\W`).FindString(errStr) != ""
+	case promsql.FlavorSqlite:
+		return strings.Contains(errStr, "database is locked") || strings.Contains(errStr, "database table is locked")
+	}
+	return false
+}
+
+// _getPost fetches a blog post within transaction tx.
+func (v *SqlBlogPostVoter) _getPost(ctx context.Context, tx *sql.Tx, postId string) (*BlogPost, error) {
+	filter := &godal.FilterOptFieldOpValue{FieldName: henge.FieldId, Operator: godal.FilterOpEqual, Value: postId}
+	gbo, err := v.postDao.GdaoFetchOneWithTx(ctx, tx, v.postTableName, filter)
+	if err != nil || gbo == nil {
+		return nil, err
+	}
+	return NewBlogPostFromUbo(v.postDao.ToUniversalBo(gbo)), nil
+}
+
+// _updatePost writes post within transaction tx if its stored checksum still equals checksum, errTxConflict is
+// returned otherwise.
+func (v *SqlBlogPostVoter) _updatePost(ctx context.Context, tx *sql.Tx, post *BlogPost, checksum string) error {
+	ok, err := updateIfChecksumSql(ctx, tx, v.postDao, v.postTableName, post.sync().UniversalBo, checksum)
+	if err == nil && !ok {
+		err = errTxConflict
+	}
+	return err
+}
+
+// Vote implements BlogPostVoter.Vote
+func (v *SqlBlogPostVoter) Vote(vote *BlogVote) (*BlogVote, *BlogPost, error) {
+	var newVote *BlogVote
+	var post *BlogPost
+	err := v._inTx(func(ctx context.Context, tx *sql.Tx) error {
+		var err error
+		newVote = nil
+		if post, err = v._getPost(ctx, tx, vote.GetTargetId()); err != nil || post == nil {
+			return err
+		}
+		filter := (&godal.FilterOptAnd{}).
+			Add(&godal.FilterOptFieldOpValue{FieldName: VoteFieldOwnerId, Operator: godal.FilterOpEqual, Value: vote.GetOwnerId()}).
+			Add(&godal.FilterOptFieldOpValue{FieldName: VoteFieldTargetId, Operator: godal.FilterOpEqual, Value: post.GetId()})
+		gboList, err := v.voteDao.GdaoFetchManyWithTx(ctx, tx, v.voteTableName, filter, nil, 0, 1)
+		if err != nil {
+			return err
+		}
+		var existing *BlogVote
+		if len(gboList) > 0 {
+			existing = NewBlogVoteFromUbo(v.voteDao.ToUniversalBo(gboList[0]))
+		}
+		castedVote, deltaUp, deltaDown := castVote(existing, vote)
+		if existing == nil {
+			_, err = v.voteDao.GdaoCreateWithTx(ctx, tx, v.voteTableName, v.voteDao.ToGenericBo(castedVote.sync().UniversalBo))
+		} else {
+			var ok bool
+			ok, err = updateIfChecksumSql(ctx, tx, v.voteDao, v.voteTableName, castedVote.sync().UniversalBo, existing.GetChecksum())
+			if err == nil && !ok {
+				err = errTxConflict
+			}
+		}
+		if err == godal.ErrGdaoDuplicatedEntry {
+			// another vote of the same user was written in the meantime
+			err = errTxConflict
+		}
+		if err != nil {
+			return err
+		}
+		checksum := post.GetChecksum()
+		post.IncNumVotesUp(deltaUp).IncNumVotesDown(deltaDown)
+		if err = v._updatePost(ctx, tx, post, checksum); err == nil {
+			newVote = castedVote
+		}
+		return err
+	})
+	if err != nil || newVote == nil {
+		return nil, nil, err
+	}
+	return newVote, post, nil
+}
+
+// RecountVotes implements BlogPostVoter.RecountVotes
+func (v *SqlBlogPostVoter) RecountVotes(postId string) (*BlogPost, bool, error) {
+	var post *BlogPost
+	var changed bool
+	err := v._inTx(func(ctx context.Context, tx *sql.Tx) error {
+		var err error
+		changed = false
+		if post, err = v._getPost(ctx, tx, postId); err != nil || post == nil {
+			return err
+		}
+		filter := &godal.FilterOptFieldOpValue{FieldName: VoteFieldTargetId, Operator: godal.FilterOpEqual, Value: post.GetId()}
+		gboList, err := v.voteDao.GdaoFetchManyWithTx(ctx, tx, v.voteTableName, filter, nil, 0, 0)
+		if err != nil {
+			return err
+		}
+		votes := make([]*BlogVote, len(gboList))
+		for i, gbo := range gboList {
+			votes[i] = NewBlogVoteFromUbo(v.voteDao.ToUniversalBo(gbo))
+		}
+		numUp, numDown := countVotes(votes)
+		if numUp == post.GetNumVotesUp() && numDown == post.GetNumVotesDown() {
+			return nil
+		}
+		checksum := post.GetChecksum()
+		post.SetNumVotesUp(numUp).SetNumVotesDown(numDown)
+		changed = true
+		return v._updatePost(ctx, tx, post, checksum)
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return post, changed, nil
+}
@@ -76,8 +76,8 @@ func sqlInitTableVote(sqlc *promsql.SqlConnect, table string) error {
 	extraCols := map[string]string{VoteColOwnerId: "VARCHAR(32)", VoteColTargetId: "VARCHAR(32)", VoteColValue: "INT"}
 	switch sqlc.GetDbFlavor() {
 	case promsql.FlavorCosmosDb:
-		spec := &henge.CosmosdbCollectionSpec{Pk: henge.CosmosdbColId}
-		err = henge.InitCosmosdbCollection(sqlc, table, spec)
+		spec := &henge.CosmosdbCollectionSpec{Pk: henge.CosmosdbColId, Uk: [][]string{{"/" + VoteFieldOwnerId, "/" + VoteFieldTargetId}}}
+		return henge.InitCosmosdbCollection(sqlc, table, spec)
 	case promsql.FlavorSqlite:
 		err = henge.InitSqliteTable(sqlc, table, extraCols)
 	case promsql.FlavorMySql:
@@ -85,6 +85,11 @@ func sqlInitTableVote(sqlc *promsql.SqlConnect, table string) error {
 	case promsql.FlavorPgSql:
 		err = henge.InitPgsqlTable(sqlc, table, extraCols)
 	}
+	if err == nil {
+		// votes are unique per (owner, target), same as the application's schema
+		_, err = sqlc.GetDB().Exec(fmt.Sprintf("CREATE UNIQUE INDEX uidx_%s_%s_%s ON %s(%s,%s)",
+			table, VoteColOwnerId, VoteColTargetId, table, VoteColOwnerId, VoteColTargetId))
+	}
 	return err
 }
 
@@ -210,6 +215,50 @@ func initSqlConnect(t *testing.T, testName string, dbtype string, info sqlDriver
 
 /*----------------------------------------------------------------------*/
 
+func TestBlogDaoSql_Conformance(t *testing.T) {
+	name := "TestBlogDaoSql_Conformance"
+	urlMap := sqlGetUrlFromEnv()
+	if len(urlMap) == 0 {
+		t.Skipf("%s skipped", name)
+	}
+	for dbtype, info := range urlMap {
+		t.Run(dbtype, func(t *testing.T) {
+			sqlc, err := initSqlConnect(t, name, dbtype, info)
+			if err != nil {
+				t.Fatalf("%s failed: error [%s]", name+"/"+dbtype, err)
+			} else if sqlc == nil {
+				t.Fatalf("%s failed: nil", name+"/"+dbtype)
+			}
+			defer sqlc.Close()
+			runBlogDaoConformance(t, name+"/"+dbtype, &blogDaoFactory{
+				newCommentDao: func(t *testing.T, testName string) BlogCommentDao {
+					if err := sqlInitTableComment(sqlc, testSqlTableComment); err != nil {
+						t.Fatalf("%s failed: error [%s]", testName+"/sqlInitTableComment", err)
+					}
+					return initBlogCommentDaoSql(sqlc)
+				},
+				newPostDao: func(t *testing.T, testName string) BlogPostDao {
+					if err := sqlInitTablePost(sqlc, testSqlTablePost); err != nil {
+						t.Fatalf("%s failed: error [%s]", testName+"/sqlInitTablePost", err)
+					}
+					return initBlogPostDaoSql(sqlc)
+				},
+				newVoteDao: func(t *testing.T, testName string) BlogVoteDao {
+					if err := sqlInitTableVote(sqlc, testSqlTableVote); err != nil {
+						t.Fatalf("%s failed: error [%s]", testName+"/sqlInitTableVote", err)
+					}
+					return initBlogVoteDaoSql(sqlc)
+				},
+				newVoter: func(BlogPostDao, BlogVoteDao) BlogPostVoter {
+					return NewBlogPostVoterSql(sqlc, testSqlTablePost, testSqlTableVote)
+				},
+			})
+		})
+	}
+}
+
+/*----------------------------------------------------------------------*/
+
 func TestNewCommentDaoSql(t *testing.T) {
 	name := "TestNewCommentDaoSql"
 	urlMap := sqlGetUrlFromEnv()
@@ -490,6 +539,64 @@ func TestPostDaoSql_CreateDelete(t *testing.T) {
 	}
 }
 
+func TestPostDaoSql_GetAll(t *testing.T) {
+	name := "TestPostDaoSql_GetAll"
+	urlMap := sqlGetUrlFromEnv()
+	if len(urlMap) == 0 {
+		t.Skipf("%s skipped", name)
+	}
+	for dbtype, info := range urlMap {
+		t.Run(dbtype, func(t *testing.T) {
+			sqlc, err := initSqlConnect(t, name, dbtype, info)
+			if err != nil {
+				t.Fatalf("%s failed: error [%s]", name+"/"+dbtype, err)
+			} else if sqlc == nil {
+				t.Fatalf("%s failed: nil", name+"/"+dbtype)
+			}
+			defer sqlc.Close()
+			err = sqlInitTablePost(sqlc, testSqlTablePost)
+			if err != nil {
+				t.Fatalf("%s failed: error [%s]", name+"/"+dbtype+"/sqlInitTablePost/"+dbtype, err)
+			}
+			dao := initBlogPostDaoSql(sqlc)
+			if dao == nil {
+				t.Fatalf("%s failed: nil", name+"/"+dbtype)
+			}
+			doTestPostDaoGetAll(t, name+"/"+dbtype, dao)
+
+		})
+	}
+}
+
+func TestPostDaoSql_GetN(t *testing.T) {
+	name := "TestPostDaoSql_GetN"
+	urlMap := sqlGetUrlFromEnv()
+	if len(urlMap) == 0 {
+		t.Skipf("%s skipped", name)
+	}
+	for dbtype, info := range urlMap {
+		t.Run(dbtype, func(t *testing.T) {
+			sqlc, err := initSqlConnect(t, name, dbtype, info)
+			if err != nil {
+				t.Fatalf("%s failed: error [%s]", name+"/"+dbtype, err)
+			} else if sqlc == nil {
+				t.Fatalf("%s failed: nil", name+"/"+dbtype)
+			}
+			defer sqlc.Close()
+			err = sqlInitTablePost(sqlc, testSqlTablePost)
+			if err != nil {
+				t.Fatalf("%s failed: error [%s]", name+"/"+dbtype+"/sqlInitTablePost/"+dbtype, err)
+			}
+			dao := initBlogPostDaoSql(sqlc)
+			if dao == nil {
+				t.Fatalf("%s failed: nil", name+"/"+dbtype)
+			}
+			doTestPostDaoGetN(t, name+"/"+dbtype, dao)
+
+		})
+	}
+}
+
 func TestPostDaoSql_GetUserPostsAll(t *testing.T) {
 	name := "TestPostDaoSql_GetUserPostsAll"
 	urlMap := sqlGetUrlFromEnv()
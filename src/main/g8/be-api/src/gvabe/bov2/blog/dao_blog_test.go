@@ -464,6 +464,22 @@ func doTestPostDaoCreateDelete(t *testing.T, name string, dao BlogPostDao) {
 	}
 }
 
+func doTestPostDaoGetAll(t *testing.T, name string, dao BlogPostDao) {
+	initSampleRowsPost(t, name, dao)
+	postList, err := dao.GetAll(nil, nil)
+	if err != nil || len(postList) != numSampleRows {
+		t.Fatalf("%s failed: expected %#v but received %#v (error %s)", name+"/GetAll", numSampleRows, len(postList), err)
+	}
+}
+
+func doTestPostDaoGetN(t *testing.T, name string, dao BlogPostDao) {
+	initSampleRowsPost(t, name, dao)
+	postList, err := dao.GetN(3, 5, nil, nil)
+	if err != nil || len(postList) != 5 {
+		t.Fatalf("%s failed: expected %#v but received %#v (error %s)", name+"/GetN", 5, len(postList), err)
+	}
+}
+
 func doTestPostDaoGetUserPostsAll(t *testing.T, name string, dao BlogPostDao) {
 	initSampleRowsPost(t, name, dao)
 	for _, u := range userList {
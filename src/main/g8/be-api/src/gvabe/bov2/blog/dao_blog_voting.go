@@ -0,0 +1,206 @@
+package blog
+
+import (
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/btnguyen2k/godal"
+	"main/src/gvabe/bov2/user"
+)
+
+// BlogPostVoter records users' votes for blog posts and keeps posts' vote counters consistent with the votes,
+// also under concurrent votes.
+//
+// Available since template-v0.5.0
+type BlogPostVoter interface {
+	// Vote records a user's vote (owner, target post and value of vote) and updates counters of the target post.
+	//   - vote's value > 0 is an up-vote, < 0 a down-vote; voting again with the same value cancels the user's vote (value 0).
+	//   - the vote as stored and the post with updated counters are returned; both are nil if the post does not exist.
+	//   - ErrVoteConflict is returned if the vote could not be recorded due to too many concurrent writes.
+	Vote(vote *BlogVote) (*BlogVote, *BlogPost, error)
+
+	// RecountVotes recomputes vote counters of a blog post from the stored votes, and updates the post if its counters
+	// drifted. The post (nil if not found) and whether its counters were corrected are returned.
+	//   - implementations that write the vote and the post separately do not recount posts with votes cast within
+	//     RecountGracePeriod, as these votes may not have been added to the post's counters yet.
+	RecountVotes(postId string) (*BlogPost, bool, error)
+}
+
+// ErrVoteConflict is returned by BlogPostVoter if a vote or recount could not be done due to too many concurrent writes.
+//
+// Available since template-v0.5.0
+var ErrVoteConflict = errors.New("too many concurrent writes, please retry")
+
+// RecountGracePeriod is how long after a vote is cast BaseBlogPostVoter.RecountVotes leaves the vote's post alone: the
+// vote is written before the post's counters, hence the counters may legitimately lag behind a recent vote.
+//
+// Available since template-v0.5.0
+const RecountGracePeriod = time.Minute
+
+// writeMaxAttempts is the maximum number of times a conflicting conditional write (vote, recount, update of a post...)
+// is attempted.
+const writeMaxAttempts = 10
+
+// writeBackoff sleeps a random short time before attempting again a conflicting write.
+func writeBackoff(attempt int) {
+	time.Sleep(time.Duration(1+rand.Intn(5*(attempt+1))) * time.Millisecond)
+}
+
+func _boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// castVote returns the vote to store when vote is cast while the user's current vote is existing (nil if none), and
+// the changes to apply to the target post's up/down counters.
+func castVote(existing, vote *BlogVote) (*BlogVote, int, int) {
+	value := _boolToInt(vote.GetValue() > 0) - _boolToInt(vote.GetValue() < 0)
+	oldValue := 0
+	result := NewBlogVoteFromUbo(vote.UniversalBo)
+	if existing != nil {
+		oldValue = existing.GetValue()
+		result = NewBlogVoteFromUbo(existing.UniversalBo)
+		result.SetTimeUpdated(time.Now())
+	}
+	if value == oldValue {
+		// same vote again: cancel it
+		value = 0
+	}
+	result.SetValue(value)
+	return result, _boolToInt(value > 0) - _boolToInt(oldValue > 0), _boolToInt(value < 0) - _boolToInt(oldValue < 0)
+}
+
+// _hasVoteSince checks if any of the votes has been cast (created or changed) after t.
+func _hasVoteSince(votes []*BlogVote, t time.Time) bool {
+	for _, vote := range votes {
+		if vote.GetTimeUpdated().After(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// countVotes counts up and down votes.
+func countVotes(votes []*BlogVote) (int, int) {
+	numUp, numDown := 0, 0
+	for _, vote := range votes {
+		numUp += _boolToInt(vote.GetValue() > 0)
+		numDown += _boolToInt(vote.GetValue() < 0)
+	}
+	return numUp, numDown
+}
+
+/*----------------------------------------------------------------------*/
+
+// NewBlogPostVoter is helper method to create a BlogPostVoter on top of BlogPostDao and BlogVoteDao, for backends
+// without multi-document transactions.
+//
+// Writes are made race-free by conditional updates (see BlogPostDao.UpdateIfChecksum): the vote is written first,
+// creating a vote relies on the uniqueness of (owner, target) and updating a vote is conditioned on its checksum;
+// then the post's counters are changed by a conditional update of the post, retried on conflict. If the application
+// stops in between, counters drift from the votes until RecountVotes is called for the post.
+//
+// Available since template-v0.5.0
+func NewBlogPostVoter(postDao BlogPostDao, voteDao BlogVoteDao) BlogPostVoter {
+	return &BaseBlogPostVoter{postDao: postDao, voteDao: voteDao}
+}
+
+// BaseBlogPostVoter is a generic implementation of BlogPostVoter.
+//
+// Available since template-v0.5.0
+type BaseBlogPostVoter struct {
+	postDao BlogPostDao
+	voteDao BlogVoteDao
+}
+
+// Vote implements BlogPostVoter.Vote
+func (v *BaseBlogPostVoter) Vote(vote *BlogVote) (*BlogVote, *BlogPost, error) {
+	for attempt := 0; attempt < writeMaxAttempts; attempt++ {
+		if attempt > 0 {
+			writeBackoff(attempt)
+		}
+		post, err := v.postDao.Get(vote.GetTargetId())
+		if err != nil || post == nil {
+			return nil, nil, err
+		}
+		// only id of the user is needed to look up the user's vote
+		existing, err := v.voteDao.GetUserVoteForTarget(user.NewUser(0, vote.GetOwnerId(), ""), post.GetId())
+		if err != nil {
+			return nil, nil, err
+		}
+		newVote, deltaUp, deltaDown := castVote(existing, vote)
+		var ok bool
+		if existing == nil {
+			ok, err = v.voteDao.Create(newVote)
+		} else {
+			ok, err = v.voteDao.UpdateIfChecksum(newVote, existing.GetChecksum())
+		}
+		if err == godal.ErrGdaoDuplicatedEntry || (err == nil && !ok) {
+			// another vote of the same user was written in the meantime
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		post, err = v._incCounters(post, deltaUp, deltaDown)
+		return newVote, post, err
+	}
+	return nil, nil, ErrVoteConflict
+}
+
+// _incCounters changes counters of a blog post with conditional updates, reloading the post on conflict.
+func (v *BaseBlogPostVoter) _incCounters(post *BlogPost, deltaUp, deltaDown int) (*BlogPost, error) {
+	for attempt := 0; attempt < writeMaxAttempts; attempt++ {
+		if attempt > 0 {
+			writeBackoff(attempt)
+			var err error
+			if post, err = v.postDao.Get(post.GetId()); err != nil || post == nil {
+				return nil, err
+			}
+		}
+		checksum := post.GetChecksum()
+		post.IncNumVotesUp(deltaUp).IncNumVotesDown(deltaDown)
+		if ok, err := v.postDao.UpdateIfChecksum(post, checksum); err != nil || ok {
+			return post, err
+		}
+	}
+	return nil, ErrVoteConflict
+}
+
+// RecountVotes implements BlogPostVoter.RecountVotes
+//
+// Posts with votes cast within RecountGracePeriod are not recounted: such a vote may have been written but not yet added
+// to the post's counters, which the recount would then count once more. Drifted counters of these posts are corrected
+// by a later recount.
+func (v *BaseBlogPostVoter) RecountVotes(postId string) (*BlogPost, bool, error) {
+	for attempt := 0; attempt < writeMaxAttempts; attempt++ {
+		if attempt > 0 {
+			writeBackoff(attempt)
+		}
+		post, err := v.postDao.Get(postId)
+		if err != nil || post == nil {
+			return nil, false, err
+		}
+		filter := &godal.FilterOptFieldOpValue{FieldName: VoteFieldTargetId, Operator: godal.FilterOpEqual, Value: post.GetId()}
+		votes, err := v.voteDao.GetAll(filter, nil)
+		if err != nil {
+			return nil, false, err
+		}
+		if _hasVoteSince(votes, time.Now().Add(-RecountGracePeriod)) {
+			return post, false, nil
+		}
+		numUp, numDown := countVotes(votes)
+		if numUp == post.GetNumVotesUp() && numDown == post.GetNumVotesDown() {
+			return post, false, nil
+		}
+		checksum := post.GetChecksum()
+		post.SetNumVotesUp(numUp).SetNumVotesDown(numDown)
+		if ok, err := v.postDao.UpdateIfChecksum(post, checksum); err != nil || ok {
+			return post, ok, err
+		}
+	}
+	return nil, false, ErrVoteConflict
+}
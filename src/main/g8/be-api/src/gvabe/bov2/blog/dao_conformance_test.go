@@ -0,0 +1,579 @@
+package blog
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/btnguyen2k/godal"
+	"github.com/btnguyen2k/henge"
+	"main/src/gvabe/bov2/user"
+	"main/src/utils"
+)
+
+// blogDaoFactory creates DAOs for the conformance suite (see runBlogDaoConformance).
+//
+// Each call must return a DAO backed by fresh, empty storage that is set up the way the application sets it up,
+// e.g. votes must be unique per (owner, target).
+type blogDaoFactory struct {
+	newCommentDao func(t *testing.T, testName string) BlogCommentDao
+	newPostDao    func(t *testing.T, testName string) BlogPostDao
+	newVoteDao    func(t *testing.T, testName string) BlogVoteDao
+	newVoter      func(postDao BlogPostDao, voteDao BlogVoteDao) BlogPostVoter // optional, NewBlogPostVoter is used if nil
+}
+
+func (factory *blogDaoFactory) voter(postDao BlogPostDao, voteDao BlogVoteDao) BlogPostVoter {
+	if factory.newVoter != nil {
+		return factory.newVoter(postDao, voteDao)
+	}
+	return NewBlogPostVoter(postDao, voteDao)
+}
+
+// runBlogDaoConformance runs the conformance suite against DAOs created by factory. Every implementation of
+// BlogCommentDao, BlogPostDao and BlogVoteDao must pass it, so that they can be used interchangeably.
+func runBlogDaoConformance(t *testing.T, name string, factory *blogDaoFactory) {
+	testCases := []struct {
+		name string
+		test func(t *testing.T, name string, factory *blogDaoFactory)
+	}{
+		{"CommentNotFound", doConformanceCommentNotFound},
+		{"CommentDuplicatedId", doConformanceCommentDuplicatedId},
+		{"PostNotFound", doConformancePostNotFound},
+		{"PostDuplicatedId", doConformancePostDuplicatedId},
+		{"PostOrdering", doConformancePostOrdering},
+		{"PostPaging", doConformancePostPaging},
+		{"PostUpdateIfChecksum", doConformancePostUpdateIfChecksum},
+		{"PostDeleteIfChecksum", doConformancePostDeleteIfChecksum},
+		{"PostUpdateIfVersion", doConformancePostUpdateIfVersion},
+		{"PostDeleteIfVersion", doConformancePostDeleteIfVersion},
+		{"VoteNotFound", doConformanceVoteNotFound},
+		{"VoteUniqueness", doConformanceVoteUniqueness},
+		{"VoteUpdateIfChecksum", doConformanceVoteUpdateIfChecksum},
+		{"Voting", doConformanceVoting},
+		{"VotingConcurrent", doConformanceVotingConcurrent},
+		{"VotingRecount", doConformanceVotingRecount},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.test(t, name+"/"+tc.name, factory)
+		})
+	}
+}
+
+var conformanceUser = user.NewUser(1337, "conformance@local", "conformance")
+
+// _checkNotFound asserts the not-found behaviour shared by all DAOs: Get returns nil without error,
+// Delete and Update return false without error, and Update does not create the missing record.
+func _checkNotFound(t *testing.T, name string, get func() (bool, error), del, update func() (bool, error)) {
+	if found, err := get(); err != nil || found {
+		t.Fatalf("%s failed: expected not found but received %#v (error %s)", name+"/Get", found, err)
+	}
+	if ok, err := del(); err != nil || ok {
+		t.Fatalf("%s failed: expected %#v but received %#v (error %s)", name+"/Delete", false, ok, err)
+	}
+	if ok, err := update(); err != nil || ok {
+		t.Fatalf("%s failed: expected %#v but received %#v (error %s)", name+"/Update", false, ok, err)
+	}
+	if found, err := get(); err != nil || found {
+		t.Fatalf("%s failed: Update must not create missing record (error %s)", name+"/Get", err)
+	}
+}
+
+func _checkDuplicated(t *testing.T, name string, ok bool, err error) {
+	if ok || err != godal.ErrGdaoDuplicatedEntry {
+		t.Fatalf("%s failed: expected %#v but received %#v/%#v", name, godal.ErrGdaoDuplicatedEntry, ok, err)
+	}
+}
+
+/*----------------------------------------------------------------------*/
+
+func _newConformanceComment() *BlogComment {
+	post := NewBlogPost(1337, conformanceUser, true, "title", "content")
+	post.SetId(utils.UniqueId())
+	return NewBlogComment(1337, conformanceUser, post, nil, "comment")
+}
+
+func doConformanceCommentNotFound(t *testing.T, name string, factory *blogDaoFactory) {
+	dao := factory.newCommentDao(t, name)
+	comment := _newConformanceComment()
+	comment.SetId(utils.UniqueId())
+	_checkNotFound(t, name,
+		func() (bool, error) { bo, err := dao.Get(comment.GetId()); return bo != nil, err },
+		func() (bool, error) { return dao.Delete(comment) },
+		func() (bool, error) { return dao.Update(comment) })
+}
+
+func doConformanceCommentDuplicatedId(t *testing.T, name string, factory *blogDaoFactory) {
+	dao := factory.newCommentDao(t, name)
+	comment := _newConformanceComment()
+	if ok, err := dao.Create(comment); err != nil || !ok {
+		t.Fatalf("%s failed: %#v / %s", name+"/Create", ok, err)
+	}
+	another := _newConformanceComment()
+	another.SetId(comment.GetId())
+	ok, err := dao.Create(another)
+	_checkDuplicated(t, name+"/Create", ok, err)
+}
+
+/*----------------------------------------------------------------------*/
+
+func doConformancePostNotFound(t *testing.T, name string, factory *blogDaoFactory) {
+	dao := factory.newPostDao(t, name)
+	post := NewBlogPost(1337, conformanceUser, true, "title", "content")
+	_checkNotFound(t, name,
+		func() (bool, error) { bo, err := dao.Get(post.GetId()); return bo != nil, err },
+		func() (bool, error) { return dao.Delete(post) },
+		func() (bool, error) { return dao.Update(post) })
+}
+
+func doConformancePostDuplicatedId(t *testing.T, name string, factory *blogDaoFactory) {
+	dao := factory.newPostDao(t, name)
+	post := NewBlogPost(1337, conformanceUser, true, "title", "content")
+	if ok, err := dao.Create(post); err != nil || !ok {
+		t.Fatalf("%s failed: %#v / %s", name+"/Create", ok, err)
+	}
+	another := NewBlogPost(1337, conformanceUser, false, "another title", "another content")
+	another.SetId(post.GetId())
+	ok, err := dao.Create(another)
+	_checkDuplicated(t, name+"/Create", ok, err)
+}
+
+func doConformancePostUpdateIfChecksum(t *testing.T, name string, factory *blogDaoFactory) {
+	dao := factory.newPostDao(t, name)
+	post := NewBlogPost(1337, conformanceUser, true, "title", "content")
+	if ok, err := dao.UpdateIfChecksum(post, post.GetChecksum()); err != nil || ok {
+		t.Fatalf("%s failed: expected %#v but received %#v (error %s)", name+"/UpdateIfChecksum(not found)", false, ok, err)
+	}
+	if ok, err := dao.Create(post); err != nil || !ok {
+		t.Fatalf("%s failed: %#v / %s", name+"/Create", ok, err)
+	}
+	p1, _ := dao.Get(post.GetId())
+	p2, _ := dao.Get(post.GetId())
+	if p1 == nil || p2 == nil || p1.GetChecksum() != post.GetChecksum() {
+		t.Fatalf("%s failed: expected checksum %#v but received %#v", name+"/Get", post.GetChecksum(), p1)
+	}
+
+	// only the first of two concurrent modifications of the same version succeeds
+	checksum := p1.GetChecksum()
+	if ok, err := dao.UpdateIfChecksum(p1.SetTitle("title 1"), checksum); err != nil || !ok {
+		t.Fatalf("%s failed: expected %#v but received %#v (error %s)", name+"/UpdateIfChecksum", true, ok, err)
+	}
+	if ok, err := dao.UpdateIfChecksum(p2.SetTitle("title 2"), checksum); err != nil || ok {
+		t.Fatalf("%s failed: expected %#v but received %#v (error %s)", name+"/UpdateIfChecksum(stale)", false, ok, err)
+	}
+	p, err := dao.Get(post.GetId())
+	if err != nil || p == nil || p.GetTitle() != "title 1" || p.GetChecksum() != p1.GetChecksum() {
+		t.Fatalf("%s failed: expected %#v but received %#v (error %s)", name+"/Get", p1, p, err)
+	}
+	if ok, err := dao.UpdateIfChecksum(p.SetTitle("title 3"), p.GetChecksum()); err != nil || !ok {
+		t.Fatalf("%s failed: expected %#v but received %#v (error %s)", name+"/UpdateIfChecksum", true, ok, err)
+	}
+}
+
+// _conformanceVote changes counters of the stored post the way a vote does, without changing its content.
+func _conformanceVote(t *testing.T, name string, dao BlogPostDao, id string) {
+	p, err := dao.Get(id)
+	if err != nil || p == nil {
+		t.Fatalf("%s failed: nil (error %s)", name+"/Get", err)
+	}
+	checksum := p.GetChecksum()
+	if ok, err := dao.UpdateIfChecksum(p.SetNumVotesUp(p.GetNumVotesUp()+1), checksum); err != nil || !ok {
+		t.Fatalf("%s failed: expected %#v but received %#v (error %s)", name+"/UpdateIfChecksum", true, ok, err)
+	}
+}
+
+func doConformancePostUpdateIfVersion(t *testing.T, name string, factory *blogDaoFactory) {
+	dao := factory.newPostDao(t, name)
+	post := NewBlogPost(1337, conformanceUser, true, "title", "content")
+	if ok, err := dao.UpdateIfVersion(post, post.GetVersion()); err != nil || ok {
+		t.Fatalf("%s failed: expected %#v but received %#v (error %s)", name+"/UpdateIfVersion(not found)", false, ok, err)
+	}
+	if ok, err := dao.Create(post); err != nil || !ok {
+		t.Fatalf("%s failed: %#v / %s", name+"/Create", ok, err)
+	}
+	p1, _ := dao.Get(post.GetId())
+	p2, _ := dao.Get(post.GetId())
+	if p1 == nil || p2 == nil || p1.GetVersion() != post.GetVersion() {
+		t.Fatalf("%s failed: expected version %#v but received %#v", name+"/Get", post.GetVersion(), p1)
+	}
+
+	// a vote between load and save changes counters only and is not a conflict
+	version := p1.GetVersion()
+	_conformanceVote(t, name, dao, post.GetId())
+	if ok, err := dao.UpdateIfVersion(p1.SetTitle("title 1"), version); err != nil || !ok {
+		t.Fatalf("%s failed: expected %#v but received %#v (error %s)", name+"/UpdateIfVersion", true, ok, err)
+	}
+	if p1.GetNumVotesUp() != 1 {
+		t.Fatalf("%s failed: expected %#v but received %#v", name+"/UpdateIfVersion", 1, p1.GetNumVotesUp())
+	}
+
+	// only the first of two concurrent modifications of the same version succeeds
+	if ok, err := dao.UpdateIfVersion(p2.SetTitle("title 2"), version); err != nil || ok {
+		t.Fatalf("%s failed: expected %#v but received %#v (error %s)", name+"/UpdateIfVersion(stale)", false, ok, err)
+	}
+	p, err := dao.Get(post.GetId())
+	if err != nil || p == nil || p.GetTitle() != "title 1" || p.GetNumVotesUp() != 1 || p.GetVersion() != p1.GetVersion() {
+		t.Fatalf("%s failed: expected %#v but received %#v (error %s)", name+"/Get", p1, p, err)
+	}
+}
+
+func doConformancePostDeleteIfVersion(t *testing.T, name string, factory *blogDaoFactory) {
+	dao := factory.newPostDao(t, name)
+	post := NewBlogPost(1337, conformanceUser, true, "title", "content")
+	if ok, err := dao.DeleteIfVersion(post, post.GetVersion()); err != nil || ok {
+		t.Fatalf("%s failed: expected %#v but received %#v (error %s)", name+"/DeleteIfVersion(not found)", false, ok, err)
+	}
+	if ok, err := dao.Create(post); err != nil || !ok {
+		t.Fatalf("%s failed: %#v / %s", name+"/Create", ok, err)
+	}
+
+	// deleting a version whose content has been modified in the meantime fails
+	version := post.GetVersion()
+	p, _ := dao.Get(post.GetId())
+	if p == nil {
+		t.Fatalf("%s failed: nil", name+"/Get")
+	}
+	if ok, err := dao.UpdateIfVersion(p.SetTitle("title 1"), version); err != nil || !ok {
+		t.Fatalf("%s failed: expected %#v but received %#v (error %s)", name+"/UpdateIfVersion", true, ok, err)
+	}
+	if ok, err := dao.DeleteIfVersion(post, version); err != nil || ok {
+		t.Fatalf("%s failed: expected %#v but received %#v (error %s)", name+"/DeleteIfVersion(stale)", false, ok, err)
+	}
+
+	// a vote in between is not a conflict
+	_conformanceVote(t, name, dao, post.GetId())
+	if ok, err := dao.DeleteIfVersion(post, p.GetVersion()); err != nil || !ok {
+		t.Fatalf("%s failed: expected %#v but received %#v (error %s)", name+"/DeleteIfVersion", true, ok, err)
+	}
+	if p, err := dao.Get(post.GetId()); err != nil || p != nil {
+		t.Fatalf("%s failed: expected nil but received %#v (error %s)", name+"/Get", p, err)
+	}
+}
+
+func doConformancePostDeleteIfChecksum(t *testing.T, name string, factory *blogDaoFactory) {
+	dao := factory.newPostDao(t, name)
+	post := NewBlogPost(1337, conformanceUser, true, "title", "content")
+	if ok, err := dao.DeleteIfChecksum(post, post.GetChecksum()); err != nil || ok {
+		t.Fatalf("%s failed: expected %#v but received %#v (error %s)", name+"/DeleteIfChecksum(not found)", false, ok, err)
+	}
+	if ok, err := dao.Create(post); err != nil || !ok {
+		t.Fatalf("%s failed: %#v / %s", name+"/Create", ok, err)
+	}
+	p, _ := dao.Get(post.GetId())
+	if p == nil {
+		t.Fatalf("%s failed: nil", name+"/Get")
+	}
+
+	// deleting a version that has been modified in the meantime fails
+	checksum := p.GetChecksum()
+	if ok, err := dao.UpdateIfChecksum(p.SetTitle("title 1"), checksum); err != nil || !ok {
+		t.Fatalf("%s failed: expected %#v but received %#v (error %s)", name+"/UpdateIfChecksum", true, ok, err)
+	}
+	if ok, err := dao.DeleteIfChecksum(post, checksum); err != nil || ok {
+		t.Fatalf("%s failed: expected %#v but received %#v (error %s)", name+"/DeleteIfChecksum(stale)", false, ok, err)
+	}
+	if p, err := dao.Get(post.GetId()); err != nil || p == nil {
+		t.Fatalf("%s failed: post must not be deleted (error %s)", name+"/Get", err)
+	}
+	if ok, err := dao.DeleteIfChecksum(post, p.GetChecksum()); err != nil || !ok {
+		t.Fatalf("%s failed: expected %#v but received %#v (error %s)", name+"/DeleteIfChecksum", true, ok, err)
+	}
+	if p, err := dao.Get(post.GetId()); err != nil || p != nil {
+		t.Fatalf("%s failed: expected nil but received %#v (error %s)", name+"/Get", p, err)
+	}
+}
+
+const (
+	numConformanceUsers = 3
+	numConformancePosts = 13
+)
+
+// _initConformancePosts creates posts of several users with distinct creation times, some of them are public.
+// It returns the users and the expected results of GetUserPostsAll and GetUserFeedAll (post ids, newest first) per user.
+func _initConformancePosts(t *testing.T, name string, dao BlogPostDao) ([]*user.User, map[string][]string, map[string][]string) {
+	users := make([]*user.User, numConformanceUsers)
+	for i := range users {
+		users[i] = user.NewUser(1337, fmt.Sprintf("user%d@local", i), fmt.Sprintf("user%d", i))
+	}
+	posts := make([]*BlogPost, 0, numConformancePosts)
+	now := time.Now().Truncate(time.Second)
+	for i := 0; i < numConformancePosts; i++ {
+		// creation order differs from id order, so that sorting by id is not mistaken for sorting by creation time
+		post := NewBlogPost(1337, users[i%len(users)], i%3 == 0, fmt.Sprintf("title %d", i), "content")
+		post.SetId(fmt.Sprintf("%03d", (i*7)%numConformancePosts))
+		post.SetExtraAttr(henge.FieldTimeCreated, now.Add(time.Duration(i)*time.Second))
+		if ok, err := dao.Create(post); err != nil || !ok {
+			t.Fatalf("%s failed: %#v / %s", name+"/Create", ok, err)
+		}
+		posts = append(posts, post)
+	}
+	sort.Slice(posts, func(i, j int) bool {
+		return posts[i].GetExtraAttr(henge.FieldTimeCreated).(time.Time).After(posts[j].GetExtraAttr(henge.FieldTimeCreated).(time.Time))
+	})
+	userPosts, userFeed := make(map[string][]string), make(map[string][]string)
+	for _, u := range users {
+		userPosts[u.GetId()], userFeed[u.GetId()] = make([]string, 0), make([]string, 0)
+		for _, post := range posts {
+			if post.GetOwnerId() == u.GetId() {
+				userPosts[u.GetId()] = append(userPosts[u.GetId()], post.GetId())
+			}
+			if post.GetOwnerId() == u.GetId() || post.IsPublic() {
+				userFeed[u.GetId()] = append(userFeed[u.GetId()], post.GetId())
+			}
+		}
+	}
+	return users, userPosts, userFeed
+}
+
+func _postIds(posts []*BlogPost) []string {
+	result := make([]string, len(posts))
+	for i, post := range posts {
+		result[i] = post.GetId()
+	}
+	return result
+}
+
+func _checkPostIds(t *testing.T, name string, expected []string, posts []*BlogPost, err error) {
+	if err != nil {
+		t.Fatalf("%s failed: %s", name, err)
+	}
+	if received := _postIds(posts); fmt.Sprintf("%v", received) != fmt.Sprintf("%v", expected) {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, expected, received)
+	}
+}
+
+func doConformancePostOrdering(t *testing.T, name string, factory *blogDaoFactory) {
+	dao := factory.newPostDao(t, name)
+	users, userPosts, userFeed := _initConformancePosts(t, name, dao)
+	for _, u := range users {
+		posts, err := dao.GetUserPostsAll(u)
+		_checkPostIds(t, name+"/GetUserPostsAll/"+u.GetId(), userPosts[u.GetId()], posts, err)
+		posts, err = dao.GetUserFeedAll(u)
+		_checkPostIds(t, name+"/GetUserFeedAll/"+u.GetId(), userFeed[u.GetId()], posts, err)
+	}
+	unknown := user.NewUser(1337, "unknown@local", "unknown")
+	posts, err := dao.GetUserPostsAll(unknown)
+	_checkPostIds(t, name+"/GetUserPostsAll/"+unknown.GetId(), []string{}, posts, err)
+}
+
+func _page(ids []string, fromOffset, maxNumRows int) []string {
+	if fromOffset > len(ids) {
+		fromOffset = len(ids)
+	}
+	ids = ids[fromOffset:]
+	if maxNumRows < len(ids) {
+		ids = ids[:maxNumRows]
+	}
+	return ids
+}
+
+func doConformancePostPaging(t *testing.T, name string, factory *blogDaoFactory) {
+	dao := factory.newPostDao(t, name)
+	users, userPosts, userFeed := _initConformancePosts(t, name, dao)
+	const pageSize = 2
+	for _, u := range users {
+		// pages must be taken from the sorted result, and together they must cover the whole result
+		for offset := 0; offset <= len(userFeed[u.GetId()])+pageSize; offset += pageSize {
+			posts, err := dao.GetUserPostsN(u, offset, pageSize)
+			_checkPostIds(t, fmt.Sprintf("%s/GetUserPostsN/%s/%d", name, u.GetId(), offset), _page(userPosts[u.GetId()], offset, pageSize), posts, err)
+			posts, err = dao.GetUserFeedN(u, offset, pageSize)
+			_checkPostIds(t, fmt.Sprintf("%s/GetUserFeedN/%s/%d", name, u.GetId(), offset), _page(userFeed[u.GetId()], offset, pageSize), posts, err)
+		}
+	}
+}
+
+/*----------------------------------------------------------------------*/
+
+func doConformanceVoteNotFound(t *testing.T, name string, factory *blogDaoFactory) {
+	dao := factory.newVoteDao(t, name)
+	vote := NewBlogVote(1337, conformanceUser, utils.UniqueId(), 1)
+	vote.SetId(utils.UniqueId())
+	_checkNotFound(t, name,
+		func() (bool, error) { bo, err := dao.Get(vote.GetId()); return bo != nil, err },
+		func() (bool, error) { return dao.Delete(vote) },
+		func() (bool, error) { return dao.Update(vote) })
+	if bo, err := dao.GetUserVoteForTarget(conformanceUser, vote.GetTargetId()); err != nil || bo != nil {
+		t.Fatalf("%s failed: expected nil but received %#v (error %s)", name+"/GetUserVoteForTarget", bo, err)
+	}
+}
+
+func doConformanceVoteUniqueness(t *testing.T, name string, factory *blogDaoFactory) {
+	dao := factory.newVoteDao(t, name)
+	targetId := utils.UniqueId()
+	vote := NewBlogVote(1337, conformanceUser, targetId, 1)
+	vote.SetId(utils.UniqueId())
+	if ok, err := dao.Create(vote); err != nil || !ok {
+		t.Fatalf("%s failed: %#v / %s", name+"/Create", ok, err)
+	}
+
+	// a second vote of the same user for the same target must be rejected, even with a different id
+	another := NewBlogVote(1337, conformanceUser, targetId, -1)
+	another.SetId(utils.UniqueId())
+	ok, err := dao.Create(another)
+	_checkDuplicated(t, name+"/Create(same owner and target)", ok, err)
+	if bo, err := dao.Get(another.GetId()); err != nil || bo != nil {
+		t.Fatalf("%s failed: rejected vote must not be stored (error %s)", name+"/Get", err)
+	}
+
+	// same user voting for another target, or another user voting for the same target, is allowed
+	otherUser := user.NewUser(1337, "other@local", "other")
+	for _, v := range []*BlogVote{NewBlogVote(1337, conformanceUser, utils.UniqueId(), 1), NewBlogVote(1337, otherUser, targetId, -1)} {
+		v.SetId(utils.UniqueId())
+		if ok, err := dao.Create(v); err != nil || !ok {
+			t.Fatalf("%s failed: %#v / %s", name+"/Create("+v.GetOwnerId()+"/"+v.GetTargetId()+")", ok, err)
+		}
+	}
+
+	// changing the value of an existing vote keeps it unique
+	vote.SetValue(-1)
+	if ok, err := dao.Update(vote); err != nil || !ok {
+		t.Fatalf("%s failed: %#v / %s", name+"/Update", ok, err)
+	}
+	bo, err := dao.GetUserVoteForTarget(conformanceUser, targetId)
+	if err != nil || bo == nil || bo.GetId() != vote.GetId() || bo.GetValue() != -1 {
+		t.Fatalf("%s failed: expected %#v but received %#v (error %s)", name+"/GetUserVoteForTarget", vote, bo, err)
+	}
+	votes, err := dao.GetAll(&godal.FilterOptFieldOpValue{FieldName: VoteFieldTargetId, Operator: godal.FilterOpEqual, Value: targetId}, nil)
+	if err != nil || len(votes) != 2 {
+		t.Fatalf("%s failed: expected %#v votes but received %#v (error %s)", name+"/GetAll", 2, len(votes), err)
+	}
+}
+
+func doConformanceVoteUpdateIfChecksum(t *testing.T, name string, factory *blogDaoFactory) {
+	dao := factory.newVoteDao(t, name)
+	vote := NewBlogVote(1337, conformanceUser, utils.UniqueId(), 1)
+	vote.SetId(utils.UniqueId())
+	if ok, err := dao.UpdateIfChecksum(vote, vote.GetChecksum()); err != nil || ok {
+		t.Fatalf("%s failed: expected %#v but received %#v (error %s)", name+"/UpdateIfChecksum(not found)", false, ok, err)
+	}
+	if ok, err := dao.Create(vote); err != nil || !ok {
+		t.Fatalf("%s failed: %#v / %s", name+"/Create", ok, err)
+	}
+	v, _ := dao.Get(vote.GetId())
+	if v == nil || v.GetChecksum() != vote.GetChecksum() {
+		t.Fatalf("%s failed: expected checksum %#v but received %#v", name+"/Get", vote.GetChecksum(), v)
+	}
+	checksum := v.GetChecksum()
+	if ok, err := dao.UpdateIfChecksum(v.SetValue(-1), checksum); err != nil || !ok {
+		t.Fatalf("%s failed: expected %#v but received %#v (error %s)", name+"/UpdateIfChecksum", true, ok, err)
+	}
+	if ok, err := dao.UpdateIfChecksum(v.SetValue(0), checksum); err != nil || ok {
+		t.Fatalf("%s failed: expected %#v but received %#v (error %s)", name+"/UpdateIfChecksum(stale)", false, ok, err)
+	}
+	if v, err := dao.Get(vote.GetId()); err != nil || v == nil || v.GetValue() != -1 {
+		t.Fatalf("%s failed: expected value %#v but received %#v (error %s)", name+"/Get", -1, v, err)
+	}
+}
+
+/*----------------------------------------------------------------------*/
+
+func _checkVoteCounters(t *testing.T, name string, post *BlogPost, numUp, numDown int) {
+	if post == nil || post.GetNumVotesUp() != numUp || post.GetNumVotesDown() != numDown {
+		t.Fatalf("%s failed: expected %d/%d votes but received %#v", name, numUp, numDown, post)
+	}
+}
+
+func _initConformanceVoting(t *testing.T, name string, factory *blogDaoFactory) (BlogPostDao, BlogVoteDao, BlogPostVoter, *BlogPost) {
+	postDao, voteDao := factory.newPostDao(t, name), factory.newVoteDao(t, name)
+	post := NewBlogPost(1337, conformanceUser, true, "title", "content")
+	if ok, err := postDao.Create(post); err != nil || !ok {
+		t.Fatalf("%s failed: %#v / %s", name+"/Create", ok, err)
+	}
+	return postDao, voteDao, factory.voter(postDao, voteDao), post
+}
+
+func doConformanceVoting(t *testing.T, name string, factory *blogDaoFactory) {
+	postDao, voteDao, voter, post := _initConformanceVoting(t, name, factory)
+	if vote, p, err := voter.Vote(NewBlogVote(1337, conformanceUser, utils.UniqueId(), 1)); err != nil || vote != nil || p != nil {
+		t.Fatalf("%s failed: expected nil but received %#v/%#v (error %s)", name+"/Vote(post not found)", vote, p, err)
+	}
+	otherUser := user.NewUser(1337, "other@local", "other")
+	testCases := []struct {
+		voter                  *user.User
+		value                  int
+		expectedValue          int
+		expectedUp, expectedDn int
+	}{
+		{conformanceUser, 1, 1, 1, 0},
+		{otherUser, -5, -1, 1, 1},
+		{conformanceUser, 1, 0, 0, 1}, // same vote again cancels it
+		{conformanceUser, -1, -1, 0, 2},
+		{conformanceUser, 1, 1, 1, 1}, // changing the vote
+		{otherUser, 3, 1, 2, 0},
+	}
+	for i, tc := range testCases {
+		vote, p, err := voter.Vote(NewBlogVote(1337, tc.voter, post.GetId(), tc.value))
+		if err != nil || vote == nil || vote.GetValue() != tc.expectedValue {
+			t.Fatalf("%s failed: expected value %#v but received %#v (error %s)", fmt.Sprintf("%s/Vote[%d]", name, i), tc.expectedValue, vote, err)
+		}
+		_checkVoteCounters(t, fmt.Sprintf("%s/Vote[%d]", name, i), p, tc.expectedUp, tc.expectedDn)
+		p, _ = postDao.Get(post.GetId())
+		_checkVoteCounters(t, fmt.Sprintf("%s/Get[%d]", name, i), p, tc.expectedUp, tc.expectedDn)
+		if v, err := voteDao.GetUserVoteForTarget(tc.voter, post.GetId()); err != nil || v == nil || v.GetValue() != tc.expectedValue {
+			t.Fatalf("%s failed: expected value %#v but received %#v (error %s)", fmt.Sprintf("%s/GetUserVoteForTarget[%d]", name, i), tc.expectedValue, v, err)
+		}
+	}
+}
+
+func doConformanceVotingConcurrent(t *testing.T, name string, factory *blogDaoFactory) {
+	postDao, _, voter, post := _initConformanceVoting(t, name, factory)
+	const numUsers, numVotesPerUser = 8, 3
+	var wg sync.WaitGroup
+	errs := make(chan error, numUsers*numVotesPerUser)
+	for i := 0; i < numUsers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			u := user.NewUser(1337, fmt.Sprintf("user%d@local", i), fmt.Sprintf("user%d", i))
+			// each user ends up with an up-vote (even i) or a down-vote (odd i): vote, cancel, vote again
+			for j := 0; j < numVotesPerUser; j++ {
+				if _, _, err := voter.Vote(NewBlogVote(1337, u, post.GetId(), 1-2*(i%2))); err != nil {
+					errs <- err
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("%s failed: %s", name+"/Vote", err)
+	}
+	p, err := postDao.Get(post.GetId())
+	if err != nil {
+		t.Fatalf("%s failed: %s", name+"/Get", err)
+	}
+	_checkVoteCounters(t, name+"/Get", p, numUsers/2, numUsers/2)
+	if _, changed, err := voter.RecountVotes(post.GetId()); err != nil || changed {
+		t.Fatalf("%s failed: expected no drift but received %#v (error %s)", name+"/RecountVotes", changed, err)
+	}
+}
+
+func doConformanceVotingRecount(t *testing.T, name string, factory *blogDaoFactory) {
+	postDao, voteDao, voter, post := _initConformanceVoting(t, name, factory)
+	if p, changed, err := voter.RecountVotes(utils.UniqueId()); err != nil || p != nil || changed {
+		t.Fatalf("%s failed: expected nil but received %#v/%#v (error %s)", name+"/RecountVotes(post not found)", p, changed, err)
+	}
+
+	// votes written bypassing the voter make counters drift; votes are cast before RecountGracePeriod, as recent votes
+	// may still be being added to counters by voters that write the vote and the post separately
+	for i, value := range []int{1, 1, -1, 0, 1} {
+		vote := NewBlogVote(1337, user.NewUser(1337, fmt.Sprintf("user%d@local", i), ""), post.GetId(), value)
+		vote.SetId(utils.UniqueId())
+		vote.SetTimeUpdated(time.Now().Add(-2 * RecountGracePeriod))
+		if ok, err := voteDao.Create(vote); err != nil || !ok {
+			t.Fatalf("%s failed: %#v / %s", name+"/Create", ok, err)
+		}
+	}
+	p, changed, err := voter.RecountVotes(post.GetId())
+	if err != nil || !changed {
+		t.Fatalf("%s failed: expected %#v but received %#v (error %s)", name+"/RecountVotes", true, changed, err)
+	}
+	_checkVoteCounters(t, name+"/RecountVotes", p, 3, 1)
+	p, _ = postDao.Get(post.GetId())
+	_checkVoteCounters(t, name+"/Get", p, 3, 1)
+	if _, changed, err := voter.RecountVotes(post.GetId()); err != nil || changed {
+		t.Fatalf("%s failed: expected %#v but received %#v (error %s)", name+"/RecountVotes", false, changed, err)
+	}
+}
@@ -0,0 +1,158 @@
+package user
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/btnguyen2k/godal"
+)
+
+// userDaoFactory creates DAOs for the conformance suite (see runUserDaoConformance).
+//
+// Each call must return a DAO backed by fresh, empty storage that is set up the way the application sets it up,
+// e.g. users' mask ids must be unique.
+type userDaoFactory func(t *testing.T, testName string) UserDao
+
+// runUserDaoConformance runs the conformance suite against DAOs created by factory. Every implementation of UserDao
+// must pass it, so that they can be used interchangeably.
+func runUserDaoConformance(t *testing.T, name string, factory userDaoFactory) {
+	testCases := []struct {
+		name string
+		test func(t *testing.T, name string, dao UserDao)
+	}{
+		{"NotFound", doConformanceUserNotFound},
+		{"DuplicatedId", doConformanceUserDuplicatedId},
+		{"DuplicatedMaskId", doConformanceUserDuplicatedMaskId},
+		{"Paging", doConformanceUserPaging},
+		{"GetMany", doConformanceUserGetMany},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.test(t, name+"/"+tc.name, factory(t, name+"/"+tc.name))
+		})
+	}
+}
+
+func doConformanceUserNotFound(t *testing.T, name string, dao UserDao) {
+	u := NewUser(1337, "nobody@local", "nobody")
+	if bo, err := dao.Get(u.GetId()); err != nil || bo != nil {
+		t.Fatalf("%s failed: expected nil but received %#v (error %s)", name+"/Get", bo, err)
+	}
+	if ok, err := dao.Delete(u); err != nil || ok {
+		t.Fatalf("%s failed: expected %#v but received %#v (error %s)", name+"/Delete", false, ok, err)
+	}
+	if ok, err := dao.Update(u); err != nil || ok {
+		t.Fatalf("%s failed: expected %#v but received %#v (error %s)", name+"/Update", false, ok, err)
+	}
+	if bo, err := dao.Get(u.GetId()); err != nil || bo != nil {
+		t.Fatalf("%s failed: Update must not create missing record (error %s)", name+"/Get", err)
+	}
+}
+
+func doConformanceUserDuplicatedId(t *testing.T, name string, dao UserDao) {
+	if ok, err := dao.Create(NewUser(1337, "user@local", "user")); err != nil || !ok {
+		t.Fatalf("%s failed: %#v / %s", name+"/Create", ok, err)
+	}
+	ok, err := dao.Create(NewUser(1337, "user@local", "another"))
+	if ok || err != godal.ErrGdaoDuplicatedEntry {
+		t.Fatalf("%s failed: expected %#v but received %#v/%#v", name+"/Create", godal.ErrGdaoDuplicatedEntry, ok, err)
+	}
+	if bo, err := dao.Get("user@local"); err != nil || bo == nil || bo.GetMaskId() != "user" {
+		t.Fatalf("%s failed: existing user must not be overridden, received %#v (error %s)", name+"/Get", bo, err)
+	}
+}
+
+func doConformanceUserDuplicatedMaskId(t *testing.T, name string, dao UserDao) {
+	u := NewUser(1337, "user@local", "user")
+	if ok, err := dao.Create(u); err != nil || !ok {
+		t.Fatalf("%s failed: %#v / %s", name+"/Create", ok, err)
+	}
+	another := NewUser(1337, "another@local", "user")
+	ok, err := dao.Create(another)
+	if ok || err != godal.ErrGdaoDuplicatedEntry {
+		t.Fatalf("%s failed: expected %#v but received %#v/%#v", name+"/Create", godal.ErrGdaoDuplicatedEntry, ok, err)
+	}
+	if bo, err := dao.Get(another.GetId()); err != nil || bo != nil {
+		t.Fatalf("%s failed: rejected user must not be stored (error %s)", name+"/Get", err)
+	}
+
+	// updating a user to take another user's mask id must be rejected, too
+	another.SetMaskId("another")
+	if ok, err := dao.Create(another); err != nil || !ok {
+		t.Fatalf("%s failed: %#v / %s", name+"/Create", ok, err)
+	}
+	another.SetMaskId(u.GetMaskId())
+	ok, err = dao.Update(another)
+	if ok || err != godal.ErrGdaoDuplicatedEntry {
+		t.Fatalf("%s failed: expected %#v but received %#v/%#v", name+"/Update", godal.ErrGdaoDuplicatedEntry, ok, err)
+	}
+}
+
+func doConformanceUserPaging(t *testing.T, name string, dao UserDao) {
+	const numUsers, pageSize = 11, 3
+	expected := make([]string, numUsers)
+	for i := 0; i < numUsers; i++ {
+		u := NewUser(1337, fmt.Sprintf("user%02d@local", i), fmt.Sprintf("user%02d", i))
+		if ok, err := dao.Create(u); err != nil || !ok {
+			t.Fatalf("%s failed: %#v / %s", name+"/Create", ok, err)
+		}
+		expected[i] = u.GetId()
+	}
+
+	// without sorting, order is backend-specific, but pages must not overlap and together they must cover all users
+	received := make([]string, 0, numUsers)
+	for offset := 0; offset < numUsers+pageSize; offset += pageSize {
+		userList, err := dao.GetN(offset, pageSize, nil, nil)
+		numExpected := numUsers - offset
+		if numExpected < 0 {
+			numExpected = 0
+		} else if numExpected > pageSize {
+			numExpected = pageSize
+		}
+		if err != nil || len(userList) != numExpected {
+			t.Fatalf("%s failed: expected %#v but received %#v (error %s)", fmt.Sprintf("%s/GetN(%d)", name, offset), numExpected, len(userList), err)
+		}
+		for _, u := range userList {
+			received = append(received, u.GetId())
+		}
+	}
+	sort.Strings(received)
+	if fmt.Sprintf("%v", received) != fmt.Sprintf("%v", expected) {
+		t.Fatalf("%s failed: expected %#v but received %#v", name+"/GetN", expected, received)
+	}
+}
+
+func doConformanceUserGetMany(t *testing.T, name string, dao UserDao) {
+	const numUsers = 5
+	for i := 0; i < numUsers; i++ {
+		u := NewUser(1337, fmt.Sprintf("user%02d@local", i), fmt.Sprintf("user%02d", i)).SetDisplayName(fmt.Sprintf("User %d", i))
+		if ok, err := dao.Create(u); err != nil || !ok {
+			t.Fatalf("%s failed: %#v / %s", name+"/Create", ok, err)
+		}
+	}
+
+	if userList, err := dao.GetMany(nil); err != nil || len(userList) != 0 {
+		t.Fatalf("%s failed: expected empty result but received %#v (error %s)", name+"/GetMany", userList, err)
+	}
+
+	// ids not found are ignored, duplicated ids are returned once; order is backend-specific
+	ids := []string{"user03@local", "nobody@local", "user00@local", "user03@local", "user04@local"}
+	userList, err := dao.GetMany(ids)
+	if err != nil {
+		t.Fatalf("%s failed: error %s", name+"/GetMany", err)
+	}
+	received := make([]string, 0, len(userList))
+	for _, u := range userList {
+		received = append(received, u.GetId())
+		expected := "User " + u.GetId()[5:6]
+		if u.GetDisplayName() != expected || u.GetMaskId() != u.GetId()[:6] {
+			t.Fatalf("%s failed: expected %#v but received %#v", name+"/GetMany", expected, u)
+		}
+	}
+	sort.Strings(received)
+	expected := []string{"user00@local", "user03@local", "user04@local"}
+	if fmt.Sprintf("%v", received) != fmt.Sprintf("%v", expected) {
+		t.Fatalf("%s failed: expected %#v but received %#v", name+"/GetMany", expected, received)
+	}
+}
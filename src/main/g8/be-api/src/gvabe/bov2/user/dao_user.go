@@ -24,6 +24,12 @@ type UserDao interface {
 	// Get retrieves a business object from storage
 	Get(username string) (*User, error)
 
+	// GetMany retrieves business objects with the specified ids in one batch. Ids not found in storage are ignored,
+	// order of the returned business objects is not guaranteed.
+	//
+	// Available since template-v0.5.0
+	GetMany(ids []string) ([]*User, error)
+
 	// GetN retrieves N business objects from storage
 	GetN(fromOffset, maxNumRows int, filter godal.FilterOpt, sorting *godal.SortingOpt) ([]*User, error)
 
@@ -34,6 +40,30 @@ type UserDao interface {
 	Update(bo *User) (bool, error)
 }
 
+// getManyChunkSize is the maximum number of ids fetched by one query of GetMany.
+const getManyChunkSize = 100
+
+// _chunkIds removes empty and duplicated ids and splits the remaining into chunks of at most size ids.
+func _chunkIds(ids []string, size int) [][]string {
+	chunks := make([][]string, 0)
+	chunk := make([]string, 0, size)
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		if chunk = append(chunk, id); len(chunk) >= size {
+			chunks = append(chunks, chunk)
+			chunk = make([]string, 0, size)
+		}
+	}
+	if len(chunk) > 0 {
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
+
 // BaseUserDaoImpl is a generic implementation of UserDao.
 //
 // Available since template-v0.3.0
@@ -60,6 +90,26 @@ func (dao *BaseUserDaoImpl) Get(id string) (*User, error) {
 	return NewUserFromUbo(ubo), nil
 }
 
+// GetMany implements UserDao.GetMany
+//
+// This generic implementation fetches users by chunks of ids using an OR-combined filter, backend-specific
+// implementations should override it with the backend's native batch read.
+func (dao *BaseUserDaoImpl) GetMany(ids []string) ([]*User, error) {
+	result := make([]*User, 0)
+	for _, chunk := range _chunkIds(ids, getManyChunkSize) {
+		filter := &godal.FilterOptOr{}
+		for _, id := range chunk {
+			filter.Add(&godal.FilterOptFieldOpValue{FieldName: henge.FieldId, Operator: godal.FilterOpEqual, Value: id})
+		}
+		userList, err := dao.GetAll(filter, nil)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, userList...)
+	}
+	return result, nil
+}
+
 // GetN implements UserDao.GetN
 func (dao *BaseUserDaoImpl) GetN(fromOffset, maxNumRows int, filter godal.FilterOpt, sorting *godal.SortingOpt) ([]*User, error) {
 	uboList, err := dao.UniversalDao.GetN(fromOffset, maxNumRows, filter, sorting)
@@ -0,0 +1,131 @@
+package user
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/btnguyen2k/godal"
+
+	"main/src/cache"
+	"main/src/utils"
+)
+
+// CachedUserDao is a read-through caching decorator of UserDao.
+//
+//   - Get and GetMany look up the cache first and cache users loaded from the wrapped DAO; users not found are not cached.
+//   - Entries are invalidated after Update and Delete; other methods are passed through to the wrapped DAO.
+//   - Users are cached in serialized form, hence callers never share instances with the cache.
+//
+// Available since template-v0.5.0
+type CachedUserDao struct {
+	dao       UserDao
+	cache     cache.Cache
+	ttl       time.Duration
+	keyPrefix string
+}
+
+// NewUserDaoCache is helper method to create a read-through caching decorator of UserDao.
+//
+//   - cache: where users are cached, keyed by "<keyPrefix><user-id>".
+//   - ttl: how long users are cached (0 or negative value means the cache's default).
+//
+// Available since template-v0.5.0
+func NewUserDaoCache(dao UserDao, cache cache.Cache, ttl time.Duration, keyPrefix string) *CachedUserDao {
+	return &CachedUserDao{dao: dao, cache: cache, ttl: ttl, keyPrefix: keyPrefix}
+}
+
+// Unwrap returns the wrapped DAO.
+func (dao *CachedUserDao) Unwrap() UserDao {
+	return dao.dao
+}
+
+func (dao *CachedUserDao) _invalidate(id string) {
+	// stale entry expires after ttl if it cannot be removed
+	dao.cache.Delete(dao.keyPrefix + id)
+}
+
+// Delete implements UserDao.Delete
+func (dao *CachedUserDao) Delete(user *User) (bool, error) {
+	defer dao._invalidate(user.GetId())
+	return dao.dao.Delete(user)
+}
+
+// Create implements UserDao.Create
+func (dao *CachedUserDao) Create(user *User) (bool, error) {
+	return dao.dao.Create(user)
+}
+
+// _getCached returns the cached user, nil if not cached.
+func (dao *CachedUserDao) _getCached(id string) *User {
+	if data, err := dao.cache.Get(dao.keyPrefix + id); err == nil && data != nil {
+		if ubo, err := utils.UboFromJson(data); err == nil {
+			return NewUserFromUbo(ubo)
+		}
+	}
+	return nil
+}
+
+func (dao *CachedUserDao) _setCached(user *User) {
+	if data, err := json.Marshal(user.sync().UniversalBo); err == nil {
+		dao.cache.Set(dao.keyPrefix+user.GetId(), data, dao.ttl)
+	}
+}
+
+// Get implements UserDao.Get
+func (dao *CachedUserDao) Get(id string) (*User, error) {
+	if user := dao._getCached(id); user != nil {
+		return user, nil
+	}
+	user, err := dao.dao.Get(id)
+	if err == nil && user != nil {
+		dao._setCached(user)
+	}
+	return user, err
+}
+
+// GetMany implements UserDao.GetMany
+//
+// Users not found in the cache are loaded from the wrapped DAO in one batch.
+func (dao *CachedUserDao) GetMany(ids []string) ([]*User, error) {
+	result := make([]*User, 0, len(ids))
+	missed := make([]string, 0)
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if user := dao._getCached(id); user != nil {
+			result = append(result, user)
+		} else {
+			missed = append(missed, id)
+		}
+	}
+	if len(missed) == 0 {
+		return result, nil
+	}
+	userList, err := dao.dao.GetMany(missed)
+	if err != nil {
+		return nil, err
+	}
+	for _, user := range userList {
+		dao._setCached(user)
+	}
+	return append(result, userList...), nil
+}
+
+// GetN implements UserDao.GetN
+func (dao *CachedUserDao) GetN(fromOffset, maxNumRows int, filter godal.FilterOpt, sorting *godal.SortingOpt) ([]*User, error) {
+	return dao.dao.GetN(fromOffset, maxNumRows, filter, sorting)
+}
+
+// GetAll implements UserDao.GetAll
+func (dao *CachedUserDao) GetAll(filter godal.FilterOpt, sorting *godal.SortingOpt) ([]*User, error) {
+	return dao.dao.GetAll(filter, sorting)
+}
+
+// Update implements UserDao.Update
+func (dao *CachedUserDao) Update(user *User) (bool, error) {
+	defer dao._invalidate(user.GetId())
+	return dao.dao.Update(user)
+}
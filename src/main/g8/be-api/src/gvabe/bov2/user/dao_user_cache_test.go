@@ -0,0 +1,130 @@
+package user
+
+import (
+	"testing"
+	"time"
+
+	"main/src/cache"
+)
+
+func initUserDaoCache(t *testing.T, testName string) UserDao {
+	return NewUserDaoCache(initUserDaoMemory(t, testName), cache.NewLruCache(1000, time.Minute), 0, "user:")
+}
+
+func TestUserDaoCache_CreateGet(t *testing.T) {
+	name := "TestUserDaoCache_CreateGet"
+	dao := initUserDaoCache(t, name)
+	doTestUserDaoCreateGet(t, name, dao)
+}
+
+func TestUserDaoCache_CreateUpdateGet(t *testing.T) {
+	name := "TestUserDaoCache_CreateUpdateGet"
+	dao := initUserDaoCache(t, name)
+	doTestUserDaoCreateUpdateGet(t, name, dao)
+}
+
+func TestUserDaoCache_CreateDelete(t *testing.T) {
+	name := "TestUserDaoCache_CreateDelete"
+	dao := initUserDaoCache(t, name)
+	doTestUserDaoCreateDelete(t, name, dao)
+}
+
+func TestUserDaoCache_GetN(t *testing.T) {
+	name := "TestUserDaoCache_GetN"
+	dao := initUserDaoCache(t, name)
+	doTestUserDaoGetN(t, name, dao)
+}
+
+func TestUserDaoCache_GetMany(t *testing.T) {
+	name := "TestUserDaoCache_GetMany"
+	dao := initUserDaoCache(t, name)
+	doTestUserDaoGetMany(t, name, dao)
+}
+
+func TestUserDaoCache_Conformance(t *testing.T) {
+	name := "TestUserDaoCache_Conformance"
+	runUserDaoConformance(t, name, initUserDaoCache)
+}
+
+func TestUserDaoCache_ReadThrough(t *testing.T) {
+	name := "TestUserDaoCache_ReadThrough"
+	lru := cache.NewMeteredCache(cache.NewLruCache(1000, time.Minute))
+	backend := initUserDaoMemory(t, name)
+	dao := NewUserDaoCache(backend, lru, 0, "user:")
+	// a timestamp in the past: cached reads must return the stored value, not the time of reading
+	user := NewUser(1337, "user@local", "user").SetDisplayName("User")
+	user.SetTimeUpdated(time.Now().Add(-time.Hour))
+	if ok, err := dao.Create(user); err != nil || !ok {
+		t.Fatalf("%s failed: %#v / %s", name+"/Create", ok, err)
+	}
+	if u, err := dao.Get("not-exist"); err != nil || u != nil {
+		t.Fatalf("%s failed: expected nil but received %#v (error %s)", name, u, err)
+	}
+	u, err := dao.Get("user@local")
+	if err != nil || u == nil {
+		t.Fatalf("%s failed: nil or error %s", name, err)
+	}
+
+	checksum, timeCreated, timeUpdated := u.GetChecksum(), u.GetTimeCreated(), u.GetTimeUpdated()
+
+	// modifying the returned user must not affect the cached one
+	u.SetDisplayName("Modified")
+	// changes made directly to the backend are not visible until the entry is invalidated
+	u0, _ := backend.Get("user@local")
+	u0.SetDisplayName("Backend")
+	backend.Update(u0)
+	if u, _ := dao.Get("user@local"); u == nil || u.GetDisplayName() != "User" {
+		t.Fatalf("%s failed: expected cached user but received %#v", name, u)
+	} else if u.GetChecksum() != checksum || !u.GetTimeCreated().Equal(timeCreated) || !u.GetTimeUpdated().Equal(timeUpdated) || u.GetMaskId() != "user" {
+		t.Fatalf("%s failed: cached user does not match stored one %#v", name, u)
+	}
+
+	// updating via the decorator invalidates the entry
+	u.SetDisplayName("Updated")
+	if ok, err := dao.Update(u); err != nil || !ok {
+		t.Fatalf("%s failed: %#v / %s", name+"/Update", ok, err)
+	}
+	if u, _ := dao.Get("user@local"); u == nil || u.GetDisplayName() != "Updated" {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, "Updated", u)
+	}
+
+	// deleting via the decorator invalidates the entry
+	if ok, err := dao.Delete(u); err != nil || !ok {
+		t.Fatalf("%s failed: %#v / %s", name+"/Delete", ok, err)
+	}
+	if u, _ := dao.Get("user@local"); u != nil {
+		t.Fatalf("%s failed: expected nil but received %#v", name, u)
+	}
+
+	expected := cache.Stats{Hits: 1, Misses: 4, Sets: 2, Invalidations: 2}
+	if stats := lru.Stats(); stats != expected {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, expected, stats)
+	}
+}
+
+func TestUserDaoCache_GetManyReadThrough(t *testing.T) {
+	name := "TestUserDaoCache_GetManyReadThrough"
+	lru := cache.NewMeteredCache(cache.NewLruCache(1000, time.Minute))
+	dao := NewUserDaoCache(initUserDaoMemory(t, name), lru, 0, "user:")
+	for _, id := range []string{"user1@local", "user2@local", "user3@local"} {
+		if ok, err := dao.Create(NewUser(1337, id, id[:5])); err != nil || !ok {
+			t.Fatalf("%s failed: %#v / %s", name+"/Create", ok, err)
+		}
+	}
+	if u, _ := dao.Get("user1@local"); u == nil {
+		t.Fatalf("%s failed: nil", name+"/Get")
+	}
+
+	// user1 is read from the cache, the others are loaded in one batch and cached
+	if userList, err := dao.GetMany([]string{"user1@local", "user2@local", "nobody@local", "user3@local", "user2@local"}); err != nil || len(userList) != 3 {
+		t.Fatalf("%s failed: expected %#v but received %#v (error %s)", name, 3, len(userList), err)
+	}
+	if userList, err := dao.GetMany([]string{"user3@local", "user2@local"}); err != nil || len(userList) != 2 {
+		t.Fatalf("%s failed: expected %#v but received %#v (error %s)", name, 2, len(userList), err)
+	}
+
+	expected := cache.Stats{Hits: 3, Misses: 4, Sets: 3, Entries: 3}
+	if stats := lru.Stats(); stats != expected {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, expected, stats)
+	}
+}
@@ -11,7 +11,8 @@ import (
 //
 // Available since template-v0.3.0
 func NewUserDaoCosmosdb(sqlc *promsql.SqlConnect, tableName string, txModeOnWrite bool) UserDao {
-	dao := &BaseUserDaoImpl{}
+	// GetMany is the same as SQL's: "SELECT ... FROM <collection> c WHERE c.id IN (...)" (cross-partition query)
+	dao := &SqlUserDaoImpl{BaseUserDaoImpl: &BaseUserDaoImpl{}, tableName: tableName, colId: henge.CosmosdbColId}
 	spec := &henge.CosmosdbDaoSpec{
 		PkName:        henge.CosmosdbColId,
 		TxModeOnWrite: txModeOnWrite,
@@ -1,6 +1,12 @@
 package user
 
 import (
+	"errors"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awsdynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
 	"github.com/btnguyen2k/henge"
 	promdynamodb "github.com/btnguyen2k/prom/dynamodb"
 )
@@ -9,8 +15,76 @@ import (
 //
 // Available since template-v0.3.0
 func NewUserDaoDynamodb(adc *promdynamodb.AwsDynamodbConnect, tableName string) UserDao {
-	dao := &BaseUserDaoImpl{}
+	dao := &DynamodbUserDaoImpl{&BaseUserDaoImpl{}}
 	spec := &henge.DynamodbDaoSpec{UidxAttrs: [][]string{{UserFieldMaskId}}}
 	dao.UniversalDao = henge.NewUniversalDaoDynamodb(adc, tableName, spec)
 	return dao
 }
+
+// DynamodbUserDaoImpl is AWS DynamoDB-implementation of UserDao.
+//
+// Available since template-v0.5.0
+type DynamodbUserDaoImpl struct {
+	*BaseUserDaoImpl
+}
+
+// dynamodbBatchGetMaxKeys is the maximum number of keys a BatchGetItem request can contain.
+const dynamodbBatchGetMaxKeys = 100
+
+// dynamodbBatchGetMaxRetries is the maximum number of times unprocessed keys of a BatchGetItem request are retried.
+const dynamodbBatchGetMaxRetries = 5
+
+var errDynamodbUnprocessedKeys = errors.New("BatchGetItem: keys are left unprocessed after retries")
+
+// GetMany implements UserDao.GetMany
+//
+// Users are fetched by chunks of ids, using one BatchGetItem request per chunk; unprocessed keys (e.g. due to throttling)
+// are retried with backoff.
+func (dao *DynamodbUserDaoImpl) GetMany(ids []string) ([]*User, error) {
+	udao, ok := dao.UniversalDao.(*henge.UniversalDaoDynamodb)
+	if !ok {
+		return dao.BaseUserDaoImpl.GetMany(ids)
+	}
+	tableName := udao.GetTableName()
+	result := make([]*User, 0)
+	for _, chunk := range _chunkIds(ids, dynamodbBatchGetMaxKeys) {
+		keys := make([]map[string]*awsdynamodb.AttributeValue, len(chunk))
+		for i, id := range chunk {
+			keys[i] = map[string]*awsdynamodb.AttributeValue{henge.FieldId: {S: aws.String(id)}}
+		}
+		requestItems := map[string]*awsdynamodb.KeysAndAttributes{tableName: {Keys: keys}}
+		for attempt := 0; len(requestItems) > 0; attempt++ {
+			if attempt > dynamodbBatchGetMaxRetries {
+				return nil, errDynamodbUnprocessedKeys
+			} else if attempt > 0 {
+				time.Sleep(time.Duration(attempt*50) * time.Millisecond)
+			}
+			output, err := dao._batchGet(udao, requestItems)
+			if err != nil {
+				return nil, err
+			}
+			for _, row := range output.Responses[tableName] {
+				item := promdynamodb.AwsDynamodbItem{}
+				if err := dynamodbattribute.UnmarshalMap(row, &item); err != nil {
+					return nil, err
+				}
+				gbo, err := udao.GetRowMapper().ToBo(tableName, item)
+				if err != nil {
+					return nil, err
+				}
+				if user := NewUserFromUbo(udao.ToUniversalBo(gbo)); user != nil {
+					result = append(result, user)
+				}
+			}
+			requestItems = output.UnprocessedKeys
+		}
+	}
+	return result, nil
+}
+
+func (dao *DynamodbUserDaoImpl) _batchGet(udao *henge.UniversalDaoDynamodb, requestItems map[string]*awsdynamodb.KeysAndAttributes) (*awsdynamodb.BatchGetItemOutput, error) {
+	adc := udao.GetAwsDynamodbConnect()
+	ctx, cancel := adc.NewContext()
+	defer cancel()
+	return adc.GetDbProxy().BatchGetItemWithContext(ctx, &awsdynamodb.BatchGetItemInput{RequestItems: requestItems})
+}
@@ -74,6 +74,26 @@ func initDaoDynamodb(adc *promdynamodb.AwsDynamodbConnect) UserDao {
 
 /*----------------------------------------------------------------------*/
 
+func TestUserDaoDynamodb_Conformance(t *testing.T) {
+	name := "TestUserDaoDynamodb_Conformance"
+	adc, err := newDynamodbConnect(t, name)
+	if err != nil {
+		t.Fatalf("%s failed: error [%s]", name, err)
+	} else if adc == nil {
+		t.Fatalf("%s failed: nil", name)
+	}
+	defer adc.Close()
+	runUserDaoConformance(t, name, func(t *testing.T, testName string) UserDao {
+		spec := &henge.DynamodbTablesSpec{MainTableRcu: 2, MainTableWcu: 1, CreateUidxTable: true, UidxTableRcu: 2, UidxTableWcu: 1}
+		if err := dynamodbInitTable(adc, testDynamodbTable, spec); err != nil {
+			t.Fatalf("%s failed: error [%s]", testName+"/dynamodbInitTable", err)
+		}
+		return initDaoDynamodb(adc)
+	})
+}
+
+/*----------------------------------------------------------------------*/
+
 func TestNewUserDaoDynamodb(t *testing.T) {
 	name := "TestNewUserDaoDynamodb"
 	adc, err := newDynamodbConnect(t, name)
@@ -198,3 +218,24 @@ func TestUserDaoDynamodb_GetN(t *testing.T) {
 	defer adc.Close()
 	doTestUserDaoGetN(t, name, dao)
 }
+
+func TestUserDaoDynamodb_GetMany(t *testing.T) {
+	name := "TestUserDaoDynamodb_GetMany"
+	adc, err := newDynamodbConnect(t, name)
+	if err != nil {
+		t.Fatalf("%s failed: error [%s]", name, err)
+	} else if adc == nil {
+		t.Fatalf("%s failed: nil", name)
+	}
+	spec := &henge.DynamodbTablesSpec{MainTableRcu: 2, MainTableWcu: 1, CreateUidxTable: true, UidxTableRcu: 2, UidxTableWcu: 1}
+	err = dynamodbInitTable(adc, testDynamodbTable, spec)
+	if err != nil {
+		t.Fatalf("%s failed: error [%s]", name+"/dynamodbInitTable", err)
+	}
+	dao := initDaoDynamodb(adc)
+	if dao == nil {
+		t.Fatalf("%s failed: nil", name+"/initDaoDynamodb")
+	}
+	defer adc.Close()
+	doTestUserDaoGetMany(t, name, dao)
+}
@@ -0,0 +1,40 @@
+package user
+
+import (
+	"main/src/memdb"
+)
+
+// NewUserDaoMemory is helper method to create in-memory implementation of UserDao.
+//
+// Available since template-v0.5.0
+func NewUserDaoMemory(db *memdb.MemoryDb, tableName string) UserDao {
+	dao := &MemoryUserDaoImpl{&BaseUserDaoImpl{}}
+	dao.UniversalDao = memdb.NewUniversalDaoMemory(db, tableName)
+	return dao
+}
+
+// MemoryUserDaoImpl is in-memory implementation of UserDao.
+//
+// Available since template-v0.5.0
+type MemoryUserDaoImpl struct {
+	*BaseUserDaoImpl
+}
+
+// GetMany implements UserDao.GetMany
+func (dao *MemoryUserDaoImpl) GetMany(ids []string) ([]*User, error) {
+	udao, ok := dao.UniversalDao.(*memdb.UniversalDaoMemory)
+	if !ok {
+		return dao.BaseUserDaoImpl.GetMany(ids)
+	}
+	uboList, err := udao.GetMany(ids)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*User, 0, len(uboList))
+	for _, ubo := range uboList {
+		if user := NewUserFromUbo(ubo); user != nil {
+			result = append(result, user)
+		}
+	}
+	return result, nil
+}
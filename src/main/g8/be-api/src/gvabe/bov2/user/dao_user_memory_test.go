@@ -0,0 +1,70 @@
+package user
+
+import (
+	"testing"
+
+	"main/src/memdb"
+)
+
+func initUserDaoMemory(t *testing.T, testName string) UserDao {
+	db := memdb.NewMemoryDb()
+	if err := db.CreateTable(TableUser); err != nil {
+		t.Fatalf("%s failed: error [%s]", testName, err)
+	}
+	if err := db.CreateUniqueIndex(TableUser, UserFieldMaskId); err != nil {
+		t.Fatalf("%s failed: error [%s]", testName, err)
+	}
+	return NewUserDaoMemory(db, TableUser)
+}
+
+func TestUserDaoMemory_CreateGet(t *testing.T) {
+	name := "TestUserDaoMemory_CreateGet"
+	dao := initUserDaoMemory(t, name)
+	doTestUserDaoCreateGet(t, name, dao)
+}
+
+func TestUserDaoMemory_CreateUpdateGet(t *testing.T) {
+	name := "TestUserDaoMemory_CreateUpdateGet"
+	dao := initUserDaoMemory(t, name)
+	doTestUserDaoCreateUpdateGet(t, name, dao)
+}
+
+func TestUserDaoMemory_CreateDelete(t *testing.T) {
+	name := "TestUserDaoMemory_CreateDelete"
+	dao := initUserDaoMemory(t, name)
+	doTestUserDaoCreateDelete(t, name, dao)
+}
+
+func TestUserDaoMemory_GetAll(t *testing.T) {
+	name := "TestUserDaoMemory_GetAll"
+	dao := initUserDaoMemory(t, name)
+	doTestUserDaoGetAll(t, name, dao)
+}
+
+func TestUserDaoMemory_GetN(t *testing.T) {
+	name := "TestUserDaoMemory_GetN"
+	dao := initUserDaoMemory(t, name)
+	doTestUserDaoGetN(t, name, dao)
+}
+
+func TestUserDaoMemory_GetMany(t *testing.T) {
+	name := "TestUserDaoMemory_GetMany"
+	dao := initUserDaoMemory(t, name)
+	doTestUserDaoGetMany(t, name, dao)
+}
+
+func TestUserDaoMemory_Conformance(t *testing.T) {
+	name := "TestUserDaoMemory_Conformance"
+	runUserDaoConformance(t, name, initUserDaoMemory)
+}
+
+func TestBaseUserDaoImpl_GetMany(t *testing.T) {
+	name := "TestBaseUserDaoImpl_GetMany"
+	db := memdb.NewMemoryDb()
+	if err := db.CreateTable(TableUser); err != nil {
+		t.Fatalf("%s failed: error [%s]", name, err)
+	}
+	// the generic implementation, used by backends without native batch read
+	dao := &BaseUserDaoImpl{UniversalDao: memdb.NewUniversalDaoMemory(db, TableUser)}
+	doTestUserDaoGetMany(t, name, dao)
+}
@@ -3,13 +3,69 @@ package user
 import (
 	"github.com/btnguyen2k/henge"
 	prommongo "github.com/btnguyen2k/prom/mongo"
+	"go.mongodb.org/mongo-driver/bson"
 )
 
 // NewUserDaoMongo is helper method to create MongoDB-implementation of UserDao
 //
 // Available since template-v0.3.0
 func NewUserDaoMongo(mc *prommongo.MongoConnect, collectionName string, txModeOnWrite bool) UserDao {
-	dao := &BaseUserDaoImpl{}
+	dao := &MongoUserDaoImpl{BaseUserDaoImpl: &BaseUserDaoImpl{}, collectionName: collectionName}
 	dao.UniversalDao = henge.NewUniversalDaoMongo(mc, collectionName, txModeOnWrite)
 	return dao
 }
+
+// MongoUserDaoImpl is MongoDB-implementation of UserDao.
+//
+// Available since template-v0.5.0
+type MongoUserDaoImpl struct {
+	*BaseUserDaoImpl
+	collectionName string
+}
+
+// GetMany implements UserDao.GetMany
+//
+// Users are fetched by chunks of ids, using one "find" command with an "in" filter on the id field per chunk.
+func (dao *MongoUserDaoImpl) GetMany(ids []string) ([]*User, error) {
+	udao, ok := dao.UniversalDao.(*henge.UniversalDaoMongo)
+	if !ok {
+		return dao.BaseUserDaoImpl.GetMany(ids)
+	}
+	result := make([]*User, 0)
+	for _, chunk := range _chunkIds(ids, getManyChunkSize) {
+		userList, err := dao._find(udao, bson.M{henge.MongoColId: bson.M{"$in": chunk}})
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, userList...)
+	}
+	return result, nil
+}
+
+func (dao *MongoUserDaoImpl) _find(udao *henge.UniversalDaoMongo, filter bson.M) ([]*User, error) {
+	ctx := udao.GetMongoConnect().NewContext()
+	cursor, err := udao.GetMongoCollection(dao.collectionName).Find(ctx, filter)
+	if cursor != nil {
+		defer func() { _ = cursor.Close(ctx) }()
+	}
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*User, 0)
+	udao.GetMongoConnect().DecodeResultCallbackRaw(ctx, cursor, func(_ int, doc []byte, e error) bool {
+		if e != nil {
+			err = e
+			return false
+		}
+		gbo, e := udao.GetRowMapper().ToBo(dao.collectionName, doc)
+		if e != nil {
+			err = e
+			return false
+		}
+		if user := NewUserFromUbo(udao.ToUniversalBo(gbo)); user != nil {
+			result = append(result, user)
+		}
+		return true
+	})
+	return result, err
+}
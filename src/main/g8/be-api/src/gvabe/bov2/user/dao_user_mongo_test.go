@@ -9,6 +9,9 @@ import (
 
 	"github.com/btnguyen2k/henge"
 	prommongo "github.com/btnguyen2k/prom/mongo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 const (
@@ -41,6 +44,33 @@ const (
 
 /*----------------------------------------------------------------------*/
 
+func TestUserDaoMongo_Conformance(t *testing.T) {
+	name := "TestUserDaoMongo_Conformance"
+	db := os.Getenv(envMongoDb)
+	url := os.Getenv(envMongoUrl)
+	mc, err := newMongoConnect(t, name, db, url)
+	if err != nil {
+		t.Fatalf("%s failed: error [%s]", name, err)
+	}
+	defer mc.Close(nil)
+	runUserDaoConformance(t, name, func(t *testing.T, testName string) UserDao {
+		if err := mongoInitCollection(mc, testMongoCollection); err != nil {
+			t.Fatalf("%s failed: error [%s]", testName+"/mongoInitCollection", err)
+		}
+		// mask ids are unique, same as the application's schema
+		idxName, unique := "uidx_mid", true
+		if _, err := mc.CreateCollectionIndexes(testMongoCollection, []interface{}{mongo.IndexModel{
+			Keys:    bson.D{{Key: UserFieldMaskId, Value: 1}},
+			Options: &options.IndexOptions{Name: &idxName, Unique: &unique},
+		}}); err != nil {
+			t.Fatalf("%s failed: error [%s]", testName+"/CreateCollectionIndexes", err)
+		}
+		return initDaoMongo(mc)
+	})
+}
+
+/*----------------------------------------------------------------------*/
+
 func TestNewUserDaoMongo(t *testing.T) {
 	name := "TestNewUserDaoMongo"
 	db := os.Getenv(envMongoDb)
@@ -156,3 +186,22 @@ func TestUserDaoMongo_GetN(t *testing.T) {
 	doTestUserDaoGetN(t, name, dao)
 	mc.Close(nil)
 }
+
+func TestUserDaoMongo_GetMany(t *testing.T) {
+	name := "TestUserDaoMongo_GetMany"
+	db := os.Getenv(envMongoDb)
+	url := os.Getenv(envMongoUrl)
+	mc, err := newMongoConnect(t, name, db, url)
+	if err != nil {
+		t.Fatalf("%s failed: error [%s]", name, err)
+	} else if mc == nil {
+		t.Fatalf("%s failed: nil", name)
+	}
+	err = mongoInitCollection(mc, testMongoCollection)
+	if err != nil {
+		t.Fatalf("%s failed: error [%s]", name+"/mongoInitCollection", err)
+	}
+	dao := initDaoMongo(mc)
+	doTestUserDaoGetMany(t, name, dao)
+	mc.Close(nil)
+}
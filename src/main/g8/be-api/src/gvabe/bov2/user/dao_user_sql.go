@@ -1,6 +1,11 @@
 package user
 
 import (
+	"fmt"
+	"strings"
+
+	"github.com/btnguyen2k/godal"
+	godalsql "github.com/btnguyen2k/godal/sql"
 	"github.com/btnguyen2k/henge"
 	promsql "github.com/btnguyen2k/prom/sql"
 )
@@ -9,9 +14,85 @@ import (
 //
 // Available since template-v0.2.0
 func NewUserDaoSql(sqlc *promsql.SqlConnect, tableName string, txModeOnWrite bool) UserDao {
-	dao := &BaseUserDaoImpl{}
+	dao := &SqlUserDaoImpl{BaseUserDaoImpl: &BaseUserDaoImpl{}, tableName: tableName, colId: henge.SqlColId}
 	dao.UniversalDao = henge.NewUniversalDaoSql(
 		sqlc, tableName, txModeOnWrite,
 		map[string]string{UserColMaskUid: UserFieldMaskId})
 	return dao
 }
+
+// SqlUserDaoImpl is SQL-implementation of UserDao, also used for Azure Cosmos DB (SQL API).
+//
+// Available since template-v0.5.0
+type SqlUserDaoImpl struct {
+	*BaseUserDaoImpl
+	tableName string
+	colId     string // name of the column storing users' id
+}
+
+// sqlUniversalDao is the part of henge.UniversalDaoSql (and henge.UniversalDaoCosmosdbSql) used by SqlUserDaoImpl.
+type sqlUniversalDao interface {
+	godalsql.IGenericDaoSql
+	ToUniversalBo(gbo godal.IGenericBo) *henge.UniversalBo
+}
+
+// filterIdIn is a godalsql.IFilter that builds the clause "<col> IN (<placeholders>)".
+type filterIdIn struct {
+	col string
+	ids []string
+}
+
+// Build implements godalsql.IFilter.Build
+func (f *filterIdIn) Build(placeholderGenerator godalsql.PlaceholderGenerator, opts ...interface{}) (string, []interface{}) {
+	tableAlias := ""
+	for _, opt := range opts {
+		switch o := opt.(type) {
+		case godalsql.OptTableAlias:
+			tableAlias = o.TableAlias + "."
+		case *godalsql.OptTableAlias:
+			tableAlias = o.TableAlias + "."
+		}
+	}
+	placeholders := make([]string, len(f.ids))
+	values := make([]interface{}, len(f.ids))
+	for i, id := range f.ids {
+		placeholders[i] = placeholderGenerator(f.col)
+		values[i] = id
+	}
+	return fmt.Sprintf("%s%s IN (%s)", tableAlias, f.col, strings.Join(placeholders, ",")), values
+}
+
+// GetMany implements UserDao.GetMany
+//
+// Users are fetched by chunks of ids, using one "SELECT ... WHERE id IN (...)" query per chunk.
+func (dao *SqlUserDaoImpl) GetMany(ids []string) ([]*User, error) {
+	udao, ok := dao.UniversalDao.(sqlUniversalDao)
+	if !ok {
+		return dao.BaseUserDaoImpl.GetMany(ids)
+	}
+	columns := udao.GetRowMapper().ColumnsList(dao.tableName)
+	result := make([]*User, 0)
+	for _, chunk := range _chunkIds(ids, getManyChunkSize) {
+		gboList, err := dao._fetchAll(udao, columns, &filterIdIn{col: dao.colId, ids: chunk})
+		if err != nil {
+			return nil, err
+		}
+		for _, gbo := range gboList {
+			if user := NewUserFromUbo(udao.ToUniversalBo(gbo)); user != nil {
+				result = append(result, user)
+			}
+		}
+	}
+	return result, nil
+}
+
+func (dao *SqlUserDaoImpl) _fetchAll(udao sqlUniversalDao, columns []string, filter godalsql.IFilter) ([]godal.IGenericBo, error) {
+	dbRows, err := udao.SqlSelect(nil, nil, dao.tableName, columns, filter, nil, 0, 0)
+	if dbRows != nil {
+		defer func() { _ = dbRows.Close() }()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return udao.FetchAll(dao.tableName, dbRows)
+}
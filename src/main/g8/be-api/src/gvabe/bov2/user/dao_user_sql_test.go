@@ -33,7 +33,7 @@ func sqlInitTable(sqlc *promsql.SqlConnect, table string) error {
 	switch sqlc.GetDbFlavor() {
 	case promsql.FlavorCosmosDb:
 		spec := &henge.CosmosdbCollectionSpec{Pk: henge.CosmosdbColId, Uk: [][]string{{"/" + UserColMaskUid}}}
-		err = henge.InitCosmosdbCollection(sqlc, table, spec)
+		return henge.InitCosmosdbCollection(sqlc, table, spec)
 	case promsql.FlavorSqlite:
 		err = henge.InitSqliteTable(sqlc, table, extraCols)
 	case promsql.FlavorMySql:
@@ -41,6 +41,10 @@ func sqlInitTable(sqlc *promsql.SqlConnect, table string) error {
 	case promsql.FlavorPgSql:
 		err = henge.InitPgsqlTable(sqlc, table, extraCols)
 	}
+	if err == nil {
+		// mask ids are unique, same as the application's schema
+		_, err = sqlc.GetDB().Exec(fmt.Sprintf("CREATE UNIQUE INDEX uidx_%s_%s ON %s(%s)", table, UserColMaskUid, table, UserColMaskUid))
+	}
 	return err
 }
 
@@ -152,6 +156,33 @@ func initSqlConnect(t *testing.T, testName string, dbtype string, info sqlDriver
 
 /*----------------------------------------------------------------------*/
 
+func TestUserDaoSql_Conformance(t *testing.T) {
+	name := "TestUserDaoSql_Conformance"
+	urlMap := sqlGetUrlFromEnv()
+	if len(urlMap) == 0 {
+		t.Skipf("%s skipped", name)
+	}
+	for dbtype, info := range urlMap {
+		t.Run(dbtype, func(t *testing.T) {
+			sqlc, err := initSqlConnect(t, name, dbtype, info)
+			if err != nil {
+				t.Fatalf("%s failed: error [%s]", name+"/"+dbtype, err)
+			} else if sqlc == nil {
+				t.Fatalf("%s failed: nil", name+"/"+dbtype)
+			}
+			defer sqlc.Close()
+			runUserDaoConformance(t, name+"/"+dbtype, func(t *testing.T, testName string) UserDao {
+				if err := sqlInitTable(sqlc, testSqlTable); err != nil {
+					t.Fatalf("%s failed: error [%s]", testName+"/sqlInitTable", err)
+				}
+				return initDaoSql(sqlc)
+			})
+		})
+	}
+}
+
+/*----------------------------------------------------------------------*/
+
 func TestNewUserDaoSql(t *testing.T) {
 	name := "TestNewUserDaoSql"
 	urlMap := sqlGetUrlFromEnv()
@@ -318,3 +349,72 @@ func TestUserDaoSql_GetN(t *testing.T) {
 		})
 	}
 }
+
+func TestUserDaoSql_GetMany(t *testing.T) {
+	name := "TestUserDaoSql_GetMany"
+	urlMap := sqlGetUrlFromEnv()
+	if len(urlMap) == 0 {
+		t.Skipf("%s skipped", name)
+	}
+	for dbtype, info := range urlMap {
+		t.Run(dbtype, func(t *testing.T) {
+			sqlc, err := initSqlConnect(t, name, dbtype, info)
+			if err != nil {
+				t.Fatalf("%s failed: error [%s]", name+"/"+dbtype, err)
+			} else if sqlc == nil {
+				t.Fatalf("%s failed: nil", name+"/"+dbtype)
+			}
+			defer sqlc.Close()
+			err = sqlInitTable(sqlc, testSqlTable)
+			if err != nil {
+				t.Fatalf("%s failed: error [%s]", name+"/"+dbtype+"/sqlInitTable/"+dbtype, err)
+			}
+			dao := initDaoSql(sqlc)
+			if dao == nil {
+				t.Fatalf("%s failed: nil", name+"/"+dbtype)
+			}
+			doTestUserDaoGetMany(t, name+"/"+dbtype, dao)
+		})
+	}
+}
+
+/*----------------------------------------------------------------------*/
+
+// BenchmarkUserDaoSql_Feed500 compares resolving owners of a 500-post feed (posts of 50 users) one post at a time
+// against resolving them in one batch with GetMany. It runs on a SQLite database in a temp directory.
+func BenchmarkUserDaoSql_Feed500(b *testing.B) {
+	const numPosts, numOwners = 500, 50
+	sqlc, err := promsql.NewSqlConnectWithFlavor("sqlite3", b.TempDir()+"/feed.db", 10000, nil, promsql.FlavorSqlite)
+	if err != nil {
+		b.Fatalf("%s failed: error [%s]", "BenchmarkUserDaoSql_Feed500", err)
+	}
+	defer sqlc.Close()
+	if err := sqlInitTable(sqlc, testSqlTable); err != nil {
+		b.Fatalf("%s failed: error [%s]", "BenchmarkUserDaoSql_Feed500/sqlInitTable", err)
+	}
+	dao := initDaoSql(sqlc)
+	for i := 0; i < numOwners; i++ {
+		dao.Create(NewUser(1337, fmt.Sprintf("user%02d@local", i), fmt.Sprintf("user%02d", i)))
+	}
+	ownerIds := make([]string, numPosts)
+	for i := range ownerIds {
+		ownerIds[i] = fmt.Sprintf("user%02d@local", i%numOwners)
+	}
+
+	b.Run("Get", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			for _, id := range ownerIds {
+				if u, err := dao.Get(id); err != nil || u == nil {
+					b.Fatalf("%s failed: %#v / %s", "Get", u, err)
+				}
+			}
+		}
+	})
+	b.Run("GetMany", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			if userList, err := dao.GetMany(ownerIds); err != nil || len(userList) != numOwners {
+				b.Fatalf("%s failed: %#v / %s", "GetMany", len(userList), err)
+			}
+		}
+	})
+}
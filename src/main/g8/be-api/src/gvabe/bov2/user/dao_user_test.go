@@ -216,3 +216,45 @@ func doTestUserDaoGetN(t *testing.T, name string, dao UserDao) {
 		t.Fatalf("%s failed: expected %#v but received %#v (error %s)", name+"/GetN", 5, len(userList), err)
 	}
 }
+
+func doTestUserDaoGetMany(t *testing.T, name string, dao UserDao) {
+	initSampleRows(t, name, dao)
+	// more ids than a chunk, including missing and duplicated ones
+	ids := make([]string, 0)
+	for i := numSampleRows + 4; i >= 0; i-- {
+		ids = append(ids, fmt.Sprintf("%03d@local", i), fmt.Sprintf("%03d@local", i/2))
+	}
+	userList, err := dao.GetMany(ids)
+	if err != nil || len(userList) != numSampleRows {
+		t.Fatalf("%s failed: expected %#v but received %#v (error %s)", name+"/GetMany", numSampleRows, len(userList), err)
+	}
+	seen := make(map[string]bool)
+	for _, u := range userList {
+		if seen[u.GetId()] {
+			t.Fatalf("%s failed: duplicated user %#v", name+"/GetMany", u.GetId())
+		}
+		seen[u.GetId()] = true
+		if expected := "Administrator" + u.GetId()[:3]; u.GetDisplayName() != expected {
+			t.Fatalf("%s failed: expected %#v but received %#v", name+"/GetMany", expected, u.GetDisplayName())
+		}
+	}
+}
+
+func TestChunkIds(t *testing.T) {
+	name := "TestChunkIds"
+	testCases := []struct {
+		ids      []string
+		size     int
+		expected string
+	}{
+		{nil, 2, "[]"},
+		{[]string{"", ""}, 2, "[]"},
+		{[]string{"a", "b", "c"}, 3, "[[a b c]]"},
+		{[]string{"a", "b", "a", "", "c", "d", "b", "e"}, 2, "[[a b] [c d] [e]]"},
+	}
+	for i, testCase := range testCases {
+		if chunks := fmt.Sprintf("%v", _chunkIds(testCase.ids, testCase.size)); chunks != testCase.expected {
+			t.Fatalf("%s failed: [%d] expected %#v but received %#v", name, i, testCase.expected, chunks)
+		}
+	}
+}
@@ -0,0 +1,562 @@
+package gvabetest
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"main/src/gvabe"
+	"main/src/gvabe/bov2/blog"
+	"main/src/logging"
+)
+
+var testServer *Server
+
+var (
+	testUserAlice = User{Id: "alice@local", Password: "alice-pwd", Name: "Alice"}
+	testUserBob   = User{Id: "bob@local", Password: "bob-pwd", Name: "Bob"}
+)
+
+// contendedPosts holds ids of blog posts that are "under contention": writes of these posts fail as if concurrent writes
+// exhausted all attempts (see contendedPostDao).
+var contendedPosts sync.Map
+
+// contendedPostDao decorates the application's BlogPostDao, failing writes of posts in contendedPosts with
+// blog.ErrPostConflict.
+type contendedPostDao struct {
+	blog.BlogPostDao
+}
+
+func (dao *contendedPostDao) UpdateIfVersion(post *blog.BlogPost, version string) (bool, error) {
+	if _, ok := contendedPosts.Load(post.GetId()); ok {
+		return false, blog.ErrPostConflict
+	}
+	return dao.BlogPostDao.UpdateIfVersion(post, version)
+}
+
+func (dao *contendedPostDao) DeleteIfVersion(post *blog.BlogPost, version string) (bool, error) {
+	if _, ok := contendedPosts.Load(post.GetId()); ok {
+		return false, blog.ErrPostConflict
+	}
+	return dao.BlogPostDao.DeleteIfVersion(post, version)
+}
+
+// contendedPostVoter decorates the application's BlogPostVoter, failing votes for posts in contendedPosts with
+// blog.ErrVoteConflict.
+type contendedPostVoter struct {
+	blog.BlogPostVoter
+}
+
+func (voter *contendedPostVoter) Vote(vote *blog.BlogVote) (*blog.BlogVote, *blog.BlogPost, error) {
+	if _, ok := contendedPosts.Load(vote.GetTargetId()); ok {
+		return nil, nil, blog.ErrVoteConflict
+	}
+	return voter.BlogPostVoter.Vote(vote)
+}
+
+func TestMain(m *testing.M) {
+	gvabe.DecorateBlogPostDao(func(dao blog.BlogPostDao) blog.BlogPostDao { return &contendedPostDao{BlogPostDao: dao} })
+	gvabe.DecorateBlogPostVoter(func(voter blog.BlogPostVoter) blog.BlogPostVoter { return &contendedPostVoter{BlogPostVoter: voter} })
+	var err error
+	if testServer, err = Start(testUserAlice, testUserBob); err != nil {
+		fmt.Fprintf(os.Stderr, "Cannot start application: %s\n", err)
+		os.Exit(1)
+	}
+	code := m.Run()
+	testServer.Stop()
+	os.Exit(code)
+}
+
+func _login(t *testing.T, name string, u User) string {
+	token, err := testServer.Login(u.Id, u.Password)
+	if err != nil {
+		t.Fatalf("%s failed: %s", name, err)
+	}
+	return token
+}
+
+func _call(t *testing.T, name, method, path, token string, body interface{}) *Result {
+	result, err := testServer.Call(method, path, token, body)
+	if err != nil {
+		t.Fatalf("%s failed: %s", name, err)
+	}
+	return result
+}
+
+func _expectStatus(t *testing.T, name string, expected int, result *Result) {
+	if result.Status != expected {
+		t.Fatalf("%s failed: expected status %#v but received %#v (%s)", name, expected, result.Status, result.Message)
+	}
+}
+
+// _createPost creates a blog post and returns its id.
+func _createPost(t *testing.T, name, token string, isPublic bool) string {
+	title := fmt.Sprintf("%s - %d", name, time.Now().UnixNano())
+	result := _call(t, name, "POST", "/api/myblog", token, map[string]interface{}{"title": title, "content": "Content of " + title, "is_public": isPublic})
+	_expectStatus(t, name+"/createBlogPost", 200, result)
+	result = _call(t, name, "GET", "/api/myblog", token, nil)
+	_expectStatus(t, name+"/myBlog", 200, result)
+	for _, item := range result.DataList() {
+		if post, _ := item.(map[string]interface{}); post != nil && post["title"] == title {
+			return post["id"].(string)
+		}
+	}
+	t.Fatalf("%s failed: cannot find post [%s] in my blog", name, title)
+	return ""
+}
+
+/*----------------------------------------------------------------------*/
+
+func TestLogin(t *testing.T) {
+	name := "TestLogin"
+	if _, err := testServer.Login(AdminUserId, AdminUserPwd); err != nil {
+		t.Fatalf("%s failed: %s", name, err)
+	}
+	_login(t, name, testUserAlice)
+
+	testCases := []struct {
+		username, password string
+	}{
+		{testUserAlice.Id, "wrong-pwd"},
+		{"nobody@local", "pwd"},
+		{testUserAlice.Id, ""},
+		{"", testUserAlice.Password},
+	}
+	for _, tc := range testCases {
+		result := _call(t, name, "POST", "/api/login", "", map[string]interface{}{"username": tc.username, "password": tc.password, "mode": "form"})
+		_expectStatus(t, name+"/"+tc.username+":"+tc.password, 403, result)
+	}
+}
+
+func TestLogin_RequestLog(t *testing.T) {
+	name := "TestLogin_RequestLog"
+	// capture entries of the request logger, the buffer is written under the logger's lock until the writer is restored
+	buf := &bytes.Buffer{}
+	root := logging.Default()
+	level := root.GetLevel()
+	root.SetWriter(buf).SetLevel(logging.LevelInfo)
+	token, err := testServer.Login(testUserAlice.Id, testUserAlice.Password)
+	root.SetWriter(os.Stderr).SetLevel(level)
+	if err != nil {
+		t.Fatalf("%s failed: %s", name, err)
+	}
+
+	output := buf.String()
+	if !strings.Contains(output, "API call finished") || !strings.Contains(output, testUserAlice.Id) {
+		t.Fatalf("%s failed: login call not logged in %s", name, output)
+	}
+	for _, secret := range []string{token, testUserAlice.Password} {
+		if strings.Contains(output, secret) {
+			t.Fatalf("%s failed: sensitive value %#v should be masked in %s", name, secret, output)
+		}
+	}
+}
+
+func TestLogin_InvalidAppId(t *testing.T) {
+	name := "TestLogin_InvalidAppId"
+	params := map[string]interface{}{"username": testUserAlice.Id, "password": testUserAlice.Password, "mode": "form"}
+	result, err := testServer.CallWithAppId("invalid-app", "POST", "/api/login", "", params)
+	if err != nil {
+		t.Fatalf("%s failed: %s", name, err)
+	}
+	if result.Status == 200 {
+		t.Fatalf("%s failed: login with invalid app-id should fail", name)
+	}
+}
+
+func TestGrpc(t *testing.T) {
+	name := "TestGrpc"
+	result, err := testServer.CallGrpc("login", "", map[string]interface{}{"username": testUserBob.Id, "password": testUserBob.Password})
+	if err != nil {
+		t.Fatalf("%s failed: %s", name, err)
+	}
+	_expectStatus(t, name+"/login", 200, result)
+	token, _ := result.Data.(string)
+	if token == "" {
+		t.Fatalf("%s failed: no token returned", name)
+	}
+
+	if result, err = testServer.CallGrpc("myBlog", token, nil); err != nil {
+		t.Fatalf("%s failed: %s", name, err)
+	}
+	_expectStatus(t, name+"/myBlog", 200, result)
+
+	if result, err = testServer.CallGrpc("myBlog", "", nil); err != nil {
+		t.Fatalf("%s failed: %s", name, err)
+	}
+	_expectStatus(t, name+"/myBlog(no token)", 403, result)
+}
+
+func TestBlogCrud(t *testing.T) {
+	name := "TestBlogCrud"
+	token := _login(t, name, testUserAlice)
+	id := _createPost(t, name, token, false)
+
+	result := _call(t, name, "GET", "/api/post/"+id, token, nil)
+	_expectStatus(t, name+"/getBlogPost", 200, result)
+	post := result.DataMap()
+	if post["owner_id"] != testUserAlice.Id || post["is_public"] != false || post["version"] == "" {
+		t.Fatalf("%s failed: unexpected post %#v", name, post)
+	}
+	version := post["version"]
+
+	result = _call(t, name, "PUT", "/api/post/"+id, token, map[string]interface{}{"title": "new title", "content": "new content", "is_public": true, "version": version})
+	_expectStatus(t, name+"/updateBlogPost", 200, result)
+	newVersion := result.DataMap()["version"]
+	if newVersion == "" || newVersion == version {
+		t.Fatalf("%s failed: expected a new version but received %#v", name, newVersion)
+	}
+	result = _call(t, name, "GET", "/api/post/"+id, token, nil)
+	_expectStatus(t, name+"/getBlogPost", 200, result)
+	post = result.DataMap()
+	if post["title"] != "new title" || post["content"] != "new content" || post["is_public"] != true || post["version"] != newVersion {
+		t.Fatalf("%s failed: unexpected post %#v", name, post)
+	}
+
+	result = _call(t, name, "PUT", "/api/post/"+id, token, map[string]interface{}{"title": "", "content": "new content", "version": newVersion})
+	_expectStatus(t, name+"/updateBlogPost(empty title)", 400, result)
+
+	result = _call(t, name, "DELETE", "/api/post/"+id, token, map[string]interface{}{"version": newVersion})
+	_expectStatus(t, name+"/deleteBlogPost", 200, result)
+	result = _call(t, name, "GET", "/api/post/"+id, token, nil)
+	_expectStatus(t, name+"/getBlogPost(deleted)", 404, result)
+	result = _call(t, name, "DELETE", "/api/post/"+id, token, map[string]interface{}{"version": newVersion})
+	_expectStatus(t, name+"/deleteBlogPost(deleted)", 404, result)
+}
+
+func TestBlogPost_VersionConflict(t *testing.T) {
+	name := "TestBlogPost_VersionConflict"
+	token := _login(t, name, testUserAlice)
+	id := _createPost(t, name, token, false)
+	result := _call(t, name, "GET", "/api/post/"+id, token, nil)
+	_expectStatus(t, name+"/getBlogPost", 200, result)
+	version := result.DataMap()["version"]
+
+	result = _call(t, name, "PUT", "/api/post/"+id, token, map[string]interface{}{"title": "title", "content": "content"})
+	_expectStatus(t, name+"/updateBlogPost(no version)", 400, result)
+	result = _call(t, name, "DELETE", "/api/post/"+id, token, nil)
+	_expectStatus(t, name+"/deleteBlogPost(no version)", 400, result)
+
+	// two editors of the same version: the first one wins, the second one gets a conflict with the current version
+	result = _call(t, name, "PUT", "/api/post/"+id, token, map[string]interface{}{"title": "first", "content": "first", "version": version})
+	_expectStatus(t, name+"/updateBlogPost(first)", 200, result)
+	currentVersion := result.DataMap()["version"]
+	result = _call(t, name, "PUT", "/api/post/"+id, token, map[string]interface{}{"title": "second", "content": "second", "version": version})
+	_expectStatus(t, name+"/updateBlogPost(stale)", 409, result)
+	if data := result.DataMap(); data["version"] != currentVersion {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, currentVersion, data["version"])
+	}
+	result = _call(t, name, "DELETE", "/api/post/"+id, token, map[string]interface{}{"version": version})
+	_expectStatus(t, name+"/deleteBlogPost(stale)", 409, result)
+
+	result = _call(t, name, "GET", "/api/post/"+id, token, nil)
+	_expectStatus(t, name+"/getBlogPost", 200, result)
+	if post := result.DataMap(); post["title"] != "first" || post["version"] != currentVersion {
+		t.Fatalf("%s failed: unexpected post %#v", name, post)
+	}
+
+	// saving again with the version returned by the conflict overwrites the post
+	result = _call(t, name, "PUT", "/api/post/"+id, token, map[string]interface{}{"title": "second", "content": "second", "version": currentVersion})
+	_expectStatus(t, name+"/updateBlogPost(retry)", 200, result)
+	result = _call(t, name, "DELETE", "/api/post/"+id, token, map[string]interface{}{"version": result.DataMap()["version"]})
+	_expectStatus(t, name+"/deleteBlogPost", 200, result)
+}
+
+func TestBlogPost_VoteBetweenLoadAndSave(t *testing.T) {
+	name := "TestBlogPost_VoteBetweenLoadAndSave"
+	tokenAlice := _login(t, name, testUserAlice)
+	tokenBob := _login(t, name, testUserBob)
+	id := _createPost(t, name, tokenAlice, true)
+	result := _call(t, name, "GET", "/api/post/"+id, tokenAlice, nil)
+	_expectStatus(t, name+"/getBlogPost", 200, result)
+	version := result.DataMap()["version"]
+
+	// votes change the post's counters but not its content, hence are not conflicts
+	result = _call(t, name, "POST", "/api/vote/"+id, tokenBob, map[string]interface{}{"vote": 1})
+	_expectStatus(t, name+"/voteForPost", 200, result)
+	result = _call(t, name, "GET", "/api/post/"+id, tokenAlice, nil)
+	_expectStatus(t, name+"/getBlogPost", 200, result)
+	if post := result.DataMap(); post["version"] != version {
+		t.Fatalf("%s failed: expected %#v but received %#v", name+"/getBlogPost", version, post["version"])
+	}
+
+	result = _call(t, name, "PUT", "/api/post/"+id, tokenAlice, map[string]interface{}{"title": "edited", "content": "edited", "is_public": true, "version": version})
+	_expectStatus(t, name+"/updateBlogPost", 200, result)
+	version = result.DataMap()["version"]
+	result = _call(t, name, "GET", "/api/post/"+id, tokenAlice, nil)
+	_expectStatus(t, name+"/getBlogPost", 200, result)
+	if post := result.DataMap(); post["title"] != "edited" || post["num_votes_up"] != float64(1) {
+		t.Fatalf("%s failed: unexpected post %#v", name, post)
+	}
+
+	result = _call(t, name, "POST", "/api/vote/"+id, tokenBob, map[string]interface{}{"vote": -1})
+	_expectStatus(t, name+"/voteForPost", 200, result)
+	result = _call(t, name, "DELETE", "/api/post/"+id, tokenAlice, map[string]interface{}{"version": version})
+	_expectStatus(t, name+"/deleteBlogPost", 200, result)
+}
+
+func TestBlogPost_WriteContention(t *testing.T) {
+	name := "TestBlogPost_WriteContention"
+	token := _login(t, name, testUserAlice)
+	id := _createPost(t, name, token, true)
+	result := _call(t, name, "GET", "/api/post/"+id, token, nil)
+	_expectStatus(t, name+"/getBlogPost", 200, result)
+	version := result.DataMap()["version"]
+
+	// too many concurrent writes is not a server fault: the client gets a conflict with the current version and may retry
+	contendedPosts.Store(id, true)
+	result = _call(t, name, "PUT", "/api/post/"+id, token, map[string]interface{}{"title": "edited", "content": "edited", "version": version})
+	_expectStatus(t, name+"/updateBlogPost(contention)", 409, result)
+	if data := result.DataMap(); data["version"] != version || data["post"] == nil {
+		t.Fatalf("%s failed: unexpected result data %#v", name+"/updateBlogPost(contention)", data)
+	}
+	result = _call(t, name, "DELETE", "/api/post/"+id, token, map[string]interface{}{"version": version})
+	_expectStatus(t, name+"/deleteBlogPost(contention)", 409, result)
+	grpcResult, err := testServer.CallGrpc("updateBlogPost", token, map[string]interface{}{"id": id, "title": "edited", "content": "edited", "version": version})
+	if err != nil {
+		t.Fatalf("%s failed: %s", name+"/updateBlogPost(gRPC)", err)
+	}
+	_expectStatus(t, name+"/updateBlogPost(gRPC)", 409, grpcResult)
+
+	contendedPosts.Delete(id)
+	result = _call(t, name, "PUT", "/api/post/"+id, token, map[string]interface{}{"title": "edited", "content": "edited", "is_public": true, "version": version})
+	_expectStatus(t, name+"/updateBlogPost(retry)", 200, result)
+	result = _call(t, name, "DELETE", "/api/post/"+id, token, map[string]interface{}{"version": result.DataMap()["version"]})
+	_expectStatus(t, name+"/deleteBlogPost", 200, result)
+}
+
+func TestBlogPermission(t *testing.T) {
+	name := "TestBlogPermission"
+	tokenAlice := _login(t, name, testUserAlice)
+	tokenBob := _login(t, name, testUserBob)
+	privateId := _createPost(t, name, tokenAlice, false)
+	publicId := _createPost(t, name, tokenAlice, true)
+
+	result := _call(t, name, "POST", "/api/myblog", "", map[string]interface{}{"title": "title", "content": "content"})
+	_expectStatus(t, name+"/createBlogPost(no token)", 403, result)
+	result = _call(t, name, "POST", "/api/myblog", "invalid-token", map[string]interface{}{"title": "title", "content": "content"})
+	_expectStatus(t, name+"/createBlogPost(invalid token)", 403, result)
+
+	result = _call(t, name, "GET", "/api/post/"+privateId, tokenBob, nil)
+	_expectStatus(t, name+"/getBlogPost(private)", 403, result)
+	result = _call(t, name, "GET", "/api/post/"+publicId, tokenBob, nil)
+	_expectStatus(t, name+"/getBlogPost(public)", 200, result)
+
+	for _, id := range []string{privateId, publicId} {
+		result = _call(t, name, "GET", "/api/post/"+id, tokenAlice, nil)
+		version := result.DataMap()["version"]
+		result = _call(t, name, "PUT", "/api/post/"+id, tokenBob, map[string]interface{}{"title": "hacked", "content": "hacked", "is_public": true, "version": version})
+		_expectStatus(t, name+"/updateBlogPost(not owner)", 403, result)
+		result = _call(t, name, "DELETE", "/api/post/"+id, tokenBob, map[string]interface{}{"version": version})
+		_expectStatus(t, name+"/deleteBlogPost(not owner)", 403, result)
+		result = _call(t, name, "GET", "/api/post/"+id, tokenAlice, nil)
+		_expectStatus(t, name+"/getBlogPost(owner)", 200, result)
+		if post := result.DataMap(); post["title"] == "hacked" {
+			t.Fatalf("%s failed: post [%s] must not be modified by other user", name, id)
+		}
+	}
+}
+
+func TestMyFeed_Owners(t *testing.T) {
+	name := "TestMyFeed_Owners"
+	tokenAlice := _login(t, name, testUserAlice)
+	tokenBob := _login(t, name, testUserBob)
+	idAlice := _createPost(t, name, tokenAlice, true)
+	idBob := _createPost(t, name, tokenBob, true)
+
+	// owners of all posts in the feed are resolved (in one batch)
+	result := _call(t, name, "GET", "/api/myfeed", tokenAlice, nil)
+	_expectStatus(t, name+"/myFeed", 200, result)
+	expected := map[string]string{idAlice: testUserAlice.Name, idBob: testUserBob.Name}
+	for _, item := range result.DataList() {
+		post, _ := item.(map[string]interface{})
+		owner, _ := post["owner"].(map[string]interface{})
+		if owner == nil || owner["id"] != post["owner_id"] {
+			t.Fatalf("%s failed: owner of post %#v not resolved", name, post["id"])
+		}
+		if displayName, ok := expected[post["id"].(string)]; ok {
+			if owner["display_name"] != displayName {
+				t.Fatalf("%s failed: expected %#v but received %#v", name, displayName, owner["display_name"])
+			}
+			delete(expected, post["id"].(string))
+		}
+	}
+	if len(expected) != 0 {
+		t.Fatalf("%s failed: posts %#v not found in feed", name, expected)
+	}
+}
+
+func TestVote(t *testing.T) {
+	name := "TestVote"
+	tokenAlice := _login(t, name, testUserAlice)
+	tokenBob := _login(t, name, testUserBob)
+	tokenAdmin := _login(t, name, User{Id: AdminUserId, Password: AdminUserPwd})
+	postId := _createPost(t, name, tokenAlice, true)
+
+	testCases := []struct {
+		token            string
+		vote             int
+		expectedValue    int
+		expectedVoteUp   int
+		expectedVoteDown int
+	}{
+		{tokenBob, 1, 1, 1, 0},
+		{tokenAdmin, 1, 1, 2, 0},
+		{tokenBob, 1, 0, 1, 0},   // same vote again cancels it
+		{tokenBob, -1, -1, 1, 1}, // cancelled vote then down-vote
+		{tokenAdmin, -5, -1, 0, 2},
+	}
+	for i, tc := range testCases {
+		caseName := fmt.Sprintf("%s/%d", name, i)
+		result := _call(t, caseName, "POST", "/api/vote/"+postId, tc.token, map[string]interface{}{"vote": tc.vote})
+		_expectStatus(t, caseName, 200, result)
+		data := result.DataMap()
+		expected := map[string]interface{}{"vote": true, "value": float64(tc.expectedValue),
+			"num_votes_up": float64(tc.expectedVoteUp), "num_votes_down": float64(tc.expectedVoteDown)}
+		for k, v := range expected {
+			if data[k] != v {
+				t.Fatalf("%s failed: expected %#v for [%s] but received %#v", caseName, v, k, data[k])
+			}
+		}
+		result = _call(t, caseName, "GET", "/api/vote/"+postId, tc.token, nil)
+		_expectStatus(t, caseName, 200, result)
+		if result.Data != float64(tc.expectedValue) {
+			t.Fatalf("%s failed: expected %#v but received %#v", caseName, tc.expectedValue, result.Data)
+		}
+	}
+
+	result := _call(t, name, "GET", "/api/post/"+postId, tokenAlice, nil)
+	_expectStatus(t, name+"/getBlogPost", 200, result)
+	if post := result.DataMap(); post["num_votes_up"] != float64(0) || post["num_votes_down"] != float64(2) {
+		t.Fatalf("%s failed: unexpected vote counters %#v/%#v", name, post["num_votes_up"], post["num_votes_down"])
+	}
+}
+
+func TestVote_Concurrent(t *testing.T) {
+	name := "TestVote_Concurrent"
+	tokens := []string{_login(t, name, testUserAlice), _login(t, name, testUserBob), _login(t, name, User{Id: AdminUserId, Password: AdminUserPwd})}
+	postId := _createPost(t, name, tokens[0], true)
+
+	// users vote concurrently; each one ends up with an up-vote (vote, cancel, vote...)
+	const numVotesPerUser = 5
+	var wg sync.WaitGroup
+	errs := make(chan error, len(tokens)*numVotesPerUser)
+	for _, token := range tokens {
+		wg.Add(1)
+		go func(token string) {
+			defer wg.Done()
+			for i := 0; i < numVotesPerUser; i++ {
+				if result, err := testServer.Call("POST", "/api/vote/"+postId, token, map[string]interface{}{"vote": 1}); err != nil {
+					errs <- err
+				} else if result.Status != 200 {
+					errs <- fmt.Errorf("status %d (%s)", result.Status, result.Message)
+				}
+			}
+		}(token)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("%s failed: %s", name+"/voteForPost", err)
+	}
+	result := _call(t, name, "GET", "/api/post/"+postId, tokens[0], nil)
+	_expectStatus(t, name+"/getBlogPost", 200, result)
+	if post := result.DataMap(); post["num_votes_up"] != float64(len(tokens)) || post["num_votes_down"] != float64(0) {
+		t.Fatalf("%s failed: unexpected vote counters %#v/%#v", name, post["num_votes_up"], post["num_votes_down"])
+	}
+}
+
+func TestVote_Contention(t *testing.T) {
+	name := "TestVote_Contention"
+	tokenAlice := _login(t, name, testUserAlice)
+	tokenBob := _login(t, name, testUserBob)
+	postId := _createPost(t, name, tokenAlice, true)
+
+	// too many concurrent writes is not a server fault: the client gets a conflict and may vote again
+	contendedPosts.Store(postId, true)
+	result := _call(t, name, "POST", "/api/vote/"+postId, tokenBob, map[string]interface{}{"vote": 1})
+	_expectStatus(t, name+"/voteForPost(contention)", 409, result)
+	contendedPosts.Delete(postId)
+	result = _call(t, name, "POST", "/api/vote/"+postId, tokenBob, map[string]interface{}{"vote": 1})
+	_expectStatus(t, name+"/voteForPost(retry)", 200, result)
+	if data := result.DataMap(); data["value"] != float64(1) || data["num_votes_up"] != float64(1) {
+		t.Fatalf("%s failed: unexpected result data %#v", name+"/voteForPost(retry)", data)
+	}
+}
+
+func TestVote_Permission(t *testing.T) {
+	name := "TestVote_Permission"
+	tokenAlice := _login(t, name, testUserAlice)
+	tokenBob := _login(t, name, testUserBob)
+	privateId := _createPost(t, name, tokenAlice, false)
+
+	result := _call(t, name, "POST", "/api/vote/"+privateId, tokenBob, map[string]interface{}{"vote": 1})
+	_expectStatus(t, name+"/voteForPost(private)", 403, result)
+	result = _call(t, name, "POST", "/api/vote/"+privateId, "", map[string]interface{}{"vote": 1})
+	_expectStatus(t, name+"/voteForPost(no token)", 403, result)
+	result = _call(t, name, "POST", "/api/vote/not-exist", tokenBob, map[string]interface{}{"vote": 1})
+	_expectStatus(t, name+"/voteForPost(not exist)", 404, result)
+
+	result = _call(t, name, "POST", "/api/vote/"+privateId, tokenAlice, map[string]interface{}{"vote": 1})
+	_expectStatus(t, name+"/voteForPost(owner)", 200, result)
+	result = _call(t, name, "GET", "/api/post/"+privateId, tokenAlice, nil)
+	if post := result.DataMap(); post["num_votes_up"] != float64(1) {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, float64(1), post["num_votes_up"])
+	}
+}
+
+func TestSystemInfo_DaoCache(t *testing.T) {
+	name := "TestSystemInfo_DaoCache"
+	token := _login(t, name, testUserAlice)
+	_createPost(t, name, token, true)
+	result := _call(t, name, "GET", "/api/systemInfo", token, nil)
+	_expectStatus(t, name, 200, result)
+	stats, _ := result.DataMap()["dao_cache"].(map[string]interface{})
+	for _, k := range []string{"hits", "misses", "hit_ratio", "sets", "invalidations", "errors", "evictions", "entries"} {
+		if _, ok := stats[k]; !ok {
+			t.Fatalf("%s failed: metric [%s] not found in %#v", name, k, result.DataMap())
+		}
+	}
+}
+
+func TestReloadConfig_InFlight(t *testing.T) {
+	name := "TestReloadConfig_InFlight"
+	token := _login(t, name, testUserAlice)
+	tokenAdmin := _login(t, name, User{Id: AdminUserId, Password: AdminUserPwd})
+
+	// API calls read configurations and i18n bundles while they are being reloaded (run with -race to detect data races)
+	const numCallers, numCallsPerCaller = 4, 10
+	var wg sync.WaitGroup
+	errs := make(chan error, numCallers*numCallsPerCaller)
+	for i := 0; i < numCallers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < numCallsPerCaller; j++ {
+				if result, err := testServer.Call("GET", "/info", "", nil); err != nil {
+					errs <- err
+				} else if result.Status != 200 {
+					errs <- fmt.Errorf("info: status %d (%s)", result.Status, result.Message)
+				}
+				if result, err := testServer.Call("GET", "/api/post/not-exist", token, nil); err != nil {
+					errs <- err
+				} else if result.Status != 404 || result.Message == "" {
+					errs <- fmt.Errorf("getBlogPost: status %d (%s)", result.Status, result.Message)
+				}
+			}
+		}()
+	}
+	for i := 0; i < 3; i++ {
+		result := _call(t, name, "POST", "/api/admin/reload_config", tokenAdmin, nil)
+		_expectStatus(t, name+"/reloadConfig", 200, result)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("%s failed: %s", name, err)
+	}
+}
@@ -0,0 +1,322 @@
+/*
+Package gvabetest provides a harness to run end-to-end tests against the whole application in-process.
+
+  - Start boots goapi and the gvabe bootstrapper with a test configuration, backed by an SQLite database in a temp directory.
+  - The HTTP gateway is served by an httptest.Server, the gRPC gateway by an in-memory bufconn listener.
+  - Helpers log in and call APIs via either gateway, returning the decoded API result.
+
+The application can be started only once per process, hence a test package usually starts it in TestMain and shares it
+between tests.
+
+@author Thanh Nguyen <btnguyen2k@gmail.com>
+@since template-v0.5.0
+*/
+package gvabetest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	pb "main/grpc"
+	"main/src/goapi"
+	"main/src/gvabe"
+)
+
+const (
+	// AdminUserId is the id of the admin account created when the application initializes its database.
+	AdminUserId = "admin@local"
+
+	// AdminUserPwd is the password of the admin account.
+	AdminUserPwd = "s3cr3t"
+
+	bufconnSize = 1024 * 1024
+)
+
+// User is an account to be created before the application starts (see Start).
+type User struct {
+	Id       string
+	Password string
+	Name     string
+	Admin    bool
+}
+
+// Result is the decoded result of an API call.
+type Result struct {
+	HttpStatus int                    // HTTP status code (HTTP gateway only)
+	Status     int                    // API result's status
+	Message    string                 // API result's message
+	Data       interface{}            // API result's data, decoded from JSON
+	Raw        map[string]interface{} // the whole response body, decoded from JSON (HTTP gateway only)
+}
+
+// DataMap returns the result's data as a map (nil if data is not a JSON object).
+func (r *Result) DataMap() map[string]interface{} {
+	m, _ := r.Data.(map[string]interface{})
+	return m
+}
+
+// DataList returns the result's data as a list (nil if data is not a JSON array).
+func (r *Result) DataList() []interface{} {
+	l, _ := r.Data.([]interface{})
+	return l
+}
+
+// Server is the application started in-process by Start.
+type Server struct {
+	BaseDir    string               // temp directory holding configurations and the SQLite database
+	AppId      string               // app-id to send along with API calls
+	HttpServer *httptest.Server     // serves the HTTP gateway
+	GrpcConn   *grpc.ClientConn     // connection to the gRPC gateway
+	Grpc       pb.PApiServiceClient // client of the gRPC gateway
+	HttpClient *http.Client         // client to call the HTTP gateway
+	listener   *bufconn.Listener
+}
+
+// _repoDir returns the application's root directory (where directory "config" resides).
+func _repoDir() (string, error) {
+	_, file, _, ok := runtime.Caller(0)
+	if !ok {
+		return "", errors.New("cannot determine location of package gvabetest")
+	}
+	return filepath.Abs(filepath.Join(filepath.Dir(file), "..", "..", ".."))
+}
+
+// _copyDir copies directory src (recursively) to dst.
+func _copyDir(src, dst string) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if info.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return ioutil.WriteFile(target, data, info.Mode())
+	})
+}
+
+// _writeTestConfig copies the application's configurations to baseDir and writes the test configuration file, which
+// includes the application's configurations and overrides settings that must not depend on the environment.
+func _writeTestConfig(baseDir string) (string, error) {
+	repoDir, err := _repoDir()
+	if err != nil {
+		return "", err
+	}
+	configDir := filepath.Join(baseDir, "config")
+	if err := _copyDir(filepath.Join(repoDir, "config"), configDir); err != nil {
+		return "", err
+	}
+	overrides := []string{
+		`include "application.conf"`,
+		`timezone = "UTC"`,
+		`logging.level = "warn"`,
+		// request log entries are written at level info, hence not written out unless a test lowers the level
+		`logging.request_log = true`,
+		`api.config_reload.watch_interval = 0`,
+		`api.config_reload.sighup = false`,
+		`gvabe.init.admin_user_id = ` + strconv.Quote(AdminUserId),
+		`gvabe.init.admin_user_pwd = ` + strconv.Quote(AdminUserPwd),
+		`gvabe.db.type = "sqlite"`,
+		`gvabe.db.auto_migrate = true`,
+		`gvabe.db.sqlite.directory = ` + strconv.Quote(filepath.Join(baseDir, "data", "sqlite")),
+		`gvabe.exter.app_id = ""`,
+		`gvabe.keys.rsa_privkey_file = ` + strconv.Quote(filepath.Join(configDir, "keys", "gva_priv.pem")),
+		`gvabe.i18n.i18n_file_or_directory = ` + strconv.Quote(filepath.Join(configDir, "conf.d", "i18n", "all_in_one.lang.yaml")),
+	}
+	configFile := filepath.Join(configDir, "test.conf")
+	return configFile, ioutil.WriteFile(configFile, []byte(strings.Join(overrides, "\n")+"\n"), 0644)
+}
+
+// _runCommand runs an application command (see goapi.RunCommand) and reports non-zero exit code as error.
+func _runCommand(args ...string) error {
+	if code := goapi.RunCommand(args, gvabe.Bootstrapper); code != 0 {
+		return fmt.Errorf("command %v exited with code %d", args, code)
+	}
+	return nil
+}
+
+// Start boots the application in-process with a test configuration, backed by a fresh SQLite database in a temp directory.
+// Users are created (via command "user create") before the application starts.
+//
+// Env APP_CONFIG is set to the test configuration file. Call Server.Stop to shut down the application and clean up.
+func Start(users ...User) (*Server, error) {
+	baseDir, err := ioutil.TempDir("", "gvabetest")
+	if err != nil {
+		return nil, err
+	}
+	server, err := _start(baseDir, users)
+	if err != nil {
+		os.RemoveAll(baseDir)
+	}
+	return server, err
+}
+
+func _start(baseDir string, users []User) (*Server, error) {
+	configFile, err := _writeTestConfig(baseDir)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Setenv("APP_CONFIG", configFile); err != nil {
+		return nil, err
+	}
+	if len(users) > 0 {
+		if err := _runCommand("db", "init"); err != nil {
+			return nil, err
+		}
+		for _, u := range users {
+			args := []string{"user", "create", u.Id, "--password", u.Password}
+			if u.Name != "" {
+				args = append(args, "--name", u.Name)
+			}
+			if u.Admin {
+				args = append(args, "--admin")
+			}
+			if err := _runCommand(args...); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	server := &Server{BaseDir: baseDir, listener: bufconn.Listen(bufconnSize)}
+	handler := goapi.StartEmbedded(server.listener, gvabe.Bootstrapper)
+	server.AppId = goapi.AppConfig().GetString("app.shortname") + "_fe"
+	server.HttpServer = httptest.NewServer(handler)
+	server.HttpClient = server.HttpServer.Client()
+	server.GrpcConn, err = grpc.Dial("bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return server.listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		server.Stop()
+		return nil, err
+	}
+	server.Grpc = pb.NewPApiServiceClient(server.GrpcConn)
+	return server, nil
+}
+
+// Stop shuts down the application and removes the temp directory.
+func (s *Server) Stop() {
+	if s.GrpcConn != nil {
+		s.GrpcConn.Close()
+	}
+	if s.HttpServer != nil {
+		s.HttpServer.Close()
+	}
+	goapi.StopEmbedded()
+	os.RemoveAll(s.BaseDir)
+}
+
+// Call invokes an API via the HTTP gateway. The access token is sent along if not empty; body (if not nil) is sent as JSON.
+func (s *Server) Call(method, path, token string, body interface{}) (*Result, error) {
+	return s.CallWithAppId(s.AppId, method, path, token, body)
+}
+
+// CallWithAppId is similar to Call, but sends appId instead of the application's app-id.
+func (s *Server) CallWithAppId(appId, method, path, token string, body interface{}) (*Result, error) {
+	var reqBody []byte
+	if body != nil {
+		var err error
+		if reqBody, err = json.Marshal(body); err != nil {
+			return nil, err
+		}
+	}
+	req, err := http.NewRequest(method, s.HttpServer.URL+path, bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(goapi.AppConfig().GetString("api.http.header_app_id"), appId)
+	if token != "" {
+		req.Header.Set(goapi.AppConfig().GetString("api.http.header_access_token"), token)
+	}
+	resp, err := s.HttpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	result := &Result{HttpStatus: resp.StatusCode}
+	if err := json.Unmarshal(respBody, &result.Raw); err != nil {
+		return nil, fmt.Errorf("cannot decode response [%s]: %s", respBody, err)
+	}
+	if status, ok := result.Raw["status"].(float64); ok {
+		result.Status = int(status)
+	}
+	result.Message, _ = result.Raw["message"].(string)
+	result.Data = result.Raw["data"]
+	return result, nil
+}
+
+// CallGrpc invokes an API via the gRPC gateway. The access token is sent along if not empty; params (if not nil) is sent as JSON.
+func (s *Server) CallGrpc(apiName, token string, params interface{}) (*Result, error) {
+	pctx := &pb.PApiContext{
+		ApiName: apiName,
+		ApiAuth: &pb.PApiAuth{AppId: s.AppId, AccessToken: token},
+	}
+	if params != nil {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return nil, err
+		}
+		pctx.ApiParams = &pb.PApiParams{Encoding: pb.PDataEncoding_JSON_STRING, ParamsData: data}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	presult, err := s.Grpc.Call(ctx, pctx)
+	if err != nil {
+		return nil, err
+	}
+	if presult.Encoding != pb.PDataEncoding_JSON_STRING {
+		return nil, fmt.Errorf("unexpected result encoding %s", presult.Encoding)
+	}
+	result := &Result{Status: int(presult.Status), Message: presult.Message}
+	if len(presult.ResultData) > 0 {
+		if err := json.Unmarshal(presult.ResultData, &result.Data); err != nil {
+			return nil, fmt.Errorf("cannot decode result data [%s]: %s", presult.ResultData, err)
+		}
+	}
+	return result, nil
+}
+
+// Login logs in with username/password via the HTTP gateway and returns the access token.
+func (s *Server) Login(username, password string) (string, error) {
+	result, err := s.Call("POST", "/api/login", "", map[string]interface{}{"username": username, "password": password, "mode": "form"})
+	if err != nil {
+		return "", err
+	}
+	if result.Status != 200 {
+		return "", fmt.Errorf("login failed: %d / %s", result.Status, result.Message)
+	}
+	token, _ := result.Data.(string)
+	if token == "" {
+		return "", fmt.Errorf("login failed: no token returned")
+	}
+	return token, nil
+}
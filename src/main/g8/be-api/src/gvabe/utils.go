@@ -3,6 +3,7 @@ package gvabe
 import (
 	"bytes"
 	"compress/zlib"
+	"context"
 	"crypto/rsa"
 	"crypto/sha1"
 	"encoding/hex"
@@ -27,7 +28,9 @@ var (
 	rsaPrivKey *rsa.PrivateKey
 	rsaPubKey  *rsa.PublicKey
 
-	i18n goyai.I18n
+	// i18nBundles is replaced as a whole when configurations are reloaded, read it via i18n()
+	i18nLock    sync.RWMutex
+	i18nBundles goyai.I18n
 
 	exterAppId   string
 	exterBaseUrl string
@@ -41,6 +44,24 @@ const (
 	loginSessionNearExpiry = 3600 * 3
 )
 
+// i18n returns the i18n bundles currently in effect.
+//
+// available since template-v0.5.0
+func i18n() goyai.I18n {
+	i18nLock.RLock()
+	defer i18nLock.RUnlock()
+	return i18nBundles
+}
+
+// setI18n replaces the i18n bundles in effect.
+//
+// available since template-v0.5.0
+func setI18n(bundles goyai.I18n) {
+	i18nLock.Lock()
+	defer i18nLock.Unlock()
+	i18nBundles = bundles
+}
+
 func encryptPassword(salt, rawPassword string) string {
 	out := sha1.Sum([]byte(salt + "." + rawPassword))
 	return strings.ToLower(hex.EncodeToString(out[:]))
@@ -144,10 +165,14 @@ func zipAndEncrypt(data []byte) ([]byte, error) {
 var muxSytemInfo sync.Mutex
 var systemInfoArr = make([]map[string]interface{}, 0)
 
-func routineUpdateSystemInfo() {
+func routineUpdateSystemInfo(ctx context.Context) {
 	for {
 		go doUpdateSystemInfo()
-		<-time.After(10 * time.Second)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(10 * time.Second):
+		}
 	}
 }
 
@@ -257,6 +282,10 @@ func doUpdateSystemInfo() {
 		}
 	}
 
+	if stats := daoCacheStats(); stats != nil {
+		data["dao_cache"] = stats
+	}
+
 	systemInfoArr = append(systemInfoArr, data)
 	if len(systemInfoArr) > 10 {
 		systemInfoArr[0] = nil
@@ -0,0 +1,143 @@
+package gvabe
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/btnguyen2k/consu/reddo"
+
+	"main/src/goapi"
+	"main/src/gvabe/bov2/audit"
+	"main/src/itineris"
+	"main/src/logging"
+)
+
+var (
+	auditLogger = logging.WithField("component", "audit")
+
+	// audited APIs, populated from config key "gvabe.audit.apis"
+	auditApis = map[string]bool{}
+
+	// names of params that hold id of the target object of an audited API call
+	auditTargetParams = []string{"id", "postId"}
+)
+
+// initAudit loads audit-trail settings and starts the retention-pruning job.
+//
+// available since template-v0.5.0
+func initAudit() {
+	if !goapi.AppConfig().GetBoolean("gvabe.audit.enabled", true) {
+		logging.Infof("Audit trail is disabled at [gvabe.audit.enabled].")
+		return
+	}
+	for _, api := range goapi.AppConfig().GetStringList("gvabe.audit.apis") {
+		auditApis[api] = true
+	}
+	if params := goapi.AppConfig().GetStringList("gvabe.audit.target_params"); len(params) > 0 {
+		auditTargetParams = params
+	}
+	retention := goapi.AppConfig().GetTimeDuration("gvabe.audit.retention", 90*24*time.Hour)
+	interval := goapi.AppConfig().GetTimeDuration("gvabe.audit.prune_interval", time.Hour)
+	if retention > 0 {
+		goapi.GoBackground("pruneAuditEvents", func(ctx context.Context) { goPruneAuditEvents(ctx, retention, interval) })
+	}
+}
+
+// goPruneAuditEvents periodically removes audit events older than the retention period.
+//
+// available since template-v0.5.0
+func goPruneAuditEvents(ctx context.Context, retention, interval time.Duration) {
+	if interval < time.Minute {
+		interval = time.Minute
+	}
+	for {
+		if n, err := pruneAuditEvents(retention); err != nil {
+			auditLogger.Errorf("Error pruning audit events: %s", err)
+		} else if n > 0 {
+			auditLogger.Infof("Pruned %d audit event(s) older than %s", n, retention)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+func pruneAuditEvents(retention time.Duration) (int, error) {
+	if auditEventDaov2 == nil {
+		return 0, nil
+	}
+	return auditEventDaov2.DeleteBefore(time.Now().Add(-retention))
+}
+
+/*----------------------------------------------------------------------*/
+
+/*
+AuditFilter records an audit event for each call to configured APIs.
+
+  - Recorded info: actor (logged-in user, or the "username" param for login attempts), API name, target id, client IP, outcome and timestamp.
+  - This filter should be placed before the authentication filter so that failed authentication attempts are also recorded.
+  - Failure to record an audit event is logged but does not affect the API result.
+
+Available since template-v0.5.0
+*/
+type AuditFilter struct {
+	*itineris.BaseApiFilter
+}
+
+/*
+Call implements IApiFilter.Call
+*/
+func (f *AuditFilter) Call(handler itineris.IApiHandler, ctx *itineris.ApiContext, auth *itineris.ApiAuth, params *itineris.ApiParams) *itineris.ApiResult {
+	var result *itineris.ApiResult
+	if f.NextFilter != nil {
+		result = f.NextFilter.Call(handler, ctx, auth, params)
+	} else {
+		result = handler(ctx, auth, params)
+	}
+	apiName := ctx.GetApiName()
+	if auditEventDaov2 != nil && auditApis[apiName] {
+		event := buildAuditEvent(apiName, ctx, params, result)
+		if _, err := auditEventDaov2.Create(event); err != nil {
+			itineris.ContextLogger(auditLogger, ctx).Errorf("Error recording audit event: %s", err)
+		}
+	}
+	return result
+}
+
+func buildAuditEvent(apiName string, ctx *itineris.ApiContext, params *itineris.ApiParams, result *itineris.ApiResult) *audit.AuditEvent {
+	actor := ""
+	if sessClaims, ok := ctx.GetContextValue(ctxFieldSession).(*SessionClaims); ok && sessClaims != nil {
+		actor = sessClaims.UserId
+	} else if v, _ := params.GetParamAsType("username", reddo.TypeString); v != nil {
+		actor = v.(string)
+	}
+	targetId := ""
+	for _, p := range auditTargetParams {
+		if v, _ := params.GetParamAsType(p, reddo.TypeString); v != nil && v.(string) != "" {
+			targetId = v.(string)
+			break
+		}
+	}
+	if targetId == "" && result != nil {
+		// e.g. id of the newly created object
+		if data, ok := result.Data.(map[string]interface{}); ok && data["id"] != nil {
+			targetId = fmt.Sprintf("%v", data["id"])
+		}
+	}
+	status, message := itineris.StatusErrorServer, ""
+	if result != nil {
+		status, message = result.Status, result.Message
+	}
+	clientIp := ctx.GetContextValueAsString(itineris.CtxClientRealAddr)
+	if clientIp == "" {
+		clientIp = ctx.GetContextValueAsString(itineris.CtxClientAddr)
+	}
+	return audit.NewAuditEvent(goapi.AppVersionNumber, actor, apiName, targetId, status).
+		SetClientIp(clientIp).
+		SetGateway(ctx.GetGateway()).
+		SetRequestId(ctx.GetId()).
+		SetMessage(message)
+}
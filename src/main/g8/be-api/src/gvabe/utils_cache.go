@@ -0,0 +1,101 @@
+package gvabe
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	hocon "github.com/go-akka/configuration"
+
+	"main/src/cache"
+	"main/src/goapi"
+	"main/src/gvabe/bov2/blog"
+	"main/src/gvabe/bov2/user"
+	"main/src/logging"
+)
+
+// DaoCacheFactory creates the cache used by read-through DAO decorators; conf is the application's configuration
+// (settings of the cache are at "gvabe.cache.*").
+//
+// available since template-v0.5.0
+type DaoCacheFactory func(conf *hocon.Config) (cache.Cache, error)
+
+// factories of caches, keyed by value of "gvabe.cache.storage"
+var daoCacheFactories = map[string]DaoCacheFactory{
+	"memory": newMemoryDaoCache,
+}
+
+// cache of DAO decorators created by initDaoCache, nil if caching is disabled
+var daoCache *cache.MeteredCache
+
+// RegisterDaoCache registers a cache storage (e.g. a shared cache backed by an external cache server), to be used
+// when "gvabe.cache.storage" is set to the specified name. It must be called before the application is bootstrapped.
+//
+// available since template-v0.5.0
+func RegisterDaoCache(storage string, factory DaoCacheFactory) {
+	daoCacheFactories[strings.ToLower(storage)] = factory
+}
+
+// newMemoryDaoCache creates an in-process LRU cache holding at most "gvabe.cache.size" entries.
+func newMemoryDaoCache(conf *hocon.Config) (cache.Cache, error) {
+	size := int(conf.GetInt32("gvabe.cache.size", 10000))
+	ttl := conf.GetTimeDuration("gvabe.cache.ttl", time.Minute)
+	return cache.NewLruCache(size, ttl), nil
+}
+
+// initDaoCache wraps userDaov2 and blogPostDaov2 with read-through caching decorators, configured via config keys
+// "gvabe.cache.*". DAOs are left untouched if caching is disabled.
+//
+// available since template-v0.5.0
+func initDaoCache() {
+	daoCache = nil
+	if !goapi.AppConfig().GetBoolean("gvabe.cache.enabled", true) {
+		logging.Infof("DAO cache is disabled at [gvabe.cache.enabled].")
+		return
+	}
+	storage := strings.ToLower(goapi.AppConfig().GetString("gvabe.cache.storage", "memory"))
+	factory, ok := daoCacheFactories[storage]
+	if !ok {
+		panic("unknown DAO cache storage: " + storage)
+	}
+	c, err := factory(goapi.AppConfig())
+	if err != nil {
+		panic(fmt.Sprintf("cannot create DAO cache [%s]: %s", storage, err))
+	}
+	ttl := goapi.AppConfig().GetTimeDuration("gvabe.cache.ttl", time.Minute)
+	daoCache = cache.NewMeteredCache(c)
+	userDaov2 = user.NewUserDaoCache(userDaov2, daoCache, ttl, user.TableUser+":")
+	blogPostDaov2 = blog.NewBlogPostDaoCache(blogPostDaov2, daoCache, ttl, blog.TableBlogPost+":")
+	logging.Infof("DAO cache is enabled (storage: %s, ttl: %s)", storage, ttl)
+}
+
+// evictCachedBlogPost removes a blog post from the DAO cache, to be called after the post was modified in storage
+// without going through blogPostDaov2 (e.g. by blogPostVoter).
+//
+// available since template-v0.5.0
+func evictCachedBlogPost(id string) {
+	if dao, ok := blogPostDaov2.(*blog.CachedBlogPostDao); ok {
+		dao.Evict(id)
+	}
+}
+
+// daoCacheStats returns metrics of the DAO cache, nil if caching is disabled.
+//
+// available since template-v0.5.0
+func daoCacheStats() map[string]interface{} {
+	if daoCache == nil {
+		return nil
+	}
+	stats := daoCache.Stats()
+	return map[string]interface{}{
+		"hits":          stats.Hits,
+		"misses":        stats.Misses,
+		"hit_ratio":     math.Floor(stats.HitRatio()*10000) / 10000,
+		"sets":          stats.Sets,
+		"invalidations": stats.Invalidations,
+		"errors":        stats.Errors,
+		"evictions":     stats.Evictions,
+		"entries":       stats.Entries,
+	}
+}
@@ -111,6 +111,21 @@ func genRsaKey(numBits int) (*rsa.PrivateKey, error) {
 	return rsa.GenerateKey(rand.Reader, numBits)
 }
 
+// encodeRsaPrivateKeyPem encodes an RSA private key to PEM (PKCS#1), encrypted with passphrase if it is not empty.
+// The result can be loaded via config keys "gvabe.keys.rsa_privkey_file" and "gvabe.keys.rsa_privkey_passphrase".
+//
+// available since template-v0.5.0
+func encodeRsaPrivateKeyPem(privKey *rsa.PrivateKey, passphrase string) ([]byte, error) {
+	block := &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privKey)}
+	if passphrase != "" {
+		var err error
+		if block, err = x509.EncryptPEMBlock(rand.Reader, block.Type, block.Bytes, []byte(passphrase), x509.PEMCipherAES256); err != nil {
+			return nil, err
+		}
+	}
+	return pem.EncodeToMemory(block), nil
+}
+
 // available since template-v0.2.0
 func parseRsaPublicKeyFromPem(pemStr string) (*rsa.PublicKey, error) {
 	block, _ := pem.Decode([]byte(pemStr))
@@ -0,0 +1,80 @@
+package gvabe
+
+import (
+	"main/src/goapi"
+	"main/src/gvabe/bov2/blog"
+	"main/src/itineris"
+)
+
+// Types of domain events published to goapi.EventBus.
+//
+// available since template-v0.5.0
+const (
+	EventPostCreated = "post.created"
+	EventPostUpdated = "post.updated"
+	EventPostDeleted = "post.deleted"
+	EventVoteChanged = "vote.changed"
+)
+
+const (
+	// event's data fields used to decide which subscribers can see the event
+	eventFieldIsPublic    = "is_public"
+	eventFieldPostOwnerId = "post_owner_id"
+)
+
+// publishEvent publishes a domain event to the application's event bus.
+//
+// available since template-v0.5.0
+func publishEvent(eventType string, data map[string]interface{}) {
+	if goapi.EventBus != nil {
+		goapi.EventBus.Publish(itineris.NewEvent(eventType, data))
+	}
+}
+
+// publishPostEvent publishes a "post.created/updated/deleted" event.
+//
+// available since template-v0.5.0
+func publishPostEvent(eventType string, post *blog.BlogPost) {
+	var data map[string]interface{}
+	if eventType == EventPostDeleted {
+		data = map[string]interface{}{"id": post.GetId(), "owner_id": post.GetOwnerId(), "is_public": post.IsPublic()}
+	} else {
+		data = post.ToMap(funcPostToMapTransform)
+	}
+	data[eventFieldPostOwnerId] = post.GetOwnerId()
+	publishEvent(eventType, data)
+}
+
+// publishVoteChangedEvent publishes a "vote.changed" event carrying the post's new vote counters (voter's identity is not revealed).
+//
+// available since template-v0.5.0
+func publishVoteChangedEvent(post *blog.BlogPost) {
+	publishEvent(EventVoteChanged, map[string]interface{}{
+		"post_id":             post.GetId(),
+		eventFieldIsPublic:    post.IsPublic(),
+		eventFieldPostOwnerId: post.GetOwnerId(),
+		"num_votes_up":        post.GetNumVotesUp(),
+		"num_votes_down":      post.GetNumVotesDown(),
+	})
+}
+
+// buildEventFilter builds the filter for an event subscriber, implementing goapi.EventFilterBuilder.
+//
+// The subscriber must be logged in; it sees events of public posts and of its own posts, until its login session expires.
+//
+// available since template-v0.5.0
+func buildEventFilter(ctx *itineris.ApiContext) itineris.EventFilter {
+	sessClaims, ok := ctx.GetContextValue(ctxFieldSession).(*SessionClaims)
+	if !ok || sessClaims == nil {
+		return func(_ *itineris.Event) bool { return false }
+	}
+	return func(event *itineris.Event) bool {
+		if sessClaims.isExpired() {
+			return false
+		}
+		if isPublic, ok := event.Data[eventFieldIsPublic].(bool); ok && isPublic {
+			return true
+		}
+		return event.Data[eventFieldPostOwnerId] == sessClaims.UserId
+	}
+}
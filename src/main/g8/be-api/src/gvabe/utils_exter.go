@@ -2,13 +2,13 @@ package gvabe
 
 import (
 	"bytes"
+	"context"
 	"crypto/rsa"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
-	"log"
 	"net/http"
 	"reflect"
 	"time"
@@ -18,12 +18,14 @@ import (
 
 	"main/src/goapi"
 	userv2 "main/src/gvabe/bov2/user"
+	"main/src/logging"
 	"main/src/utils"
 )
 
 var (
 	exterClient    *ExterClient
 	exterRsaPubKey *rsa.PublicKey
+	exterLogger    = logging.WithField("component", "exter")
 )
 
 // available since template-v0.2.0
@@ -118,28 +120,32 @@ func (er *ExterResponse) GetInt(path string) int {
 /*----------------------------------------------------------------------*/
 
 // available since template-v0.2.0
-func goFetchExterInfo(sleepSeconds int) {
+func goFetchExterInfo(ctx context.Context, sleepSeconds int) {
 	if sleepSeconds < 60 {
 		sleepSeconds = 60
 	}
 	for ; ; {
 		resp, err := exterClient.Info()
 		if err != nil {
-			log.Printf("[ERROR] goFetchExterInfo - Error calling Exter api: 0/%s", err)
+			exterLogger.Errorf("goFetchExterInfo - Error calling Exter api: 0/%s", err)
 		} else if resp.Status == 200 {
 			pubKeyPem := resp.GetString("data.rsa_public_key")
 			pubKey, err := parseRsaPublicKeyFromPem(pubKeyPem)
 			if err != nil {
-				log.Printf("[ERROR] goFetchExterInfo - Cannot extract Exter RSA public key: %e / %v", err, resp.raw)
+				exterLogger.Errorf("goFetchExterInfo - Cannot extract Exter RSA public key: %s / %v", err, resp.raw)
 			} else {
 				exterRsaPubKey = pubKey
-				log.Printf("[DEBUG_MODE] Exter public key: {Size: %d / Exponent: %d / Modulus: %x}",
+				exterLogger.Debugf("Exter public key: {Size: %d / Exponent: %d / Modulus: %x}",
 					exterRsaPubKey.Size()*8, exterRsaPubKey.E, exterRsaPubKey.N)
 			}
 		} else {
-			log.Printf("[ERROR] goFetchExterInfo - Error calling Exter api: %d / %s", resp.Status, resp.Message)
+			exterLogger.Errorf("goFetchExterInfo - Error calling Exter api: %d / %s", resp.Status, resp.Message)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Duration(sleepSeconds) * time.Second):
 		}
-		time.Sleep(time.Duration(sleepSeconds) * time.Second)
 	}
 }
 
@@ -180,7 +186,7 @@ func createUserFromExterToken(exterToken *ExterToken) (*userv2.User, error) {
 		return nil, errors.New(fmt.Sprintf("error while getting user [%s]: %e", exterToken.UserId, err))
 	}
 	if user == nil {
-		log.Printf("[INFO] Creating user [%s] from Exter token...", exterToken.UserId)
+		exterLogger.Infof("Creating user [%s] from Exter token...", exterToken.UserId)
 		user = userv2.NewUser(goapi.AppVersionNumber, exterToken.UserId, utils.UniqueId())
 		displayName := exterToken.UserName
 		if displayName == "" {
@@ -0,0 +1,50 @@
+package gvabe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"main/src/goapi"
+)
+
+// Names of health checks registered by initHealthChecks.
+const (
+	healthCheckDatabase = "database"
+	healthCheckKeys     = "keys"
+	healthCheckExter    = "exter"
+)
+
+// initHealthChecks registers health checks of gvabe's dependencies with goapi.Health:
+//   - database (critical): pings the database connection created by initDaos
+//   - keys (critical): RSA key pair has been loaded
+//   - exter (non-critical, only when Exter login is enabled): Exter server is reachable and its public key has been fetched
+//
+// available since template-v0.5.0
+func initHealthChecks() {
+	goapi.Health.Register(healthCheckDatabase, true, pingDatabase)
+
+	goapi.Health.Register(healthCheckKeys, true, func(_ context.Context) error {
+		if rsaPrivKey == nil || rsaPubKey == nil {
+			return errors.New("RSA key pair has not been loaded")
+		}
+		return nil
+	})
+
+	if exterClient != nil {
+		client := exterClient
+		goapi.Health.Register(healthCheckExter, false, func(_ context.Context) error {
+			resp, err := client.Info()
+			if err != nil {
+				return err
+			}
+			if resp.Status != 200 {
+				return fmt.Errorf("Exter responded with status %d: %s", resp.Status, resp.Message)
+			}
+			if exterRsaPubKey == nil {
+				return errors.New("Exter public key has not been fetched")
+			}
+			return nil
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package gvabe
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/btnguyen2k/consu/reddo"
+	"github.com/btnguyen2k/godal"
+	"github.com/btnguyen2k/henge"
+
+	"main/src/goapi"
+	"main/src/itineris"
+	"main/src/logging"
+)
+
+const (
+	// TableIdempotency is the table/collection that stores idempotency records when gvabe.idempotency.storage is "database".
+	TableIdempotency = "gva_idempotency"
+
+	idempotencyColExpiry       = "zexp"
+	idempotencyFieldExpiry     = "exp" // expiry time, stored as UNIX timestamp in milliseconds
+	idempotencyAttrFingerprint = "fp"
+	idempotencyAttrResult      = "rst"
+
+	// number of expired records fetched and deleted at a time by DaoIdempotencyStore.DeleteExpired
+	idempotencyDeleteBatchSize = 100
+)
+
+var (
+	idempotencyLogger = logging.WithField("component", "idempotency")
+
+	// underlying storage of DaoIdempotencyStore, created by initDaos
+	idempotencyDao henge.UniversalDao
+)
+
+// newIdempotencyFilter creates the IdempotencyFilter from config keys "gvabe.idempotency.*"; nil is returned if the feature is disabled.
+//
+// available since template-v0.5.0
+func newIdempotencyFilter(apiRouter *itineris.ApiRouter, nextFilter itineris.IApiFilter) itineris.IApiFilter {
+	if !goapi.AppConfig().GetBoolean("gvabe.idempotency.enabled", true) {
+		logging.Infof("Idempotency support is disabled at [gvabe.idempotency.enabled].")
+		return nil
+	}
+	ttl := goapi.AppConfig().GetTimeDuration("gvabe.idempotency.ttl", 24*time.Hour)
+	apis := goapi.AppConfig().GetStringList("gvabe.idempotency.apis")
+	var store itineris.IIdempotencyStore
+	switch storage := strings.ToLower(goapi.AppConfig().GetString("gvabe.idempotency.storage", "memory")); storage {
+	case "database", "db":
+		if idempotencyDao == nil {
+			panic("idempotency storage [database] is configured but DAO has not been initialized")
+		}
+		dbStore := NewDaoIdempotencyStore(idempotencyDao)
+		pruneInterval := goapi.AppConfig().GetTimeDuration("gvabe.idempotency.prune_interval", time.Hour)
+		goapi.GoBackground("pruneIdempotencyRecords", func(ctx context.Context) { goPruneIdempotencyRecords(ctx, dbStore, pruneInterval) })
+		store = dbStore
+	case "memory", "":
+		store = itineris.NewMemoryIdempotencyStore(int(goapi.AppConfig().GetInt32("gvabe.idempotency.memory_max_entries", 10000)))
+	default:
+		panic("unknown idempotency storage: " + storage)
+	}
+	logging.Infof("Idempotency support is enabled for APIs %v (ttl: %s)", apis, ttl)
+	return itineris.NewIdempotencyFilter(apiRouter, nextFilter, store, ttl, apis).SetScopeFunc(idempotencyScope)
+}
+
+// idempotencyScope scopes idempotency keys per logged-in user.
+func idempotencyScope(ctx *itineris.ApiContext, auth *itineris.ApiAuth) string {
+	if sessClaims, ok := ctx.GetContextValue(ctxFieldSession).(*SessionClaims); ok && sessClaims != nil {
+		return sessClaims.UserId
+	}
+	return auth.GetAppId() + ":" + auth.GetAccessToken()
+}
+
+// goPruneIdempotencyRecords periodically removes expired idempotency records from storage.
+//
+// available since template-v0.5.0
+func goPruneIdempotencyRecords(ctx context.Context, store *DaoIdempotencyStore, interval time.Duration) {
+	if interval < time.Minute {
+		interval = time.Minute
+	}
+	for {
+		if n, err := store.DeleteExpired(time.Now()); err != nil {
+			idempotencyLogger.Errorf("Error pruning idempotency records: %s", err)
+		} else if n > 0 {
+			idempotencyLogger.Debugf("Pruned %d expired idempotency record(s)", n)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+/*----------------------------------------------------------------------*/
+
+// DaoIdempotencyStore is the database-backed implementation of itineris.IIdempotencyStore, records are shared between application instances.
+//
+// Available since template-v0.5.0
+type DaoIdempotencyStore struct {
+	dao henge.UniversalDao
+}
+
+// NewDaoIdempotencyStore creates a new DaoIdempotencyStore instance.
+func NewDaoIdempotencyStore(dao henge.UniversalDao) *DaoIdempotencyStore {
+	return &DaoIdempotencyStore{dao: dao}
+}
+
+// Get implements itineris.IIdempotencyStore.Get
+func (s *DaoIdempotencyStore) Get(key string) (*itineris.IdempotencyRecord, error) {
+	ubo, err := s.dao.Get(key)
+	if err != nil || ubo == nil {
+		return nil, err
+	}
+	exp, err := ubo.GetExtraAttrAs(idempotencyFieldExpiry, reddo.TypeInt)
+	if err != nil {
+		return nil, err
+	}
+	record := &itineris.IdempotencyRecord{Expiry: time.Unix(0, exp.(int64)*int64(time.Millisecond))}
+	if !record.Expiry.After(time.Now()) {
+		return nil, nil
+	}
+	if v, err := ubo.GetDataAttrAs(idempotencyAttrFingerprint, reddo.TypeString); err != nil {
+		return nil, err
+	} else {
+		record.Fingerprint, _ = v.(string)
+	}
+	if v, err := ubo.GetDataAttrAs(idempotencyAttrResult, reddo.TypeString); err != nil {
+		return nil, err
+	} else if js, _ := v.(string); js != "" {
+		if err := json.Unmarshal([]byte(js), &record.Result); err != nil {
+			return nil, err
+		}
+	}
+	return record, nil
+}
+
+// Put implements itineris.IIdempotencyStore.Put
+func (s *DaoIdempotencyStore) Put(key string, record *itineris.IdempotencyRecord) error {
+	js, err := json.Marshal(record.Result)
+	if err != nil {
+		return err
+	}
+	ubo := henge.NewUniversalBo(key, goapi.AppVersionNumber)
+	ubo.SetDataAttr(idempotencyAttrFingerprint, record.Fingerprint)
+	ubo.SetDataAttr(idempotencyAttrResult, string(js))
+	ubo.SetExtraAttr(idempotencyFieldExpiry, record.Expiry.UnixNano()/int64(time.Millisecond))
+	_, _, err = s.dao.Save(ubo.Sync())
+	return err
+}
+
+// DeleteExpired removes all records that expired before the specified time, returns number of removed records.
+//
+// Expired records are fetched and deleted page by page, so that a large backlog is never loaded into memory at once.
+func (s *DaoIdempotencyStore) DeleteExpired(t time.Time) (int, error) {
+	filter := &godal.FilterOptFieldOpValue{FieldName: idempotencyFieldExpiry, Operator: godal.FilterOpLess, Value: t.UnixNano() / int64(time.Millisecond)}
+	count := 0
+	for {
+		// deleted records no longer match the filter, hence the next page always starts at offset 0
+		uboList, err := s.dao.GetN(0, idempotencyDeleteBatchSize, filter, nil)
+		if err != nil {
+			return count, err
+		}
+		numDeleted := 0
+		for _, ubo := range uboList {
+			if ok, err := s.dao.Delete(ubo); err != nil {
+				return count, err
+			} else if ok {
+				numDeleted++
+			}
+		}
+		count += numDeleted
+		if len(uboList) < idempotencyDeleteBatchSize || numDeleted == 0 {
+			// numDeleted == 0: records are being deleted concurrently (e.g. by another application instance), leave them to it
+			return count, nil
+		}
+	}
+}
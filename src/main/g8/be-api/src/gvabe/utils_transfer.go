@@ -0,0 +1,527 @@
+package gvabe
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/btnguyen2k/godal"
+	"github.com/btnguyen2k/henge"
+
+	"main/src/goapi"
+	"main/src/gvabe/bov2/blog"
+	"main/src/gvabe/bov2/user"
+)
+
+// Data transfer between database backends (commands "db export" and "db import"): each table/collection is exported
+// to file <table-name>.jsonl, one henge.UniversalBo (JSON-encoded, see henge.UniversalBo.MarshalJSON) per line, so that
+// ids, timestamps, checksums and tag-versions are preserved. File manifest.json records the number of exported records
+// of each table.
+
+const (
+	transferFormat       = "gvabe-jsonl/1"
+	transferManifestFile = "manifest.json"
+	transferProgressFile = "import-progress.json"
+	transferPageSize     = 100
+)
+
+// transferManifest describes an export.
+type transferManifest struct {
+	Format        string         `json:"format"`
+	AppVersion    string         `json:"app_version"`
+	DbType        string         `json:"db_type"`
+	SchemaVersion int            `json:"schema_version"`
+	TimeExported  string         `json:"t_exported"`
+	Counts        map[string]int `json:"counts"` // number of exported records, only completely exported tables are listed
+}
+
+// transferTable reads/writes records of a table/collection as henge.UniversalBo through the configured DAOs.
+type transferTable struct {
+	name   string
+	getN   func(fromOffset, maxNumRows int, sorting *godal.SortingOpt) ([]*henge.UniversalBo, error)
+	get    func(id string) (*henge.UniversalBo, error)
+	create func(ubo *henge.UniversalBo, checksum string) (bool, error)
+	update func(ubo *henge.UniversalBo) (bool, error)
+}
+
+// _checkTransferChecksum makes sure a business object built from an imported record still has the exported checksum.
+func _checkTransferChecksum(checksum string, bo *henge.UniversalBo) error {
+	if bo.GetChecksum() != checksum {
+		return fmt.Errorf("record [%s]: checksum changed from %s to %s", bo.GetId(), checksum, bo.GetChecksum())
+	}
+	return nil
+}
+
+// transferTables returns the tables/collections to be exported/imported, in import order (users before their posts,
+// posts before their comments and votes).
+func transferTables() []*transferTable {
+	return []*transferTable{
+		{
+			name: user.TableUser,
+			getN: func(fromOffset, maxNumRows int, sorting *godal.SortingOpt) ([]*henge.UniversalBo, error) {
+				list, err := userDaov2.GetN(fromOffset, maxNumRows, nil, sorting)
+				result := make([]*henge.UniversalBo, len(list))
+				for i, bo := range list {
+					result[i] = bo.UniversalBo
+				}
+				return result, err
+			},
+			get: func(id string) (*henge.UniversalBo, error) {
+				if bo, err := userDaov2.Get(id); err != nil || bo == nil {
+					return nil, err
+				} else {
+					return bo.UniversalBo, nil
+				}
+			},
+			create: func(ubo *henge.UniversalBo, checksum string) (bool, error) {
+				bo := user.NewUserFromUbo(ubo)
+				if bo == nil {
+					return false, fmt.Errorf("record [%s]: invalid user", ubo.GetId())
+				}
+				if err := _checkTransferChecksum(checksum, bo.UniversalBo); err != nil {
+					return false, err
+				}
+				return userDaov2.Create(bo)
+			},
+			update: func(ubo *henge.UniversalBo) (bool, error) {
+				if bo := user.NewUserFromUbo(ubo); bo != nil {
+					return userDaov2.Update(bo)
+				}
+				return false, fmt.Errorf("record [%s]: invalid user", ubo.GetId())
+			},
+		},
+		{
+			name: blog.TableBlogPost,
+			getN: func(fromOffset, maxNumRows int, sorting *godal.SortingOpt) ([]*henge.UniversalBo, error) {
+				list, err := blogPostDaov2.GetN(fromOffset, maxNumRows, nil, sorting)
+				result := make([]*henge.UniversalBo, len(list))
+				for i, bo := range list {
+					result[i] = bo.UniversalBo
+				}
+				return result, err
+			},
+			get: func(id string) (*henge.UniversalBo, error) {
+				if bo, err := blogPostDaov2.Get(id); err != nil || bo == nil {
+					return nil, err
+				} else {
+					return bo.UniversalBo, nil
+				}
+			},
+			create: func(ubo *henge.UniversalBo, checksum string) (bool, error) {
+				bo := blog.NewBlogPostFromUbo(ubo)
+				if bo == nil {
+					return false, fmt.Errorf("record [%s]: invalid blog post", ubo.GetId())
+				}
+				if err := _checkTransferChecksum(checksum, bo.UniversalBo); err != nil {
+					return false, err
+				}
+				return blogPostDaov2.Create(bo)
+			},
+			update: func(ubo *henge.UniversalBo) (bool, error) {
+				if bo := blog.NewBlogPostFromUbo(ubo); bo != nil {
+					return blogPostDaov2.Update(bo)
+				}
+				return false, fmt.Errorf("record [%s]: invalid blog post", ubo.GetId())
+			},
+		},
+		{
+			name: blog.TableBlogComment,
+			getN: func(fromOffset, maxNumRows int, sorting *godal.SortingOpt) ([]*henge.UniversalBo, error) {
+				list, err := blogCommentDaov2.GetN(fromOffset, maxNumRows, nil, sorting)
+				result := make([]*henge.UniversalBo, len(list))
+				for i, bo := range list {
+					result[i] = bo.UniversalBo
+				}
+				return result, err
+			},
+			get: func(id string) (*henge.UniversalBo, error) {
+				if bo, err := blogCommentDaov2.Get(id); err != nil || bo == nil {
+					return nil, err
+				} else {
+					return bo.UniversalBo, nil
+				}
+			},
+			create: func(ubo *henge.UniversalBo, checksum string) (bool, error) {
+				bo := blog.NewBlogCommentFromUbo(ubo)
+				if bo == nil {
+					return false, fmt.Errorf("record [%s]: invalid blog comment", ubo.GetId())
+				}
+				if err := _checkTransferChecksum(checksum, bo.UniversalBo); err != nil {
+					return false, err
+				}
+				return blogCommentDaov2.Create(bo)
+			},
+			update: func(ubo *henge.UniversalBo) (bool, error) {
+				if bo := blog.NewBlogCommentFromUbo(ubo); bo != nil {
+					return blogCommentDaov2.Update(bo)
+				}
+				return false, fmt.Errorf("record [%s]: invalid blog comment", ubo.GetId())
+			},
+		},
+		{
+			name: blog.TableBlogVote,
+			getN: func(fromOffset, maxNumRows int, sorting *godal.SortingOpt) ([]*henge.UniversalBo, error) {
+				list, err := blogVoteDaov2.GetN(fromOffset, maxNumRows, nil, sorting)
+				result := make([]*henge.UniversalBo, len(list))
+				for i, bo := range list {
+					result[i] = bo.UniversalBo
+				}
+				return result, err
+			},
+			get: func(id string) (*henge.UniversalBo, error) {
+				if bo, err := blogVoteDaov2.Get(id); err != nil || bo == nil {
+					return nil, err
+				} else {
+					return bo.UniversalBo, nil
+				}
+			},
+			create: func(ubo *henge.UniversalBo, checksum string) (bool, error) {
+				bo := blog.NewBlogVoteFromUbo(ubo)
+				if bo == nil {
+					return false, fmt.Errorf("record [%s]: invalid blog vote", ubo.GetId())
+				}
+				if err := _checkTransferChecksum(checksum, bo.UniversalBo); err != nil {
+					return false, err
+				}
+				return blogVoteDaov2.Create(bo)
+			},
+			update: func(ubo *henge.UniversalBo) (bool, error) {
+				if bo := blog.NewBlogVoteFromUbo(ubo); bo != nil {
+					return blogVoteDaov2.Update(bo)
+				}
+				return false, fmt.Errorf("record [%s]: invalid blog vote", ubo.GetId())
+			},
+		},
+	}
+}
+
+// _transferSorting returns the sorting used to page through tables/collections: by id, so that pages are stable
+// between runs (needed to resume an export). DynamoDB does not support sorting on scan, its scan order is used instead.
+func _transferSorting() *godal.SortingOpt {
+	if dynamodbConnect != nil {
+		return nil
+	}
+	return (&godal.SortingField{FieldName: henge.FieldId}).ToSortingOpt()
+}
+
+// _countRecords returns number of records currently stored in a table/collection.
+func _countRecords(tbl *transferTable) (int, error) {
+	sorting := _transferSorting()
+	count := 0
+	for {
+		list, err := tbl.getN(count, transferPageSize, sorting)
+		if err != nil {
+			return count, err
+		}
+		count += len(list)
+		if len(list) < transferPageSize {
+			return count, nil
+		}
+	}
+}
+
+func _readJsonFile(path string, v interface{}) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// _writeJsonFile writes v to a temp file then renames it, so that the file is never left half-written.
+func _writeJsonFile(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(path+".tmp", data, 0640); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+// _countCompleteLines returns number of newline-terminated lines of a file and truncates the trailing incomplete line (if any).
+func _countCompleteLines(path string) (int, error) {
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if os.IsNotExist(err) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	reader := bufio.NewReader(f)
+	lines, size := 0, int64(0)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			if len(line) > 0 {
+				return lines, f.Truncate(size)
+			}
+			return lines, nil
+		} else if err != nil {
+			return lines, err
+		}
+		lines++
+		size += int64(len(line))
+	}
+}
+
+/*----------------------------------------------------------------------*/
+
+// exportData exports users, blog posts, comments and votes from the configured database to directory dir.
+//
+// If resume is true, completely exported tables (listed in the manifest) are skipped and the partially exported
+// table continues from its last exported record. Otherwise dir must be empty or not exist.
+//
+// After each table is exported, the number of exported records is verified against the number of records in the table.
+//
+// available since template-v0.5.0
+func exportData(dir string, resume bool, report func(format string, args ...interface{})) error {
+	manifestPath := filepath.Join(dir, transferManifestFile)
+	manifest := &transferManifest{}
+	if resume {
+		if err := _readJsonFile(manifestPath, manifest); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("cannot read [%s]: %s", manifestPath, err)
+		}
+	} else if entries, _ := ioutil.ReadDir(dir); len(entries) > 0 {
+		return fmt.Errorf("directory [%s] is not empty, use --resume to continue a previous export", dir)
+	}
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return err
+	}
+	schemaVersion, err := getSchemaVersion()
+	if err != nil {
+		return fmt.Errorf("cannot read schema version: %s", err)
+	}
+	if manifest.Counts == nil {
+		manifest.Counts = make(map[string]int)
+	}
+	manifest.Format = transferFormat
+	manifest.AppVersion = goapi.AppVersion
+	manifest.DbType = strings.ToLower(goapi.AppConfig().GetString("gvabe.db.type"))
+	manifest.SchemaVersion = schemaVersion
+	manifest.TimeExported = time.Now().Format(time.RFC3339)
+
+	sorting := _transferSorting()
+	for _, tbl := range transferTables() {
+		if count, ok := manifest.Counts[tbl.name]; ok {
+			report("%-20s %d record(s), already exported", tbl.name, count)
+			continue
+		}
+		path := filepath.Join(dir, tbl.name+".jsonl")
+		offset := 0
+		if resume {
+			if offset, err = _countCompleteLines(path); err != nil {
+				return err
+			}
+		}
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0640)
+		if err != nil {
+			return err
+		}
+		writer := bufio.NewWriter(f)
+		for {
+			list, err := tbl.getN(offset, transferPageSize, sorting)
+			for _, ubo := range list {
+				var js []byte
+				if js, err = json.Marshal(ubo); err != nil {
+					break
+				}
+				writer.Write(js)
+				writer.WriteByte('\n')
+			}
+			if err == nil {
+				err = writer.Flush()
+			}
+			if err != nil {
+				f.Close()
+				return fmt.Errorf("%s: exporting records from offset %d: %s", tbl.name, offset, err)
+			}
+			offset += len(list)
+			if len(list) < transferPageSize {
+				break
+			}
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+
+		total, err := _countRecords(tbl)
+		if err != nil {
+			return err
+		}
+		if total != offset {
+			return fmt.Errorf("%s: %d record(s) exported but the table has %d, make sure data is not modified during export then rerun with --resume", tbl.name, offset, total)
+		}
+		manifest.Counts[tbl.name] = offset
+		if err := _writeJsonFile(manifestPath, manifest); err != nil {
+			return err
+		}
+		report("%-20s %d record(s) exported to [%s]", tbl.name, offset, path)
+	}
+	return nil
+}
+
+// importData imports records exported by exportData from directory dir to the configured database.
+//
+// Records are written through the configured DAOs, with their original ids, timestamps, checksums and tag-versions.
+// A record that already exists with the same checksum is skipped; one that exists with a different content is an
+// error, unless overwrite is true.
+//
+// Progress is saved to file import-progress.json in dir; if resume is true, records imported by a previous run are skipped.
+//
+// After each table is imported, the number of imported records is verified against the manifest and the number of
+// records in the table.
+//
+// available since template-v0.5.0
+func importData(dir string, resume, overwrite bool, report func(format string, args ...interface{})) error {
+	manifest := &transferManifest{}
+	if err := _readJsonFile(filepath.Join(dir, transferManifestFile), manifest); err != nil {
+		return fmt.Errorf("cannot read manifest of export: %s", err)
+	}
+	if manifest.Format != transferFormat {
+		return fmt.Errorf("unsupported export format [%s]", manifest.Format)
+	}
+	if current, err := getSchemaVersion(); err != nil {
+		return fmt.Errorf("cannot read schema version: %s", err)
+	} else if current < manifest.SchemaVersion {
+		return fmt.Errorf("data was exported from schema version %d but the database is at version %d, run \"db migrate\" first", manifest.SchemaVersion, current)
+	}
+	progressPath := filepath.Join(dir, transferProgressFile)
+	progress := make(map[string]int)
+	if resume {
+		if err := _readJsonFile(progressPath, &progress); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("cannot read [%s]: %s", progressPath, err)
+		}
+	}
+
+	for _, tbl := range transferTables() {
+		expected, ok := manifest.Counts[tbl.name]
+		if !ok {
+			return fmt.Errorf("%s: table was not completely exported, rerun the export with --resume", tbl.name)
+		}
+		var created, unchanged, overwritten int
+		lines, err := _importTable(filepath.Join(dir, tbl.name+".jsonl"), progress[tbl.name], func(ubo *henge.UniversalBo, checksum string) error {
+			ok, err := tbl.create(ubo, checksum)
+			if err == nil && ok {
+				created++
+				return nil
+			}
+			if err != nil && err != godal.ErrGdaoDuplicatedEntry {
+				return err
+			}
+			existing, err := tbl.get(ubo.GetId())
+			if err != nil {
+				return err
+			}
+			if existing == nil {
+				return fmt.Errorf("record [%s] conflicts with an existing record (unique index)", ubo.GetId())
+			}
+			if existing.GetChecksum() == checksum {
+				unchanged++
+				return nil
+			}
+			if !overwrite {
+				return fmt.Errorf("record [%s] already exists with different content, use --overwrite to replace it", ubo.GetId())
+			}
+			if ok, err := tbl.update(ubo); err != nil {
+				return err
+			} else if !ok {
+				return fmt.Errorf("record [%s] cannot be overwritten", ubo.GetId())
+			}
+			overwritten++
+			return nil
+		}, func(lines int) error {
+			progress[tbl.name] = lines
+			return _writeJsonFile(progressPath, progress)
+		})
+		if err != nil {
+			return fmt.Errorf("%s: %s (line %d)", tbl.name, err, lines+1)
+		}
+		if lines != expected {
+			return fmt.Errorf("%s: %d record(s) imported but %d were exported", tbl.name, lines, expected)
+		}
+		total, err := _countRecords(tbl)
+		if err != nil {
+			return err
+		}
+		if total < expected {
+			return fmt.Errorf("%s: %d record(s) imported but the table has only %d", tbl.name, expected, total)
+		}
+		report("%-20s %d record(s) imported (created: %d, unchanged: %d, overwritten: %d, skipped by resume: %d), table has %d record(s)",
+			tbl.name, lines, created, unchanged, overwritten, lines-created-unchanged-overwritten, total)
+	}
+	if err := os.Remove(progressPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// _parseTransferRecord parses an exported line back to a henge.UniversalBo and returns it along with the exported checksum.
+//
+// json.Unmarshal is not used directly on henge.UniversalBo because it recomputes the checksum from JSON-typed extra
+// attributes (numbers become float64) and would touch the "time-updated" timestamp.
+func _parseTransferRecord(line []byte) (*henge.UniversalBo, string, error) {
+	var record map[string]interface{}
+	if err := json.Unmarshal(line, &record); err != nil {
+		return nil, "", err
+	}
+	if ext, ok := record[henge.FieldExtras].(map[string]interface{}); ok {
+		for k, v := range ext {
+			record[k] = v
+		}
+	}
+	delete(record, henge.FieldExtras)
+	checksum, _ := record[henge.FieldChecksum].(string)
+	gbo := godal.NewGenericBo()
+	if err := gbo.GboImportViaJson(record); err != nil {
+		return nil, "", err
+	}
+	ubo := henge.NewUniversalBoFromGbo(gbo)
+	if ubo == nil || ubo.GetId() == "" || checksum == "" {
+		return nil, "", errors.New("invalid record")
+	}
+	return ubo, checksum, nil
+}
+
+// _importTable reads records from file path and passes them to importFunc, skipping the first skip records.
+// saveProgress is called periodically with the number of processed lines. This function returns number of processed lines.
+func _importTable(path string, skip int, importFunc func(ubo *henge.UniversalBo, checksum string) error, saveProgress func(lines int) error) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	reader := bufio.NewReader(f)
+	lines := 0
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF && len(line) == 0 {
+			return lines, saveProgress(lines)
+		} else if err != nil && err != io.EOF {
+			return lines, err
+		}
+		if lines >= skip {
+			ubo, checksum, err := _parseTransferRecord(line)
+			if err != nil {
+				return lines, err
+			}
+			if err := importFunc(ubo, checksum); err != nil {
+				return lines, err
+			}
+		}
+		lines++
+		if lines%transferPageSize == 0 {
+			if err := saveProgress(lines); err != nil {
+				return lines, err
+			}
+		}
+	}
+}
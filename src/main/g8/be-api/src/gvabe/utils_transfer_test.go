@@ -0,0 +1,274 @@
+package gvabe
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/btnguyen2k/henge"
+
+	"main/src/gvabe/bov2/blog"
+	"main/src/gvabe/bov2/user"
+	"main/src/memdb"
+)
+
+// _initTransferTestDb points the package's DAOs to a fresh in-memory database with the latest schema.
+func _initTransferTestDb(t *testing.T, name string) {
+	memoryDb = memdb.NewMemoryDb()
+	userDaov2 = _createUserDaoMemory(memoryDb)
+	blogPostDaov2 = _createBlogPostDaoMemory(memoryDb)
+	blogCommentDaov2 = _createBlogCommentDaoMemory(memoryDb)
+	blogVoteDaov2 = _createBlogVoteDaoMemory(memoryDb)
+	schemaVersionDao = _createSchemaVersionDaoMemory(memoryDb)
+	if _, _, err := migrateDatabase(0, false, func(string, ...interface{}) {}); err != nil {
+		t.Fatalf("%s failed: %s", name+"/migrateDatabase", err)
+	}
+}
+
+// _populateTransferTestDb creates users, blog posts, comments and votes, with timestamps in the past so that changed
+// timestamps are detected. Records are returned by table name.
+func _populateTransferTestDb(t *testing.T, name string) map[string][]*henge.UniversalBo {
+	past := time.Now().Add(-24 * time.Hour)
+	records := make(map[string][]*henge.UniversalBo)
+	var users []*user.User
+	for i := 0; i < 3; i++ {
+		u := user.NewUser(1337, fmt.Sprintf("user%d@local", i), fmt.Sprintf("user%d", i)).SetDisplayName(fmt.Sprintf("User %d", i))
+		u.SetTimeUpdated(past)
+		if ok, err := userDaov2.Create(u); err != nil || !ok {
+			t.Fatalf("%s failed: %#v / %s", name+"/createUser", ok, err)
+		}
+		users = append(users, u)
+		records[user.TableUser] = append(records[user.TableUser], u.UniversalBo)
+	}
+	for i := 0; i < transferPageSize+5; i++ {
+		owner := users[i%len(users)]
+		post := blog.NewBlogPost(1337, owner, i%2 == 0, fmt.Sprintf("title %d", i), fmt.Sprintf("content %d", i))
+		post.SetNumVotesUp(1)
+		post.SetTimeUpdated(past)
+		if ok, err := blogPostDaov2.Create(post); err != nil || !ok {
+			t.Fatalf("%s failed: %#v / %s", name+"/createPost", ok, err)
+		}
+		records[blog.TableBlogPost] = append(records[blog.TableBlogPost], post.UniversalBo)
+		comment := blog.NewBlogComment(1337, owner, post, nil, fmt.Sprintf("comment %d", i))
+		comment.SetTimeUpdated(past)
+		if ok, err := blogCommentDaov2.Create(comment); err != nil || !ok {
+			t.Fatalf("%s failed: %#v / %s", name+"/createComment", ok, err)
+		}
+		records[blog.TableBlogComment] = append(records[blog.TableBlogComment], comment.UniversalBo)
+		vote := blog.NewBlogVote(1337, users[(i+1)%len(users)], post.GetId(), 1)
+		vote.SetTimeUpdated(past)
+		if ok, err := blogVoteDaov2.Create(vote); err != nil || !ok {
+			t.Fatalf("%s failed: %#v / %s", name+"/createVote", ok, err)
+		}
+		records[blog.TableBlogVote] = append(records[blog.TableBlogVote], vote.UniversalBo)
+	}
+	return records
+}
+
+// _checkTransferredRecords verifies that records exist in the tables with their original checksums and timestamps.
+func _checkTransferredRecords(t *testing.T, name string, records map[string][]*henge.UniversalBo) {
+	for _, tbl := range transferTables() {
+		for _, expected := range records[tbl.name] {
+			ubo, err := tbl.get(expected.GetId())
+			if err != nil || ubo == nil {
+				t.Fatalf("%s failed: record [%s/%s] not found (error %s)", name, tbl.name, expected.GetId(), err)
+			}
+			if ubo.GetChecksum() != expected.GetChecksum() || !ubo.GetTimeCreated().Equal(expected.GetTimeCreated()) ||
+				!ubo.GetTimeUpdated().Equal(expected.GetTimeUpdated()) || ubo.GetTagVersion() != expected.GetTagVersion() {
+				t.Fatalf("%s failed: record [%s/%s] expected %s/%s/%s but received %s/%s/%s", name, tbl.name, expected.GetId(),
+					expected.GetChecksum(), expected.GetTimeCreated(), expected.GetTimeUpdated(),
+					ubo.GetChecksum(), ubo.GetTimeCreated(), ubo.GetTimeUpdated())
+			}
+		}
+		if count, err := _countRecords(tbl); err != nil || count != len(records[tbl.name]) {
+			t.Fatalf("%s failed: expected %d record(s) in [%s] but received %d (error %s)", name, len(records[tbl.name]), tbl.name, count, err)
+		}
+	}
+}
+
+// _exportTransferTestDb populates a fresh database and exports it to a temp directory, which is returned along with
+// the exported records.
+func _exportTransferTestDb(t *testing.T, name string) (string, map[string][]*henge.UniversalBo) {
+	_initTransferTestDb(t, name)
+	records := _populateTransferTestDb(t, name)
+	dir, err := ioutil.TempDir("", "gvabe")
+	if err != nil {
+		t.Fatalf("%s failed: %s", name, err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	if err := exportData(dir, false, t.Logf); err != nil {
+		t.Fatalf("%s failed: %s", name+"/exportData", err)
+	}
+	return dir, records
+}
+
+func TestTransfer_RoundTrip(t *testing.T) {
+	name := "TestTransfer_RoundTrip"
+	dir, records := _exportTransferTestDb(t, name)
+	if err := exportData(dir, false, t.Logf); err == nil || !strings.Contains(err.Error(), "not empty") {
+		t.Fatalf("%s failed: expected error exporting to a non-empty directory but received %#v", name+"/exportData", err)
+	}
+
+	_initTransferTestDb(t, name)
+	if err := importData(dir, false, false, t.Logf); err != nil {
+		t.Fatalf("%s failed: %s", name+"/importData", err)
+	}
+	_checkTransferredRecords(t, name+"/importData", records)
+	if _, err := os.Stat(filepath.Join(dir, transferProgressFile)); !os.IsNotExist(err) {
+		t.Fatalf("%s failed: expected progress file to be removed (error %s)", name, err)
+	}
+
+	// importing again: records exist with the same content and are skipped
+	var reports []string
+	if err := importData(dir, false, false, func(format string, args ...interface{}) {
+		reports = append(reports, fmt.Sprintf(format, args...))
+	}); err != nil {
+		t.Fatalf("%s failed: %s", name+"/importData(again)", err)
+	}
+	if len(reports) != 4 || !strings.Contains(reports[1], fmt.Sprintf("unchanged: %d", len(records[blog.TableBlogPost]))) {
+		t.Fatalf("%s failed: unexpected reports %#v", name+"/importData(again)", reports)
+	}
+	_checkTransferredRecords(t, name+"/importData(again)", records)
+}
+
+func TestTransfer_Overwrite(t *testing.T) {
+	name := "TestTransfer_Overwrite"
+	dir, records := _exportTransferTestDb(t, name)
+	_initTransferTestDb(t, name)
+	if err := importData(dir, false, false, t.Logf); err != nil {
+		t.Fatalf("%s failed: %s", name+"/importData", err)
+	}
+
+	// a record modified after the import conflicts with the exported one
+	id := records[blog.TableBlogPost][0].GetId()
+	post, _ := blogPostDaov2.Get(id)
+	if ok, err := blogPostDaov2.Update(post.SetTitle("modified")); err != nil || !ok {
+		t.Fatalf("%s failed: %#v / %s", name+"/Update", ok, err)
+	}
+	if err := importData(dir, false, false, t.Logf); err == nil || !strings.Contains(err.Error(), "--overwrite") {
+		t.Fatalf("%s failed: expected error but received %#v", name+"/importData", err)
+	}
+	if err := importData(dir, false, true, t.Logf); err != nil {
+		t.Fatalf("%s failed: %s", name+"/importData(overwrite)", err)
+	}
+	if post, _ := blogPostDaov2.Get(id); post == nil || post.GetTitle() == "modified" {
+		t.Fatalf("%s failed: expected post to be overwritten but received %#v", name, post)
+	}
+	_checkTransferredRecords(t, name+"/importData(overwrite)", records)
+}
+
+func TestTransfer_ResumeExport(t *testing.T) {
+	name := "TestTransfer_ResumeExport"
+	dir, records := _exportTransferTestDb(t, name)
+	postFile := filepath.Join(dir, blog.TableBlogPost+".jsonl")
+	exported, err := ioutil.ReadFile(postFile)
+	if err != nil {
+		t.Fatalf("%s failed: %s", name, err)
+	}
+
+	// simulate an export interrupted while writing table of blog posts: the table is not listed in the manifest and its
+	// file ends with an incomplete line; tables after it have not been exported yet
+	manifest := &transferManifest{}
+	if err := _readJsonFile(filepath.Join(dir, transferManifestFile), manifest); err != nil {
+		t.Fatalf("%s failed: %s", name, err)
+	}
+	for _, tblName := range []string{blog.TableBlogPost, blog.TableBlogComment, blog.TableBlogVote} {
+		delete(manifest.Counts, tblName)
+		if tblName != blog.TableBlogPost {
+			os.Remove(filepath.Join(dir, tblName+".jsonl"))
+		}
+	}
+	if err := _writeJsonFile(filepath.Join(dir, transferManifestFile), manifest); err != nil {
+		t.Fatalf("%s failed: %s", name, err)
+	}
+	lines := strings.SplitAfter(string(exported), "\n")
+	partial := strings.Join(lines[:transferPageSize/2], "") + lines[transferPageSize/2][:10]
+	if err := ioutil.WriteFile(postFile, []byte(partial), 0640); err != nil {
+		t.Fatalf("%s failed: %s", name, err)
+	}
+
+	// an incomplete export cannot be imported (users are imported as unchanged, as they exist in the database)
+	if err := importData(dir, false, false, t.Logf); err == nil || !strings.Contains(err.Error(), "--resume") {
+		t.Fatalf("%s failed: expected error but received %#v", name+"/importData(incomplete)", err)
+	}
+
+	// the export continues from the last complete line of the partially exported table
+	if err := exportData(dir, true, t.Logf); err != nil {
+		t.Fatalf("%s failed: %s", name+"/exportData(resume)", err)
+	}
+	if data, _ := ioutil.ReadFile(postFile); string(data) != string(exported) {
+		t.Fatalf("%s failed: resumed export differs from complete export", name+"/exportData(resume)")
+	}
+	_initTransferTestDb(t, name)
+	if err := importData(dir, false, false, t.Logf); err != nil {
+		t.Fatalf("%s failed: %s", name+"/importData", err)
+	}
+	_checkTransferredRecords(t, name+"/importData", records)
+}
+
+func TestTransfer_ResumeImport(t *testing.T) {
+	name := "TestTransfer_ResumeImport"
+	dir, records := _exportTransferTestDb(t, name)
+	_initTransferTestDb(t, name)
+	if err := importData(dir, false, false, t.Logf); err != nil {
+		t.Fatalf("%s failed: %s", name+"/importData", err)
+	}
+
+	// simulate an import interrupted while importing table of blog posts
+	numSkipped := transferPageSize / 2
+	progress := map[string]int{user.TableUser: len(records[user.TableUser]), blog.TableBlogPost: numSkipped}
+	progressPath := filepath.Join(dir, transferProgressFile)
+	if err := _writeJsonFile(progressPath, progress); err != nil {
+		t.Fatalf("%s failed: %s", name, err)
+	}
+	testCases := []struct {
+		resume            bool
+		expectedSkipped   int
+		expectedUnchanged int
+	}{
+		{false, 0, len(records[blog.TableBlogPost])},
+		{true, numSkipped, len(records[blog.TableBlogPost]) - numSkipped},
+	}
+	for _, tc := range testCases {
+		caseName := fmt.Sprintf("%s/resume=%v", name, tc.resume)
+		var reports []string
+		if err := importData(dir, tc.resume, false, func(format string, args ...interface{}) {
+			reports = append(reports, fmt.Sprintf(format, args...))
+		}); err != nil {
+			t.Fatalf("%s failed: %s", caseName, err)
+		}
+		expected := fmt.Sprintf("unchanged: %d, overwritten: 0, skipped by resume: %d", tc.expectedUnchanged, tc.expectedSkipped)
+		if len(reports) != 4 || !strings.Contains(reports[1], expected) {
+			t.Fatalf("%s failed: expected [%s] but received %#v", caseName, expected, reports)
+		}
+		if err := _writeJsonFile(progressPath, progress); err != nil {
+			t.Fatalf("%s failed: %s", name, err)
+		}
+	}
+	_checkTransferredRecords(t, name, records)
+}
+
+func TestTransfer_CountMismatch(t *testing.T) {
+	name := "TestTransfer_CountMismatch"
+	dir, _ := _exportTransferTestDb(t, name)
+
+	// records missing from the exported file are detected
+	manifest := &transferManifest{}
+	if err := _readJsonFile(filepath.Join(dir, transferManifestFile), manifest); err != nil {
+		t.Fatalf("%s failed: %s", name, err)
+	}
+	manifest.Counts[blog.TableBlogComment]++
+	if err := _writeJsonFile(filepath.Join(dir, transferManifestFile), manifest); err != nil {
+		t.Fatalf("%s failed: %s", name, err)
+	}
+	_initTransferTestDb(t, name)
+	expected := fmt.Sprintf("%s: %d record(s) imported but %d were exported", blog.TableBlogComment,
+		manifest.Counts[blog.TableBlogComment]-1, manifest.Counts[blog.TableBlogComment])
+	if err := importData(dir, false, false, t.Logf); err == nil || err.Error() != expected {
+		t.Fatalf("%s failed: expected error [%s] but received %#v", name+"/importData", expected, err)
+	}
+}
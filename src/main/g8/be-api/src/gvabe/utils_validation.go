@@ -0,0 +1,51 @@
+package gvabe
+
+import (
+	"fmt"
+
+	"github.com/btnguyen2k/goyai"
+
+	"main/src/goapi"
+	"main/src/itineris"
+	"main/src/logging"
+)
+
+// i18n keys of validation errors, one per itineris.FieldError code
+var validationMessageKeys = map[string]string{
+	itineris.ValidationRequired:  "error_field_required",
+	itineris.ValidationType:      "error_field_type",
+	itineris.ValidationMinLength: "error_field_min_length",
+	itineris.ValidationMaxLength: "error_field_max_length",
+	itineris.ValidationEnum:      "error_field_enum",
+	itineris.ValidationPattern:   "error_field_pattern",
+}
+
+// newValidationFilter creates the ValidationFilter with localized error messages; nil is returned if the feature is disabled at "gvabe.validation.enabled".
+//
+// available since template-v0.5.0
+func newValidationFilter(apiRouter *itineris.ApiRouter, nextFilter itineris.IApiFilter) itineris.IApiFilter {
+	if !goapi.AppConfig().GetBoolean("gvabe.validation.enabled", true) {
+		logging.Infof("Request parameter validation is disabled at [gvabe.validation.enabled].")
+		return nil
+	}
+	return itineris.NewValidationFilter(apiRouter, nextFilter).
+		SetMessageFunc(localizeFieldError).
+		SetSummaryFunc(func(ctx *itineris.ApiContext, _ []*itineris.FieldError) string {
+			return i18n().Localize(ctx.GetClientLocale(), "error_invalid_params",
+				&goyai.LocalizeConfig{DefaultMessage: "Invalid request parameters, please check and try again."})
+		})
+}
+
+// localizeFieldError renders the message of a validation error in client's locale.
+func localizeFieldError(ctx *itineris.ApiContext, fieldErr *itineris.FieldError) string {
+	key, ok := validationMessageKeys[fieldErr.Code]
+	if !ok {
+		key = "error_invalid_param"
+	}
+	templateData := map[string]interface{}{"param": fieldErr.Field}
+	for k, v := range fieldErr.Params {
+		templateData[k] = fmt.Sprint(v)
+	}
+	return i18n().Localize(ctx.GetClientLocale(), key,
+		&goyai.LocalizeConfig{DefaultMessage: fieldErr.DefaultMessage(), TemplateData: templateData})
+}
@@ -0,0 +1,79 @@
+package gvabe
+
+import (
+	"context"
+	"time"
+
+	"main/src/goapi"
+	"main/src/logging"
+)
+
+var voteLogger = logging.WithField("component", "votes")
+
+// initVoteReconciliation starts the job that periodically recomputes vote counters of blog posts from the stored
+// votes, at the interval set by config key "gvabe.blog.vote_reconcile_interval" (0 to disable).
+//
+// available since template-v0.5.0
+func initVoteReconciliation() {
+	interval := goapi.AppConfig().GetTimeDuration("gvabe.blog.vote_reconcile_interval", 0)
+	if interval > 0 {
+		goapi.GoBackground("reconcileVotes", func(ctx context.Context) { goReconcileVotes(ctx, interval) })
+	}
+}
+
+// goReconcileVotes periodically reconciles vote counters of all blog posts, see reconcileVotes.
+//
+// available since template-v0.5.0
+func goReconcileVotes(ctx context.Context, interval time.Duration) {
+	if interval < time.Minute {
+		interval = time.Minute
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+		if numPosts, numFixed, err := reconcileVotes(ctx, voteLogger.Infof); err != nil {
+			voteLogger.Errorf("Error reconciling vote counters: %s", err)
+		} else if numFixed > 0 {
+			voteLogger.Infof("Reconciled vote counters of %d post(s), %d post(s) checked", numFixed, numPosts)
+		}
+	}
+}
+
+// reconcileVotes recomputes vote counters of all blog posts from table/collection "gva_blog_vote" and corrects the
+// posts whose counters drifted (e.g. the application stopped between writing a vote and updating the post).
+// Numbers of checked and corrected posts are returned.
+//
+// available since template-v0.5.0
+func reconcileVotes(ctx context.Context, report func(format string, a ...interface{})) (int, int, error) {
+	sorting := _transferSorting()
+	numPosts, numFixed := 0, 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return numPosts, numFixed, err
+		}
+		list, err := blogPostDaov2.GetN(numPosts, transferPageSize, nil, sorting)
+		if err != nil {
+			return numPosts, numFixed, err
+		}
+		for _, post := range list {
+			p, fixed, err := blogPostVoter.RecountVotes(post.GetId())
+			if err != nil {
+				return numPosts, numFixed, err
+			}
+			if fixed {
+				numFixed++
+				evictCachedBlogPost(p.GetId())
+				publishVoteChangedEvent(p)
+				report("Post [%s]: vote counters corrected from %d/%d to %d/%d (up/down)", p.GetId(),
+					post.GetNumVotesUp(), post.GetNumVotesDown(), p.GetNumVotesUp(), p.GetNumVotesDown())
+			}
+		}
+		numPosts += len(list)
+		if len(list) < transferPageSize {
+			return numPosts, numFixed, nil
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package itineris
+
+import (
+	"sync"
+)
+
+const (
+	// CtxBatchId is the context attribute that holds id of the batch's API context (available only for sub-calls of a batch).
+	//
+	// Available since template-v0.5.0
+	CtxBatchId = "batch_id"
+)
+
+/*
+BatchCall is a single API call in a batch.
+
+Available since template-v0.5.0
+*/
+type BatchCall struct {
+	Id      string     // optional id supplied by client, echoed back in the corresponding BatchCallResult
+	ApiName string     // name of the API to call
+	Params  *ApiParams // params of the API call
+}
+
+/*
+BatchCallResult is the result of a single API call in a batch.
+
+Available since template-v0.5.0
+*/
+type BatchCallResult struct {
+	Id      string
+	ApiName string
+	Result  *ApiResult
+}
+
+/*
+ToMap exports the BatchCallResult data to a map, which is the API result's map plus fields "id" and "apiName".
+*/
+func (r *BatchCallResult) ToMap() map[string]interface{} {
+	m := r.Result.ToMap()
+	m["id"] = r.Id
+	m["apiName"] = r.ApiName
+	return m
+}
+
+/*
+BatchOptions controls how a batch is executed.
+
+Available since template-v0.5.0
+*/
+type BatchOptions struct {
+	Parallel       bool // if true, API calls are executed in parallel; otherwise, sequentially in order
+	MaxConcurrency int  // (parallel mode) maximum number of API calls executed at the same time, 0 or negative means unlimited
+}
+
+/*
+CallBatch performs a batch of API calls, each one is routed through the api-filter chain via CallApi.
+
+  - Each API call has its own context, which inherits values of the batch's context (except the idempotency key)
+    and is marked with CtxBatchId.
+  - Result of each API call is returned at the same position in the result list.
+  - Failure of one API call does not affect other calls.
+
+Available since template-v0.5.0
+*/
+func (router *ApiRouter) CallBatch(ctx *ApiContext, auth *ApiAuth, calls []*BatchCall, opts BatchOptions) []*BatchCallResult {
+	results := make([]*BatchCallResult, len(calls))
+	doCall := func(i int) {
+		call := calls[i]
+		results[i] = &BatchCallResult{Id: call.Id, ApiName: call.ApiName}
+		defer func() {
+			if r := recover(); r != nil {
+				ContextLogger(nil, ctx).Errorf("Panic while calling API [%s] in batch: %v", call.ApiName, r)
+				results[i].Result = NewApiResult(StatusErrorServer).SetMessage("Error while calling API [" + call.ApiName + "].")
+			}
+		}()
+		params := call.Params
+		if params == nil {
+			params = NewApiParams()
+		}
+		result := router.CallApi(newBatchSubContext(ctx, call.ApiName), auth, params)
+		if result == nil {
+			result = NewApiResult(StatusErrorServer).SetMessage("API [" + call.ApiName + "] returned no result.")
+		}
+		results[i].Result = result
+	}
+
+	if !opts.Parallel || len(calls) < 2 {
+		for i := range calls {
+			doCall(i)
+		}
+		return results
+	}
+	maxConcurrency := opts.MaxConcurrency
+	if maxConcurrency <= 0 || maxConcurrency > len(calls) {
+		maxConcurrency = len(calls)
+	}
+	semaphore := make(chan struct{}, maxConcurrency)
+	var wg sync.WaitGroup
+	for i := range calls {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(i int) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+			doCall(i)
+		}(i)
+	}
+	wg.Wait()
+	return results
+}
+
+// newBatchSubContext creates context for an API call in a batch.
+func newBatchSubContext(batchCtx *ApiContext, apiName string) *ApiContext {
+	ctx := NewApiContext()
+	for k, v := range batchCtx.GetAllContextValues() {
+		switch k {
+		case ctxId, ctxTimestamp, ctxApiName, CtxIdempotencyKey:
+		default:
+			ctx.SetContextValue(k, v)
+		}
+	}
+	return ctx.SetApiName(apiName).SetContextValue(CtxBatchId, batchCtx.GetId())
+}
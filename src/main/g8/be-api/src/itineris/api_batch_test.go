@@ -0,0 +1,90 @@
+package itineris
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type _countingFilter struct {
+	*BaseApiFilter
+	count int32
+}
+
+func (f *_countingFilter) Call(handler IApiHandler, ctx *ApiContext, auth *ApiAuth, params *ApiParams) *ApiResult {
+	atomic.AddInt32(&f.count, 1)
+	return handler(ctx, auth, params)
+}
+
+func TestApiRouter_CallBatch(t *testing.T) {
+	name := "TestApiRouter_CallBatch"
+	router := NewApiRouter()
+	filter := &_countingFilter{BaseApiFilter: &BaseApiFilter{ApiRouter: router}}
+	router.SetApiFilter(filter)
+	router.SetHandler("echo", func(ctx *ApiContext, _ *ApiAuth, params *ApiParams) *ApiResult {
+		return NewApiResult(StatusOk).SetData(map[string]interface{}{
+			"value": params.GetParam("value"), "batch": ctx.GetContextValue(CtxBatchId), "locale": ctx.GetClientLocale(),
+			"idempotency": ctx.GetContextValue(CtxIdempotencyKey),
+		})
+	})
+	router.SetHandler("panic", func(_ *ApiContext, _ *ApiAuth, _ *ApiParams) *ApiResult {
+		panic("oops")
+	})
+
+	ctx := NewApiContext().SetGateway("TEST").SetContextValue(CtxLocale, "vi").SetContextValue(CtxIdempotencyKey, "key")
+	calls := []*BatchCall{
+		{Id: "1", ApiName: "echo", Params: NewApiParams().SetParam("value", 1)},
+		{Id: "2", ApiName: "notfound"},
+		{Id: "3", ApiName: "panic"},
+		{Id: "4", ApiName: "echo", Params: NewApiParams().SetParam("value", 4)},
+	}
+	for _, opts := range []BatchOptions{{}, {Parallel: true, MaxConcurrency: 2}} {
+		filter.count = 0
+		results := router.CallBatch(ctx, NewApiAuth("", ""), calls, opts)
+		if len(results) != len(calls) {
+			t.Fatalf("%s failed: expected %#v but received %#v", name, len(calls), len(results))
+		}
+		expectedStatus := []int{StatusOk, StatusNotImplemented, StatusErrorServer, StatusOk}
+		for i, r := range results {
+			if r.Id != calls[i].Id || r.ApiName != calls[i].ApiName || r.Result.Status != expectedStatus[i] {
+				t.Fatalf("%s failed: unexpected result at %d: %#v", name, i, r.ToMap())
+			}
+		}
+		data := results[3].Result.Data.(map[string]interface{})
+		if data["value"] != 4 || data["batch"] != ctx.GetId() || data["locale"] != "vi" || data["idempotency"] != nil {
+			t.Fatalf("%s failed: unexpected data %#v", name, data)
+		}
+		if m := results[0].ToMap(); m["id"] != "1" || m["apiName"] != "echo" || m["status"] != StatusOk {
+			t.Fatalf("%s failed: unexpected map %#v", name, m)
+		}
+		if filter.count != 3 {
+			t.Fatalf("%s failed: filter chain should be applied per sub-call, expected %#v but received %#v", name, 3, filter.count)
+		}
+	}
+}
+
+func TestApiRouter_CallBatch_MaxConcurrency(t *testing.T) {
+	name := "TestApiRouter_CallBatch_MaxConcurrency"
+	router := NewApiRouter()
+	var current, max int32
+	router.SetHandler("slow", func(_ *ApiContext, _ *ApiAuth, _ *ApiParams) *ApiResult {
+		n := atomic.AddInt32(&current, 1)
+		for {
+			m := atomic.LoadInt32(&max)
+			if n <= m || atomic.CompareAndSwapInt32(&max, m, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		atomic.AddInt32(&current, -1)
+		return NewApiResult(StatusOk)
+	})
+	calls := make([]*BatchCall, 10)
+	for i := range calls {
+		calls[i] = &BatchCall{ApiName: "slow"}
+	}
+	router.CallBatch(NewApiContext(), NewApiAuth("", ""), calls, BatchOptions{Parallel: true, MaxConcurrency: 3})
+	if max > 3 || max < 2 {
+		t.Fatalf("%s failed: expected at most %#v concurrent calls but received %#v", name, 3, max)
+	}
+}
@@ -0,0 +1,288 @@
+package itineris
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// ApiResultExtraIdempotentReplay is added to the result's "extras" field (with value true) when the result is replayed from IIdempotencyStore.
+	//
+	// Available since template-v0.5.0
+	ApiResultExtraIdempotentReplay = "_idempotent_replay_"
+
+	// MaxIdempotencyKeyLength is the maximum length of an idempotency key.
+	//
+	// Available since template-v0.5.0
+	MaxIdempotencyKeyLength = 255
+)
+
+/*
+IdempotencyRecord is the stored outcome of an API call made with an idempotency key.
+
+Available since template-v0.5.0
+*/
+type IdempotencyRecord struct {
+	Fingerprint string     // fingerprint of the original request, used to detect reuse of a key with different params
+	Result      *ApiResult // result of the original call
+	Expiry      time.Time  // the record should not be replayed after this time
+}
+
+/*
+IIdempotencyStore stores outcomes of API calls made with idempotency keys.
+
+Available since template-v0.5.0
+*/
+type IIdempotencyStore interface {
+	// Get returns the record stored under the specified key; nil is returned if not found or if the record has expired.
+	Get(key string) (*IdempotencyRecord, error)
+
+	// Put stores the record under the specified key, replacing the existing one (if any).
+	Put(key string, record *IdempotencyRecord) error
+}
+
+/*----------------------------------------------------------------------*/
+
+/*
+MemoryIdempotencyStore is an in-memory implementation of IIdempotencyStore.
+
+  - Records are kept in the local process, hence are not shared between application instances.
+  - Expired records are purged when the store grows beyond its capacity; if still full, records that expire soonest are evicted.
+
+Available since template-v0.5.0
+*/
+type MemoryIdempotencyStore struct {
+	lock       sync.Mutex
+	records    map[string]*IdempotencyRecord
+	maxEntries int
+}
+
+/*
+NewMemoryIdempotencyStore creates a new MemoryIdempotencyStore instance that holds at most maxEntries records (0 or negative value means unlimited).
+*/
+func NewMemoryIdempotencyStore(maxEntries int) *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{records: make(map[string]*IdempotencyRecord), maxEntries: maxEntries}
+}
+
+/*
+Get implements IIdempotencyStore.Get
+*/
+func (s *MemoryIdempotencyStore) Get(key string) (*IdempotencyRecord, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	record, ok := s.records[key]
+	if !ok {
+		return nil, nil
+	}
+	if !record.Expiry.After(time.Now()) {
+		delete(s.records, key)
+		return nil, nil
+	}
+	return &IdempotencyRecord{Fingerprint: record.Fingerprint, Result: cloneApiResult(record.Result), Expiry: record.Expiry}, nil
+}
+
+/*
+Put implements IIdempotencyStore.Put
+*/
+func (s *MemoryIdempotencyStore) Put(key string, record *IdempotencyRecord) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if _, ok := s.records[key]; !ok && s.maxEntries > 0 && len(s.records) >= s.maxEntries {
+		s.evict()
+	}
+	s.records[key] = &IdempotencyRecord{Fingerprint: record.Fingerprint, Result: cloneApiResult(record.Result), Expiry: record.Expiry}
+	return nil
+}
+
+/*
+Size returns number of records currently held by the store (including expired ones that have not been purged).
+*/
+func (s *MemoryIdempotencyStore) Size() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return len(s.records)
+}
+
+// evict makes room for a new record, caller must hold the lock.
+func (s *MemoryIdempotencyStore) evict() {
+	now := time.Now()
+	for k, r := range s.records {
+		if !r.Expiry.After(now) {
+			delete(s.records, k)
+		}
+	}
+	for len(s.records) >= s.maxEntries {
+		var victim string
+		var victimExpiry time.Time
+		for k, r := range s.records {
+			if victim == "" || r.Expiry.Before(victimExpiry) {
+				victim, victimExpiry = k, r.Expiry
+			}
+		}
+		delete(s.records, victim)
+	}
+}
+
+// cloneApiResult replicates the ApiResult instance, including its extras map, so that stored results are not affected by post-processing of filters.
+func cloneApiResult(rst *ApiResult) *ApiResult {
+	if rst == nil {
+		return nil
+	}
+	clone := rst.Clone()
+	clone.Extras = make(map[string]interface{}, len(rst.Extras))
+	for k, v := range rst.Extras {
+		clone.Extras[k] = v
+	}
+	return clone
+}
+
+/*----------------------------------------------------------------------*/
+
+/*
+IdempotencyFilter makes configured APIs idempotent: the first result of a call made with an idempotency key
+(context value CtxIdempotencyKey) is stored and replayed for subsequent calls with the same key.
+
+  - Calls without idempotency key, or calls to APIs that are not configured, pass through untouched.
+  - Keys are scoped per API and per caller (see SetScopeFunc), so that different callers can not see each other's results.
+  - Reusing a key with different request params is rejected with StatusErrorClient.
+  - Concurrent calls with the same key are serialized; the later ones receive the replayed result.
+  - Results with status StatusErrorServer are not stored, so that the caller can retry.
+  - Replayed results are marked with extra info ApiResultExtraIdempotentReplay.
+
+Available since template-v0.5.0
+*/
+type IdempotencyFilter struct {
+	*BaseApiFilter
+	store     IIdempotencyStore
+	ttl       time.Duration
+	apis      map[string]bool
+	scopeFunc func(*ApiContext, *ApiAuth) string
+	lock      sync.Mutex
+	inflight  map[string]chan struct{}
+}
+
+/*
+NewIdempotencyFilter creates a new IdempotencyFilter instance.
+
+  - store: where results are stored.
+  - ttl: how long results are replayed.
+  - apis: names of the APIs to be made idempotent.
+*/
+func NewIdempotencyFilter(apiRouter *ApiRouter, nextFilter IApiFilter, store IIdempotencyStore, ttl time.Duration, apis []string) *IdempotencyFilter {
+	f := &IdempotencyFilter{
+		BaseApiFilter: &BaseApiFilter{ApiRouter: apiRouter, NextFilter: nextFilter},
+		store:         store,
+		ttl:           ttl,
+		apis:          make(map[string]bool),
+		scopeFunc:     defaultIdempotencyScope,
+		inflight:      make(map[string]chan struct{}),
+	}
+	for _, api := range apis {
+		f.apis[api] = true
+	}
+	return f
+}
+
+// defaultIdempotencyScope scopes keys by app-id and access token.
+func defaultIdempotencyScope(_ *ApiContext, auth *ApiAuth) string {
+	if auth == nil {
+		return ""
+	}
+	return auth.GetAppId() + ":" + auth.GetAccessToken()
+}
+
+/*
+SetScopeFunc sets the function that identifies the caller of an API call (e.g. id of the logged-in user).
+Idempotency keys are scoped per caller.
+*/
+func (f *IdempotencyFilter) SetScopeFunc(scopeFunc func(*ApiContext, *ApiAuth) string) *IdempotencyFilter {
+	if scopeFunc == nil {
+		scopeFunc = defaultIdempotencyScope
+	}
+	f.scopeFunc = scopeFunc
+	return f
+}
+
+/*
+Call implements IApiFilter.Call
+*/
+func (f *IdempotencyFilter) Call(handler IApiHandler, ctx *ApiContext, auth *ApiAuth, params *ApiParams) *ApiResult {
+	key := strings.TrimSpace(ctx.GetContextValueAsString(CtxIdempotencyKey))
+	if key == "" || !f.apis[ctx.GetApiName()] {
+		return f.next(handler, ctx, auth, params)
+	}
+	if len(key) > MaxIdempotencyKeyLength {
+		return NewApiResult(StatusErrorClient).SetMessage("Idempotency key is too long.")
+	}
+
+	storeKey := f.storeKey(ctx.GetApiName(), f.scopeFunc(ctx, auth), key)
+	release := f.acquire(storeKey)
+	defer release()
+
+	fingerprint := f.fingerprint(params)
+	record, err := f.store.Get(storeKey)
+	if err != nil {
+		ContextLogger(nil, ctx).Errorf("Error loading idempotency record: %s", err)
+		return NewApiResult(StatusErrorServer).SetMessage(err.Error())
+	}
+	if record != nil && record.Result != nil {
+		if record.Fingerprint != fingerprint {
+			return NewApiResult(StatusErrorClient).SetMessage("Idempotency key has already been used with different request parameters.")
+		}
+		return record.Result.AddExtraInfo(ApiResultExtraIdempotentReplay, true)
+	}
+
+	result := f.next(handler, ctx, auth, params)
+	if result != nil && result.Status != StatusErrorServer {
+		record = &IdempotencyRecord{Fingerprint: fingerprint, Result: cloneApiResult(result), Expiry: time.Now().Add(f.ttl)}
+		if err := f.store.Put(storeKey, record); err != nil {
+			ContextLogger(nil, ctx).Errorf("Error storing idempotency record: %s", err)
+		}
+	}
+	return result
+}
+
+func (f *IdempotencyFilter) next(handler IApiHandler, ctx *ApiContext, auth *ApiAuth, params *ApiParams) *ApiResult {
+	if f.NextFilter != nil {
+		return f.NextFilter.Call(handler, ctx, auth, params)
+	}
+	return handler(ctx, auth, params)
+}
+
+// storeKey builds a fixed-length key suitable to be used as storage id.
+func (f *IdempotencyFilter) storeKey(apiName, scope, key string) string {
+	h := sha256.Sum256([]byte(apiName + "\n" + scope + "\n" + key))
+	return hex.EncodeToString(h[:])
+}
+
+// fingerprint calculates hash of the request params (json.Marshal sorts map keys, hence the output is deterministic).
+func (f *IdempotencyFilter) fingerprint(params *ApiParams) string {
+	js, _ := json.Marshal(params.GetAllParams())
+	h := sha256.Sum256(js)
+	return hex.EncodeToString(h[:])
+}
+
+// acquire waits until no other call with the same key is in progress, returns the function to release the key.
+func (f *IdempotencyFilter) acquire(key string) func() {
+	for {
+		f.lock.Lock()
+		ch, busy := f.inflight[key]
+		if !busy {
+			ch = make(chan struct{})
+			f.inflight[key] = ch
+			f.lock.Unlock()
+			return func() {
+				f.lock.Lock()
+				delete(f.inflight, key)
+				f.lock.Unlock()
+				close(ch)
+			}
+		}
+		f.lock.Unlock()
+		<-ch
+	}
+}
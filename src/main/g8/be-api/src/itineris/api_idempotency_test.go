@@ -0,0 +1,126 @@
+package itineris
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func _newIdempotencyTestFilter(store IIdempotencyStore, ttl time.Duration) (*IdempotencyFilter, IApiHandler, *int32) {
+	counter := new(int32)
+	handler := func(_ *ApiContext, _ *ApiAuth, _ *ApiParams) *ApiResult {
+		n := atomic.AddInt32(counter, 1)
+		time.Sleep(10 * time.Millisecond)
+		return NewApiResult(StatusOk).SetData(n)
+	}
+	return NewIdempotencyFilter(nil, nil, store, ttl, []string{"create"}), handler, counter
+}
+
+func TestIdempotencyFilter_Replay(t *testing.T) {
+	name := "TestIdempotencyFilter_Replay"
+	f, handler, counter := _newIdempotencyTestFilter(NewMemoryIdempotencyStore(0), time.Minute)
+	auth := NewApiAuth("app", "token")
+	params := NewApiParams().SetParam("title", "hello")
+	ctx := NewApiContext().SetApiName("create").SetContextValue(CtxIdempotencyKey, "key1")
+	r1 := f.Call(handler, ctx, auth, params)
+	r1.AddExtraInfo("_access_token_", "modified by outer filter")
+	r2 := f.Call(handler, NewApiContext().SetApiName("create").SetContextValue(CtxIdempotencyKey, "key1"), auth, params)
+	if *counter != 1 || r2.Data != r1.Data {
+		t.Fatalf("%s failed: expected handler to be called once and result replayed, received %#v calls, %#v", name, *counter, r2.Data)
+	}
+	if r2.Extras[ApiResultExtraIdempotentReplay] != true {
+		t.Fatalf("%s failed: replayed result should be marked", name)
+	}
+	if _, ok := r2.Extras["_access_token_"]; ok {
+		t.Fatalf("%s failed: stored result should not be affected by post-processing", name)
+	}
+
+	// different key, different caller or no key: handler is called
+	f.Call(handler, NewApiContext().SetApiName("create").SetContextValue(CtxIdempotencyKey, "key2"), auth, params)
+	f.Call(handler, NewApiContext().SetApiName("create").SetContextValue(CtxIdempotencyKey, "key1"), NewApiAuth("app", "other"), params)
+	f.Call(handler, NewApiContext().SetApiName("create"), auth, params)
+	if *counter != 4 {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, 4, *counter)
+	}
+
+	// reusing a key with different params is rejected
+	r := f.Call(handler, NewApiContext().SetApiName("create").SetContextValue(CtxIdempotencyKey, "key1"), auth, NewApiParams().SetParam("title", "other"))
+	if r.Status != StatusErrorClient || *counter != 4 {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, StatusErrorClient, r.Status)
+	}
+}
+
+func TestIdempotencyFilter_NotConfigured(t *testing.T) {
+	name := "TestIdempotencyFilter_NotConfigured"
+	f, handler, counter := _newIdempotencyTestFilter(NewMemoryIdempotencyStore(0), time.Minute)
+	for i := 0; i < 2; i++ {
+		f.Call(handler, NewApiContext().SetApiName("other").SetContextValue(CtxIdempotencyKey, "key"), NewApiAuth("", ""), NewApiParams())
+	}
+	if *counter != 2 {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, 2, *counter)
+	}
+}
+
+func TestIdempotencyFilter_Expiry(t *testing.T) {
+	name := "TestIdempotencyFilter_Expiry"
+	f, handler, counter := _newIdempotencyTestFilter(NewMemoryIdempotencyStore(0), 50*time.Millisecond)
+	for i := 0; i < 2; i++ {
+		f.Call(handler, NewApiContext().SetApiName("create").SetContextValue(CtxIdempotencyKey, "key"), NewApiAuth("", ""), NewApiParams())
+		time.Sleep(60 * time.Millisecond)
+	}
+	if *counter != 2 {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, 2, *counter)
+	}
+}
+
+func TestIdempotencyFilter_ServerError(t *testing.T) {
+	name := "TestIdempotencyFilter_ServerError"
+	counter := 0
+	handler := func(_ *ApiContext, _ *ApiAuth, _ *ApiParams) *ApiResult {
+		counter++
+		return NewApiResult(StatusErrorServer)
+	}
+	f := NewIdempotencyFilter(nil, nil, NewMemoryIdempotencyStore(0), time.Minute, []string{"create"})
+	for i := 0; i < 2; i++ {
+		f.Call(handler, NewApiContext().SetApiName("create").SetContextValue(CtxIdempotencyKey, "key"), NewApiAuth("", ""), NewApiParams())
+	}
+	if counter != 2 {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, 2, counter)
+	}
+}
+
+func TestIdempotencyFilter_Concurrent(t *testing.T) {
+	name := "TestIdempotencyFilter_Concurrent"
+	f, handler, counter := _newIdempotencyTestFilter(NewMemoryIdempotencyStore(0), time.Minute)
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			f.Call(handler, NewApiContext().SetApiName("create").SetContextValue(CtxIdempotencyKey, "key"), NewApiAuth("", ""), NewApiParams())
+		}()
+	}
+	wg.Wait()
+	if *counter != 1 {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, 1, *counter)
+	}
+}
+
+func TestMemoryIdempotencyStore_Capacity(t *testing.T) {
+	name := "TestMemoryIdempotencyStore_Capacity"
+	store := NewMemoryIdempotencyStore(2)
+	now := time.Now()
+	store.Put("k1", &IdempotencyRecord{Result: NewApiResult(StatusOk), Expiry: now.Add(time.Minute)})
+	store.Put("k2", &IdempotencyRecord{Result: NewApiResult(StatusOk), Expiry: now.Add(2 * time.Minute)})
+	store.Put("k3", &IdempotencyRecord{Result: NewApiResult(StatusOk), Expiry: now.Add(3 * time.Minute)})
+	if store.Size() != 2 {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, 2, store.Size())
+	}
+	if r, _ := store.Get("k1"); r != nil {
+		t.Fatalf("%s failed: record expiring soonest should be evicted", name)
+	}
+	if r, _ := store.Get("k3"); r == nil {
+		t.Fatalf("%s failed: expected record k3", name)
+	}
+}
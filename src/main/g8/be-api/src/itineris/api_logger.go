@@ -1,9 +1,14 @@
 package itineris
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
+	"reflect"
+	"strings"
+
+	"main/src/logging"
 )
 
 /*
@@ -108,6 +113,13 @@ func (logger *WriterPerfLogger) writeLog(data map[string]interface{}) {
 
 /*----------------------------------------------------------------------*/
 
+/*
+DefaultSensitiveParams lists names of parameters (case-insensitive) whose values are masked by request loggers.
+*/
+var DefaultSensitiveParams = []string{"password", "pwd", "passwd", "token", "access_token", "accessToken", "secret", "passphrase"}
+
+const redactedValue = "***"
+
 /*
 BaseRequestLogger is base struct to implement API request/response logger.
 */
@@ -125,22 +137,15 @@ type BaseRequestLogger struct {
 	FieldAuth           string
 	FieldParams         string
 	FieldResult         string
-}
 
-/*
-WriterRequestLogger writes API request/response logs to an io.Writer in JSON format.
-*/
-type WriterRequestLogger struct {
-	*BaseRequestLogger
-	writer              io.Writer
-	appName, appVersion string
+	// values of params/context/result fields whose names match (case-insensitive) one of these are masked before logging.
+	SensitiveParams []string
+	appName         string
+	appVersion      string
 }
 
-/*
-NewWriterRequestLogger creates a new WriterRequestLogger instance.
-*/
-func NewWriterRequestLogger(writer io.Writer, appName, appVersion string) *WriterRequestLogger {
-	return &WriterRequestLogger{BaseRequestLogger: &BaseRequestLogger{
+func newBaseRequestLogger(appName, appVersion string) *BaseRequestLogger {
+	return &BaseRequestLogger{
 		FieldAppName:        "api_name",
 		FieldAppVersion:     "api_version",
 		FieldId:             "id",
@@ -154,15 +159,78 @@ func NewWriterRequestLogger(writer io.Writer, appName, appVersion string) *Write
 		FieldAuth:           "auth",
 		FieldParams:         "params",
 		FieldResult:         "result",
-	}, writer: writer, appName: appName, appVersion: appVersion}
+		SensitiveParams:     append([]string{}, DefaultSensitiveParams...),
+		appName:             appName,
+		appVersion:          appVersion,
+	}
+}
+
+func (logger *BaseRequestLogger) isSensitive(name string) bool {
+	for _, p := range logger.SensitiveParams {
+		if strings.EqualFold(p, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeForRedact converts typed maps, slices, arrays, structs and pointers to their generic JSON form
+// (map[string]interface{}/[]interface{}), so that their keys can be checked for sensitive names.
+// It returns false if the value is not of these kinds or cannot be converted.
+func normalizeForRedact(data interface{}) (interface{}, bool) {
+	if data == nil {
+		return nil, false
+	}
+	switch reflect.TypeOf(data).Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array, reflect.Struct, reflect.Ptr:
+		js, err := json.Marshal(data)
+		if err != nil {
+			return nil, false
+		}
+		// numbers are kept as json.Number so that large integers are logged without loss of precision
+		decoder := json.NewDecoder(bytes.NewReader(js))
+		decoder.UseNumber()
+		var result interface{}
+		if err := decoder.Decode(&result); err != nil {
+			return nil, false
+		}
+		return result, true
+	}
+	return nil, false
+}
+
+// redact returns a copy of the input with values of sensitive keys masked (nested maps, slices and structs are processed recursively).
+func (logger *BaseRequestLogger) redact(data interface{}) interface{} {
+	switch v := data.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for k, val := range v {
+			if logger.isSensitive(k) {
+				result[k] = redactedValue
+			} else {
+				result[k] = logger.redact(val)
+			}
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, val := range v {
+			result[i] = logger.redact(val)
+		}
+		return result
+	}
+	if normalized, ok := normalizeForRedact(data); ok {
+		return logger.redact(normalized)
+	}
+	return data
 }
 
-func (logger *WriterRequestLogger) buildAuthMap(auth *ApiAuth) map[string]interface{} {
+func (logger *BaseRequestLogger) buildAuthMap(auth *ApiAuth) map[string]interface{} {
 	accessToken := auth.GetAccessToken()
 	if len(accessToken) <= 3 {
-		accessToken = "***"
+		accessToken = redactedValue
 	} else {
-		accessToken = "***" + accessToken[0:len(accessToken)-3]
+		accessToken = redactedValue + accessToken[len(accessToken)-3:]
 	}
 	return map[string]interface{}{
 		"app_id":      auth.GetAppId(),
@@ -170,11 +238,8 @@ func (logger *WriterRequestLogger) buildAuthMap(auth *ApiAuth) map[string]interf
 	}
 }
 
-/*
-PreApiCall implements IApiLogger.PreApiCall
-*/
-func (logger *WriterRequestLogger) PreApiCall(concurrency int64, ctx *ApiContext, auth *ApiAuth, params *ApiParams) {
-	data := map[string]interface{}{
+func (logger *BaseRequestLogger) buildPreApiCallData(concurrency int64, ctx *ApiContext, auth *ApiAuth, params *ApiParams) map[string]interface{} {
+	return map[string]interface{}{
 		logger.FieldAppName:        logger.appName,
 		logger.FieldAppVersion:     logger.appVersion,
 		logger.FieldId:             ctx.GetId(),
@@ -183,33 +248,55 @@ func (logger *WriterRequestLogger) PreApiCall(concurrency int64, ctx *ApiContext
 		logger.FieldGateway:        ctx.GetGateway(),
 		logger.FieldTimestampStart: ctx.GetTimestamp().UnixNano() / 1000000, // convert to milliseconds
 		logger.FieldConcurrency:    concurrency,
-		logger.FieldContext:        ctx.GetAllContextValues(),
+		logger.FieldContext:        logger.redact(ctx.GetAllContextValues()),
 		logger.FieldAuth:           logger.buildAuthMap(auth),
-		logger.FieldParams:         params.GetAllParams(),
+		logger.FieldParams:         logger.redact(params.GetAllParams()),
 	}
-	logger.writeLog(data)
+}
+
+func (logger *BaseRequestLogger) buildPostApiCallData(durationNano, concurrency int64, ctx *ApiContext, auth *ApiAuth, params *ApiParams, result *ApiResult) map[string]interface{} {
+	data := logger.buildPreApiCallData(concurrency, ctx, auth, params)
+	data[logger.FieldStage] = "END"
+	data[logger.FieldDuration] = durationNano
+	if result != nil {
+		resultMap := result.ToMap()
+		if _, ok := resultMap["data"]; ok && result.IsDataSensitive() {
+			resultMap["data"] = redactedValue
+		}
+		data[logger.FieldResult] = logger.redact(resultMap)
+	}
+	return data
+}
+
+/*
+WriterRequestLogger writes API request/response logs to an io.Writer in JSON format.
+
+Values of sensitive params (see BaseRequestLogger.SensitiveParams) and sensitive result data (see ApiResult.SetDataSensitive) are masked.
+*/
+type WriterRequestLogger struct {
+	*BaseRequestLogger
+	writer io.Writer
+}
+
+/*
+NewWriterRequestLogger creates a new WriterRequestLogger instance.
+*/
+func NewWriterRequestLogger(writer io.Writer, appName, appVersion string) *WriterRequestLogger {
+	return &WriterRequestLogger{BaseRequestLogger: newBaseRequestLogger(appName, appVersion), writer: writer}
+}
+
+/*
+PreApiCall implements IApiLogger.PreApiCall
+*/
+func (logger *WriterRequestLogger) PreApiCall(concurrency int64, ctx *ApiContext, auth *ApiAuth, params *ApiParams) {
+	logger.writeLog(logger.buildPreApiCallData(concurrency, ctx, auth, params))
 }
 
 /*
 PostApiCall implements IApiLogger.PostApiCall
 */
 func (logger *WriterRequestLogger) PostApiCall(durationNano, concurrency int64, ctx *ApiContext, auth *ApiAuth, params *ApiParams, result *ApiResult) {
-	data := map[string]interface{}{
-		logger.FieldAppName:        logger.appName,
-		logger.FieldAppVersion:     logger.appVersion,
-		logger.FieldId:             ctx.GetId(),
-		logger.FieldStage:          "END",
-		logger.FieldApiName:        ctx.GetApiName(),
-		logger.FieldGateway:        ctx.GetGateway(),
-		logger.FieldTimestampStart: ctx.GetTimestamp().UnixNano() / 1000000, // convert to milliseconds
-		logger.FieldDuration:       durationNano,
-		logger.FieldConcurrency:    concurrency,
-		logger.FieldContext:        ctx.GetAllContextValues(),
-		logger.FieldAuth:           logger.buildAuthMap(auth),
-		logger.FieldParams:         params.GetAllParams(),
-		logger.FieldResult:         result.ToMap(),
-	}
-	logger.writeLog(data)
+	logger.writeLog(logger.buildPostApiCallData(durationNano, concurrency, ctx, auth, params, result))
 }
 
 func (logger *WriterRequestLogger) writeLog(data map[string]interface{}) {
@@ -218,3 +305,78 @@ func (logger *WriterRequestLogger) writeLog(data map[string]interface{}) {
 		fmt.Fprintln(logger.writer, string(js))
 	}
 }
+
+/*----------------------------------------------------------------------*/
+
+/*
+StructuredRequestLogger writes API request/response logs as structured entries to a logging.ILogger.
+
+Entries are written at level logging.LevelInfo; values of sensitive params (see BaseRequestLogger.SensitiveParams)
+and sensitive result data (see ApiResult.SetDataSensitive) are masked.
+
+Available since template-v0.5.0
+*/
+type StructuredRequestLogger struct {
+	*BaseRequestLogger
+	logger logging.ILogger
+}
+
+/*
+NewStructuredRequestLogger creates a new StructuredRequestLogger instance.
+*/
+func NewStructuredRequestLogger(logger logging.ILogger, appName, appVersion string) *StructuredRequestLogger {
+	base := newBaseRequestLogger(appName, appVersion)
+	base.FieldId = FieldRequestId
+	return &StructuredRequestLogger{BaseRequestLogger: base, logger: logger}
+}
+
+/*
+PreApiCall implements IApiLogger.PreApiCall
+*/
+func (logger *StructuredRequestLogger) PreApiCall(concurrency int64, ctx *ApiContext, auth *ApiAuth, params *ApiParams) {
+	if logger.logger.IsEnabled(logging.LevelInfo) {
+		logger.logger.With(logger.buildPreApiCallData(concurrency, ctx, auth, params)).Log(logging.LevelInfo, "API call started")
+	}
+}
+
+/*
+PostApiCall implements IApiLogger.PostApiCall
+*/
+func (logger *StructuredRequestLogger) PostApiCall(durationNano, concurrency int64, ctx *ApiContext, auth *ApiAuth, params *ApiParams, result *ApiResult) {
+	if logger.logger.IsEnabled(logging.LevelInfo) {
+		logger.logger.With(logger.buildPostApiCallData(durationNano, concurrency, ctx, auth, params, result)).Log(logging.LevelInfo, "API call finished")
+	}
+}
+
+/*----------------------------------------------------------------------*/
+
+const (
+	// FieldRequestId is the log field that holds the API context's unique id, used to correlate log entries of the same API call.
+	FieldRequestId = "request_id"
+
+	// FieldApiName is the log field that holds the API name.
+	FieldApiName = "api"
+
+	// FieldGateway is the log field that holds the gateway name.
+	FieldGateway = "gw"
+)
+
+/*
+ContextLogger returns a logger derived from the supplied one that attaches correlation fields of the API context
+(request id, API name and gateway) to every entry it writes.
+
+Available since template-v0.5.0
+*/
+func ContextLogger(logger logging.ILogger, ctx *ApiContext) logging.ILogger {
+	if logger == nil {
+		logger = logging.Default()
+	}
+	if ctx == nil {
+		return logger
+	}
+	return logger.With(logging.Fields{
+		FieldRequestId: ctx.GetContextValueAsString(ctxId),
+		FieldApiName:   ctx.GetContextValueAsString(ctxApiName),
+		FieldGateway:   ctx.GetContextValueAsString(ctxGateway),
+	})
+}
@@ -0,0 +1,146 @@
+package itineris
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"main/src/logging"
+)
+
+func TestWriterRequestLogger_Redact(t *testing.T) {
+	name := "TestWriterRequestLogger_Redact"
+	buf := &bytes.Buffer{}
+	logger := NewWriterRequestLogger(buf, "myApp", "1.0")
+	ctx := NewApiContext().SetApiName("login").SetGateway("TEST")
+	params := NewApiParams().SetParam("username", "admin").SetParam("Password", "secret1").
+		SetParam("nested", map[string]interface{}{"token": "secret2", "other": "visible"})
+	logger.PreApiCall(1, ctx, NewApiAuth("myApp", "my-access-token"), params)
+	output := buf.String()
+	for _, secret := range []string{"secret1", "secret2", "my-access-token"} {
+		if strings.Contains(output, secret) {
+			t.Fatalf("%s failed: sensitive value %#v should be masked in %s", name, secret, output)
+		}
+	}
+	for _, v := range []string{"admin", "visible"} {
+		if !strings.Contains(output, v) {
+			t.Fatalf("%s failed: expected %#v in %s", name, v, output)
+		}
+	}
+	if v := params.GetParam("Password"); v != "secret1" {
+		t.Fatalf("%s failed: params should not be modified, expected %#v but received %#v", name, "secret1", v)
+	}
+}
+
+func TestWriterRequestLogger_RedactResult(t *testing.T) {
+	name := "TestWriterRequestLogger_RedactResult"
+	buf := &bytes.Buffer{}
+	logger := NewWriterRequestLogger(buf, "myApp", "1.0")
+	logger.SensitiveParams = append(logger.SensitiveParams, "_renewed_token_")
+	ctx := NewApiContext().SetApiName("login").SetGateway("TEST")
+	params := NewApiParams().SetParam("username", "admin").SetParam("password", "secret1")
+	result := NewApiResult(StatusOk).SetData("secret2").SetDataSensitive(true).
+		AddExtraInfo("_renewed_token_", "secret3").AddExtraInfo("other", "visible")
+	logger.PostApiCall(1000, 1, ctx, NewApiAuth("myApp", ""), params, result)
+	output := buf.String()
+	for _, secret := range []string{"secret1", "secret2", "secret3"} {
+		if strings.Contains(output, secret) {
+			t.Fatalf("%s failed: sensitive value %#v should be masked in %s", name, secret, output)
+		}
+	}
+	for _, v := range []string{"admin", "visible"} {
+		if !strings.Contains(output, v) {
+			t.Fatalf("%s failed: expected %#v in %s", name, v, output)
+		}
+	}
+	if result.GetData() != "secret2" || result.GetExtras()["_renewed_token_"] != "secret3" {
+		t.Fatalf("%s failed: result should not be modified, received %#v", name, result.ToMap())
+	}
+
+	buf.Reset()
+	logger.PostApiCall(1000, 1, ctx, NewApiAuth("myApp", ""), params, NewApiResult(StatusOk).SetData("not-a-secret"))
+	if !strings.Contains(buf.String(), "not-a-secret") {
+		t.Fatalf("%s failed: expected %#v in %s", name, "not-a-secret", buf.String())
+	}
+}
+
+func TestStructuredRequestLogger(t *testing.T) {
+	name := "TestStructuredRequestLogger"
+	buf := &bytes.Buffer{}
+	root := logging.NewLogger(buf, logging.NewJsonFormatter(), logging.LevelInfo)
+	logger := NewStructuredRequestLogger(root, "myApp", "1.0")
+	ctx := NewApiContext().SetApiName("login").SetGateway("TEST")
+	params := NewApiParams().SetParam("password", "secret")
+	logger.PostApiCall(1000, 1, ctx, NewApiAuth("myApp", ""), params, NewApiResult(StatusOk))
+	data := map[string]interface{}{}
+	if err := json.Unmarshal(buf.Bytes(), &data); err != nil {
+		t.Fatalf("%s failed: %s", name, err)
+	}
+	if data[FieldRequestId] != ctx.GetId() {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, ctx.GetId(), data[FieldRequestId])
+	}
+	if strings.Contains(buf.String(), "secret") {
+		t.Fatalf("%s failed: sensitive value should be masked in %s", name, buf.String())
+	}
+
+	buf.Reset()
+	root.SetLevel(logging.LevelWarn)
+	logger.PreApiCall(1, ctx, NewApiAuth("myApp", ""), params)
+	if buf.Len() != 0 {
+		t.Fatalf("%s failed: expected no output but received %#v", name, buf.String())
+	}
+}
+
+func TestContextLogger(t *testing.T) {
+	name := "TestContextLogger"
+	buf := &bytes.Buffer{}
+	root := logging.NewLogger(buf, logging.NewJsonFormatter(), logging.LevelInfo)
+	ctx := NewApiContext().SetApiName("myApi").SetGateway("TEST")
+	ContextLogger(root, ctx).Infof("message")
+	data := map[string]interface{}{}
+	if err := json.Unmarshal(buf.Bytes(), &data); err != nil {
+		t.Fatalf("%s failed: %s", name, err)
+	}
+	expected := map[string]interface{}{FieldRequestId: ctx.GetId(), FieldApiName: "myApi", FieldGateway: "TEST"}
+	for k, v := range expected {
+		if data[k] != v {
+			t.Fatalf("%s failed: expected %#v for field %s but received %#v", name, v, k, data[k])
+		}
+	}
+}
+
+type testLoginPayload struct {
+	Username string            `json:"username"`
+	Password string            `json:"password"`
+	Id       int64             `json:"id"`
+	Extras   map[string]string `json:"extras"`
+}
+
+func TestWriterRequestLogger_RedactTyped(t *testing.T) {
+	name := "TestWriterRequestLogger_RedactTyped"
+	buf := &bytes.Buffer{}
+	logger := NewWriterRequestLogger(buf, "myApp", "1.0")
+	ctx := NewApiContext().SetApiName("login").SetGateway("TEST")
+	payload := &testLoginPayload{Username: "admin", Password: "secret1", Id: 1234567890123456789,
+		Extras: map[string]string{"token": "secret2", "other": "visible1"}}
+	params := NewApiParams().
+		SetParam("typed_map", map[string]string{"password": "secret3", "other": "visible2"}).
+		SetParam("struct", payload).
+		SetParam("list", []map[string]interface{}{{"token": "secret4", "other": "visible3"}})
+	logger.PreApiCall(1, ctx, NewApiAuth("myApp", ""), params)
+	output := buf.String()
+	for _, secret := range []string{"secret1", "secret2", "secret3", "secret4"} {
+		if strings.Contains(output, secret) {
+			t.Fatalf("%s failed: sensitive value %#v should be masked in %s", name, secret, output)
+		}
+	}
+	for _, v := range []string{"admin", "visible1", "visible2", "visible3", "1234567890123456789"} {
+		if !strings.Contains(output, v) {
+			t.Fatalf("%s failed: expected %#v in %s", name, v, output)
+		}
+	}
+	if payload.Password != "secret1" || payload.Extras["token"] != "secret2" {
+		t.Fatalf("%s failed: params should not be modified, received %#v", name, payload)
+	}
+}
@@ -1,34 +1,41 @@
 package itineris
 
-import "sync/atomic"
+import (
+	"sync"
+	"sync/atomic"
+)
 
 /*
 ApiRouter is responsible to routing API call to handler.
 */
 type ApiRouter struct {
 	concurrency int64
+	lock        sync.RWMutex
 	apiFilter   IApiFilter
 	handlersMap map[string]IApiHandler
+	schemasMap  map[string]*ApiSchema
 }
 
 /*
 NewApiRouter creates a new ApiRouter instance.
 */
 func NewApiRouter() *ApiRouter {
-	return &ApiRouter{concurrency: 0, handlersMap: map[string]IApiHandler{}}
+	return &ApiRouter{concurrency: 0, handlersMap: map[string]IApiHandler{}, schemasMap: map[string]*ApiSchema{}}
 }
 
 /*
 GetConcurrency returns current number of concurrent API calls.
 */
 func (router *ApiRouter) GetConcurrency() int64 {
-	return router.concurrency
+	return atomic.LoadInt64(&router.concurrency)
 }
 
 /*
 GetApiFilter returns the associated api-filter.
 */
 func (router *ApiRouter) GetApiFilter() IApiFilter {
+	router.lock.RLock()
+	defer router.lock.RUnlock()
 	return router.apiFilter
 }
 
@@ -36,6 +43,8 @@ func (router *ApiRouter) GetApiFilter() IApiFilter {
 SetApiFilter associated an api-filter to the router.
 */
 func (router *ApiRouter) SetApiFilter(apiFilter IApiFilter) *ApiRouter {
+	router.lock.Lock()
+	defer router.lock.Unlock()
 	router.apiFilter = apiFilter
 	return router
 }
@@ -44,6 +53,8 @@ func (router *ApiRouter) SetApiFilter(apiFilter IApiFilter) *ApiRouter {
 GetHandler returns an api-handler by name.
 */
 func (router *ApiRouter) GetHandler(apiName string) IApiHandler {
+	router.lock.RLock()
+	defer router.lock.RUnlock()
 	f, ok := router.handlersMap[apiName]
 	if ok {
 		return f
@@ -58,6 +69,8 @@ func (router *ApiRouter) SetHandler(apiName string, handler IApiHandler) *ApiRou
 	if handler == nil {
 		return router.RemoveHandler(apiName)
 	}
+	router.lock.Lock()
+	defer router.lock.Unlock()
 	router.handlersMap[apiName] = handler
 	return router
 }
@@ -66,32 +79,51 @@ func (router *ApiRouter) SetHandler(apiName string, handler IApiHandler) *ApiRou
 RemoveHandler removes an api-handler.
 */
 func (router *ApiRouter) RemoveHandler(apiName string) *ApiRouter {
+	router.lock.Lock()
+	defer router.lock.Unlock()
 	delete(router.handlersMap, apiName)
 	return router
 }
 
 /*
-GetAllHandlers returns all api-handlers as a map.
+GetAllHandlers returns a snapshot of all api-handlers as a map.
 */
 func (router *ApiRouter) GetAllHandlers() map[string]IApiHandler {
-	return router.handlersMap
+	router.lock.RLock()
+	defer router.lock.RUnlock()
+	result := make(map[string]IApiHandler, len(router.handlersMap))
+	for k, v := range router.handlersMap {
+		result[k] = v
+	}
+	return result
 }
 
 /*
 CallApi performs an API call.
 */
 func (router *ApiRouter) CallApi(ctx *ApiContext, auth *ApiAuth, params *ApiParams) *ApiResult {
-	atomic.AddInt64(&router.concurrency, 1)
-	defer atomic.AddInt64(&router.concurrency, -1)
 	apiName := ctx.GetApiName()
 	handler := router.GetHandler(apiName)
-	var apiResult *ApiResult
 	if handler == nil {
-		apiResult = NewApiResult(StatusNotImplemented).SetMessage("No handler for API [" + apiName + "].")
-	} else if router.apiFilter != nil {
-		apiResult = router.apiFilter.Call(handler, ctx, auth, params)
-	} else {
-		apiResult = handler(ctx, auth, params)
+		atomic.AddInt64(&router.concurrency, 1)
+		defer atomic.AddInt64(&router.concurrency, -1)
+		return NewApiResult(StatusNotImplemented).SetMessage("No handler for API [" + apiName + "].")
+	}
+	return router.CallHandler(ctx, auth, params, handler)
+}
+
+/*
+CallHandler performs an API call with the supplied handler (which is not necessarily registered with the router), routed through the api-filter chain.
+
+This function is useful for gateway-level operations that need the same pre-/post-processing as normal API calls (e.g. authentication check).
+
+Available since template-v0.5.0
+*/
+func (router *ApiRouter) CallHandler(ctx *ApiContext, auth *ApiAuth, params *ApiParams, handler IApiHandler) *ApiResult {
+	atomic.AddInt64(&router.concurrency, 1)
+	defer atomic.AddInt64(&router.concurrency, -1)
+	if apiFilter := router.GetApiFilter(); apiFilter != nil {
+		return apiFilter.Call(handler, ctx, auth, params)
 	}
-	return apiResult
+	return handler(ctx, auth, params)
 }
@@ -0,0 +1,97 @@
+package itineris
+
+// Data types of a Schema, same as JSON Schema's.
+//
+// Available since template-v0.5.0
+const (
+	TypeString  = "string"
+	TypeInteger = "integer"
+	TypeNumber  = "number"
+	TypeBoolean = "boolean"
+	TypeObject  = "object"
+	TypeArray   = "array"
+)
+
+// Credentials an API requires, see ApiSchema.Security.
+//
+// Available since template-v0.5.0
+const (
+	SecurityAppId       = "appId"
+	SecurityAccessToken = "accessToken"
+)
+
+/*
+Schema describes an API parameter or a piece of API result's data, modelled after JSON Schema.
+
+Available since template-v0.5.0
+*/
+type Schema struct {
+	Type        string             // one of TypeString, TypeInteger, TypeNumber, TypeBoolean, TypeObject, TypeArray
+	Description string             // human-readable description
+	Format      string             // optional format hint, e.g. "date-time", "email"
+	Required    bool               // (API param or object's property) the field must be present
+	Enum        []interface{}      // if not empty, value must be one of these
+	MinLength   int                // (string) minimum length, 0 means no limit
+	MaxLength   int                // (string) maximum length, 0 means no limit
+	Pattern     string             // (string) regular expression the value must match
+	Properties  map[string]*Schema // (object) properties of the object
+	Items       *Schema            // (array) schema of array's items
+	Example     interface{}        // optional example value
+}
+
+/*
+ApiSchema describes an API: its parameters and the "data" part of its result.
+
+Available since template-v0.5.0
+*/
+type ApiSchema struct {
+	Summary     string             // short summary of the API
+	Description string             // longer description of the API
+	Tags        []string           // tags to group APIs
+	Params      map[string]*Schema // API parameters, keyed by param name
+	Response    *Schema            // schema of API result's "data" field
+	Security    []string           // credentials the API requires (SecurityAppId, SecurityAccessToken), nil means all of them
+}
+
+/*
+SetApiSchema associates a schema with an api name.
+*/
+func (router *ApiRouter) SetApiSchema(apiName string, schema *ApiSchema) *ApiRouter {
+	router.lock.Lock()
+	defer router.lock.Unlock()
+	if schema == nil {
+		delete(router.schemasMap, apiName)
+	} else {
+		router.schemasMap[apiName] = schema
+	}
+	return router
+}
+
+/*
+SetHandlerWithSchema maps an handler to api name and associates a schema with the api name.
+*/
+func (router *ApiRouter) SetHandlerWithSchema(apiName string, handler IApiHandler, schema *ApiSchema) *ApiRouter {
+	return router.SetHandler(apiName, handler).SetApiSchema(apiName, schema)
+}
+
+/*
+GetApiSchema returns the schema associated with an api name, nil if none.
+*/
+func (router *ApiRouter) GetApiSchema(apiName string) *ApiSchema {
+	router.lock.RLock()
+	defer router.lock.RUnlock()
+	return router.schemasMap[apiName]
+}
+
+/*
+GetAllApiSchemas returns a snapshot of all api schemas as a map.
+*/
+func (router *ApiRouter) GetAllApiSchemas() map[string]*ApiSchema {
+	router.lock.RLock()
+	defer router.lock.RUnlock()
+	result := make(map[string]*ApiSchema, len(router.schemasMap))
+	for k, v := range router.schemasMap {
+		result[k] = v
+	}
+	return result
+}
@@ -0,0 +1,30 @@
+package itineris
+
+import (
+	"testing"
+)
+
+func TestApiRouter_ApiSchema(t *testing.T) {
+	name := "TestApiRouter_ApiSchema"
+	router := NewApiRouter()
+	schema := &ApiSchema{Summary: "echo", Params: map[string]*Schema{"value": {Type: TypeString, Required: true}}}
+	router.SetHandlerWithSchema("echo", func(_ *ApiContext, _ *ApiAuth, _ *ApiParams) *ApiResult {
+		return NewApiResult(StatusOk)
+	}, schema)
+	if router.GetHandler("echo") == nil {
+		t.Fatalf("%s failed: handler should be registered", name)
+	}
+	if s := router.GetApiSchema("echo"); s != schema {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, schema, s)
+	}
+	if s := router.GetApiSchema("notfound"); s != nil {
+		t.Fatalf("%s failed: expected nil but received %#v", name, s)
+	}
+	if schemas := router.GetAllApiSchemas(); len(schemas) != 1 || schemas["echo"] != schema {
+		t.Fatalf("%s failed: unexpected schemas %#v", name, schemas)
+	}
+	router.SetApiSchema("echo", nil)
+	if s := router.GetApiSchema("echo"); s != nil {
+		t.Fatalf("%s failed: expected nil but received %#v", name, s)
+	}
+}
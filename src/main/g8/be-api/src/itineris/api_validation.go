@@ -0,0 +1,297 @@
+package itineris
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Codes of validation errors, see FieldError.
+//
+// Available since template-v0.5.0
+const (
+	ValidationRequired  = "required"
+	ValidationType      = "type"
+	ValidationMinLength = "min_length"
+	ValidationMaxLength = "max_length"
+	ValidationEnum      = "enum"
+	ValidationPattern   = "pattern"
+)
+
+/*
+FieldError describes why a field failed validation.
+
+Available since template-v0.5.0
+*/
+type FieldError struct {
+	Field  string                 // path of the field, e.g. "title", "author.name" or "tags[1]"
+	Code   string                 // one of ValidationRequired, ValidationType, ValidationMinLength, ValidationMaxLength, ValidationEnum, ValidationPattern
+	Params map[string]interface{} // details of the error, e.g. {"min_length": 3}
+}
+
+/*
+DefaultMessage returns the (English) message describing the error.
+*/
+func (e *FieldError) DefaultMessage() string {
+	switch e.Code {
+	case ValidationRequired:
+		return fmt.Sprintf("Parameter %s is required.", e.Field)
+	case ValidationType:
+		return fmt.Sprintf("Parameter %s must be of type %v.", e.Field, e.Params["type"])
+	case ValidationMinLength:
+		return fmt.Sprintf("Parameter %s must have at least %v characters.", e.Field, e.Params["min_length"])
+	case ValidationMaxLength:
+		return fmt.Sprintf("Parameter %s must have at most %v characters.", e.Field, e.Params["max_length"])
+	case ValidationEnum:
+		return fmt.Sprintf("Parameter %s must be one of %v.", e.Field, e.Params["enum"])
+	case ValidationPattern:
+		return fmt.Sprintf("Parameter %s does not match the required format.", e.Field)
+	}
+	return fmt.Sprintf("Invalid value for parameter %s.", e.Field)
+}
+
+var patternCache sync.Map // pattern -> *regexp.Regexp
+
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if re, ok := patternCache.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	patternCache.Store(pattern, re)
+	return re, nil
+}
+
+// isEmptyValue checks if a value is considered "missing": nil or a blank string.
+func isEmptyValue(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	s, ok := value.(string)
+	return ok && strings.TrimSpace(s) == ""
+}
+
+// checkType checks if value is of the specified type. Since HTTP query/path params are always strings,
+// strings that can be parsed as integer, number or boolean are accepted for those types.
+func checkType(typ string, value interface{}) bool {
+	switch typ {
+	case "":
+		return true
+	case TypeString:
+		_, ok := value.(string)
+		return ok
+	case TypeBoolean:
+		switch v := value.(type) {
+		case bool:
+			return true
+		case string:
+			_, err := strconv.ParseBool(v)
+			return err == nil
+		}
+		return false
+	case TypeInteger, TypeNumber:
+		var f float64
+		switch v := value.(type) {
+		case string:
+			var err error
+			if f, err = strconv.ParseFloat(strings.TrimSpace(v), 64); err != nil {
+				return false
+			}
+		default:
+			rv := reflect.ValueOf(value)
+			switch rv.Kind() {
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+				reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+				return true
+			case reflect.Float32, reflect.Float64:
+				f = rv.Float()
+			default:
+				return false
+			}
+		}
+		return typ == TypeNumber || f == math.Trunc(f)
+	case TypeObject:
+		_, ok := value.(map[string]interface{})
+		return ok
+	case TypeArray:
+		if value == nil {
+			return false
+		}
+		kind := reflect.TypeOf(value).Kind()
+		return kind == reflect.Slice || kind == reflect.Array
+	}
+	return false
+}
+
+/*
+Validate validates a value against the schema, field is the path of the value used in the returned errors.
+A nil (or blank string) value is valid if the schema is not required.
+*/
+func (s *Schema) Validate(field string, value interface{}) []*FieldError {
+	if s == nil {
+		return nil
+	}
+	if isEmptyValue(value) {
+		if s.Required {
+			return []*FieldError{{Field: field, Code: ValidationRequired}}
+		}
+		return nil
+	}
+	if !checkType(s.Type, value) {
+		return []*FieldError{{Field: field, Code: ValidationType, Params: map[string]interface{}{"type": s.Type}}}
+	}
+
+	errors := make([]*FieldError, 0)
+	if len(s.Enum) > 0 {
+		found := false
+		for _, v := range s.Enum {
+			if fmt.Sprint(v) == fmt.Sprint(value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			errors = append(errors, &FieldError{Field: field, Code: ValidationEnum, Params: map[string]interface{}{"enum": s.Enum}})
+		}
+	}
+	if str, ok := value.(string); ok {
+		length := utf8.RuneCountInString(str)
+		if s.MinLength > 0 && length < s.MinLength {
+			errors = append(errors, &FieldError{Field: field, Code: ValidationMinLength, Params: map[string]interface{}{"min_length": s.MinLength}})
+		}
+		if s.MaxLength > 0 && length > s.MaxLength {
+			errors = append(errors, &FieldError{Field: field, Code: ValidationMaxLength, Params: map[string]interface{}{"max_length": s.MaxLength}})
+		}
+		if s.Pattern != "" {
+			if re, err := compilePattern(s.Pattern); err != nil || !re.MatchString(str) {
+				errors = append(errors, &FieldError{Field: field, Code: ValidationPattern, Params: map[string]interface{}{"pattern": s.Pattern}})
+			}
+		}
+	}
+	if obj, ok := value.(map[string]interface{}); ok && len(s.Properties) > 0 {
+		errors = append(errors, validateFields(field+".", s.Properties, func(name string) interface{} { return obj[name] })...)
+	}
+	if s.Items != nil && s.Type == TypeArray {
+		rv := reflect.ValueOf(value)
+		for i := 0; i < rv.Len(); i++ {
+			errors = append(errors, s.Items.Validate(fmt.Sprintf("%s[%d]", field, i), rv.Index(i).Interface())...)
+		}
+	}
+	return errors
+}
+
+// validateFields validates fields in order of their names, so that errors are reported in a deterministic order.
+func validateFields(prefix string, fields map[string]*Schema, getValue func(name string) interface{}) []*FieldError {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	errors := make([]*FieldError, 0)
+	for _, name := range names {
+		errors = append(errors, fields[name].Validate(prefix+name, getValue(name))...)
+	}
+	return errors
+}
+
+/*
+ValidateParams validates API params against the API schema's params, returns all errors found (empty if params are valid).
+
+Available since template-v0.5.0
+*/
+func ValidateParams(schema *ApiSchema, params *ApiParams) []*FieldError {
+	if schema == nil || len(schema.Params) == 0 {
+		return []*FieldError{}
+	}
+	return validateFields("", schema.Params, func(name string) interface{} {
+		if params == nil {
+			return nil
+		}
+		return params.GetParam(name)
+	})
+}
+
+/*
+ValidationFilter validates API params against the schema registered with the ApiRouter (see ApiRouter.SetApiSchema) before calling the API.
+
+If validation fails, API result has status StatusErrorClient and its data is {"errors": [{"field": ..., "code": ..., "message": ...}, ...]}.
+APIs without schema are not validated.
+
+Available since template-v0.5.0
+*/
+type ValidationFilter struct {
+	*BaseApiFilter
+	messageFunc func(ctx *ApiContext, fieldErr *FieldError) string
+	summaryFunc func(ctx *ApiContext, fieldErrors []*FieldError) string
+}
+
+/*
+NewValidationFilter creates a new ValidationFilter instance.
+*/
+func NewValidationFilter(apiRouter *ApiRouter, nextFilter IApiFilter) *ValidationFilter {
+	return &ValidationFilter{BaseApiFilter: &BaseApiFilter{ApiRouter: apiRouter, NextFilter: nextFilter}}
+}
+
+/*
+SetMessageFunc sets the function to render the (e.g. localized) message of a field error. By default, FieldError.DefaultMessage is used.
+*/
+func (f *ValidationFilter) SetMessageFunc(messageFunc func(ctx *ApiContext, fieldErr *FieldError) string) *ValidationFilter {
+	f.messageFunc = messageFunc
+	return f
+}
+
+/*
+SetSummaryFunc sets the function to render the (e.g. localized) message of the API result when validation fails.
+*/
+func (f *ValidationFilter) SetSummaryFunc(summaryFunc func(ctx *ApiContext, fieldErrors []*FieldError) string) *ValidationFilter {
+	f.summaryFunc = summaryFunc
+	return f
+}
+
+/*
+Call implements IApiFilter.Call
+*/
+func (f *ValidationFilter) Call(handler IApiHandler, ctx *ApiContext, auth *ApiAuth, params *ApiParams) *ApiResult {
+	if f.ApiRouter != nil {
+		if fieldErrors := ValidateParams(f.ApiRouter.GetApiSchema(ctx.GetApiName()), params); len(fieldErrors) > 0 {
+			return f.buildErrorResult(ctx, fieldErrors)
+		}
+	}
+	if f.NextFilter != nil {
+		return f.NextFilter.Call(handler, ctx, auth, params)
+	}
+	return handler(ctx, auth, params)
+}
+
+func (f *ValidationFilter) buildErrorResult(ctx *ApiContext, fieldErrors []*FieldError) *ApiResult {
+	errors := make([]map[string]interface{}, len(fieldErrors))
+	for i, e := range fieldErrors {
+		msg := ""
+		if f.messageFunc != nil {
+			msg = f.messageFunc(ctx, e)
+		}
+		if msg == "" {
+			msg = e.DefaultMessage()
+		}
+		errors[i] = map[string]interface{}{"field": e.Field, "code": e.Code, "message": msg}
+		if len(e.Params) > 0 {
+			errors[i]["params"] = e.Params
+		}
+	}
+	summary := ""
+	if f.summaryFunc != nil {
+		summary = f.summaryFunc(ctx, fieldErrors)
+	}
+	if summary == "" {
+		summary = errors[0]["message"].(string)
+	}
+	return NewApiResult(StatusErrorClient).SetMessage(summary).SetData(map[string]interface{}{"errors": errors})
+}
@@ -0,0 +1,109 @@
+package itineris
+
+import (
+	"testing"
+)
+
+func TestSchema_Validate(t *testing.T) {
+	name := "TestSchema_Validate"
+	testCases := []struct {
+		schema *Schema
+		value  interface{}
+		codes  []string
+	}{
+		{&Schema{Type: TypeString}, nil, []string{}},
+		{&Schema{Type: TypeString, Required: true}, nil, []string{ValidationRequired}},
+		{&Schema{Type: TypeString, Required: true}, "  ", []string{ValidationRequired}},
+		{&Schema{Type: TypeString}, 1, []string{ValidationType}},
+		{&Schema{Type: TypeInteger}, "12", []string{}},
+		{&Schema{Type: TypeInteger}, 12.0, []string{}},
+		{&Schema{Type: TypeInteger}, 12.5, []string{ValidationType}},
+		{&Schema{Type: TypeInteger}, "abc", []string{ValidationType}},
+		{&Schema{Type: TypeNumber}, "12.5", []string{}},
+		{&Schema{Type: TypeBoolean}, "true", []string{}},
+		{&Schema{Type: TypeBoolean}, "yes", []string{ValidationType}},
+		{&Schema{Type: TypeString, MinLength: 3, MaxLength: 5}, "ab", []string{ValidationMinLength}},
+		{&Schema{Type: TypeString, MinLength: 3, MaxLength: 5}, "abcdef", []string{ValidationMaxLength}},
+		{&Schema{Type: TypeString, MinLength: 3, MaxLength: 5}, "ươꝏ", []string{}},
+		{&Schema{Type: TypeString, Enum: []interface{}{"form", "exter"}}, "exter", []string{}},
+		{&Schema{Type: TypeString, Enum: []interface{}{"form", "exter"}}, "other", []string{ValidationEnum}},
+		{&Schema{Type: TypeInteger, Enum: []interface{}{-1, 0, 1}}, "1", []string{}},
+		{&Schema{Type: TypeString, Pattern: `^[a-z]+\d*$`}, "abc12", []string{}},
+		{&Schema{Type: TypeString, Pattern: `^[a-z]+\d*$`}, "12abc", []string{ValidationPattern}},
+		{&Schema{Type: TypeString, MinLength: 5, Pattern: `^\d+$`}, "abc", []string{ValidationMinLength, ValidationPattern}},
+	}
+	for i, tc := range testCases {
+		errors := tc.schema.Validate("f", tc.value)
+		if len(errors) != len(tc.codes) {
+			t.Fatalf("%s failed at case #%d: expected %#v but received %#v", name, i, tc.codes, errors)
+		}
+		for j, e := range errors {
+			if e.Field != "f" || e.Code != tc.codes[j] {
+				t.Fatalf("%s failed at case #%d: expected %#v but received %#v", name, i, tc.codes[j], e)
+			}
+		}
+	}
+}
+
+func TestSchema_ValidateNested(t *testing.T) {
+	name := "TestSchema_ValidateNested"
+	schema := &Schema{Type: TypeObject, Properties: map[string]*Schema{
+		"name": {Type: TypeString, Required: true},
+		"tags": {Type: TypeArray, Items: &Schema{Type: TypeString, MaxLength: 3}},
+	}}
+	value := map[string]interface{}{"tags": []interface{}{"a", "abcd", 1}}
+	errors := schema.Validate("author", value)
+	expected := []FieldError{
+		{Field: "author.name", Code: ValidationRequired},
+		{Field: "author.tags[1]", Code: ValidationMaxLength},
+		{Field: "author.tags[2]", Code: ValidationType},
+	}
+	if len(errors) != len(expected) {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, expected, errors)
+	}
+	for i, e := range errors {
+		if e.Field != expected[i].Field || e.Code != expected[i].Code {
+			t.Fatalf("%s failed: expected %#v but received %#v", name, expected[i], e)
+		}
+	}
+}
+
+func TestValidationFilter(t *testing.T) {
+	name := "TestValidationFilter"
+	router := NewApiRouter()
+	router.SetApiFilter(NewValidationFilter(router, nil).SetMessageFunc(func(_ *ApiContext, e *FieldError) string {
+		if e.Code == ValidationRequired {
+			return "missing " + e.Field
+		}
+		return ""
+	}))
+	handler := func(_ *ApiContext, _ *ApiAuth, _ *ApiParams) *ApiResult {
+		return NewApiResult(StatusOk)
+	}
+	router.SetHandlerWithSchema("create", handler, &ApiSchema{Params: map[string]*Schema{
+		"title": {Type: TypeString, Required: true},
+		"mode":  {Type: TypeString, Enum: []interface{}{"a", "b"}},
+	}})
+	router.SetHandler("noschema", handler)
+
+	result := router.CallApi(NewApiContext().SetApiName("create"), NewApiAuth("", ""), NewApiParams().SetParam("mode", "c"))
+	if result.Status != StatusErrorClient {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, StatusErrorClient, result.Status)
+	}
+	errors, _ := result.Data.(map[string]interface{})["errors"].([]map[string]interface{})
+	if len(errors) != 2 || errors[0]["field"] != "mode" || errors[0]["code"] != ValidationEnum || errors[1]["message"] != "missing title" {
+		t.Fatalf("%s failed: unexpected errors %#v", name, errors)
+	}
+	if result.Message != errors[0]["message"] {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, errors[0]["message"], result.Message)
+	}
+
+	result = router.CallApi(NewApiContext().SetApiName("create"), NewApiAuth("", ""), NewApiParams().SetParam("title", "t").SetParam("mode", "a"))
+	if result.Status != StatusOk {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, StatusOk, result.Status)
+	}
+	result = router.CallApi(NewApiContext().SetApiName("noschema"), NewApiAuth("", ""), NewApiParams())
+	if result.Status != StatusOk {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, StatusOk, result.Status)
+	}
+}
@@ -0,0 +1,178 @@
+package itineris
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"main/src/utils"
+)
+
+/*
+Event is a domain event published by the application via EventBus.
+
+Available since template-v0.5.0
+*/
+type Event struct {
+	Id        string                 `json:"id"`
+	Type      string                 `json:"type"`
+	Timestamp time.Time              `json:"timestamp"`
+	Data      map[string]interface{} `json:"data"`
+}
+
+/*
+NewEvent creates a new Event instance with a unique id and current timestamp.
+*/
+func NewEvent(eventType string, data map[string]interface{}) *Event {
+	return &Event{Id: utils.UniqueId(), Type: eventType, Timestamp: time.Now(), Data: data}
+}
+
+/*
+ToMap exports the Event data to a map.
+*/
+func (e *Event) ToMap() map[string]interface{} {
+	return map[string]interface{}{"id": e.Id, "type": e.Type, "timestamp": e.Timestamp, "data": e.Data}
+}
+
+/*
+EventFilter decides if an event is delivered to a subscriber.
+
+Available since template-v0.5.0
+*/
+type EventFilter func(event *Event) bool
+
+/*
+EventSubscription receives events published to an EventBus.
+
+Available since template-v0.5.0
+*/
+type EventSubscription struct {
+	bus     *EventBus
+	filter  EventFilter
+	events  chan *Event
+	dropped int64
+	once    sync.Once
+}
+
+/*
+Events returns the channel from which subscriber receives events. The channel is closed when the subscription is closed.
+*/
+func (s *EventSubscription) Events() <-chan *Event {
+	return s.events
+}
+
+/*
+Dropped returns number of events that were not delivered to the subscriber because it was too slow to consume them.
+*/
+func (s *EventSubscription) Dropped() int64 {
+	return atomic.LoadInt64(&s.dropped)
+}
+
+/*
+Close unsubscribes from the event bus. It is safe to call Close multiple times.
+*/
+func (s *EventSubscription) Close() {
+	s.once.Do(func() {
+		s.bus.unsubscribe(s)
+	})
+}
+
+/*
+EventBus is an in-process publish/subscribe channel of domain events.
+
+  - Publish never blocks: if a subscriber's buffer is full, the event is dropped for that subscriber.
+  - Each subscriber has its own filter, which is evaluated on the publisher's goroutine.
+
+EventBus is safe for concurrent use by multiple goroutines.
+
+Available since template-v0.5.0
+*/
+type EventBus struct {
+	lock        sync.RWMutex
+	subscribers map[*EventSubscription]bool
+	closed      bool
+}
+
+/*
+NewEventBus creates a new EventBus instance.
+*/
+func NewEventBus() *EventBus {
+	return &EventBus{subscribers: make(map[*EventSubscription]bool)}
+}
+
+/*
+Subscribe creates a new subscription that receives events accepted by filter (nil filter accepts all events).
+
+bufferSize is the number of events that can be queued for the subscriber before new events are dropped (minimum 1).
+
+If the bus has been closed, the returned subscription's channel is already closed.
+*/
+func (bus *EventBus) Subscribe(filter EventFilter, bufferSize int) *EventSubscription {
+	if bufferSize < 1 {
+		bufferSize = 1
+	}
+	sub := &EventSubscription{bus: bus, filter: filter, events: make(chan *Event, bufferSize)}
+	bus.lock.Lock()
+	defer bus.lock.Unlock()
+	if bus.closed {
+		close(sub.events)
+		return sub
+	}
+	bus.subscribers[sub] = true
+	return sub
+}
+
+/*
+Close closes all existing subscriptions (so that subscribers stop waiting for events) and rejects new ones.
+Events published after Close are discarded.
+
+Available since template-v0.5.0
+*/
+func (bus *EventBus) Close() {
+	bus.lock.Lock()
+	defer bus.lock.Unlock()
+	bus.closed = true
+	for sub := range bus.subscribers {
+		delete(bus.subscribers, sub)
+		close(sub.events)
+	}
+}
+
+func (bus *EventBus) unsubscribe(sub *EventSubscription) {
+	bus.lock.Lock()
+	defer bus.lock.Unlock()
+	if bus.subscribers[sub] {
+		delete(bus.subscribers, sub)
+		close(sub.events)
+	}
+}
+
+/*
+NumSubscribers returns the current number of subscribers.
+*/
+func (bus *EventBus) NumSubscribers() int {
+	bus.lock.RLock()
+	defer bus.lock.RUnlock()
+	return len(bus.subscribers)
+}
+
+/*
+Publish delivers an event to all subscribers whose filter accepts it.
+*/
+func (bus *EventBus) Publish(event *Event) {
+	if bus == nil || event == nil {
+		return
+	}
+	bus.lock.RLock()
+	defer bus.lock.RUnlock()
+	for sub := range bus.subscribers {
+		if sub.filter != nil && !sub.filter(event) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			atomic.AddInt64(&sub.dropped, 1)
+		}
+	}
+}
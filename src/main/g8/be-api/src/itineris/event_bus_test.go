@@ -0,0 +1,72 @@
+package itineris
+
+import (
+	"testing"
+)
+
+func TestEventBus_PublishSubscribe(t *testing.T) {
+	name := "TestEventBus_PublishSubscribe"
+	bus := NewEventBus()
+	subAll := bus.Subscribe(nil, 10)
+	subFiltered := bus.Subscribe(func(e *Event) bool { return e.Type == "post.created" }, 10)
+	if bus.NumSubscribers() != 2 {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, 2, bus.NumSubscribers())
+	}
+
+	bus.Publish(NewEvent("post.created", map[string]interface{}{"id": "1"}))
+	bus.Publish(NewEvent("vote.changed", map[string]interface{}{"id": "1"}))
+	bus.Publish(nil)
+	if len(subAll.Events()) != 2 {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, 2, len(subAll.Events()))
+	}
+	if len(subFiltered.Events()) != 1 {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, 1, len(subFiltered.Events()))
+	}
+	if e := <-subFiltered.Events(); e.Type != "post.created" || e.Id == "" || e.Data["id"] != "1" {
+		t.Fatalf("%s failed: unexpected event %#v", name, e)
+	}
+
+	subFiltered.Close()
+	subFiltered.Close()
+	if _, ok := <-subFiltered.Events(); ok {
+		t.Fatalf("%s failed: channel should be closed", name)
+	}
+	if bus.NumSubscribers() != 1 {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, 1, bus.NumSubscribers())
+	}
+	bus.Publish(NewEvent("post.created", nil))
+	if len(subAll.Events()) != 3 {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, 3, len(subAll.Events()))
+	}
+}
+
+func TestEventBus_SlowSubscriber(t *testing.T) {
+	name := "TestEventBus_SlowSubscriber"
+	bus := NewEventBus()
+	sub := bus.Subscribe(nil, 2)
+	defer sub.Close()
+	for i := 0; i < 5; i++ {
+		bus.Publish(NewEvent("test", nil))
+	}
+	if len(sub.Events()) != 2 || sub.Dropped() != 3 {
+		t.Fatalf("%s failed: expected %#v/%#v but received %#v/%#v", name, 2, 3, len(sub.Events()), sub.Dropped())
+	}
+}
+
+func TestEventBus_Close(t *testing.T) {
+	name := "TestEventBus_Close"
+	bus := NewEventBus()
+	sub := bus.Subscribe(nil, 2)
+	bus.Close()
+	if _, ok := <-sub.Events(); ok {
+		t.Fatalf("%s failed: channel should be closed", name)
+	}
+	sub.Close()
+	if bus.NumSubscribers() != 0 {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, 0, bus.NumSubscribers())
+	}
+	bus.Publish(NewEvent("test", nil))
+	if _, ok := <-bus.Subscribe(nil, 1).Events(); ok {
+		t.Fatalf("%s failed: subscription to closed bus should be closed", name)
+	}
+}
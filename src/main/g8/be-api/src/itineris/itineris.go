@@ -11,6 +11,7 @@ package itineris
 import (
 	"encoding/json"
 	"reflect"
+	"sync"
 	"time"
 
 	"github.com/btnguyen2k/consu/reddo"
@@ -51,10 +52,23 @@ const (
 
 	// CtxLocale is the context attribute that holds the client's preferred localized language (one of CtxLang, CtxLanguage, CtxLocale).
 	CtxLocale = "locale"
+
+	// CtxIdempotencyKey is the context attribute that holds the idempotency key of the API call (see IdempotencyFilter).
+	//
+	// Available since template-v0.5.0
+	CtxIdempotencyKey = "idempotency-key"
+
+	// CtxClientCertCN is the context attribute that holds common name of the client's verified TLS certificate (mTLS).
+	//
+	// Available since template-v0.5.0
+	CtxClientCertCN = "client_cert_cn"
 )
 
 // ApiContext encapsulates the context information of an API call.
+//
+// ApiContext is safe for concurrent use by multiple goroutines.
 type ApiContext struct {
+	lock        sync.RWMutex
 	contextData map[string]interface{}
 }
 
@@ -129,18 +143,24 @@ func (ctx *ApiContext) SetContextValue(field string, value interface{}) *ApiCont
 	if value == nil {
 		return ctx.RemoveContextValue(field)
 	}
+	ctx.lock.Lock()
+	defer ctx.lock.Unlock()
 	ctx.contextData[field] = value
 	return ctx
 }
 
 // RemoveContextValue removes an associated context field value.
 func (ctx *ApiContext) RemoveContextValue(field string) *ApiContext {
+	ctx.lock.Lock()
+	defer ctx.lock.Unlock()
 	delete(ctx.contextData, field)
 	return ctx
 }
 
 // GetContextValue returns a context value.
 func (ctx *ApiContext) GetContextValue(field string) interface{} {
+	ctx.lock.RLock()
+	defer ctx.lock.RUnlock()
 	v, ok := ctx.contextData[field]
 	if ok {
 		return v
@@ -177,14 +197,27 @@ func (ctx *ApiContext) GetContextValueAsFloat(field string) float64 {
 	return val
 }
 
-// GetAllContextValues returns all context values as a map.
+// GetAllContextValues returns a snapshot of all context values as a map.
+//
+// The returned map is a (shallow) copy; modifying it does not affect the ApiContext.
 func (ctx *ApiContext) GetAllContextValues() map[string]interface{} {
-	return ctx.contextData
+	ctx.lock.RLock()
+	defer ctx.lock.RUnlock()
+	result := make(map[string]interface{}, len(ctx.contextData))
+	for k, v := range ctx.contextData {
+		result[k] = v
+	}
+	return result
+}
+
+// Clone creates a new ApiContext instance with a snapshot of this context's values.
+func (ctx *ApiContext) Clone() *ApiContext {
+	return &ApiContext{contextData: ctx.GetAllContextValues()}
 }
 
 // ToJsonString serializes the ApiContext to JSON string.
 func (ctx *ApiContext) ToJsonString() string {
-	js, _ := json.Marshal(ctx.contextData)
+	js, _ := json.Marshal(ctx.GetAllContextValues())
 	return string(js)
 }
 
@@ -225,7 +258,10 @@ func (auth *ApiAuth) GetAccessToken() string {
 /*----------------------------------------------------------------------*/
 
 // ApiParams encapsulates parameters to be passed to the API.
+//
+// ApiParams is safe for concurrent use by multiple goroutines.
 type ApiParams struct {
+	lock   sync.RWMutex
 	params map[string]interface{}
 }
 
@@ -240,18 +276,24 @@ func (prm *ApiParams) SetParam(key string, value interface{}) *ApiParams {
 	if value == nil {
 		return prm.RemoveParam(key)
 	}
+	prm.lock.Lock()
+	defer prm.lock.Unlock()
 	prm.params[key] = value
 	return prm
 }
 
 // RemoveParam removes a parameter value.
 func (prm *ApiParams) RemoveParam(key string) *ApiParams {
+	prm.lock.Lock()
+	defer prm.lock.Unlock()
 	delete(prm.params, key)
 	return prm
 }
 
 // GetParam returns a parameter value.
 func (prm *ApiParams) GetParam(key string) interface{} {
+	prm.lock.RLock()
+	defer prm.lock.RUnlock()
 	v, ok := prm.params[key]
 	if ok {
 		return v
@@ -264,9 +306,22 @@ func (prm *ApiParams) GetParamAsType(key string, typ reflect.Type) (interface{},
 	return reddo.Convert(prm.GetParam(key), typ)
 }
 
-// GetAllParams returns all parameters as a map.
+// GetAllParams returns a snapshot of all parameters as a map.
+//
+// The returned map is a (shallow) copy; modifying it does not affect the ApiParams.
 func (prm *ApiParams) GetAllParams() map[string]interface{} {
-	return prm.params
+	prm.lock.RLock()
+	defer prm.lock.RUnlock()
+	result := make(map[string]interface{}, len(prm.params))
+	for k, v := range prm.params {
+		result[k] = v
+	}
+	return result
+}
+
+// Clone creates a new ApiParams instance with a snapshot of this instance's parameters.
+func (prm *ApiParams) Clone() *ApiParams {
+	return &ApiParams{params: prm.GetAllParams()}
 }
 
 /*----------------------------------------------------------------------*/
@@ -276,6 +331,7 @@ const (
 	StatusErrorClient    = 400
 	StatusNoPermission   = 403
 	StatusNotFound       = 404
+	StatusConflict       = 409
 	StatusDeprecated     = 410
 	StatusErrorServer    = 500
 	StatusNotImplemented = 501
@@ -296,6 +352,9 @@ type ApiResult struct {
 	Data      interface{}            `json:"data"`
 	DebugInfo interface{}            `json:"debug"`
 	Extras    map[string]interface{} `json:"extras"`
+
+	// dataSensitive marks Data as sensitive (e.g. an access token): it is masked by request loggers
+	dataSensitive bool
 }
 
 /*
@@ -393,16 +452,37 @@ func (rst *ApiResult) SetDebugInfo(debugInfo interface{}) *ApiResult {
 	return rst
 }
 
+/*
+IsDataSensitive returns true if result Data is sensitive (see SetDataSensitive).
+
+Available since template-v0.5.0
+*/
+func (rst *ApiResult) IsDataSensitive() bool {
+	return rst.dataSensitive
+}
+
+/*
+SetDataSensitive marks result Data as sensitive (e.g. it holds an access token), so that request loggers mask it.
+The result is still sent to client as-is.
+
+Available since template-v0.5.0
+*/
+func (rst *ApiResult) SetDataSensitive(sensitive bool) *ApiResult {
+	rst.dataSensitive = sensitive
+	return rst
+}
+
 /*
 Clone replicates the ApiResult instance.
 */
 func (rst *ApiResult) Clone() *ApiResult {
 	return &ApiResult{
-		Status:    rst.Status,
-		Message:   rst.Message,
-		Data:      rst.Data,
-		DebugInfo: rst.DebugInfo,
-		Extras:    rst.Extras,
+		Status:        rst.Status,
+		Message:       rst.Message,
+		Data:          rst.Data,
+		DebugInfo:     rst.DebugInfo,
+		Extras:        rst.Extras,
+		dataSensitive: rst.dataSensitive,
 	}
 }
 
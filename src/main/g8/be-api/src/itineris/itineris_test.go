@@ -0,0 +1,177 @@
+package itineris
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+const numTestGoroutines = 64
+
+func TestApiContext_GetAllContextValues(t *testing.T) {
+	name := "TestApiContext_GetAllContextValues"
+	ctx := NewApiContext().SetApiName("myApi").SetGateway("TEST")
+	snapshot := ctx.GetAllContextValues()
+	snapshot["key"] = "value"
+	delete(snapshot, ctxApiName)
+	if v := ctx.GetContextValue("key"); v != nil {
+		t.Fatalf("%s failed: modifying snapshot should not affect context, but received %#v", name, v)
+	}
+	if v := ctx.GetApiName(); v != "myApi" {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, "myApi", v)
+	}
+}
+
+func TestApiContext_Clone(t *testing.T) {
+	name := "TestApiContext_Clone"
+	ctx := NewApiContext().SetApiName("myApi").SetGateway("TEST")
+	clone := ctx.Clone()
+	clone.SetApiName("anotherApi")
+	if v := ctx.GetApiName(); v != "myApi" {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, "myApi", v)
+	}
+	if v1, v0 := clone.GetId(), ctx.GetId(); v1 != v0 {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, v0, v1)
+	}
+}
+
+func TestApiContext_Concurrency(t *testing.T) {
+	name := "TestApiContext_Concurrency"
+	ctx := NewApiContext().SetApiName("myApi").SetGateway("TEST")
+	var wg sync.WaitGroup
+	for i := 0; i < numTestGoroutines; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			field := "field" + strconv.Itoa(i)
+			ctx.SetContextValue(field, i)
+			ctx.GetContextValueAsInt(field)
+			ctx.GetAllContextValues()
+			ctx.ToJsonString()
+			ctx.GetClientLocale()
+			if i%2 == 0 {
+				ctx.RemoveContextValue(field)
+			}
+		}(i)
+	}
+	wg.Wait()
+	for i := 0; i < numTestGoroutines; i++ {
+		field := "field" + strconv.Itoa(i)
+		v := ctx.GetContextValue(field)
+		if i%2 == 0 && v != nil {
+			t.Fatalf("%s failed: expected field %s to be removed but received %#v", name, field, v)
+		}
+		if i%2 != 0 && v != i {
+			t.Fatalf("%s failed: expected field %s to be %#v but received %#v", name, field, i, v)
+		}
+	}
+}
+
+func TestApiParams_GetAllParams(t *testing.T) {
+	name := "TestApiParams_GetAllParams"
+	params := NewApiParams().SetParam("p1", "v1")
+	snapshot := params.GetAllParams()
+	snapshot["p2"] = "v2"
+	delete(snapshot, "p1")
+	if v := params.GetParam("p2"); v != nil {
+		t.Fatalf("%s failed: modifying snapshot should not affect params, but received %#v", name, v)
+	}
+	if v := params.GetParam("p1"); v != "v1" {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, "v1", v)
+	}
+}
+
+func TestApiParams_Clone(t *testing.T) {
+	name := "TestApiParams_Clone"
+	params := NewApiParams().SetParam("p1", "v1")
+	clone := params.Clone().SetParam("p1", "another")
+	if v := params.GetParam("p1"); v != "v1" {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, "v1", v)
+	}
+	if v := clone.GetParam("p1"); v != "another" {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, "another", v)
+	}
+}
+
+func TestApiParams_Concurrency(t *testing.T) {
+	name := "TestApiParams_Concurrency"
+	params := NewApiParams()
+	var wg sync.WaitGroup
+	for i := 0; i < numTestGoroutines; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			key := fmt.Sprintf("param%d", i)
+			params.SetParam(key, i)
+			params.GetParam(key)
+			params.GetAllParams()
+			if i%2 == 0 {
+				params.RemoveParam(key)
+			}
+		}(i)
+	}
+	wg.Wait()
+	if n := len(params.GetAllParams()); n != numTestGoroutines/2 {
+		t.Fatalf("%s failed: expected %#v params but received %#v", name, numTestGoroutines/2, n)
+	}
+}
+
+func TestApiRouter_Concurrency(t *testing.T) {
+	name := "TestApiRouter_Concurrency"
+	router := NewApiRouter()
+	handler := func(ctx *ApiContext, _ *ApiAuth, params *ApiParams) *ApiResult {
+		// handler spawns goroutines that access context and params
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				ctx.SetContextValue(fmt.Sprintf("worker%d", i), i)
+				params.GetAllParams()
+			}(i)
+		}
+		wg.Wait()
+		return NewApiResult(StatusOk).SetData(len(ctx.GetAllContextValues()))
+	}
+	router.SetHandler("myApi", handler)
+	router.SetApiFilter(NewAddPerfInfoFilter(router, nil))
+	var wg sync.WaitGroup
+	for i := 0; i < numTestGoroutines; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			router.SetHandler(fmt.Sprintf("api%d", i), handler)
+			ctx := NewApiContext().SetApiName("myApi").SetGateway("TEST")
+			result := router.CallApi(ctx, NewApiAuth("", ""), NewApiParams().SetParam("i", i))
+			if result.GetStatus() != StatusOk {
+				t.Errorf("%s failed: expected status %#v but received %#v", name, StatusOk, result.GetStatus())
+			}
+			router.GetConcurrency()
+			router.GetAllHandlers()
+		}(i)
+	}
+	wg.Wait()
+	if n := len(router.GetAllHandlers()); n != numTestGoroutines+1 {
+		t.Fatalf("%s failed: expected %#v handlers but received %#v", name, numTestGoroutines+1, n)
+	}
+	if c := router.GetConcurrency(); c != 0 {
+		t.Fatalf("%s failed: expected concurrency %#v but received %#v", name, 0, c)
+	}
+}
+
+func TestApiRouter_CallHandler(t *testing.T) {
+	name := "TestApiRouter_CallHandler"
+	router := NewApiRouter()
+	filter := &_countingFilter{BaseApiFilter: &BaseApiFilter{ApiRouter: router}}
+	router.SetApiFilter(filter)
+	result := router.CallHandler(NewApiContext().SetApiName("_unregistered"), NewApiAuth("", ""), NewApiParams(), func(_ *ApiContext, _ *ApiAuth, _ *ApiParams) *ApiResult {
+		return NewApiResult(StatusOk)
+	})
+	if result.Status != StatusOk || filter.count != 1 {
+		t.Fatalf("%s failed: handler should be called through the filter chain, received status %#v and %#v filter calls", name, result.Status, filter.count)
+	}
+	if result := router.CallApi(NewApiContext().SetApiName("_unregistered"), NewApiAuth("", ""), NewApiParams()); result.Status != StatusNotImplemented {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, StatusNotImplemented, result.Status)
+	}
+}
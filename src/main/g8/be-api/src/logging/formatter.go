@@ -0,0 +1,140 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// IFormatter formats a log entry to bytes to be written out (including trailing newline).
+type IFormatter interface {
+	Format(entry *Entry) []byte
+}
+
+// NewFormatter creates a formatter by name: "json" or "console" (default).
+func NewFormatter(name string) (IFormatter, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "json":
+		return NewJsonFormatter(), nil
+	case "console", "text", "":
+		return NewConsoleFormatter(), nil
+	}
+	return nil, fmt.Errorf("unknown log format [%s]", name)
+}
+
+// normalizeValue converts values that do not serialize well (e.g. error) to a friendlier form.
+func normalizeValue(v interface{}) interface{} {
+	switch tv := v.(type) {
+	case error:
+		return tv.Error()
+	case time.Time:
+		return tv.Format(time.RFC3339Nano)
+	}
+	return v
+}
+
+func sortedKeys(fields Fields) []string {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+/*----------------------------------------------------------------------*/
+
+// ConsoleFormatter formats log entries as human-friendly lines:
+//
+//	2022-11-23T10:20:30.123+07:00 [WARN] message {key1=value1 key2=value2}
+type ConsoleFormatter struct {
+	TimeLayout string
+}
+
+// NewConsoleFormatter creates a new ConsoleFormatter instance.
+func NewConsoleFormatter() *ConsoleFormatter {
+	return &ConsoleFormatter{TimeLayout: "2006-01-02T15:04:05.000Z07:00"}
+}
+
+// Format implements IFormatter.Format
+func (f *ConsoleFormatter) Format(entry *Entry) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(entry.Time.Format(f.TimeLayout))
+	buf.WriteString(" [")
+	buf.WriteString(entry.Level.String())
+	buf.WriteString("] ")
+	buf.WriteString(strings.TrimRight(entry.Message, "\r\n"))
+	if len(entry.Fields) > 0 {
+		buf.WriteString(" {")
+		for i, k := range sortedKeys(entry.Fields) {
+			if i > 0 {
+				buf.WriteString(" ")
+			}
+			v := normalizeValue(entry.Fields[k])
+			switch v.(type) {
+			case string, fmt.Stringer:
+				fmt.Fprintf(&buf, "%s=%v", k, v)
+			default:
+				js, err := json.Marshal(v)
+				if err != nil {
+					fmt.Fprintf(&buf, "%s=%v", k, v)
+				} else {
+					fmt.Fprintf(&buf, "%s=%s", k, js)
+				}
+			}
+		}
+		buf.WriteString("}")
+	}
+	buf.WriteString("\n")
+	return buf.Bytes()
+}
+
+/*----------------------------------------------------------------------*/
+
+// JsonFormatter formats log entries as JSON lines.
+//
+// Entry's fields are placed at top level alongside time, level and message; a field whose name clashes
+// with those keys is prefixed with "fields.".
+type JsonFormatter struct {
+	FieldTime    string
+	FieldLevel   string
+	FieldMessage string
+	TimeLayout   string
+}
+
+// NewJsonFormatter creates a new JsonFormatter instance.
+func NewJsonFormatter() *JsonFormatter {
+	return &JsonFormatter{
+		FieldTime:    "time",
+		FieldLevel:   "level",
+		FieldMessage: "msg",
+		TimeLayout:   time.RFC3339Nano,
+	}
+}
+
+// Format implements IFormatter.Format
+func (f *JsonFormatter) Format(entry *Entry) []byte {
+	data := make(map[string]interface{}, len(entry.Fields)+3)
+	for k, v := range entry.Fields {
+		if k == f.FieldTime || k == f.FieldLevel || k == f.FieldMessage {
+			k = "fields." + k
+		}
+		data[k] = normalizeValue(v)
+	}
+	data[f.FieldTime] = entry.Time.Format(f.TimeLayout)
+	data[f.FieldLevel] = entry.Level.String()
+	data[f.FieldMessage] = strings.TrimRight(entry.Message, "\r\n")
+	js, err := json.Marshal(data)
+	if err != nil {
+		js, _ = json.Marshal(map[string]interface{}{
+			f.FieldTime:    entry.Time.Format(f.TimeLayout),
+			f.FieldLevel:   entry.Level.String(),
+			f.FieldMessage: strings.TrimRight(entry.Message, "\r\n"),
+			"log_error":    err.Error(),
+		})
+	}
+	return append(js, '\n')
+}
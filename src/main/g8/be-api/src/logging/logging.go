@@ -0,0 +1,282 @@
+/*
+Package logging provides a structured, leveled logger for the application.
+
+  - Log entries carry a level, a message and arbitrary key/value fields.
+  - Output format is pluggable (see IFormatter): human-friendly console lines or JSON lines.
+  - Log level can be changed at runtime and the change applies to all loggers derived from the same root.
+
+@author Thanh Nguyen <btnguyen2k@gmail.com>
+@since template-v0.5.0
+*/
+package logging
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Level is the severity of a log entry.
+type Level int32
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+var levelNames = map[Level]string{
+	LevelDebug: "DEBUG",
+	LevelInfo:  "INFO",
+	LevelWarn:  "WARN",
+	LevelError: "ERROR",
+}
+
+// String implements fmt.Stringer.String
+func (l Level) String() string {
+	if name, ok := levelNames[l]; ok {
+		return name
+	}
+	return fmt.Sprintf("LEVEL(%d)", int32(l))
+}
+
+// ParseLevel parses a level name (case-insensitive, e.g. "debug", "INFO", "warning") to Level.
+func ParseLevel(name string) (Level, error) {
+	switch strings.ToUpper(strings.TrimSpace(name)) {
+	case "DEBUG", "TRACE":
+		return LevelDebug, nil
+	case "INFO", "":
+		return LevelInfo, nil
+	case "WARN", "WARNING":
+		return LevelWarn, nil
+	case "ERROR", "FATAL":
+		return LevelError, nil
+	}
+	return LevelInfo, fmt.Errorf("unknown log level [%s]", name)
+}
+
+// Fields holds key/value pairs attached to log entries.
+type Fields map[string]interface{}
+
+// Entry is a single log entry to be formatted and written out.
+type Entry struct {
+	Time    time.Time
+	Level   Level
+	Message string
+	Fields  Fields
+}
+
+// ILogger defines API of a structured, leveled logger.
+type ILogger interface {
+	// With returns a derived logger that attaches the specified fields to every entry it writes.
+	With(fields Fields) ILogger
+
+	// WithField is shortcut of With(Fields{key: value}).
+	WithField(key string, value interface{}) ILogger
+
+	// IsEnabled checks if entries of the specified level would be written out.
+	IsEnabled(level Level) bool
+
+	// Log writes a log entry at the specified level.
+	Log(level Level, msg string)
+
+	// Debugf writes a formatted log entry at level LevelDebug.
+	Debugf(format string, args ...interface{})
+
+	// Infof writes a formatted log entry at level LevelInfo.
+	Infof(format string, args ...interface{})
+
+	// Warnf writes a formatted log entry at level LevelWarn.
+	Warnf(format string, args ...interface{})
+
+	// Errorf writes a formatted log entry at level LevelError.
+	Errorf(format string, args ...interface{})
+}
+
+/*----------------------------------------------------------------------*/
+
+// loggerCore is shared by a root Logger and all loggers derived from it.
+type loggerCore struct {
+	level     int32
+	lock      sync.Mutex
+	writer    io.Writer
+	formatter IFormatter
+}
+
+// Logger is the default implementation of ILogger.
+//
+// Logger is safe for concurrent use by multiple goroutines.
+type Logger struct {
+	core   *loggerCore
+	fields Fields
+}
+
+// NewLogger creates a new root Logger instance.
+//
+// If writer is nil, os.Stderr is used. If formatter is nil, a ConsoleFormatter is used.
+func NewLogger(writer io.Writer, formatter IFormatter, level Level) *Logger {
+	if writer == nil {
+		writer = os.Stderr
+	}
+	if formatter == nil {
+		formatter = NewConsoleFormatter()
+	}
+	return &Logger{core: &loggerCore{level: int32(level), writer: writer, formatter: formatter}, fields: Fields{}}
+}
+
+// GetLevel returns the current minimum level of entries to be written out.
+func (l *Logger) GetLevel() Level {
+	return Level(atomic.LoadInt32(&l.core.level))
+}
+
+// SetLevel changes the minimum level of entries to be written out.
+//
+// The change applies to this logger, its root and all loggers derived from the same root.
+func (l *Logger) SetLevel(level Level) *Logger {
+	atomic.StoreInt32(&l.core.level, int32(level))
+	return l
+}
+
+// SetFormatter changes the output format of this logger, its root and all loggers derived from the same root.
+func (l *Logger) SetFormatter(formatter IFormatter) *Logger {
+	if formatter != nil {
+		l.core.lock.Lock()
+		defer l.core.lock.Unlock()
+		l.core.formatter = formatter
+	}
+	return l
+}
+
+// SetWriter changes the output destination of this logger, its root and all loggers derived from the same root.
+func (l *Logger) SetWriter(writer io.Writer) *Logger {
+	if writer != nil {
+		l.core.lock.Lock()
+		defer l.core.lock.Unlock()
+		l.core.writer = writer
+	}
+	return l
+}
+
+// With implements ILogger.With
+func (l *Logger) With(fields Fields) ILogger {
+	merged := make(Fields, len(l.fields)+len(fields))
+	for k, v := range l.fields {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return &Logger{core: l.core, fields: merged}
+}
+
+// WithField implements ILogger.WithField
+func (l *Logger) WithField(key string, value interface{}) ILogger {
+	return l.With(Fields{key: value})
+}
+
+// IsEnabled implements ILogger.IsEnabled
+func (l *Logger) IsEnabled(level Level) bool {
+	return level >= l.GetLevel()
+}
+
+// Log implements ILogger.Log
+func (l *Logger) Log(level Level, msg string) {
+	if !l.IsEnabled(level) {
+		return
+	}
+	entry := &Entry{Time: time.Now(), Level: level, Message: msg, Fields: l.fields}
+	l.core.lock.Lock()
+	defer l.core.lock.Unlock()
+	l.core.writer.Write(l.core.formatter.Format(entry))
+}
+
+// Debugf implements ILogger.Debugf
+func (l *Logger) Debugf(format string, args ...interface{}) {
+	if l.IsEnabled(LevelDebug) {
+		l.Log(LevelDebug, fmt.Sprintf(format, args...))
+	}
+}
+
+// Infof implements ILogger.Infof
+func (l *Logger) Infof(format string, args ...interface{}) {
+	if l.IsEnabled(LevelInfo) {
+		l.Log(LevelInfo, fmt.Sprintf(format, args...))
+	}
+}
+
+// Warnf implements ILogger.Warnf
+func (l *Logger) Warnf(format string, args ...interface{}) {
+	if l.IsEnabled(LevelWarn) {
+		l.Log(LevelWarn, fmt.Sprintf(format, args...))
+	}
+}
+
+// Errorf implements ILogger.Errorf
+func (l *Logger) Errorf(format string, args ...interface{}) {
+	if l.IsEnabled(LevelError) {
+		l.Log(LevelError, fmt.Sprintf(format, args...))
+	}
+}
+
+/*----------------------------------------------------------------------*/
+
+var (
+	defaultLock   sync.RWMutex
+	defaultLogger = NewLogger(os.Stderr, NewConsoleFormatter(), LevelInfo)
+)
+
+// Default returns the application-wide root logger.
+func Default() *Logger {
+	defaultLock.RLock()
+	defer defaultLock.RUnlock()
+	return defaultLogger
+}
+
+// SetDefault replaces the application-wide root logger.
+func SetDefault(logger *Logger) {
+	if logger != nil {
+		defaultLock.Lock()
+		defer defaultLock.Unlock()
+		defaultLogger = logger
+	}
+}
+
+// With is shortcut of Default().With(fields).
+func With(fields Fields) ILogger {
+	return Default().With(fields)
+}
+
+// WithField is shortcut of Default().WithField(key, value).
+func WithField(key string, value interface{}) ILogger {
+	return Default().WithField(key, value)
+}
+
+// IsEnabled is shortcut of Default().IsEnabled(level).
+func IsEnabled(level Level) bool {
+	return Default().IsEnabled(level)
+}
+
+// Debugf is shortcut of Default().Debugf(format, args...).
+func Debugf(format string, args ...interface{}) {
+	Default().Debugf(format, args...)
+}
+
+// Infof is shortcut of Default().Infof(format, args...).
+func Infof(format string, args ...interface{}) {
+	Default().Infof(format, args...)
+}
+
+// Warnf is shortcut of Default().Warnf(format, args...).
+func Warnf(format string, args ...interface{}) {
+	Default().Warnf(format, args...)
+}
+
+// Errorf is shortcut of Default().Errorf(format, args...).
+func Errorf(format string, args ...interface{}) {
+	Default().Errorf(format, args...)
+}
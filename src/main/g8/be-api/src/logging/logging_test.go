@@ -0,0 +1,119 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	name := "TestParseLevel"
+	testCases := map[string]Level{"debug": LevelDebug, "INFO": LevelInfo, "": LevelInfo, "Warning": LevelWarn, "error": LevelError}
+	for input, expected := range testCases {
+		if level, err := ParseLevel(input); err != nil || level != expected {
+			t.Fatalf("%s failed: expected %#v but received %#v (error: %s)", name, expected, level, err)
+		}
+	}
+	if _, err := ParseLevel("invalid"); err == nil {
+		t.Fatalf("%s failed: expected error for invalid level", name)
+	}
+}
+
+func TestNewFormatter(t *testing.T) {
+	name := "TestNewFormatter"
+	if f, err := NewFormatter("json"); err != nil {
+		t.Fatalf("%s failed: %s", name, err)
+	} else if _, ok := f.(*JsonFormatter); !ok {
+		t.Fatalf("%s failed: expected *JsonFormatter but received %T", name, f)
+	}
+	if f, err := NewFormatter("console"); err != nil {
+		t.Fatalf("%s failed: %s", name, err)
+	} else if _, ok := f.(*ConsoleFormatter); !ok {
+		t.Fatalf("%s failed: expected *ConsoleFormatter but received %T", name, f)
+	}
+	if _, err := NewFormatter("invalid"); err == nil {
+		t.Fatalf("%s failed: expected error for invalid format", name)
+	}
+}
+
+func TestLogger_Level(t *testing.T) {
+	name := "TestLogger_Level"
+	buf := &bytes.Buffer{}
+	logger := NewLogger(buf, NewConsoleFormatter(), LevelWarn)
+	derived := logger.WithField("key", "value")
+	derived.Infof("info message")
+	if buf.Len() != 0 {
+		t.Fatalf("%s failed: expected no output but received %#v", name, buf.String())
+	}
+	derived.Warnf("warn %s", "message")
+	if !strings.Contains(buf.String(), "[WARN] warn message {key=value}") {
+		t.Fatalf("%s failed: unexpected output %#v", name, buf.String())
+	}
+
+	// changing level of the root logger applies to derived loggers
+	buf.Reset()
+	logger.SetLevel(LevelDebug)
+	derived.Debugf("debug message")
+	if !strings.Contains(buf.String(), "[DEBUG] debug message") {
+		t.Fatalf("%s failed: unexpected output %#v", name, buf.String())
+	}
+}
+
+func TestLogger_Json(t *testing.T) {
+	name := "TestLogger_Json"
+	buf := &bytes.Buffer{}
+	logger := NewLogger(buf, NewJsonFormatter(), LevelInfo)
+	logger.With(Fields{"request_id": "abc", "msg": "clash", "error": errors.New("an error")}).Errorf("error message")
+	data := map[string]interface{}{}
+	if err := json.Unmarshal(buf.Bytes(), &data); err != nil {
+		t.Fatalf("%s failed: %s", name, err)
+	}
+	expected := map[string]interface{}{"level": "ERROR", "msg": "error message", "request_id": "abc", "fields.msg": "clash", "error": "an error"}
+	for k, v := range expected {
+		if data[k] != v {
+			t.Fatalf("%s failed: expected %#v for field %s but received %#v", name, v, k, data[k])
+		}
+	}
+}
+
+func TestLogger_With(t *testing.T) {
+	name := "TestLogger_With"
+	buf := &bytes.Buffer{}
+	logger := NewLogger(buf, NewJsonFormatter(), LevelInfo)
+	l1 := logger.WithField("k1", "v1")
+	l1.WithField("k2", "v2")
+	l1.Infof("message")
+	data := map[string]interface{}{}
+	json.Unmarshal(buf.Bytes(), &data)
+	if _, ok := data["k2"]; ok {
+		t.Fatalf("%s failed: fields of derived logger should not leak to its parent", name)
+	}
+	if data["k1"] != "v1" {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, "v1", data["k1"])
+	}
+}
+
+func TestLogger_Concurrency(t *testing.T) {
+	name := "TestLogger_Concurrency"
+	buf := &bytes.Buffer{}
+	logger := NewLogger(buf, NewJsonFormatter(), LevelInfo)
+	var wg sync.WaitGroup
+	numGoroutines := 64
+	for i := 0; i < numGoroutines; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			logger.WithField("i", i).Infof("message %d", i)
+			if i%8 == 0 {
+				logger.SetLevel(LevelInfo)
+			}
+		}(i)
+	}
+	wg.Wait()
+	if n := strings.Count(buf.String(), "\n"); n != numGoroutines {
+		t.Fatalf("%s failed: expected %#v lines but received %#v", name, numGoroutines, n)
+	}
+}
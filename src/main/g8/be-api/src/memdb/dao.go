@@ -0,0 +1,253 @@
+package memdb
+
+import (
+	"sort"
+
+	"github.com/btnguyen2k/godal"
+	"github.com/btnguyen2k/henge"
+)
+
+// UniversalDaoMemory is an in-memory implementation of henge.UniversalDao.
+//
+// Business objects are stored the way other implementations persist them (exported via ToGenericBo and read back via
+// ToUniversalBo) and cloned when returned, so callers never share instances with the storage.
+type UniversalDaoMemory struct {
+	db             *MemoryDb
+	tableName      string
+	defaultSorting *godal.SortingOpt
+}
+
+// NewUniversalDaoMemory creates a new UniversalDaoMemory that stores business objects in table tableName of db.
+func NewUniversalDaoMemory(db *MemoryDb, tableName string) *UniversalDaoMemory {
+	return &UniversalDaoMemory{
+		db:             db,
+		tableName:      tableName,
+		defaultSorting: (&godal.SortingField{FieldName: henge.FieldId}).ToSortingOpt(),
+	}
+}
+
+// GetTableName returns name of the table this DAO works on.
+func (dao *UniversalDaoMemory) GetTableName() string {
+	return dao.tableName
+}
+
+// ToUniversalBo implements henge.UniversalDao.ToUniversalBo.
+func (dao *UniversalDaoMemory) ToUniversalBo(gbo godal.IGenericBo) *henge.UniversalBo {
+	return henge.NewUniversalBoFromGbo(gbo)
+}
+
+// ToGenericBo implements henge.UniversalDao.ToGenericBo.
+func (dao *UniversalDaoMemory) ToGenericBo(ubo *henge.UniversalBo) godal.IGenericBo {
+	if ubo == nil {
+		return nil
+	}
+	return ubo.ToGenericBo()
+}
+
+// _toStored transforms bo to the instance to be kept in storage.
+func (dao *UniversalDaoMemory) _toStored(bo *henge.UniversalBo) *henge.UniversalBo {
+	return dao.ToUniversalBo(dao.ToGenericBo(bo))
+}
+
+// Delete implements henge.UniversalDao.Delete.
+func (dao *UniversalDaoMemory) Delete(bo *henge.UniversalBo) (bool, error) {
+	dao.db.lock.Lock()
+	defer dao.db.lock.Unlock()
+	t, err := dao.db._table(dao.tableName)
+	if err != nil {
+		return false, err
+	}
+	if _, ok := t.rows[bo.GetId()]; !ok {
+		return false, nil
+	}
+	delete(t.rows, bo.GetId())
+	delete(t.checksums, bo.GetId())
+	return true, nil
+}
+
+// DeleteIfChecksum is a compare-and-set variant of Delete: the business object is deleted only if the stored one
+// still has the specified checksum, otherwise false is returned.
+func (dao *UniversalDaoMemory) DeleteIfChecksum(bo *henge.UniversalBo, checksum string) (bool, error) {
+	dao.db.lock.Lock()
+	defer dao.db.lock.Unlock()
+	t, err := dao.db._table(dao.tableName)
+	if err != nil {
+		return false, err
+	}
+	if _, ok := t.rows[bo.GetId()]; !ok || t.checksums[bo.GetId()] != checksum {
+		return false, nil
+	}
+	delete(t.rows, bo.GetId())
+	delete(t.checksums, bo.GetId())
+	return true, nil
+}
+
+// Create implements henge.UniversalDao.Create.
+//
+// godal.ErrGdaoDuplicatedEntry is returned if a business object with the same id exists, or if a unique index is violated.
+func (dao *UniversalDaoMemory) Create(bo *henge.UniversalBo) (bool, error) {
+	dao.db.lock.Lock()
+	defer dao.db.lock.Unlock()
+	t, err := dao.db._table(dao.tableName)
+	if err != nil {
+		return false, err
+	}
+	stored := dao._toStored(bo)
+	if _, ok := t.rows[bo.GetId()]; ok || t.conflict(stored, t.uniqueIndexes) {
+		return false, godal.ErrGdaoDuplicatedEntry
+	}
+	t.put(stored, bo.GetChecksum())
+	return true, nil
+}
+
+// Get implements henge.UniversalDao.Get.
+func (dao *UniversalDaoMemory) Get(id string) (*henge.UniversalBo, error) {
+	dao.db.lock.RLock()
+	defer dao.db.lock.RUnlock()
+	t, err := dao.db._table(dao.tableName)
+	if err != nil {
+		return nil, err
+	}
+	if bo, ok := t.rows[id]; ok {
+		return bo.Clone(), nil
+	}
+	return nil, nil
+}
+
+// GetMany fetches business objects with the specified ids under one lock; ids not found are ignored and duplicated ids
+// are returned once. Business objects are returned in order of ids.
+func (dao *UniversalDaoMemory) GetMany(ids []string) ([]*henge.UniversalBo, error) {
+	dao.db.lock.RLock()
+	defer dao.db.lock.RUnlock()
+	t, err := dao.db._table(dao.tableName)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*henge.UniversalBo, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if bo, ok := t.rows[id]; ok && !seen[id] {
+			seen[id] = true
+			result = append(result, bo.Clone())
+		}
+	}
+	return result, nil
+}
+
+// GetN implements henge.UniversalDao.GetN.
+//
+// Business objects are sorted by id if sorting is not specified. maxNumRows <= 0 means no limit.
+func (dao *UniversalDaoMemory) GetN(fromOffset, maxNumRows int, filter godal.FilterOpt, sorting *godal.SortingOpt) ([]*henge.UniversalBo, error) {
+	if sorting == nil {
+		sorting = dao.defaultSorting
+	}
+	dao.db.lock.RLock()
+	defer dao.db.lock.RUnlock()
+	t, err := dao.db._table(dao.tableName)
+	if err != nil {
+		return nil, err
+	}
+	rows := make([]*henge.UniversalBo, 0)
+	for _, bo := range t.rows {
+		if ok, err := matchFilter(bo, filter); err != nil {
+			return nil, err
+		} else if ok {
+			rows = append(rows, bo)
+		}
+	}
+	var sortErr error
+	sort.SliceStable(rows, func(i, j int) bool {
+		less, err := lessBySorting(rows[i], rows[j], sorting)
+		if err != nil && sortErr == nil {
+			sortErr = err
+		}
+		return less
+	})
+	if sortErr != nil {
+		return nil, sortErr
+	}
+	if fromOffset < 0 {
+		fromOffset = 0
+	}
+	if fromOffset > len(rows) {
+		fromOffset = len(rows)
+	}
+	rows = rows[fromOffset:]
+	if maxNumRows > 0 && maxNumRows < len(rows) {
+		rows = rows[:maxNumRows]
+	}
+	result := make([]*henge.UniversalBo, len(rows))
+	for i, bo := range rows {
+		result[i] = bo.Clone()
+	}
+	return result, nil
+}
+
+// GetAll implements henge.UniversalDao.GetAll.
+func (dao *UniversalDaoMemory) GetAll(filter godal.FilterOpt, sorting *godal.SortingOpt) ([]*henge.UniversalBo, error) {
+	return dao.GetN(0, 0, filter, sorting)
+}
+
+// Update implements henge.UniversalDao.Update.
+//
+// This function returns false if the business object does not exist, godal.ErrGdaoDuplicatedEntry if a unique index is violated.
+func (dao *UniversalDaoMemory) Update(bo *henge.UniversalBo) (bool, error) {
+	dao.db.lock.Lock()
+	defer dao.db.lock.Unlock()
+	t, err := dao.db._table(dao.tableName)
+	if err != nil {
+		return false, err
+	}
+	if _, ok := t.rows[bo.GetId()]; !ok {
+		return false, nil
+	}
+	stored := dao._toStored(bo)
+	if t.conflict(stored, t.uniqueIndexes) {
+		return false, godal.ErrGdaoDuplicatedEntry
+	}
+	t.put(stored, bo.GetChecksum())
+	return true, nil
+}
+
+// UpdateIfChecksum is a compare-and-set variant of Update: the business object is updated only if the stored one
+// still has the specified checksum, otherwise false is returned.
+//
+// Like other implementations, the checksum compared is the one of the business object last written, not the one
+// recomputed from the stored row.
+func (dao *UniversalDaoMemory) UpdateIfChecksum(bo *henge.UniversalBo, checksum string) (bool, error) {
+	dao.db.lock.Lock()
+	defer dao.db.lock.Unlock()
+	t, err := dao.db._table(dao.tableName)
+	if err != nil {
+		return false, err
+	}
+	if _, ok := t.rows[bo.GetId()]; !ok || t.checksums[bo.GetId()] != checksum {
+		return false, nil
+	}
+	stored := dao._toStored(bo)
+	if t.conflict(stored, t.uniqueIndexes) {
+		return false, godal.ErrGdaoDuplicatedEntry
+	}
+	t.put(stored, bo.GetChecksum())
+	return true, nil
+}
+
+// Save implements henge.UniversalDao.Save.
+func (dao *UniversalDaoMemory) Save(bo *henge.UniversalBo) (bool, *henge.UniversalBo, error) {
+	dao.db.lock.Lock()
+	defer dao.db.lock.Unlock()
+	t, err := dao.db._table(dao.tableName)
+	if err != nil {
+		return false, nil, err
+	}
+	var existing *henge.UniversalBo
+	if row, ok := t.rows[bo.GetId()]; ok {
+		existing = row.Clone()
+	}
+	stored := dao._toStored(bo)
+	if t.conflict(stored, t.uniqueIndexes) {
+		return false, existing, godal.ErrGdaoDuplicatedEntry
+	}
+	t.put(stored, bo.GetChecksum())
+	return true, existing, nil
+}
@@ -0,0 +1,184 @@
+package memdb
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/btnguyen2k/consu/reddo"
+	"github.com/btnguyen2k/godal"
+	"github.com/btnguyen2k/henge"
+)
+
+// fieldValue returns value of a top-level field or an extra attribute of bo (nil if not exist).
+func fieldValue(bo *henge.UniversalBo, field string) interface{} {
+	switch field {
+	case henge.FieldId:
+		return bo.GetId()
+	case henge.FieldData:
+		return bo.GetDataJson()
+	case henge.FieldTagVersion:
+		return bo.GetTagVersion()
+	case henge.FieldChecksum:
+		return bo.GetChecksum()
+	case henge.FieldTimeCreated:
+		return bo.GetTimeCreated()
+	case henge.FieldTimeUpdated:
+		return bo.GetTimeUpdated()
+	}
+	return bo.GetExtraAttr(field)
+}
+
+func _isNumber(v interface{}) bool {
+	switch reflect.ValueOf(v).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// compareValues compares a to b, returning -1, 0 or +1. Numbers of different types are compared by value.
+// An error is returned if the two values are not comparable (nil, different kinds or unsupported types).
+func compareValues(a, b interface{}) (int, error) {
+	if a == nil || b == nil {
+		return 0, fmt.Errorf("cannot compare %#v with %#v", a, b)
+	}
+	if _isNumber(a) && _isNumber(b) {
+		fa, _ := reddo.ToFloat(a)
+		fb, _ := reddo.ToFloat(b)
+		switch {
+		case fa < fb:
+			return -1, nil
+		case fa > fb:
+			return 1, nil
+		}
+		return 0, nil
+	}
+	switch va := a.(type) {
+	case string:
+		if vb, ok := b.(string); ok {
+			return strings.Compare(va, vb), nil
+		}
+	case bool:
+		if vb, ok := b.(bool); ok {
+			switch {
+			case va == vb:
+				return 0, nil
+			case !va:
+				return -1, nil
+			}
+			return 1, nil
+		}
+	case time.Time:
+		if vb, ok := b.(time.Time); ok {
+			switch {
+			case va.Before(vb):
+				return -1, nil
+			case va.After(vb):
+				return 1, nil
+			}
+			return 0, nil
+		}
+	}
+	return 0, fmt.Errorf("cannot compare %#v with %#v", a, b)
+}
+
+// _matchOp applies operator op on value a against value b. Values that are not comparable only satisfy FilterOpNotEqual.
+func _matchOp(a interface{}, op godal.FilterOperator, b interface{}) (bool, error) {
+	c, err := compareValues(a, b)
+	if err != nil {
+		return op == godal.FilterOpNotEqual && (a != nil || b != nil), nil
+	}
+	switch op {
+	case godal.FilterOpEqual:
+		return c == 0, nil
+	case godal.FilterOpNotEqual:
+		return c != 0, nil
+	case godal.FilterOpGreater:
+		return c > 0, nil
+	case godal.FilterOpGreaterOrEqual:
+		return c >= 0, nil
+	case godal.FilterOpLess:
+		return c < 0, nil
+	case godal.FilterOpLessOrEqual:
+		return c <= 0, nil
+	}
+	return false, fmt.Errorf("unsupported filter operator: %v", op)
+}
+
+// matchFilter checks if bo satisfies filter (nil filter matches everything).
+func matchFilter(bo *henge.UniversalBo, filter godal.FilterOpt) (bool, error) {
+	switch f := filter.(type) {
+	case nil:
+		return true, nil
+	case godal.FilterOptAnd:
+		return matchFilter(bo, &f)
+	case *godal.FilterOptAnd:
+		for _, inner := range f.Filters {
+			if ok, err := matchFilter(bo, inner); err != nil || !ok {
+				return false, err
+			}
+		}
+		return true, nil
+	case godal.FilterOptOr:
+		return matchFilter(bo, &f)
+	case *godal.FilterOptOr:
+		for _, inner := range f.Filters {
+			if ok, err := matchFilter(bo, inner); err != nil || ok {
+				return ok, err
+			}
+		}
+		return len(f.Filters) == 0, nil
+	case godal.FilterOptFieldOpValue:
+		return matchFilter(bo, &f)
+	case *godal.FilterOptFieldOpValue:
+		return _matchOp(fieldValue(bo, f.FieldName), f.Operator, f.Value)
+	case godal.FilterOptFieldOpField:
+		return matchFilter(bo, &f)
+	case *godal.FilterOptFieldOpField:
+		return _matchOp(fieldValue(bo, f.FieldNameLeft), f.Operator, fieldValue(bo, f.FieldNameRight))
+	case godal.FilterOptFieldIsNull:
+		return matchFilter(bo, &f)
+	case *godal.FilterOptFieldIsNull:
+		return fieldValue(bo, f.FieldName) == nil, nil
+	case godal.FilterOptFieldIsNotNull:
+		return matchFilter(bo, &f)
+	case *godal.FilterOptFieldIsNotNull:
+		return fieldValue(bo, f.FieldName) != nil, nil
+	}
+	return false, fmt.Errorf("unsupported filter type: %T", filter)
+}
+
+// lessBySorting checks if a should be placed before b according to sorting. nil values are placed first.
+func lessBySorting(a, b *henge.UniversalBo, sorting *godal.SortingOpt) (bool, error) {
+	if sorting == nil {
+		return false, nil
+	}
+	for _, field := range sorting.Fields {
+		va, vb := fieldValue(a, field.FieldName), fieldValue(b, field.FieldName)
+		var c int
+		switch {
+		case va == nil && vb == nil:
+			c = 0
+		case va == nil:
+			c = -1
+		case vb == nil:
+			c = 1
+		default:
+			var err error
+			if c, err = compareValues(va, vb); err != nil {
+				return false, err
+			}
+		}
+		if field.Descending {
+			c = -c
+		}
+		if c != 0 {
+			return c < 0, nil
+		}
+	}
+	return false, nil
+}
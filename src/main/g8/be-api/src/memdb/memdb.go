@@ -0,0 +1,160 @@
+/*
+Package memdb provides a thread-safe, in-memory storage for henge.UniversalBo, for unit tests and demo mode.
+
+  - A MemoryDb holds tables, each table stores business objects keyed by id.
+  - Tables and unique indexes must be created before use, similar to a real database.
+  - UniversalDaoMemory implements henge.UniversalDao on top of a MemoryDb table and honours godal.FilterOpt and godal.SortingOpt.
+  - Data lives as long as the MemoryDb instance; nothing is persisted.
+
+@author Thanh Nguyen <btnguyen2k@gmail.com>
+@since template-v0.5.0
+*/
+package memdb
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/btnguyen2k/henge"
+)
+
+// ErrClosed is returned when accessing a MemoryDb that has been closed.
+var ErrClosed = errors.New("memdb: database is closed")
+
+type memTable struct {
+	rows          map[string]*henge.UniversalBo
+	checksums     map[string]string // rows' checksums as supplied by writers (stored rows recompute theirs)
+	uniqueIndexes [][]string
+}
+
+// put stores a row, along with the checksum of the business object it was created from.
+func (t *memTable) put(stored *henge.UniversalBo, checksum string) {
+	t.rows[stored.GetId()] = stored
+	t.checksums[stored.GetId()] = checksum
+}
+
+// MemoryDb is an in-memory database: a set of named tables.
+type MemoryDb struct {
+	lock   sync.RWMutex
+	tables map[string]*memTable
+	closed bool
+}
+
+// NewMemoryDb creates a new empty MemoryDb.
+func NewMemoryDb() *MemoryDb {
+	return &MemoryDb{tables: make(map[string]*memTable)}
+}
+
+// CreateTable creates a new empty table. An error containing "already exists" is returned if the table exists.
+func (db *MemoryDb) CreateTable(name string) error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+	if db.closed {
+		return ErrClosed
+	}
+	if _, ok := db.tables[name]; ok {
+		return fmt.Errorf("table [%s] already exists", name)
+	}
+	db.tables[name] = &memTable{rows: make(map[string]*henge.UniversalBo), checksums: make(map[string]string)}
+	return nil
+}
+
+// HasTable checks if the table exists.
+func (db *MemoryDb) HasTable(name string) bool {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+	_, ok := db.tables[name]
+	return ok
+}
+
+// CreateUniqueIndex adds a unique constraint on the combination of fields to a table.
+// Fields are names of top-level fields (e.g. henge.FieldId) or extra attributes of the stored business objects.
+// Similar to SQL, business objects having nil value on any of the fields are not constrained.
+func (db *MemoryDb) CreateUniqueIndex(tableName string, fields ...string) error {
+	if len(fields) == 0 {
+		return errors.New("no field specified")
+	}
+	db.lock.Lock()
+	defer db.lock.Unlock()
+	t, err := db._table(tableName)
+	if err != nil {
+		return err
+	}
+	for _, idx := range t.uniqueIndexes {
+		if strings.Join(idx, ",") == strings.Join(fields, ",") {
+			return fmt.Errorf("unique index on %s(%s) already exists", tableName, strings.Join(fields, ","))
+		}
+	}
+	for _, bo := range t.rows {
+		if t.conflict(bo, [][]string{fields}) {
+			return fmt.Errorf("cannot create unique index on %s(%s): duplicated values", tableName, strings.Join(fields, ","))
+		}
+	}
+	t.uniqueIndexes = append(t.uniqueIndexes, append([]string{}, fields...))
+	return nil
+}
+
+// Ping returns ErrClosed if the database has been closed, nil otherwise.
+func (db *MemoryDb) Ping() error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+	if db.closed {
+		return ErrClosed
+	}
+	return nil
+}
+
+// Close drops all tables and marks the database as closed.
+func (db *MemoryDb) Close() error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+	db.tables = make(map[string]*memTable)
+	db.closed = true
+	return nil
+}
+
+// _table returns the named table, caller must hold the lock.
+func (db *MemoryDb) _table(name string) (*memTable, error) {
+	if db.closed {
+		return nil, ErrClosed
+	}
+	t, ok := db.tables[name]
+	if !ok {
+		return nil, fmt.Errorf("table [%s] does not exist", name)
+	}
+	return t, nil
+}
+
+// conflict checks if bo violates any of the unique indexes against stored rows (other than the row with the same id).
+func (t *memTable) conflict(bo *henge.UniversalBo, uniqueIndexes [][]string) bool {
+	for _, fields := range uniqueIndexes {
+		values := make([]interface{}, len(fields))
+		hasNil := false
+		for i, field := range fields {
+			if values[i] = fieldValue(bo, field); values[i] == nil {
+				hasNil = true
+			}
+		}
+		if hasNil {
+			continue
+		}
+		for id, row := range t.rows {
+			if id == bo.GetId() {
+				continue
+			}
+			match := true
+			for i, field := range fields {
+				if c, err := compareValues(fieldValue(row, field), values[i]); err != nil || c != 0 {
+					match = false
+					break
+				}
+			}
+			if match {
+				return true
+			}
+		}
+	}
+	return false
+}
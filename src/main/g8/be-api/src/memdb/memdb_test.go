@@ -0,0 +1,337 @@
+package memdb
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/btnguyen2k/godal"
+	"github.com/btnguyen2k/henge"
+)
+
+const testTable = "test_table"
+
+func newTestDao(t *testing.T, testName string) (*MemoryDb, *UniversalDaoMemory) {
+	db := NewMemoryDb()
+	if err := db.CreateTable(testTable); err != nil {
+		t.Fatalf("%s failed: error [%s]", testName, err)
+	}
+	return db, NewUniversalDaoMemory(db, testTable)
+}
+
+func newTestBo(i int) *henge.UniversalBo {
+	bo := henge.NewUniversalBo(fmt.Sprintf("%03d", i), 1337)
+	bo.SetDataAttr("name", fmt.Sprintf("name-%d", i))
+	bo.SetExtraAttr("owner", fmt.Sprintf("user%d", i%3))
+	bo.SetExtraAttr("num", i%5)
+	return bo.Sync()
+}
+
+func TestMemoryDb_CreateTable(t *testing.T) {
+	name := "TestMemoryDb_CreateTable"
+	db := NewMemoryDb()
+	if db.HasTable(testTable) {
+		t.Fatalf("%s failed: table should not exist", name)
+	}
+	if err := db.CreateTable(testTable); err != nil {
+		t.Fatalf("%s failed: error [%s]", name, err)
+	}
+	if !db.HasTable(testTable) {
+		t.Fatalf("%s failed: table should exist", name)
+	}
+	if err := db.CreateTable(testTable); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Fatalf("%s failed: expected 'already exists' error but received %#v", name, err)
+	}
+	if _, err := NewUniversalDaoMemory(db, "not_exist").Get("1"); err == nil {
+		t.Fatalf("%s failed: expected error for non-existing table", name)
+	}
+}
+
+func TestMemoryDb_Close(t *testing.T) {
+	name := "TestMemoryDb_Close"
+	db, dao := newTestDao(t, name)
+	if err := db.Ping(); err != nil {
+		t.Fatalf("%s failed: error [%s]", name, err)
+	}
+	db.Close()
+	if err := db.Ping(); err != ErrClosed {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, ErrClosed, err)
+	}
+	if _, err := dao.Create(newTestBo(1)); err != ErrClosed {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, ErrClosed, err)
+	}
+}
+
+func TestUniversalDaoMemory_CreateGetUpdateDelete(t *testing.T) {
+	name := "TestUniversalDaoMemory_CreateGetUpdateDelete"
+	_, dao := newTestDao(t, name)
+	bo := newTestBo(1)
+	if ok, err := dao.Create(bo); !ok || err != nil {
+		t.Fatalf("%s failed: %#v / %s", name, ok, err)
+	}
+	if ok, err := dao.Create(bo); ok || err != godal.ErrGdaoDuplicatedEntry {
+		t.Fatalf("%s failed: expected %#v but received %#v / %s", name, godal.ErrGdaoDuplicatedEntry, ok, err)
+	}
+	bo1, err := dao.Get(bo.GetId())
+	if err != nil || bo1 == nil {
+		t.Fatalf("%s failed: %#v / %s", name, bo1, err)
+	}
+	if bo1.GetDataAttrUnsafe("name") != "name-1" || bo1.GetTimeCreated().Unix() != bo.GetTimeCreated().Unix() {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, bo.GetDataJson(), bo1.GetDataJson())
+	}
+
+	// returned instances are not shared with the storage
+	bo1.SetDataAttr("name", "changed")
+	if bo2, _ := dao.Get(bo.GetId()); bo2.GetDataAttrUnsafe("name") != "name-1" {
+		t.Fatalf("%s failed: storage was modified via returned instance", name)
+	}
+
+	bo1.Sync()
+	if ok, err := dao.Update(bo1); !ok || err != nil {
+		t.Fatalf("%s failed: %#v / %s", name, ok, err)
+	}
+	if bo2, _ := dao.Get(bo.GetId()); bo2.GetDataAttrUnsafe("name") != "changed" {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, "changed", bo2.GetDataAttrUnsafe("name"))
+	}
+	if ok, err := dao.Update(newTestBo(2)); ok || err != nil {
+		t.Fatalf("%s failed: expected update of non-existing object to fail but received %#v / %s", name, ok, err)
+	}
+
+	if ok, existing, err := dao.Save(newTestBo(2)); !ok || existing != nil || err != nil {
+		t.Fatalf("%s failed: %#v / %#v / %s", name, ok, existing, err)
+	}
+	if ok, existing, err := dao.Save(newTestBo(2)); !ok || existing == nil || err != nil {
+		t.Fatalf("%s failed: %#v / %#v / %s", name, ok, existing, err)
+	}
+
+	if ok, err := dao.Delete(bo); !ok || err != nil {
+		t.Fatalf("%s failed: %#v / %s", name, ok, err)
+	}
+	if ok, err := dao.Delete(bo); ok || err != nil {
+		t.Fatalf("%s failed: expected delete of non-existing object to fail but received %#v / %s", name, ok, err)
+	}
+	if bo2, err := dao.Get(bo.GetId()); bo2 != nil || err != nil {
+		t.Fatalf("%s failed: expected nil but received %#v / %s", name, bo2, err)
+	}
+}
+
+func TestUniversalDaoMemory_UniqueIndex(t *testing.T) {
+	name := "TestUniversalDaoMemory_UniqueIndex"
+	db, dao := newTestDao(t, name)
+	if err := db.CreateUniqueIndex(testTable, "owner", "num"); err != nil {
+		t.Fatalf("%s failed: error [%s]", name, err)
+	}
+	if err := db.CreateUniqueIndex(testTable, "owner", "num"); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Fatalf("%s failed: expected 'already exists' error but received %#v", name, err)
+	}
+	bo1 := newTestBo(1)
+	if ok, err := dao.Create(bo1); !ok || err != nil {
+		t.Fatalf("%s failed: %#v / %s", name, ok, err)
+	}
+	bo2 := newTestBo(2)
+	bo2.SetExtraAttr("owner", bo1.GetExtraAttr("owner"))
+	bo2.SetExtraAttr("num", bo1.GetExtraAttr("num"))
+	if ok, err := dao.Create(bo2); ok || err != godal.ErrGdaoDuplicatedEntry {
+		t.Fatalf("%s failed: expected %#v but received %#v / %s", name, godal.ErrGdaoDuplicatedEntry, ok, err)
+	}
+	bo2.SetExtraAttr("num", 99)
+	if ok, err := dao.Create(bo2); !ok || err != nil {
+		t.Fatalf("%s failed: %#v / %s", name, ok, err)
+	}
+	bo2.SetExtraAttr("num", bo1.GetExtraAttr("num"))
+	if ok, err := dao.Update(bo2); ok || err != godal.ErrGdaoDuplicatedEntry {
+		t.Fatalf("%s failed: expected %#v but received %#v / %s", name, godal.ErrGdaoDuplicatedEntry, ok, err)
+	}
+	// updating an object does not conflict with itself
+	if ok, err := dao.Update(bo1); !ok || err != nil {
+		t.Fatalf("%s failed: %#v / %s", name, ok, err)
+	}
+}
+
+func TestUniversalDaoMemory_GetNFilter(t *testing.T) {
+	name := "TestUniversalDaoMemory_GetNFilter"
+	_, dao := newTestDao(t, name)
+	for i := 0; i < 30; i++ {
+		dao.Create(newTestBo(i))
+	}
+	testCases := []struct {
+		filter   godal.FilterOpt
+		expected int
+	}{
+		{nil, 30},
+		{&godal.FilterOptFieldOpValue{FieldName: "owner", Operator: godal.FilterOpEqual, Value: "user1"}, 10},
+		{godal.FilterOptFieldOpValue{FieldName: "owner", Operator: godal.FilterOpNotEqual, Value: "user1"}, 20},
+		{&godal.FilterOptFieldOpValue{FieldName: "num", Operator: godal.FilterOpEqual, Value: int64(2)}, 6},
+		{&godal.FilterOptFieldOpValue{FieldName: "num", Operator: godal.FilterOpGreater, Value: 2}, 12},
+		{&godal.FilterOptFieldOpValue{FieldName: "num", Operator: godal.FilterOpGreaterOrEqual, Value: 2.0}, 18},
+		{&godal.FilterOptFieldOpValue{FieldName: "num", Operator: godal.FilterOpLess, Value: 2}, 12},
+		{&godal.FilterOptFieldOpValue{FieldName: "num", Operator: godal.FilterOpLessOrEqual, Value: 2}, 18},
+		{&godal.FilterOptFieldOpValue{FieldName: henge.FieldId, Operator: godal.FilterOpLess, Value: "010"}, 10},
+		{(&godal.FilterOptAnd{}).
+			Add(&godal.FilterOptFieldOpValue{FieldName: "owner", Operator: godal.FilterOpEqual, Value: "user1"}).
+			Add(&godal.FilterOptFieldOpValue{FieldName: "num", Operator: godal.FilterOpEqual, Value: 1}), 2},
+		{(&godal.FilterOptOr{}).
+			Add(&godal.FilterOptFieldOpValue{FieldName: "owner", Operator: godal.FilterOpEqual, Value: "user1"}).
+			Add(&godal.FilterOptFieldOpValue{FieldName: "num", Operator: godal.FilterOpEqual, Value: 0}), 14},
+		{&godal.FilterOptFieldIsNull{FieldName: "not_exist"}, 30},
+		{&godal.FilterOptFieldIsNotNull{FieldName: "owner"}, 30},
+		{&godal.FilterOptFieldOpValue{FieldName: "not_exist", Operator: godal.FilterOpEqual, Value: 1}, 0},
+	}
+	for i, testCase := range testCases {
+		boList, err := dao.GetAll(testCase.filter, nil)
+		if err != nil || len(boList) != testCase.expected {
+			t.Fatalf("%s failed: [%d] expected %#v but received %#v (error %s)", name, i, testCase.expected, len(boList), err)
+		}
+	}
+	if _, err := dao.GetAll("invalid filter", nil); err == nil {
+		t.Fatalf("%s failed: expected error for unsupported filter type", name)
+	}
+}
+
+func TestUniversalDaoMemory_GetMany(t *testing.T) {
+	name := "TestUniversalDaoMemory_GetMany"
+	_, dao := newTestDao(t, name)
+	for i := 0; i < 10; i++ {
+		dao.Create(newTestBo(i))
+	}
+	boList, err := dao.GetMany([]string{"007", "not-exist", "002", "007", "009"})
+	if err != nil || len(boList) != 3 {
+		t.Fatalf("%s failed: expected %#v but received %#v (error %s)", name, 3, len(boList), err)
+	}
+	for i, expected := range []string{"007", "002", "009"} {
+		if boList[i].GetId() != expected {
+			t.Fatalf("%s failed: [%d] expected %#v but received %#v", name, i, expected, boList[i].GetId())
+		}
+	}
+
+	// returned instances are not shared with the storage
+	boList[0].SetDataAttr("name", "changed")
+	if bo, _ := dao.Get("007"); bo.GetDataAttrUnsafe("name") != "name-7" {
+		t.Fatalf("%s failed: storage was modified via returned instance", name)
+	}
+
+	if boList, err := dao.GetMany(nil); err != nil || len(boList) != 0 {
+		t.Fatalf("%s failed: expected empty result but received %#v (error %s)", name, boList, err)
+	}
+}
+
+func TestUniversalDaoMemory_UpdateIfChecksum(t *testing.T) {
+	name := "TestUniversalDaoMemory_UpdateIfChecksum"
+	_, dao := newTestDao(t, name)
+	bo := newTestBo(1)
+	dao.Create(bo)
+	checksum := bo.GetChecksum()
+
+	bo1, _ := dao.Get(bo.GetId())
+	bo1.SetDataAttr("name", "changed-1")
+	bo1.Sync()
+	bo2, _ := dao.Get(bo.GetId())
+	bo2.SetDataAttr("name", "changed-2")
+	bo2.Sync()
+	if ok, err := dao.UpdateIfChecksum(bo1, checksum); !ok || err != nil {
+		t.Fatalf("%s failed: %#v / %s", name, ok, err)
+	}
+	if ok, err := dao.UpdateIfChecksum(bo2, checksum); ok || err != nil {
+		t.Fatalf("%s failed: expected stale update to fail but received %#v / %s", name, ok, err)
+	}
+	if bo3, _ := dao.Get(bo.GetId()); bo3.GetDataAttrUnsafe("name") != "changed-1" {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, "changed-1", bo3.GetDataAttrUnsafe("name"))
+	}
+	if ok, err := dao.UpdateIfChecksum(newTestBo(2), checksum); ok || err != nil {
+		t.Fatalf("%s failed: expected update of non-existing object to fail but received %#v / %s", name, ok, err)
+	}
+}
+
+func TestUniversalDaoMemory_DeleteIfChecksum(t *testing.T) {
+	name := "TestUniversalDaoMemory_DeleteIfChecksum"
+	_, dao := newTestDao(t, name)
+	bo := newTestBo(1)
+	dao.Create(bo)
+	checksum := bo.GetChecksum()
+
+	bo1, _ := dao.Get(bo.GetId())
+	bo1.SetDataAttr("name", "changed-1")
+	bo1.Sync()
+	dao.Update(bo1)
+	if ok, err := dao.DeleteIfChecksum(bo, checksum); ok || err != nil {
+		t.Fatalf("%s failed: expected stale delete to fail but received %#v / %s", name, ok, err)
+	}
+	if ok, err := dao.DeleteIfChecksum(bo, bo1.GetChecksum()); !ok || err != nil {
+		t.Fatalf("%s failed: %#v / %s", name, ok, err)
+	}
+	if bo2, _ := dao.Get(bo.GetId()); bo2 != nil {
+		t.Fatalf("%s failed: expected nil but received %#v", name, bo2)
+	}
+	if ok, err := dao.DeleteIfChecksum(bo, bo1.GetChecksum()); ok || err != nil {
+		t.Fatalf("%s failed: expected delete of non-existing object to fail but received %#v / %s", name, ok, err)
+	}
+}
+
+func TestUniversalDaoMemory_GetNSortingPaging(t *testing.T) {
+	name := "TestUniversalDaoMemory_GetNSortingPaging"
+	_, dao := newTestDao(t, name)
+	for _, i := range []int{7, 3, 9, 1, 5, 0, 8, 2, 6, 4} {
+		dao.Create(newTestBo(i))
+	}
+	boList, err := dao.GetN(2, 3, nil, nil)
+	if err != nil || len(boList) != 3 {
+		t.Fatalf("%s failed: expected %#v but received %#v (error %s)", name, 3, len(boList), err)
+	}
+	for i, bo := range boList {
+		if expected := fmt.Sprintf("%03d", i+2); bo.GetId() != expected {
+			t.Fatalf("%s failed: expected %#v but received %#v", name, expected, bo.GetId())
+		}
+	}
+	sorting := (&godal.SortingOpt{}).Add(&godal.SortingField{FieldName: "num", Descending: true}, &godal.SortingField{FieldName: henge.FieldId})
+	boList, _ = dao.GetAll(nil, sorting)
+	for i := 1; i < len(boList); i++ {
+		prev, cur := boList[i-1], boList[i]
+		c, _ := compareValues(prev.GetExtraAttr("num"), cur.GetExtraAttr("num"))
+		if c < 0 || (c == 0 && prev.GetId() > cur.GetId()) {
+			t.Fatalf("%s failed: not in correct order %s -> %s", name, prev.GetId(), cur.GetId())
+		}
+	}
+	if boList, _ = dao.GetN(20, 5, nil, nil); len(boList) != 0 {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, 0, len(boList))
+	}
+}
+
+func TestUniversalDaoMemory_SortByTime(t *testing.T) {
+	name := "TestUniversalDaoMemory_SortByTime"
+	_, dao := newTestDao(t, name)
+	now := time.Now()
+	for i := 0; i < 10; i++ {
+		bo := newTestBo(i)
+		bo.SetExtraAttr(henge.FieldTimeCreated, now.Add(time.Duration(10-i)*time.Second))
+		dao.Create(bo)
+	}
+	boList, _ := dao.GetAll(nil, (&godal.SortingField{FieldName: henge.FieldTimeCreated}).ToSortingOpt())
+	for i := 1; i < len(boList); i++ {
+		if !boList[i-1].GetTimeCreated().Before(boList[i].GetTimeCreated()) {
+			t.Fatalf("%s failed: not in correct order {%s:%s} -> {%s:%s}", name, boList[i-1].GetId(), boList[i-1].GetTimeCreated(), boList[i].GetId(), boList[i].GetTimeCreated())
+		}
+	}
+}
+
+func TestUniversalDaoMemory_Concurrency(t *testing.T) {
+	name := "TestUniversalDaoMemory_Concurrency"
+	_, dao := newTestDao(t, name)
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			bo := newTestBo(i)
+			dao.Create(bo)
+			dao.GetAll(&godal.FilterOptFieldOpValue{FieldName: "owner", Operator: godal.FilterOpEqual, Value: "user1"}, nil)
+			bo.SetDataAttr("name", "updated")
+			dao.Update(bo.Sync())
+		}(i)
+	}
+	wg.Wait()
+	if boList, err := dao.GetAll(nil, nil); err != nil || len(boList) != 50 {
+		t.Fatalf("%s failed: expected %#v but received %#v (error %s)", name, 50, len(boList), err)
+	}
+}
@@ -8,6 +8,8 @@ package utils
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
 	"math/rand"
 	"net"
 	"strconv"
@@ -15,6 +17,8 @@ import (
 	"time"
 
 	olaf2 "github.com/btnguyen2k/consu/olaf"
+	"github.com/btnguyen2k/godal"
+	"github.com/btnguyen2k/henge"
 )
 
 // global variables
@@ -77,3 +81,31 @@ func VersionToNumber(version string) uint64 {
 	}
 	return result
 }
+
+// UboFromJson decodes a henge.UniversalBo serialized by json.Marshal.
+//
+// json.Unmarshal is not used directly on henge.UniversalBo because it re-syncs the bo from JSON-typed extra attributes
+// (numbers become float64) and, as the checksum changes, sets the "time-updated" timestamp to the current time.
+//
+// Available since template-v0.5.0
+func UboFromJson(data []byte) (*henge.UniversalBo, error) {
+	var record map[string]interface{}
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, err
+	}
+	if ext, ok := record[henge.FieldExtras].(map[string]interface{}); ok {
+		for k, v := range ext {
+			record[k] = v
+		}
+	}
+	delete(record, henge.FieldExtras)
+	gbo := godal.NewGenericBo()
+	if err := gbo.GboImportViaJson(record); err != nil {
+		return nil, err
+	}
+	ubo := henge.NewUniversalBoFromGbo(gbo)
+	if ubo == nil {
+		return nil, errors.New("invalid business object")
+	}
+	return ubo, nil
+}
@@ -28,6 +28,7 @@ let apiMyBlog = '/api/myblog'
 let apiMyFeed = '/api/myfeed'
 let apiPost = '/api/post'
 let apiUserVoteForPost = '/api/vote'
+let apiBatch = '/api/batch'
 
 let apiSystemInfo = '/api/systemInfo'
 let apiGroupList = '/api/groups'
@@ -68,6 +69,8 @@ function buildHeaders() {
   headers[headerAppId] = appId
   headers[headerAccessToken] = session != null ? session.token : ''
   headers[headerLanguage] = i18n.global.locale
+  // this client expects results in the legacy envelope (HTTP 200 + status in body), regardless of server's response mode
+  headers['X-Response-Mode'] = 'legacy'
   return headers
 }
 
@@ -123,6 +126,7 @@ export default {
   apiMyFeed,
   apiPost,
   apiUserVoteForPost,
+  apiBatch,
 
   apiSystemInfo,
   apiGroupList,